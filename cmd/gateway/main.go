@@ -1,113 +1,301 @@
-package main
-
-import (
-	"context"
-	"fmt"
-	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
-	"time"
-
-	"github.com/gin-gonic/gin"
-	"github.com/vhvplatform/go-shared/config"
-	"github.com/vhvplatform/go-shared/logger"
-	"github.com/vhvplatform/go-tenant-service/internal/gateway"
-	"go.uber.org/zap"
-)
-
-func main() {
-	// Load configuration
-	cfg, err := config.Load()
-	if err != nil {
-		panic(fmt.Sprintf("Failed to load config: %v", err))
-	}
-
-	// Initialize logger
-	log, err := logger.New(cfg.LogLevel)
-	if err != nil {
-		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
-	}
-	defer log.Sync()
-
-	log.Info("Starting API Gateway", zap.String("environment", cfg.Environment))
-
-	// Initialize local cache
-	// Point 5: "thêm cấu hình để giới hạn cache tối đa bao nhiêu dữ liệu"
-	cache := gateway.NewCache(5*time.Minute, 10*time.Minute)
-
-	// Initialize mock auth provider (In production, this would be a gRPC client to Auth service)
-	authProvider := &MockAuthProvider{}
-
-	// Initialize proxy handler
-	proxyHandler := gateway.NewProxyHandler(log)
-
-	// Setup Gin
-	gin.SetMode(gin.ReleaseMode)
-	router := gin.New()
-	router.Use(gin.Recovery())
-
-	// Health check
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "healthy", "cache_items": cache.ItemCount()})
-	})
-
-	// Apply Auth Middleware and Proxy all other requests
-	router.Use(gateway.AuthMiddleware(authProvider, cache, log))
-	router.NoRoute(proxyHandler.HandleRequest)
-
-	port := os.Getenv("GATEWAY_PORT")
-	if port == "" {
-		port = "8080"
-	}
-
-	srv := &http.Server{
-		Addr:    ":" + port,
-		Handler: router,
-	}
-
-	// Graceful shutdown
-	go func() {
-		log.Info("Gateway listening", zap.String("port", port))
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatal("Failed to start Gateway", zap.Error(err))
-		}
-	}()
-
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	log.Info("Shutting down Gateway...")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("Gateway forced to shutdown", zap.Error(err))
-	}
-
-	log.Info("Gateway exited")
-}
-
-// MockAuthProvider for demonstration
-type MockAuthProvider struct{}
-
-func (m *MockAuthProvider) VerifyToken(ctx context.Context, token string) (*gateway.TokenInfo, error) {
-	return &gateway.TokenInfo{
-		UserID:      "user-123",
-		TenantID:    "tenant-abc",
-		Permissions: []string{"read", "write"},
-	}, nil
-}
-
-func (m *MockAuthProvider) GetTenantInfo(ctx context.Context, tenantID string) (*gateway.TenantInfo, error) {
-	return &gateway.TenantInfo{
-		ID:             tenantID,
-		DefaultService: "tenant-service",
-		IsActive:       true,
-	}, nil
-}
-
-func (m *MockAuthProvider) GenerateInternalToken(ctx context.Context, info *gateway.TokenInfo) (string, error) {
-	return "internal-jwt-token-for-" + info.UserID, nil
-}
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vhvplatform/go-shared/config"
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-shared/mongodb"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/gateway"
+	"github.com/vhvplatform/go-tenant-service/internal/grpc"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+	"github.com/vhvplatform/go-tenant-service/internal/service"
+	pb "github.com/vhvplatform/go-tenant-service/proto"
+	"go.uber.org/zap"
+	grpcClient "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to load config: %v", err))
+	}
+
+	// Initialize logger
+	log, err := logger.New(cfg.LogLevel)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
+	}
+	defer log.Sync()
+
+	log.Info("Starting API Gateway", zap.String("environment", cfg.Environment))
+
+	// Initialize local cache
+	// Point 5: "thêm cấu hình để giới hạn cache tối đa bao nhiêu dữ liệu"
+	cache := gateway.NewCache(5*time.Minute, 10*time.Minute)
+
+	// Initialize service resolver
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	baseDomain := os.Getenv("GATEWAY_BASE_DOMAIN")
+	resolver, hostResolver, defaultResolver, closeResolver, err := newResolvers(ctx, cfg, baseDomain, log)
+	if err != nil {
+		log.Fatal("Failed to initialize service resolver", zap.Error(err))
+	}
+	defer closeResolver()
+
+	// Initialize mock auth provider (In production, this would be a gRPC client to Auth service)
+	authProvider := &MockAuthProvider{
+		internalTokenKey: []byte(os.Getenv("INTERNAL_TOKEN_KEY")),
+		defaultServices:  defaultResolver,
+	}
+
+	// Initialize proxy handler
+	proxyHandler := gateway.NewProxyHandler(resolver, loadFailoverConfig(), log)
+
+	// Setup Gin
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	// Health check
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "healthy", "cache_items": cache.ItemCount()})
+	})
+
+	// Apply Auth Middleware and Proxy all other requests
+	router.Use(gateway.AuthMiddleware(authProvider, cache, gateway.AuthConfig{
+		HostResolver:     hostResolver,
+		PublicRoutes:     loadPublicRouteConfig(),
+		RoutePermissions: loadRoutePermissions(log),
+	}, log))
+	router.NoRoute(proxyHandler.HandleRequest)
+
+	port := os.Getenv("GATEWAY_PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
+	}
+
+	// Graceful shutdown
+	go func() {
+		log.Info("Gateway listening", zap.String("port", port))
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Failed to start Gateway", zap.Error(err))
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Info("Shutting down Gateway...")
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Fatal("Gateway forced to shutdown", zap.Error(err))
+	}
+
+	log.Info("Gateway exited")
+}
+
+// newResolvers builds the resolvers used to route requests, map hosts to tenants and find the
+// default services of tenants. With GATEWAY_RESOLVER=grpc they are served by the tenant service
+// over gRPC; otherwise the gateway runs its own ServiceRegistry against MongoDB, keeps its
+// config cache fresh via a change stream and probes the endpoints with its own HealthChecker.
+func newResolvers(ctx context.Context, cfg *config.Config, baseDomain string, log *logger.Logger) (gateway.ServiceResolver, gateway.TenantHostResolver, gateway.DefaultServiceResolver, func(), error) {
+	if os.Getenv("GATEWAY_RESOLVER") == "grpc" {
+		addr := os.Getenv("TENANT_SERVICE_GRPC_ADDR")
+		if addr == "" {
+			addr = "localhost:50053"
+		}
+
+		creds := grpcClient.WithTransportCredentials(insecure.NewCredentials())
+		if certFile := os.Getenv("GATEWAY_TLS_CERT"); certFile != "" {
+			tlsCreds, err := grpc.LoadClientTLSCredentials(certFile, os.Getenv("GATEWAY_TLS_KEY"), os.Getenv("GATEWAY_TLS_CA"))
+			if err != nil {
+				return nil, nil, nil, nil, err
+			}
+			creds = tlsCreds
+		}
+
+		conn, err := grpcClient.NewClient(addr, creds)
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("failed to connect to tenant service: %w", err)
+		}
+
+		client := pb.NewTenantServiceClient(conn)
+		log.Info("Resolving routes via tenant service", zap.String("addr", addr))
+		return gateway.NewGRPCResolver(client), gateway.NewGRPCHostResolver(client, baseDomain), gateway.NewGRPCDefaultResolver(client),
+			func() { conn.Close() }, nil
+	}
+
+	mongoClient, err := mongodb.NewClient(ctx, mongodb.Config{
+		URI:         cfg.MongoDB.URI,
+		Database:    cfg.MongoDB.Database,
+		MaxPoolSize: cfg.MongoDB.MaxPoolSize,
+		MinPoolSize: cfg.MongoDB.MinPoolSize,
+	})
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+
+	db := mongoClient.Database()
+	serviceConfigRepo := repository.NewServiceConfigRepository(db)
+	tenantRepo := repository.NewTenantRepository(db)
+	registry := service.NewServiceRegistry(serviceConfigRepo, log)
+	registry.SetTenantRepository(tenantRepo)
+	go registry.WatchConfigChanges(ctx)
+
+	// Probe endpoints here as well, so routing skips the ones failing their health checks
+	refreshInterval, _ := time.ParseDuration(os.Getenv("HEALTH_CHECK_REFRESH_INTERVAL"))
+	healthChecker := service.NewHealthChecker(serviceConfigRepo, registry, refreshInterval, log)
+	healthChecker.Start(ctx)
+
+	// Only used for host lookups; verification runs in the tenant service
+	domainService := service.NewDomainService(
+		repository.NewTenantDomainRepository(db),
+		tenantRepo,
+		service.NewNetVerificationResolver(),
+		service.DefaultDomainVerificationConfig(),
+		log,
+	)
+
+	// Only used to read default services; tenants are changed through the tenant service
+	tenantService := service.NewTenantService(tenantRepo, nil, domainService, nil, nil, nil, nil, nil, log)
+
+	log.Info("Resolving routes via in-process service registry")
+	return gateway.NewRegistryResolver(registry), gateway.NewServiceHostResolver(domainService, baseDomain),
+		gateway.NewServiceDefaultResolver(tenantService), func() {
+			healthChecker.Stop()
+			mongoClient.Close(context.Background())
+		}, nil
+}
+
+// loadFailoverConfig reads failover settings from the environment, keeping defaults for unset values
+func loadFailoverConfig() gateway.FailoverConfig {
+	failover := gateway.DefaultFailoverConfig()
+
+	if codes := os.Getenv("GATEWAY_FAILOVER_STATUS_CODES"); codes != "" {
+		failover.RetryStatusCodes = nil
+		for _, code := range strings.Split(codes, ",") {
+			if status, err := strconv.Atoi(strings.TrimSpace(code)); err == nil {
+				failover.RetryStatusCodes = append(failover.RetryStatusCodes, status)
+			}
+		}
+	}
+	if attempts, err := strconv.Atoi(os.Getenv("GATEWAY_FAILOVER_MAX_ATTEMPTS")); err == nil && attempts > 0 {
+		failover.MaxAttempts = attempts
+	}
+	if maxBody, err := strconv.ParseInt(os.Getenv("GATEWAY_FAILOVER_MAX_BODY_BYTES"), 10, 64); err == nil && maxBody >= 0 {
+		failover.MaxBodyBytes = maxBody
+	}
+	if service := os.Getenv("GATEWAY_SYSTEM_DEFAULT_SERVICE"); service != "" {
+		failover.SystemDefaultService = service
+	}
+
+	return failover
+}
+
+// loadPublicRouteConfig reads the routes served without authentication from the environment
+func loadPublicRouteConfig() gateway.PublicRouteConfig {
+	public := gateway.PublicRouteConfig{
+		AllowSlugs: os.Getenv("GATEWAY_PUBLIC_SLUGS") == "true",
+	}
+
+	for _, prefix := range strings.Split(os.Getenv("GATEWAY_PUBLIC_PATHS"), ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			public.PathPrefixes = append(public.PathPrefixes, prefix)
+		}
+	}
+
+	return public
+}
+
+// loadRoutePermissions reads route permission rules from GATEWAY_ROUTE_PERMISSIONS, a comma-separated
+// list of "[METHOD ]/path/prefix=permission" entries, e.g. "POST /api/billing/=billing:manage"
+func loadRoutePermissions(log *logger.Logger) []gateway.RoutePermission {
+	var rules []gateway.RoutePermission
+
+	for _, entry := range strings.Split(os.Getenv("GATEWAY_ROUTE_PERMISSIONS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		route, permission, ok := strings.Cut(entry, "=")
+		permission = strings.TrimSpace(permission)
+		if !ok || !domain.IsValidPermission(permission) {
+			log.Warn("Ignoring invalid route permission", zap.String("entry", entry))
+			continue
+		}
+
+		rule := gateway.RoutePermission{Permission: permission}
+		if method, prefix, hasMethod := strings.Cut(strings.TrimSpace(route), " "); hasMethod {
+			rule.Method = strings.ToUpper(method)
+			rule.PathPrefix = strings.TrimSpace(prefix)
+		} else {
+			rule.PathPrefix = method
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// MockAuthProvider for demonstration
+type MockAuthProvider struct {
+	internalTokenKey []byte                         // Shared with the services that verify internal tokens
+	defaultServices  gateway.DefaultServiceResolver // Fills the default service and fallback chain of tenants
+}
+
+func (m *MockAuthProvider) VerifyToken(ctx context.Context, token string) (*gateway.TokenInfo, error) {
+	return &gateway.TokenInfo{
+		UserID:      "user-123",
+		TenantID:    "tenant-abc",
+		Permissions: []string{domain.PermissionAll},
+	}, nil
+}
+
+func (m *MockAuthProvider) GetTenantInfo(ctx context.Context, tenantID string) (*gateway.TenantInfo, error) {
+	info := &gateway.TenantInfo{
+		ID:             tenantID,
+		DefaultService: "tenant-service",
+		IsActive:       true,
+		Status:         domain.TenantStatusActive,
+	}
+
+	defaults, err := m.defaultServices.ResolveDefaultService(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve default service: %w", err)
+	}
+	info.DefaultServiceURL = defaults.DefaultServiceURL
+	info.FallbackChain = defaults.FallbackURLs
+	return info, nil
+}
+
+func (m *MockAuthProvider) GenerateInternalToken(ctx context.Context, info *gateway.TokenInfo) (string, error) {
+	if len(m.internalTokenKey) == 0 {
+		return "", fmt.Errorf("INTERNAL_TOKEN_KEY is not set")
+	}
+	actor := domain.Actor{ID: info.UserID, Type: domain.ActorTypeUser}
+	return domain.SignInternalToken(m.internalTokenKey, actor, time.Now().Add(domain.InternalTokenTTL)), nil
+}
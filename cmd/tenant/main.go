@@ -1,159 +1,465 @@
-package main
-
-import (
-	"context"
-	"fmt"
-	"net"
-	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
-	"time"
-
-	"github.com/gin-gonic/gin"
-	"github.com/vhvplatform/go-shared/config"
-	"github.com/vhvplatform/go-shared/logger"
-	"github.com/vhvplatform/go-shared/mongodb"
-	"github.com/vhvplatform/go-tenant-service/internal/grpc"
-	"github.com/vhvplatform/go-tenant-service/internal/handler"
-	"github.com/vhvplatform/go-tenant-service/internal/repository"
-	"github.com/vhvplatform/go-tenant-service/internal/service"
-
-	pb "github.com/vhvplatform/go-tenant-service/proto"
-	"go.uber.org/zap"
-	grpcServer "google.golang.org/grpc"
-	"google.golang.org/grpc/health"
-	healthpb "google.golang.org/grpc/health/grpc_health_v1"
-)
-
-func main() {
-	// Load configuration
-	cfg, err := config.Load()
-	if err != nil {
-		panic(fmt.Sprintf("Failed to load config: %v", err))
-	}
-
-	// Initialize logger
-	log, err := logger.New(cfg.LogLevel)
-	if err != nil {
-		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
-	}
-	defer log.Sync()
-
-	log.Info("Starting Tenant Service", zap.String("environment", cfg.Environment))
-
-	// Initialize MongoDB
-	mongoClient, err := mongodb.NewClient(context.Background(), mongodb.Config{
-		URI:         cfg.MongoDB.URI,
-		Database:    cfg.MongoDB.Database,
-		MaxPoolSize: cfg.MongoDB.MaxPoolSize,
-		MinPoolSize: cfg.MongoDB.MinPoolSize,
-	})
-	if err != nil {
-		log.Fatal("Failed to connect to MongoDB", zap.Error(err))
-	}
-	defer mongoClient.Close(context.Background())
-
-	// Initialize repositories
-	tenantRepo := repository.NewTenantRepository(mongoClient.Database())
-	tenantUserRepo := repository.NewTenantUserRepository(mongoClient.Database())
-
-	// Initialize services
-	tenantService := service.NewTenantService(tenantRepo, tenantUserRepo, log)
-
-	// Start gRPC server
-	grpcPort := os.Getenv("TENANT_SERVICE_PORT")
-	if grpcPort == "" {
-		grpcPort = "50053"
-	}
-	go startGRPCServer(tenantService, log, grpcPort)
-
-	// Start HTTP server
-	httpPort := os.Getenv("TENANT_SERVICE_HTTP_PORT")
-	if httpPort == "" {
-		httpPort = "8083"
-	}
-	startHTTPServer(tenantService, log, httpPort)
-}
-
-func startGRPCServer(tenantService *service.TenantService, log *logger.Logger, port string) {
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
-	if err != nil {
-		log.Fatal("Failed to listen", zap.Error(err))
-	}
-
-	grpcSrv := grpcServer.NewServer()
-	tenantGrpcServer := grpc.NewTenantServiceServer(tenantService, log)
-	pb.RegisterTenantServiceServer(grpcSrv, tenantGrpcServer)
-
-	// Register health check service
-	healthServer := health.NewServer()
-	healthpb.RegisterHealthServer(grpcSrv, healthServer)
-	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
-
-	log.Info("gRPC server listening", zap.String("port", port))
-	if err := grpcSrv.Serve(lis); err != nil {
-		log.Fatal("Failed to serve gRPC", zap.Error(err))
-	}
-}
-
-func startHTTPServer(tenantService *service.TenantService, log *logger.Logger, port string) {
-	gin.SetMode(gin.ReleaseMode)
-	router := gin.New()
-	router.Use(gin.Recovery())
-
-	// Initialize handlers
-	tenantHandler := handler.NewTenantHandler(tenantService, log)
-
-	// Health check endpoints
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
-	})
-	router.GET("/ready", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ready"})
-	})
-
-	// API routes
-	v1 := router.Group("/api/v1")
-	{
-		tenants := v1.Group("/tenants")
-		{
-			tenants.POST("", tenantHandler.CreateTenant)
-			tenants.GET("", tenantHandler.ListTenants)
-			tenants.GET("/:id", tenantHandler.GetTenant)
-			tenants.PUT("/:id", tenantHandler.UpdateTenant)
-			tenants.DELETE("/:id", tenantHandler.DeleteTenant)
-			tenants.POST("/:id/users", tenantHandler.AddUserToTenant)
-			tenants.DELETE("/:id/users/:user_id", tenantHandler.RemoveUserFromTenant)
-		}
-	}
-
-	srv := &http.Server{
-		Addr:    fmt.Sprintf(":%s", port),
-		Handler: router,
-	}
-
-	// Start server in goroutine
-	go func() {
-		log.Info("HTTP server listening", zap.String("port", port))
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatal("Failed to start HTTP server", zap.Error(err))
-		}
-	}()
-
-	// Wait for interrupt signal to gracefully shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	log.Info("Shutting down server...")
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown", zap.Error(err))
-	}
-
-	log.Info("Server exited")
-}
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vhvplatform/go-shared/config"
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-shared/mongodb"
+	"github.com/vhvplatform/go-tenant-service/internal/grpc"
+	"github.com/vhvplatform/go-tenant-service/internal/handler"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+	"github.com/vhvplatform/go-tenant-service/internal/service"
+
+	pb "github.com/vhvplatform/go-tenant-service/proto"
+	"go.uber.org/zap"
+	grpcServer "google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to load config: %v", err))
+	}
+
+	// Initialize logger
+	log, err := logger.New(cfg.LogLevel)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
+	}
+	defer log.Sync()
+
+	log.Info("Starting Tenant Service", zap.String("environment", cfg.Environment))
+	if len(loadInternalTokenKey()) == 0 {
+		log.Warn("No internal token key configured; HTTP callers will be recorded as anonymous")
+	}
+
+	// Initialize MongoDB
+	mongoClient, err := mongodb.NewClient(context.Background(), mongodb.Config{
+		URI:         cfg.MongoDB.URI,
+		Database:    cfg.MongoDB.Database,
+		MaxPoolSize: cfg.MongoDB.MaxPoolSize,
+		MinPoolSize: cfg.MongoDB.MinPoolSize,
+	})
+	if err != nil {
+		log.Fatal("Failed to connect to MongoDB", zap.Error(err))
+	}
+	defer mongoClient.Close(context.Background())
+
+	// Initialize repositories
+	tenantRepo := repository.NewTenantRepository(mongoClient.Database())
+	tenantUserRepo := repository.NewTenantUserRepository(mongoClient.Database())
+	serviceConfigRepo := repository.NewServiceConfigRepository(mongoClient.Database())
+	tenantDomainRepo := repository.NewTenantDomainRepository(mongoClient.Database())
+	purgeReportRepo := repository.NewPurgeReportRepository(mongoClient.Database())
+	tenantRoleRepo := repository.NewTenantRoleRepository(mongoClient.Database())
+	tenantInvitationRepo := repository.NewTenantInvitationRepository(mongoClient.Database())
+	settingDefinitionRepo := repository.NewSettingDefinitionRepository(mongoClient.Database())
+	auditConfig := loadAuditConfig()
+	auditEventRepo := repository.NewAuditEventRepository(mongoClient.Database(), auditConfig.Retention)
+	outboxConfig := loadOutboxConfig()
+	outboxRepo := repository.NewOutboxRepository(mongoClient.Database(), outboxConfig.Retention)
+	transactions, err := repository.NewTransactionRunner(mongoClient.Database())
+	if err != nil {
+		log.Fatal("Failed to initialize transactions", zap.Error(err))
+	}
+	if !transactions.Supported() {
+		if os.Getenv("MONGODB_ALLOW_NO_TRANSACTIONS") != "true" {
+			log.Fatal("MongoDB does not support transactions; use a replica set, or set MONGODB_ALLOW_NO_TRANSACTIONS=true for development")
+		}
+		log.Warn("MongoDB does not support transactions; tenant changes and their events are written without them")
+	}
+
+	// Initialize services
+	domainService := service.NewDomainService(tenantDomainRepo, tenantRepo, service.NewNetVerificationResolver(), loadDomainVerificationConfig(), log)
+	roleService := service.NewRoleService(tenantRoleRepo, tenantRepo, tenantUserRepo, log)
+	entitlementService := service.NewEntitlementService(tenantRepo, tenantUserRepo, serviceConfigRepo, log)
+	settingsService := service.NewSettingsService(settingDefinitionRepo, tenantRepo, log)
+	auditService := service.NewAuditService(auditEventRepo, log)
+	domainService.SetAuditService(auditService)
+	roleService.SetAuditService(auditService)
+	entitlementService.SetAuditService(auditService)
+	eventOutbox := service.NewEventOutbox(outboxRepo, transactions)
+	outboxRelay := service.NewOutboxRelay(outboxRepo, newEventSink(log), outboxConfig, log)
+	tenantService := service.NewTenantService(tenantRepo, tenantUserRepo, domainService, roleService, entitlementService, settingsService, auditService, eventOutbox, log)
+	registryService := service.NewServiceRegistry(serviceConfigRepo, log)
+	registryService.SetEntitlementService(entitlementService)
+	registryService.SetTenantRepository(tenantRepo)
+	registryService.SetAuditService(auditService)
+	registryService.SetEventOutbox(eventOutbox)
+	purgeService := service.NewPurgeService(tenantService, tenantRepo, purgeReportRepo, loadPurgeConfig(), log)
+	planService := service.NewPlanService(tenantService, tenantRepo, tenantUserRepo, serviceConfigRepo, tenantDomainRepo, loadPlanConfig(), log)
+	trialService := service.NewTrialService(tenantService, tenantRepo, loadTrialConfig(), log)
+	invitationService := service.NewInvitationService(tenantInvitationRepo, tenantRepo, tenantUserRepo, roleService, entitlementService, service.NewLogInvitationSender(log), eventOutbox, loadInvitationConfig(), log)
+	invitationService.SetAuditService(auditService)
+
+	// Tenant-owned collections removed when a tenant is purged
+	purgeService.RegisterCollection("tenant_users", tenantUserRepo.DeleteByTenant)
+	purgeService.RegisterCollection("service_configs", registryService.DeleteTenantServiceConfigs)
+	purgeService.RegisterCollection("tenant_domains", tenantDomainRepo.DeleteByTenant)
+	purgeService.RegisterCollection("tenant_roles", tenantRoleRepo.DeleteByTenant)
+	purgeService.RegisterCollection("tenant_invitations", tenantInvitationRepo.DeleteByTenant)
+
+	// Start background domain verification
+	domainService.Start(context.Background())
+	defer domainService.Stop()
+
+	// Start background tenant purge
+	purgeService.Start(context.Background())
+	defer purgeService.Stop()
+
+	// Start background scheduled plan changes
+	planService.Start(context.Background())
+	defer planService.Stop()
+
+	// Start background trial expiry
+	trialService.Start(context.Background())
+	defer trialService.Stop()
+
+	// Start background publishing of tenant events
+	outboxRelay.Start(context.Background())
+	defer outboxRelay.Stop()
+
+	// Start background health checker
+	refreshInterval, _ := time.ParseDuration(os.Getenv("HEALTH_CHECK_REFRESH_INTERVAL"))
+	healthChecker := service.NewHealthChecker(serviceConfigRepo, registryService, refreshInterval, log)
+	healthChecker.Start(context.Background())
+	defer healthChecker.Stop()
+
+	// Start gRPC server
+	grpcPort := os.Getenv("TENANT_SERVICE_PORT")
+	if grpcPort == "" {
+		grpcPort = "50053"
+	}
+	go startGRPCServer(tenantService, registryService, domainService, purgeService, roleService, invitationService, entitlementService, planService, trialService, settingsService, auditService, log, grpcPort)
+
+	// Start HTTP server
+	httpPort := os.Getenv("TENANT_SERVICE_HTTP_PORT")
+	if httpPort == "" {
+		httpPort = "8083"
+	}
+	startHTTPServer(tenantService, domainService, purgeService, roleService, invitationService, entitlementService, planService, trialService, settingsService, auditService, log, httpPort)
+}
+
+// loadInternalTokenKey reads the key internal tokens identifying callers are signed with
+func loadInternalTokenKey() []byte {
+	return []byte(os.Getenv("INTERNAL_TOKEN_KEY"))
+}
+
+// loadDomainVerificationConfig reads domain verification settings from the environment, keeping defaults for unset values
+func loadDomainVerificationConfig() service.DomainVerificationConfig {
+	config := service.DefaultDomainVerificationConfig()
+
+	if interval, err := time.ParseDuration(os.Getenv("DOMAIN_VERIFICATION_INTERVAL")); err == nil {
+		config.Interval = interval
+	}
+	if ttl, err := time.ParseDuration(os.Getenv("DOMAIN_CLAIM_TTL")); err == nil {
+		config.ClaimTTL = ttl
+	}
+	if attempts, err := strconv.Atoi(os.Getenv("DOMAIN_VERIFICATION_MAX_ATTEMPTS")); err == nil {
+		config.MaxAttempts = attempts
+	}
+
+	return config
+}
+
+// loadPurgeConfig reads tenant purge settings from the environment, keeping defaults for unset values
+func loadPurgeConfig() service.PurgeConfig {
+	config := service.DefaultPurgeConfig()
+
+	if gracePeriod, err := time.ParseDuration(os.Getenv("TENANT_PURGE_GRACE_PERIOD")); err == nil {
+		config.GracePeriod = gracePeriod
+	}
+	if interval, err := time.ParseDuration(os.Getenv("TENANT_PURGE_INTERVAL")); err == nil {
+		config.Interval = interval
+	}
+	if batchSize, err := strconv.Atoi(os.Getenv("TENANT_PURGE_BATCH_SIZE")); err == nil {
+		config.BatchSize = batchSize
+	}
+
+	return config
+}
+
+// loadPlanConfig reads scheduled plan change settings from the environment, keeping defaults for unset values
+func loadPlanConfig() service.PlanConfig {
+	config := service.DefaultPlanConfig()
+
+	if interval, err := time.ParseDuration(os.Getenv("PLAN_CHANGE_INTERVAL")); err == nil {
+		config.Interval = interval
+	}
+	if batchSize, err := strconv.Atoi(os.Getenv("PLAN_CHANGE_BATCH_SIZE")); err == nil {
+		config.BatchSize = batchSize
+	}
+
+	return config
+}
+
+// loadTrialConfig reads trial settings from the environment, keeping defaults for unset values
+func loadTrialConfig() service.TrialConfig {
+	config := service.DefaultTrialConfig()
+
+	if duration, err := time.ParseDuration(os.Getenv("TRIAL_DEFAULT_DURATION")); err == nil {
+		config.DefaultDuration = duration
+	}
+	if duration, err := time.ParseDuration(os.Getenv("TRIAL_MAX_DURATION")); err == nil {
+		config.MaxDuration = duration
+	}
+	if gracePeriod, err := time.ParseDuration(os.Getenv("TRIAL_GRACE_PERIOD")); err == nil {
+		config.GracePeriod = gracePeriod
+	}
+	if action := os.Getenv("TRIAL_EXPIRY_ACTION"); action != "" {
+		config.ExpiryAction = action
+	}
+	if tier := os.Getenv("TRIAL_EXPIRED_TIER"); tier != "" {
+		config.ExpiredTier = tier
+	}
+	if interval, err := time.ParseDuration(os.Getenv("TRIAL_EXPIRY_INTERVAL")); err == nil {
+		config.Interval = interval
+	}
+	if batchSize, err := strconv.Atoi(os.Getenv("TRIAL_EXPIRY_BATCH_SIZE")); err == nil {
+		config.BatchSize = batchSize
+	}
+
+	return config
+}
+
+// loadAuditConfig reads audit log settings from the environment, keeping defaults for unset values
+func loadAuditConfig() service.AuditConfig {
+	config := service.DefaultAuditConfig()
+
+	if retention, err := time.ParseDuration(os.Getenv("AUDIT_RETENTION")); err == nil {
+		config.Retention = retention
+	}
+
+	return config
+}
+
+// loadOutboxConfig reads event outbox settings from the environment, keeping defaults for unset values
+func loadOutboxConfig() service.OutboxConfig {
+	config := service.DefaultOutboxConfig()
+
+	if interval, err := time.ParseDuration(os.Getenv("OUTBOX_RELAY_INTERVAL")); err == nil {
+		config.Interval = interval
+	}
+	if batchSize, err := strconv.Atoi(os.Getenv("OUTBOX_BATCH_SIZE")); err == nil {
+		config.BatchSize = batchSize
+	}
+	if lease, err := time.ParseDuration(os.Getenv("OUTBOX_LEASE_DURATION")); err == nil {
+		config.LeaseDuration = lease
+	}
+	if backoff, err := time.ParseDuration(os.Getenv("OUTBOX_MIN_BACKOFF")); err == nil {
+		config.MinBackoff = backoff
+	}
+	if backoff, err := time.ParseDuration(os.Getenv("OUTBOX_MAX_BACKOFF")); err == nil {
+		config.MaxBackoff = backoff
+	}
+	if retention, err := time.ParseDuration(os.Getenv("OUTBOX_RETENTION")); err == nil {
+		config.Retention = retention
+	}
+
+	return config
+}
+
+// newEventSink creates the sink tenant events are published to, as selected by OUTBOX_SINK.
+// The sink must be chosen explicitly since the log sink marks events published without delivering them.
+func newEventSink(log *logger.Logger) service.EventSink {
+	switch sink := os.Getenv("OUTBOX_SINK"); sink {
+	case "webhook":
+		url := os.Getenv("OUTBOX_WEBHOOK_URL")
+		if url == "" {
+			log.Fatal("OUTBOX_WEBHOOK_URL is required for the webhook event sink")
+		}
+		timeout, _ := time.ParseDuration(os.Getenv("OUTBOX_WEBHOOK_TIMEOUT"))
+		return service.NewWebhookEventSink(url, os.Getenv("OUTBOX_WEBHOOK_SECRET"), timeout)
+	case "log":
+		log.Warn("Tenant events are only logged and never delivered; set OUTBOX_SINK=webhook to deliver them")
+		return service.NewLogEventSink(log)
+	case "":
+		log.Fatal("OUTBOX_SINK is required: webhook, or log to only log events")
+		return nil
+	default:
+		log.Fatal("Unknown event sink", zap.String("sink", sink))
+		return nil
+	}
+}
+
+// loadInvitationConfig reads invitation settings from the environment, keeping defaults for unset values
+func loadInvitationConfig() service.InvitationConfig {
+	config := service.DefaultInvitationConfig()
+
+	config.SigningKey = []byte(os.Getenv("INVITATION_SIGNING_KEY"))
+	if ttl, err := time.ParseDuration(os.Getenv("INVITATION_TTL")); err == nil {
+		config.TTL = ttl
+	}
+	if interval, err := time.ParseDuration(os.Getenv("INVITATION_RESEND_INTERVAL")); err == nil {
+		config.ResendInterval = interval
+	}
+	if maxSends, err := strconv.Atoi(os.Getenv("INVITATION_MAX_SENDS")); err == nil {
+		config.MaxSends = maxSends
+	}
+
+	return config
+}
+
+func startGRPCServer(tenantService *service.TenantService, registryService *service.ServiceRegistry, domainService *service.DomainService, purgeService *service.PurgeService, roleService *service.RoleService, invitationService *service.InvitationService, entitlementService *service.EntitlementService, planService *service.PlanService, trialService *service.TrialService, settingsService *service.SettingsService, auditService *service.AuditService, log *logger.Logger, port string) {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
+	if err != nil {
+		log.Fatal("Failed to listen", zap.Error(err))
+	}
+
+	grpcSrv := grpcServer.NewServer(grpcServer.UnaryInterceptor(grpc.ActorInterceptor(loadInternalTokenKey())))
+	tenantGrpcServer := grpc.NewTenantServiceServer(tenantService, registryService, domainService, purgeService, roleService, invitationService, entitlementService, planService, trialService, settingsService, auditService, log)
+	pb.RegisterTenantServiceServer(grpcSrv, tenantGrpcServer)
+
+	// Register health check service
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcSrv, healthServer)
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+
+	log.Info("gRPC server listening", zap.String("port", port))
+	if err := grpcSrv.Serve(lis); err != nil {
+		log.Fatal("Failed to serve gRPC", zap.Error(err))
+	}
+}
+
+func startHTTPServer(tenantService *service.TenantService, domainService *service.DomainService, purgeService *service.PurgeService, roleService *service.RoleService, invitationService *service.InvitationService, entitlementService *service.EntitlementService, planService *service.PlanService, trialService *service.TrialService, settingsService *service.SettingsService, auditService *service.AuditService, log *logger.Logger, port string) {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(handler.ActorMiddleware(loadInternalTokenKey()))
+
+	// Initialize handlers
+	tenantHandler := handler.NewTenantHandler(tenantService, domainService, purgeService, roleService, invitationService, entitlementService, planService, trialService, settingsService, auditService, log)
+
+	// Health check endpoints
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
+	})
+	router.GET("/ready", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ready"})
+	})
+
+	// API routes
+	v1 := router.Group("/api/v1")
+	{
+		tenants := v1.Group("/tenants")
+		{
+			tenants.POST("", tenantHandler.CreateTenant)
+			tenants.GET("", tenantHandler.ListTenants)
+			tenants.GET("/:id", tenantHandler.GetTenant)
+			tenants.PUT("/:id", tenantHandler.UpdateTenant)
+			tenants.PATCH("/:id", tenantHandler.PatchTenant)
+			tenants.DELETE("/:id", tenantHandler.DeleteTenant)
+			tenants.GET("/:id/children", tenantHandler.ListChildTenants)
+			tenants.GET("/:id/ancestors", tenantHandler.GetTenantAncestors)
+			tenants.PUT("/:id/parent", tenantHandler.SetTenantParent)
+			tenants.POST("/:id/suspend", tenantHandler.SuspendTenant)
+			tenants.POST("/:id/reactivate", tenantHandler.ReactivateTenant)
+			tenants.POST("/:id/cancel-deletion", tenantHandler.CancelTenantDeletion)
+			tenants.POST("/:id/purge", tenantHandler.PurgeTenant)
+			tenants.GET("/:id/purge-report", tenantHandler.GetPurgeReport)
+			tenants.GET("/:id/users", tenantHandler.ListMembers)
+			tenants.POST("/:id/users", tenantHandler.AddUserToTenant)
+			tenants.PUT("/:id/users/:user_id/role", tenantHandler.UpdateMemberRole)
+			tenants.GET("/:id/users/:user_id/history", tenantHandler.GetMemberHistory)
+			tenants.DELETE("/:id/users/:user_id", tenantHandler.RemoveUserFromTenant)
+			tenants.GET("/:id/users/:user_id/permissions/:permission", tenantHandler.CheckPermission)
+			tenants.POST("/:id/transfer-ownership", tenantHandler.TransferOwnership)
+			tenants.GET("/:id/roles", tenantHandler.ListRoles)
+			tenants.POST("/:id/roles", tenantHandler.CreateRole)
+			tenants.PUT("/:id/roles/:role", tenantHandler.UpdateRole)
+			tenants.DELETE("/:id/roles/:role", tenantHandler.DeleteRole)
+			tenants.GET("/:id/invitations", tenantHandler.ListInvitations)
+			tenants.POST("/:id/invitations", tenantHandler.CreateInvitation)
+			tenants.POST("/:id/invitations/:invitation_id/resend", tenantHandler.ResendInvitation)
+			tenants.DELETE("/:id/invitations/:invitation_id", tenantHandler.RevokeInvitation)
+			tenants.GET("/:id/entitlements", tenantHandler.GetEntitlements)
+			tenants.PUT("/:id/entitlements", tenantHandler.UpdateEntitlementOverrides)
+			tenants.GET("/:id/plan", tenantHandler.GetPlan)
+			tenants.POST("/:id/plan", tenantHandler.ChangePlan)
+			tenants.DELETE("/:id/plan/scheduled", tenantHandler.CancelScheduledPlanChange)
+			tenants.POST("/:id/trial", tenantHandler.StartTrial)
+			tenants.GET("/:id/settings/effective", tenantHandler.GetEffectiveSettings)
+			tenants.GET("/:id/audit-events", tenantHandler.ListTenantAuditEvents)
+			tenants.GET("/:id/config", tenantHandler.GetTenantConfig)
+			tenants.GET("/:id/config/effective", tenantHandler.GetEffectiveTenantConfig)
+			tenants.PUT("/:id/config", tenantHandler.UpdateTenantConfig)
+			tenants.GET("/:id/default-service", tenantHandler.GetDefaultService)
+			tenants.GET("/:id/domains", tenantHandler.ListDomains)
+			tenants.POST("/:id/domains", tenantHandler.ClaimDomain)
+			tenants.POST("/:id/domains/:domain_id/verify", tenantHandler.VerifyDomain)
+			tenants.PUT("/:id/domains/:domain_id/primary", tenantHandler.SetPrimaryDomain)
+			tenants.DELETE("/:id/domains/:domain_id", tenantHandler.RemoveDomain)
+		}
+
+		users := v1.Group("/users")
+		{
+			users.GET("/:id/tenants", tenantHandler.ListUserTenants)
+		}
+
+		trials := v1.Group("/trials")
+		{
+			trials.GET("/expiring", tenantHandler.ListExpiringTrials)
+		}
+
+		invitations := v1.Group("/invitations")
+		{
+			invitations.POST("/accept", tenantHandler.AcceptInvitation)
+			invitations.POST("/decline", tenantHandler.DeclineInvitation)
+		}
+
+		settings := v1.Group("/settings")
+		{
+			settings.GET("/definitions", tenantHandler.ListSettingDefinitions)
+			settings.GET("/definitions/:key", tenantHandler.GetSettingDefinition)
+			settings.PUT("/definitions/:key", tenantHandler.RegisterSetting)
+			settings.DELETE("/definitions/:key", tenantHandler.DeleteSettingDefinition)
+		}
+
+		auditEvents := v1.Group("/audit-events")
+		{
+			auditEvents.GET("", tenantHandler.ListAuditEvents)
+		}
+	}
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%s", port),
+		Handler: router,
+	}
+
+	// Start server in goroutine
+	go func() {
+		log.Info("HTTP server listening", zap.String("port", port))
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Failed to start HTTP server", zap.Error(err))
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Info("Shutting down server...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatal("Server forced to shutdown", zap.Error(err))
+	}
+
+	log.Info("Server exited")
+}
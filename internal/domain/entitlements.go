@@ -0,0 +1,207 @@
+package domain
+
+// Unlimited marks a limit that is not enforced
+const Unlimited = -1
+
+// Rate limit classes consulted by the gateway and downstream services
+const (
+	RateLimitLow       = "low"
+	RateLimitStandard  = "standard"
+	RateLimitHigh      = "high"
+	RateLimitDedicated = "dedicated"
+)
+
+// Feature flags granted by subscription tiers
+const (
+	FeatureSSO             = "sso"
+	FeatureAuditLog        = "audit_log"
+	FeaturePrioritySupport = "priority_support"
+	FeatureSubTenants      = "sub_tenants"
+)
+
+// Entitlements are the limits and features available to a tenant
+type Entitlements struct {
+	MaxMembers            int             `bson:"maxMembers" json:"max_members"`                // Unlimited (-1) disables the limit
+	MaxServiceConfigs     int             `bson:"maxServiceConfigs" json:"max_service_configs"` // Unlimited (-1) disables the limit
+	LoadBalanceStrategies []string        `bson:"loadBalanceStrategies" json:"load_balance_strategies"`
+	CustomDomains         bool            `bson:"customDomains" json:"custom_domains"`
+	RateLimitClass        string          `bson:"rateLimitClass" json:"rate_limit_class"`
+	Features              map[string]bool `bson:"features,omitempty" json:"features,omitempty"`
+}
+
+// EntitlementOverrides replace the tier entitlements of a single tenant. Unset fields keep
+// the tier value; features are merged over the tier features.
+type EntitlementOverrides struct {
+	MaxMembers            *int            `bson:"maxMembers,omitempty" json:"max_members,omitempty"`
+	MaxServiceConfigs     *int            `bson:"maxServiceConfigs,omitempty" json:"max_service_configs,omitempty"`
+	LoadBalanceStrategies []string        `bson:"loadBalanceStrategies,omitempty" json:"load_balance_strategies,omitempty"`
+	CustomDomains         *bool           `bson:"customDomains,omitempty" json:"custom_domains,omitempty"`
+	RateLimitClass        string          `bson:"rateLimitClass,omitempty" json:"rate_limit_class,omitempty"`
+	Features              map[string]bool `bson:"features,omitempty" json:"features,omitempty"`
+}
+
+// EntitlementUsage is how much of its limits a tenant currently uses
+type EntitlementUsage struct {
+	Members        int64 `json:"members"`
+	ServiceConfigs int64 `json:"service_configs"`
+}
+
+// EntitlementsResponse represents the effective entitlements of a tenant
+type EntitlementsResponse struct {
+	TenantID     string                `json:"tenant_id"`
+	Tier         string                `json:"tier"`
+	Entitlements Entitlements          `json:"entitlements"`
+	Overrides    *EntitlementOverrides `json:"overrides,omitempty"`
+	Usage        EntitlementUsage      `json:"usage"`
+}
+
+// tierEntitlements is the entitlement catalog of each subscription tier
+var tierEntitlements = map[string]Entitlements{
+	SubscriptionFree: {
+		MaxMembers:            5,
+		MaxServiceConfigs:     2,
+		LoadBalanceStrategies: []string{LoadBalanceRoundRobin},
+		CustomDomains:         false,
+		RateLimitClass:        RateLimitLow,
+	},
+	SubscriptionBasic: {
+		MaxMembers:            25,
+		MaxServiceConfigs:     5,
+		LoadBalanceStrategies: []string{LoadBalanceRoundRobin, LoadBalanceRandom},
+		CustomDomains:         true,
+		RateLimitClass:        RateLimitStandard,
+	},
+	SubscriptionProfessional: {
+		MaxMembers:            100,
+		MaxServiceConfigs:     20,
+		LoadBalanceStrategies: []string{LoadBalanceRoundRobin, LoadBalanceRandom, LoadBalanceWeighted},
+		CustomDomains:         true,
+		RateLimitClass:        RateLimitHigh,
+		Features:              map[string]bool{FeatureSSO: true},
+	},
+	SubscriptionEnterprise: {
+		MaxMembers:            Unlimited,
+		MaxServiceConfigs:     Unlimited,
+		LoadBalanceStrategies: []string{LoadBalanceRoundRobin, LoadBalanceRandom, LoadBalanceWeighted, LoadBalanceLeastConn},
+		CustomDomains:         true,
+		RateLimitClass:        RateLimitDedicated,
+		Features: map[string]bool{
+			FeatureSSO:             true,
+			FeatureAuditLog:        true,
+			FeaturePrioritySupport: true,
+			FeatureSubTenants:      true,
+		},
+	},
+}
+
+// IsValidSubscriptionTier checks if the tier is in the entitlement catalog
+func IsValidSubscriptionTier(tier string) bool {
+	_, ok := tierEntitlements[tier]
+	return ok
+}
+
+// TierEntitlements returns a copy of the entitlements of a tier. Tenants without a
+// known tier get the free tier.
+func TierEntitlements(tier string) Entitlements {
+	entitlements, ok := tierEntitlements[tier]
+	if !ok {
+		entitlements = tierEntitlements[SubscriptionFree]
+	}
+
+	entitlements.LoadBalanceStrategies = append([]string(nil), entitlements.LoadBalanceStrategies...)
+	features := make(map[string]bool, len(entitlements.Features))
+	for name, enabled := range entitlements.Features {
+		features[name] = enabled
+	}
+	entitlements.Features = features
+	return entitlements
+}
+
+// ResolveEntitlements applies the overrides of a tenant to the entitlements of its tier
+func ResolveEntitlements(tier string, overrides *EntitlementOverrides) Entitlements {
+	entitlements := TierEntitlements(tier)
+	if overrides == nil {
+		return entitlements
+	}
+
+	if overrides.MaxMembers != nil {
+		entitlements.MaxMembers = *overrides.MaxMembers
+	}
+	if overrides.MaxServiceConfigs != nil {
+		entitlements.MaxServiceConfigs = *overrides.MaxServiceConfigs
+	}
+	if overrides.LoadBalanceStrategies != nil {
+		entitlements.LoadBalanceStrategies = append([]string(nil), overrides.LoadBalanceStrategies...)
+	}
+	if overrides.CustomDomains != nil {
+		entitlements.CustomDomains = *overrides.CustomDomains
+	}
+	if overrides.RateLimitClass != "" {
+		entitlements.RateLimitClass = overrides.RateLimitClass
+	}
+	for name, enabled := range overrides.Features {
+		entitlements.Features[name] = enabled
+	}
+	return entitlements
+}
+
+// Entitlements returns the effective entitlements of the tenant
+func (t *Tenant) Entitlements() Entitlements {
+	return ResolveEntitlements(t.SubscriptionTier, t.EntitlementOverrides)
+}
+
+// AllowsCount checks if a limit leaves room for one more item beyond the current count
+func AllowsCount(limit int, current int64) bool {
+	return limit == Unlimited || current < int64(limit)
+}
+
+// FitsCount checks if the current count is within a limit
+func FitsCount(limit int, current int64) bool {
+	return limit == Unlimited || current <= int64(limit)
+}
+
+// AllowsLoadBalanceStrategy checks if a load-balance strategy may be used. An empty
+// strategy means the default round-robin.
+func (e Entitlements) AllowsLoadBalanceStrategy(strategy string) bool {
+	if strategy == "" {
+		strategy = LoadBalanceRoundRobin
+	}
+	for _, allowed := range e.LoadBalanceStrategies {
+		if allowed == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// HasFeature checks if a feature flag is enabled
+func (e Entitlements) HasFeature(name string) bool {
+	return e.Features[name]
+}
+
+// Validate validates entitlement overrides
+func (o *EntitlementOverrides) Validate() error {
+	if o.MaxMembers != nil && *o.MaxMembers < Unlimited {
+		return ErrInvalidEntitlementLimit
+	}
+	if o.MaxServiceConfigs != nil && *o.MaxServiceConfigs < Unlimited {
+		return ErrInvalidEntitlementLimit
+	}
+	for _, strategy := range o.LoadBalanceStrategies {
+		if !IsValidLoadBalanceStrategy(strategy) {
+			return NewValidationError("invalid load balance strategy: " + strategy)
+		}
+	}
+	switch o.RateLimitClass {
+	case "", RateLimitLow, RateLimitStandard, RateLimitHigh, RateLimitDedicated:
+	default:
+		return ErrInvalidRateLimitClass
+	}
+	return nil
+}
+
+// Entitlement errors
+var (
+	ErrInvalidEntitlementLimit = NewValidationError("limits must be -1 (unlimited) or a non-negative number")
+	ErrInvalidRateLimitClass   = NewValidationError("rate limit class must be low, standard, high or dedicated")
+)
@@ -1,58 +1,86 @@
-package domain
-
-import (
-	"time"
-
-	"go.mongodb.org/mongo-driver/bson/primitive"
-)
-
-// Tenant represents an organization/tenant in the system
-type Tenant struct {
-	ID               primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
-	Name             string                 `bson:"name" json:"name"`
-	Domain           string                 `bson:"domain,omitempty" json:"domain,omitempty"`
-	SubscriptionTier string                 `bson:"subscriptionTier" json:"subscription_tier"`
-	IsActive         bool                   `bson:"isActive" json:"is_active"`
-	AuthSettings     AuthSettings           `bson:"authSettings" json:"auth_settings"`
-	DefaultService   string                 `bson:"defaultService" json:"default_service"`
-	Settings         map[string]interface{} `bson:"settings,omitempty" json:"settings,omitempty"`
-	CreatedAt        time.Time              `bson:"createdAt" json:"created_at"`
-	UpdatedAt        time.Time              `bson:"updatedAt" json:"updated_at"`
-}
-
-// AuthSettings defines authentication configuration for a tenant
-type AuthSettings struct {
-	AllowedLoginMethods []string `bson:"allowedLoginMethods" json:"allowed_login_methods"`
-}
-
-// User represents a global user account
-type User struct {
-	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Username       string             `bson:"username,omitempty" json:"username,omitempty"`
-	Email          string             `bson:"email,omitempty" json:"email,omitempty"`
-	Phone          string             `bson:"phone,omitempty" json:"phone,omitempty"`
-	DocumentNumber string             `bson:"documentNumber,omitempty" json:"document_number,omitempty"`
-	PasswordHash   string             `bson:"passwordHash" json:"-"`
-	IsActive       bool               `bson:"isActive" json:"is_active"`
-	CreatedAt      time.Time          `bson:"createdAt" json:"created_at"`
-	UpdatedAt      time.Time          `bson:"updatedAt" json:"updated_at"`
-}
-
-// TenantUser represents a user's relationship with a tenant
-type TenantUser struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	TenantID  string             `bson:"tenantId" json:"tenant_id"`
-	UserID    string             `bson:"userId" json:"user_id"`
-	Role      string             `bson:"role" json:"role"`
-	IsActive  bool               `bson:"isActive" json:"is_active"`
-	CreatedAt time.Time          `bson:"createdAt" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updatedAt" json:"updated_at"`
-}
-
-// Subscription tiers
-const (
-	SubscriptionFree         = "free"
-	SubscriptionBasic        = "basic"
-	SubscriptionProfessional = "professional"
-	SubscriptionEnterprise   = "enterprise"
-)
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tenant represents an organization/tenant in the system
+type Tenant struct {
+	ID                   primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	Name                 string                 `bson:"name" json:"name"`
+	Domain               string                 `bson:"domain,omitempty" json:"domain,omitempty"`       // Verified primary custom domain
+	Subdomain            string                 `bson:"subdomain,omitempty" json:"subdomain,omitempty"` // Label under the platform base domain, e.g. "acme"
+	ParentID             string                 `bson:"parentId,omitempty" json:"parent_id,omitempty"`  // Parent tenant of a sub-tenant
+	SubscriptionTier     string                 `bson:"subscriptionTier" json:"subscription_tier"`
+	IsActive             bool                   `bson:"isActive" json:"is_active"` // True only while Status is active
+	Status               string                 `bson:"status" json:"status"`
+	StatusReason         string                 `bson:"statusReason,omitempty" json:"status_reason,omitempty"`
+	StatusChangedAt      time.Time              `bson:"statusChangedAt,omitempty" json:"status_changed_at,omitempty"`
+	StatusHistory        []TenantStatusChange   `bson:"statusHistory,omitempty" json:"status_history,omitempty"`
+	PurgeAfter           *time.Time             `bson:"purgeAfter,omitempty" json:"purge_after,omitempty"`                     // When the data of a tenant pending deletion is purged
+	EntitlementOverrides *EntitlementOverrides  `bson:"entitlementOverrides,omitempty" json:"entitlement_overrides,omitempty"` // Per-tenant changes to the tier entitlements
+	ScheduledPlanChange  *PlanChange            `bson:"scheduledPlanChange,omitempty" json:"scheduled_plan_change,omitempty"`
+	PlanHistory          []PlanChange           `bson:"planHistory,omitempty" json:"plan_history,omitempty"`
+	Trial                *TenantTrial           `bson:"trial,omitempty" json:"trial,omitempty"`
+	AuthSettings         AuthSettings           `bson:"authSettings" json:"auth_settings"`
+	DefaultService       string                 `bson:"defaultService" json:"default_service"`
+	Config               TenantConfig           `bson:"config" json:"config"`
+	Settings             map[string]interface{} `bson:"settings,omitempty" json:"settings,omitempty"`
+	CreatedAt            time.Time              `bson:"createdAt" json:"created_at"`
+	UpdatedAt            time.Time              `bson:"updatedAt" json:"updated_at"`
+	Version              int64                  `bson:"version" json:"version"` // Incremented by every change, for optimistic concurrency
+}
+
+// AuthSettings defines authentication configuration for a tenant
+type AuthSettings struct {
+	AllowedLoginMethods []string `bson:"allowedLoginMethods" json:"allowed_login_methods"`
+}
+
+// User represents a global user account
+type User struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Username       string             `bson:"username,omitempty" json:"username,omitempty"`
+	Email          string             `bson:"email,omitempty" json:"email,omitempty"`
+	Phone          string             `bson:"phone,omitempty" json:"phone,omitempty"`
+	DocumentNumber string             `bson:"documentNumber,omitempty" json:"document_number,omitempty"`
+	PasswordHash   string             `bson:"passwordHash" json:"-"`
+	IsActive       bool               `bson:"isActive" json:"is_active"`
+	CreatedAt      time.Time          `bson:"createdAt" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updatedAt" json:"updated_at"`
+}
+
+// TenantUser represents a user's relationship with a tenant
+type TenantUser struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID  string             `bson:"tenantId" json:"tenant_id"`
+	UserID    string             `bson:"userId" json:"user_id"`
+	Role      string             `bson:"role" json:"role"`
+	IsActive  bool               `bson:"isActive" json:"is_active"`
+	History   []MembershipEvent  `bson:"history,omitempty" json:"history,omitempty"` // Joins, departures and role changes, oldest first
+	CreatedAt time.Time          `bson:"createdAt" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updated_at"`
+}
+
+// MemberFilter narrows a listing of tenant memberships
+type MemberFilter struct {
+	Role   string // Exact role name
+	Search string // User ID prefix
+}
+
+// UserTenant is a tenant a user belongs to, with the user's role in it
+type UserTenant struct {
+	TenantID string
+	Role     string
+	JoinedAt time.Time
+	Tenant   *Tenant // Nil if the tenant no longer exists
+}
+
+// Subscription tiers
+const (
+	SubscriptionFree         = "free"
+	SubscriptionBasic        = "basic"
+	SubscriptionProfessional = "professional"
+	SubscriptionEnterprise   = "enterprise"
+)
@@ -0,0 +1,35 @@
+package domain
+
+import "errors"
+
+// Page sizes accepted by paginated listings
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ErrInvalidPageToken is returned for page tokens that are malformed or were issued
+// for a listing with a different sort order
+var ErrInvalidPageToken = NewValidationError("invalid page token")
+
+// PageRequest selects a page of a listing. A page token from a previous response continues
+// the listing next to that page, which stays stable while items are added or removed.
+// Without a token the listing starts at the given page number.
+type PageRequest struct {
+	PageToken    string
+	Page         int  // Offset page used without a page token
+	PageSize     int  // Defaults to DefaultPageSize, at most MaxPageSize
+	IncludeTotal bool // Count every matching item, which is slow on large collections
+}
+
+// PageInfo locates a page within its listing
+type PageInfo struct {
+	NextPageToken string // Empty on the last page
+	PrevPageToken string // Empty on the first page
+	Total         *int64 // Set only when the total was requested
+}
+
+// IsInvalidPageToken checks if an error was caused by an invalid page token
+func IsInvalidPageToken(err error) bool {
+	return errors.Is(err, ErrInvalidPageToken)
+}
@@ -1,47 +1,158 @@
-package domain
-
-// CreateTenantRequest represents a tenant creation request
-type CreateTenantRequest struct {
-	Name             string `json:"name" binding:"required"`
-	Domain           string `json:"domain"`
-	SubscriptionTier string `json:"subscription_tier"`
-}
-
-// UpdateTenantRequest represents a tenant update request
-type UpdateTenantRequest struct {
-	Name             string `json:"name"`
-	Domain           string `json:"domain"`
-	SubscriptionTier string `json:"subscription_tier"`
-}
-
-// AddUserRequest represents adding a user to tenant
-type AddUserRequest struct {
-	UserID string `json:"user_id" binding:"required"`
-	Role   string `json:"role" binding:"required"`
-}
-
-// ListTenantsRequest represents a list tenants request
-type ListTenantsRequest struct {
-	Page     int `form:"page"`
-	PageSize int `form:"page_size"`
-}
-
-// TenantResponse represents a tenant in API responses
-type TenantResponse struct {
-	ID               string                 `json:"id"`
-	Name             string                 `json:"name"`
-	Domain           string                 `json:"domain,omitempty"`
-	SubscriptionTier string                 `json:"subscription_tier"`
-	IsActive         bool                   `json:"is_active"`
-	Settings         map[string]interface{} `json:"settings,omitempty"`
-	CreatedAt        string                 `json:"created_at"`
-	UpdatedAt        string                 `json:"updated_at"`
-}
-
-// ListTenantsResponse represents a paginated list of tenants
-type ListTenantsResponse struct {
-	Tenants  []TenantResponse `json:"tenants"`
-	Total    int64            `json:"total"`
-	Page     int              `json:"page"`
-	PageSize int              `json:"page_size"`
-}
+package domain
+
+// CreateTenantRequest represents a tenant creation request
+type CreateTenantRequest struct {
+	Name             string `json:"name" binding:"required"`
+	Domain           string `json:"domain"`
+	Subdomain        string `json:"subdomain"`
+	SubscriptionTier string `json:"subscription_tier"`
+	ParentID         string `json:"parent_id"` // Creates a sub-tenant that inherits the parent settings
+}
+
+// UpdateTenantRequest represents a tenant update request
+type UpdateTenantRequest struct {
+	Name             string `json:"name"`
+	Domain           string `json:"domain"`
+	Subdomain        string `json:"subdomain"`
+	SubscriptionTier string `json:"subscription_tier"`
+	ExpectedVersion  int64  `json:"expected_version"` // Rejects the update unless the tenant is at this version; 0 skips the check
+}
+
+// TenantModifiedMessage is the error of a change rejected because the tenant is no longer
+// at the version it was read or expected at
+const TenantModifiedMessage = "Tenant has been modified; reload it and retry"
+
+// AddUserRequest represents adding a user to tenant
+type AddUserRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	Role   string `json:"role"` // Built-in or custom role; defaults to member
+}
+
+// UpdateMemberRoleRequest represents changing the role of a tenant member
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// TransferOwnershipRequest represents handing ownership of a tenant from one member to another
+type TransferOwnershipRequest struct {
+	FromUserID string `json:"from_user_id" binding:"required"`
+	ToUserID   string `json:"to_user_id" binding:"required"`
+	FromRole   string `json:"from_role"` // Role given to the previous owner; defaults to admin
+}
+
+// ClaimDomainRequest represents a custom domain claim
+type ClaimDomainRequest struct {
+	Domain             string `json:"domain" binding:"required"`
+	VerificationMethod string `json:"verification_method"` // dns-txt (default) or http
+	IsPrimary          bool   `json:"is_primary"`
+}
+
+// ListTenantsRequest represents a list tenants request
+type ListTenantsRequest struct {
+	Page     int `form:"page"`
+	PageSize int `form:"page_size"`
+}
+
+// TenantResponse represents a tenant in API responses
+type TenantResponse struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name"`
+	Domain           string                 `json:"domain,omitempty"`
+	Subdomain        string                 `json:"subdomain,omitempty"`
+	ParentID         string                 `json:"parent_id,omitempty"`
+	SubscriptionTier string                 `json:"subscription_tier"`
+	IsActive         bool                   `json:"is_active"`
+	Status           string                 `json:"status"`
+	StatusReason     string                 `json:"status_reason,omitempty"`
+	StatusHistory    []TenantStatusChange   `json:"status_history,omitempty"`
+	PurgeAfter       string                 `json:"purge_after,omitempty"`
+	Trial            *TrialResponse         `json:"trial,omitempty"`
+	Config           TenantConfig           `json:"config"`
+	DefaultService   string                 `json:"default_service,omitempty"`
+	AuthSettings     AuthSettings           `json:"auth_settings"`
+	Settings         map[string]interface{} `json:"settings,omitempty"`
+	CreatedAt        string                 `json:"created_at"`
+	UpdatedAt        string                 `json:"updated_at"`
+	Version          int64                  `json:"version"`
+}
+
+// TenantDomainResponse represents a domain claim and how to verify it
+type TenantDomainResponse struct {
+	ID                 string `json:"id"`
+	Domain             string `json:"domain"`
+	Status             string `json:"status"`
+	VerificationMethod string `json:"verification_method"`
+	IsPrimary          bool   `json:"is_primary"`
+	TXTRecordName      string `json:"txt_record_name,omitempty"`
+	TXTRecordValue     string `json:"txt_record_value,omitempty"`
+	WellKnownURL       string `json:"well_known_url,omitempty"`
+	WellKnownContent   string `json:"well_known_content,omitempty"`
+	Attempts           int    `json:"attempts"`
+	LastError          string `json:"last_error,omitempty"`
+	VerifiedAt         string `json:"verified_at,omitempty"`
+	ExpiresAt          string `json:"expires_at,omitempty"`
+	CreatedAt          string `json:"created_at"`
+}
+
+// ListTenantsResponse represents a paginated list of tenants
+type ListTenantsResponse struct {
+	Tenants       []TenantResponse `json:"tenants"`
+	Total         *int64           `json:"total,omitempty"` // Set when include_total is requested
+	Page          int              `json:"page,omitempty"`
+	PageSize      int              `json:"page_size"`
+	NextPageToken string           `json:"next_page_token,omitempty"`
+	PrevPageToken string           `json:"prev_page_token,omitempty"`
+}
+
+// TenantMemberResponse represents a tenant member in API responses
+type TenantMemberResponse struct {
+	UserID    string `json:"user_id"`
+	Role      string `json:"role"`
+	JoinedAt  string `json:"joined_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// ListMembersResponse represents a paginated list of tenant members
+type ListMembersResponse struct {
+	Members       []TenantMemberResponse `json:"members"`
+	Total         *int64                 `json:"total,omitempty"` // Set when include_total is requested
+	Page          int                    `json:"page,omitempty"`
+	PageSize      int                    `json:"page_size"`
+	NextPageToken string                 `json:"next_page_token,omitempty"`
+	PrevPageToken string                 `json:"prev_page_token,omitempty"`
+}
+
+// UserTenantResponse represents a tenant a user belongs to
+type UserTenantResponse struct {
+	TenantID   string `json:"tenant_id"`
+	TenantName string `json:"tenant_name,omitempty"`
+	Subdomain  string `json:"subdomain,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Role       string `json:"role"`
+	JoinedAt   string `json:"joined_at"`
+}
+
+// ListUserTenantsResponse represents a paginated list of the tenants of a user
+type ListUserTenantsResponse struct {
+	Tenants       []UserTenantResponse `json:"tenants"`
+	Total         *int64               `json:"total,omitempty"` // Set when include_total is requested
+	Page          int                  `json:"page,omitempty"`
+	PageSize      int                  `json:"page_size"`
+	NextPageToken string               `json:"next_page_token,omitempty"`
+	PrevPageToken string               `json:"prev_page_token,omitempty"`
+}
+
+// PurgeReportResponse represents a purge report in API responses
+type PurgeReportResponse struct {
+	ID            string           `json:"id"`
+	TenantID      string           `json:"tenant_id"`
+	TenantName    string           `json:"tenant_name"`
+	Status        string           `json:"status"`
+	Reason        string           `json:"reason,omitempty"`
+	Actor         string           `json:"actor,omitempty"`
+	PurgeAfter    string           `json:"purge_after,omitempty"`
+	DeletedCounts map[string]int64 `json:"deleted_counts"`
+	Errors        []string         `json:"errors,omitempty"`
+	StartedAt     string           `json:"started_at"`
+	CompletedAt   string           `json:"completed_at"`
+}
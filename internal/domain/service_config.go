@@ -1,246 +1,405 @@
-package domain
-
-import (
-	"time"
-
-	"go.mongodb.org/mongo-driver/bson/primitive"
-)
-
-// ServiceConfig defines service endpoint configuration for a tenant
-type ServiceConfig struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	TenantID    string             `bson:"tenantId" json:"tenant_id"`
-	ServiceName string             `bson:"serviceName" json:"service_name"` // e.g., "user", "auth", "notification"
-
-	// Primary endpoint
-	PrimaryEndpoint ServiceEndpoint `bson:"primaryEndpoint" json:"primary_endpoint"`
-
-	// Fallback chain (tried in order if primary fails)
-	FallbackChain []ServiceEndpoint `bson:"fallbackChain,omitempty" json:"fallback_chain,omitempty"`
-
-	// Default service URL (used if no tenant-specific config)
-	DefaultServiceURL string `bson:"defaultServiceUrl,omitempty" json:"default_service_url,omitempty"`
-
-	// Health check configuration
-	HealthCheck HealthCheckConfig `bson:"healthCheck,omitempty" json:"health_check,omitempty"`
-
-	// Load balancing strategy
-	LoadBalanceStrategy string `bson:"loadBalanceStrategy,omitempty" json:"load_balance_strategy,omitempty"` // "round-robin", "random", "weighted"
-
-	// Metadata
-	IsActive  bool              `bson:"isActive" json:"is_active"`
-	Metadata  map[string]string `bson:"metadata,omitempty" json:"metadata,omitempty"`
-	CreatedAt time.Time         `bson:"createdAt" json:"created_at"`
-	UpdatedAt time.Time         `bson:"updatedAt" json:"updated_at"`
-}
-
-// ServiceEndpoint represents a single service endpoint
-type ServiceEndpoint struct {
-	URL      string            `bson:"url" json:"url"`                             // e.g., "http://user-service:8080"
-	Priority int               `bson:"priority" json:"priority"`                   // Lower = higher priority (0 = primary)
-	Weight   int               `bson:"weight,omitempty" json:"weight,omitempty"`   // For weighted load balancing
-	Timeout  int               `bson:"timeout,omitempty" json:"timeout,omitempty"` // Timeout in seconds
-	Headers  map[string]string `bson:"headers,omitempty" json:"headers,omitempty"` // Custom headers
-	IsActive bool              `bson:"isActive" json:"is_active"`
-}
-
-// HealthCheckConfig defines how to check service health
-type HealthCheckConfig struct {
-	Enabled       bool   `bson:"enabled" json:"enabled"`
-	Path          string `bson:"path,omitempty" json:"path,omitempty"`                    // e.g., "/health"
-	Method        string `bson:"method,omitempty" json:"method,omitempty"`                // "GET", "POST"
-	Interval      int    `bson:"interval,omitempty" json:"interval,omitempty"`            // Check interval in seconds
-	Timeout       int    `bson:"timeout,omitempty" json:"timeout,omitempty"`              // Health check timeout
-	FailThreshold int    `bson:"failThreshold,omitempty" json:"fail_threshold,omitempty"` // Failures before marking unhealthy
-}
-
-// DefaultServiceConfig holds system-wide default configurations
-type DefaultServiceConfig struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	ServiceName string             `bson:"serviceName" json:"service_name"`
-	ServiceURL  string             `bson:"serviceUrl" json:"service_url"`
-
-	// Default health check
-	HealthCheck HealthCheckConfig `bson:"healthCheck,omitempty" json:"health_check,omitempty"`
-
-	// Fallback behavior
-	FallbackToDefault bool `bson:"fallbackToDefault" json:"fallback_to_default"`
-
-	CreatedAt time.Time `bson:"createdAt" json:"created_at"`
-	UpdatedAt time.Time `bson:"updatedAt" json:"updated_at"`
-}
-
-// ServiceStatus represents real-time service health status
-type ServiceStatus struct {
-	ServiceName      string    `json:"service_name"`
-	EndpointURL      string    `json:"endpoint_url"`
-	IsHealthy        bool      `json:"is_healthy"`
-	LastCheckTime    time.Time `json:"last_check_time"`
-	ConsecutiveFails int       `json:"consecutive_fails"`
-	ResponseTime     int64     `json:"response_time_ms"`
-	ErrorMessage     string    `json:"error_message,omitempty"`
-}
-
-// ServiceRegistryEntry combines config and status
-type ServiceRegistryEntry struct {
-	Config ServiceConfig `json:"config"`
-	Status ServiceStatus `json:"status"`
-}
-
-// FallbackChainResult contains the result of fallback chain resolution
-type FallbackChainResult struct {
-	SelectedEndpoint ServiceEndpoint `json:"selected_endpoint"`
-	TriedEndpoints   []string        `json:"tried_endpoints"`
-	FallbackLevel    int             `json:"fallback_level"` // 0 = primary, 1+ = fallback
-	Success          bool            `json:"success"`
-	Error            string          `json:"error,omitempty"`
-}
-
-// ServiceDiscoveryRequest for dynamic service discovery
-type ServiceDiscoveryRequest struct {
-	TenantID    string `json:"tenant_id"`
-	ServiceName string `json:"service_name"`
-	Version     string `json:"version,omitempty"` // Optional version requirement
-}
-
-// ServiceDiscoveryResponse contains discovered service info
-type ServiceDiscoveryResponse struct {
-	ServiceName string            `json:"service_name"`
-	Endpoint    ServiceEndpoint   `json:"endpoint"`
-	Metadata    map[string]string `json:"metadata,omitempty"`
-}
-
-// Constants for load balancing strategies
-const (
-	LoadBalanceRoundRobin = "round-robin"
-	LoadBalanceRandom     = "random"
-	LoadBalanceWeighted   = "weighted"
-	LoadBalanceLeastConn  = "least-conn"
-)
-
-// Constants for service names
-const (
-	ServiceAuth         = "auth"
-	ServiceUser         = "user"
-	ServiceTenant       = "tenant"
-	ServiceNotification = "notification"
-	ServiceCMS          = "cms"
-	ServiceConfig       = "config"
-)
-
-// Default health check values
-const (
-	DefaultHealthCheckPath     = "/health"
-	DefaultHealthCheckMethod   = "GET"
-	DefaultHealthCheckInterval = 30 // seconds
-	DefaultHealthCheckTimeout  = 5  // seconds
-	DefaultFailThreshold       = 3  // consecutive failures
-)
-
-// Validate validates the ServiceConfig
-func (sc *ServiceConfig) Validate() error {
-	if sc.TenantID == "" {
-		return ErrTenantIDRequired
-	}
-	if sc.ServiceName == "" {
-		return ErrServiceNameRequired
-	}
-	if sc.PrimaryEndpoint.URL == "" {
-		return ErrPrimaryEndpointRequired
-	}
-	return nil
-}
-
-// GetActiveEndpoints returns all active endpoints (primary + fallbacks)
-func (sc *ServiceConfig) GetActiveEndpoints() []ServiceEndpoint {
-	endpoints := []ServiceEndpoint{}
-
-	if sc.PrimaryEndpoint.IsActive {
-		endpoints = append(endpoints, sc.PrimaryEndpoint)
-	}
-
-	for _, endpoint := range sc.FallbackChain {
-		if endpoint.IsActive {
-			endpoints = append(endpoints, endpoint)
-		}
-	}
-
-	return endpoints
-}
-
-// GetEndpointByPriority returns endpoints sorted by priority
-func (sc *ServiceConfig) GetEndpointByPriority() []ServiceEndpoint {
-	endpoints := sc.GetActiveEndpoints()
-
-	// Sort by priority (lower number = higher priority)
-	for i := 0; i < len(endpoints); i++ {
-		for j := i + 1; j < len(endpoints); j++ {
-			if endpoints[j].Priority < endpoints[i].Priority {
-				endpoints[i], endpoints[j] = endpoints[j], endpoints[i]
-			}
-		}
-	}
-
-	return endpoints
-}
-
-// IsHealthCheckEnabled checks if health checking is enabled
-func (sc *ServiceConfig) IsHealthCheckEnabled() bool {
-	return sc.HealthCheck.Enabled
-}
-
-// GetHealthCheckInterval returns health check interval with default
-func (sc *ServiceConfig) GetHealthCheckInterval() int {
-	if sc.HealthCheck.Interval <= 0 {
-		return DefaultHealthCheckInterval
-	}
-	return sc.HealthCheck.Interval
-}
-
-// Custom errors
-var (
-	ErrTenantIDRequired        = NewValidationError("tenant_id is required")
-	ErrServiceNameRequired     = NewValidationError("service_name is required")
-	ErrPrimaryEndpointRequired = NewValidationError("primary_endpoint is required")
-	ErrServiceNotFound         = NewNotFoundError("service configuration not found")
-	ErrNoHealthyEndpoint       = NewServiceError("no healthy endpoint available")
-)
-
-// ValidationError represents a validation error
-type ValidationError struct {
-	Message string
-}
-
-func (e *ValidationError) Error() string {
-	return e.Message
-}
-
-func NewValidationError(msg string) *ValidationError {
-	return &ValidationError{Message: msg}
-}
-
-// NotFoundError represents a not found error
-type NotFoundError struct {
-	Message string
-}
-
-func (e *NotFoundError) Error() string {
-	return e.Message
-}
-
-func NewNotFoundError(msg string) *NotFoundError {
-	return &NotFoundError{Message: msg}
-}
-
-// ServiceError represents a service error
-type ServiceError struct {
-	Message string
-}
-
-func (e *ServiceError) Error() string {
-	return e.Message
-}
-
-func NewServiceError(msg string) *ServiceError {
-	return &ServiceError{Message: msg}
-}
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ServiceConfig defines service endpoint configuration for a tenant
+type ServiceConfig struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID    string             `bson:"tenantId" json:"tenant_id"`
+	ServiceName string             `bson:"serviceName" json:"service_name"` // e.g., "user", "auth", "notification"
+
+	// Primary endpoint
+	PrimaryEndpoint ServiceEndpoint `bson:"primaryEndpoint" json:"primary_endpoint"`
+
+	// Fallback chain (tried in order if primary fails)
+	FallbackChain []ServiceEndpoint `bson:"fallbackChain,omitempty" json:"fallback_chain,omitempty"`
+
+	// Default service URL (used if no tenant-specific config)
+	DefaultServiceURL string `bson:"defaultServiceUrl,omitempty" json:"default_service_url,omitempty"`
+
+	// Health check configuration
+	HealthCheck HealthCheckConfig `bson:"healthCheck,omitempty" json:"health_check,omitempty"`
+
+	// Load balancing strategy
+	LoadBalanceStrategy string `bson:"loadBalanceStrategy,omitempty" json:"load_balance_strategy,omitempty"` // "round-robin", "random", "weighted"
+
+	// Circuit breaker configuration (passive failure detection)
+	CircuitBreaker CircuitBreakerConfig `bson:"circuitBreaker,omitempty" json:"circuit_breaker,omitempty"`
+
+	// Metadata
+	IsActive  bool              `bson:"isActive" json:"is_active"`
+	Metadata  map[string]string `bson:"metadata,omitempty" json:"metadata,omitempty"`
+	CreatedAt time.Time         `bson:"createdAt" json:"created_at"`
+	UpdatedAt time.Time         `bson:"updatedAt" json:"updated_at"`
+	Version   int64             `bson:"version,omitempty" json:"version"` // Incremented by every change, for optimistic concurrency
+}
+
+// ServiceEndpoint represents a single service endpoint
+type ServiceEndpoint struct {
+	URL      string            `bson:"url" json:"url"`                             // e.g., "http://user-service:8080"
+	Priority int               `bson:"priority" json:"priority"`                   // Lower = higher priority (0 = primary)
+	Weight   int               `bson:"weight,omitempty" json:"weight,omitempty"`   // For weighted load balancing
+	Timeout  int               `bson:"timeout,omitempty" json:"timeout,omitempty"` // Timeout in seconds
+	Headers  map[string]string `bson:"headers,omitempty" json:"headers,omitempty"` // Custom headers
+	IsActive bool              `bson:"isActive" json:"is_active"`
+}
+
+// HealthCheckConfig defines how to check service health
+type HealthCheckConfig struct {
+	Enabled       bool   `bson:"enabled" json:"enabled"`
+	Protocol      string `bson:"protocol,omitempty" json:"protocol,omitempty"`            // "http" (default) or "grpc"
+	Path          string `bson:"path,omitempty" json:"path,omitempty"`                    // e.g., "/health" (gRPC: health service name)
+	Method        string `bson:"method,omitempty" json:"method,omitempty"`                // "GET", "POST"
+	Interval      int    `bson:"interval,omitempty" json:"interval,omitempty"`            // Check interval in seconds
+	Timeout       int    `bson:"timeout,omitempty" json:"timeout,omitempty"`              // Health check timeout
+	FailThreshold int    `bson:"failThreshold,omitempty" json:"fail_threshold,omitempty"` // Failures before marking unhealthy
+}
+
+// CircuitBreakerConfig defines when an endpoint stops receiving traffic based on live request outcomes
+type CircuitBreakerConfig struct {
+	Enabled            bool    `bson:"enabled" json:"enabled"`
+	ErrorRateThreshold float64 `bson:"errorRateThreshold,omitempty" json:"error_rate_threshold,omitempty"` // Failure ratio (0-1] that opens the circuit
+	MinRequests        int     `bson:"minRequests,omitempty" json:"min_requests,omitempty"`                // Requests in a window before the error rate is evaluated
+	Window             int     `bson:"window,omitempty" json:"window,omitempty"`                           // Error-rate window in seconds
+	OpenDuration       int     `bson:"openDuration,omitempty" json:"open_duration,omitempty"`              // Seconds to stay open before allowing trial requests
+	HalfOpenRequests   int     `bson:"halfOpenRequests,omitempty" json:"half_open_requests,omitempty"`     // Trial requests allowed while half-open
+}
+
+// DefaultServiceConfig holds system-wide default configurations
+type DefaultServiceConfig struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ServiceName string             `bson:"serviceName" json:"service_name"`
+	DefaultURL  string             `bson:"defaultURL" json:"default_url"`
+
+	// Default health check
+	HealthCheck HealthCheckConfig `bson:"healthCheck,omitempty" json:"health_check,omitempty"`
+
+	// Fallback behavior
+	FallbackToDefault bool `bson:"fallbackToDefault" json:"fallback_to_default"`
+
+	CreatedAt time.Time `bson:"createdAt" json:"created_at"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updated_at"`
+}
+
+// ServiceStatus represents real-time service health status
+type ServiceStatus struct {
+	TenantID          string    `json:"tenant_id"`
+	ServiceName       string    `json:"service_name"`
+	EndpointURL       string    `json:"endpoint_url"`
+	IsHealthy         bool      `json:"is_healthy"`
+	LastChecked       time.Time `json:"last_checked"`
+	LastSuccessful    time.Time `json:"last_successful"`
+	LastFailure       time.Time `json:"last_failure"`
+	ConsecutiveFails  int       `json:"consecutive_fails"`
+	ResponseTime      int64     `json:"response_time_ms"`
+	LastError         string    `json:"last_error,omitempty"`
+	CircuitState      string    `json:"circuit_state,omitempty"`
+	ActiveConnections int       `json:"active_connections"`
+}
+
+// ServiceRegistryEntry combines config and status
+type ServiceRegistryEntry struct {
+	Config ServiceConfig `json:"config"`
+	Status ServiceStatus `json:"status"`
+}
+
+// FallbackChainResult contains the result of fallback chain resolution
+type FallbackChainResult struct {
+	TenantID      string           `json:"tenant_id"`
+	ServiceName   string           `json:"service_name"`
+	ResolvedURL   string           `json:"resolved_url"`
+	UsedEndpoint  *ServiceEndpoint `json:"used_endpoint,omitempty"`
+	AttemptedURLs []string         `json:"attempted_urls,omitempty"`
+	FallbackLevel int              `json:"fallback_level"` // 0 = primary, 1+ = fallback
+	IsDefault     bool             `json:"is_default"`
+	InheritedFrom string           `json:"inherited_from,omitempty"` // Parent tenant whose config was used
+	Success       bool             `json:"success"`
+	Error         string           `json:"error,omitempty"`
+	AttemptedAt   time.Time        `json:"attempted_at"`
+}
+
+// ConfigTenantID returns the tenant whose service config resolved the URL
+func (r *FallbackChainResult) ConfigTenantID() string {
+	if r.InheritedFrom != "" {
+		return r.InheritedFrom
+	}
+	return r.TenantID
+}
+
+// ServiceConfigChange identifies a changed service configuration. Empty TenantID and
+// ServiceName mean the change could not be attributed (e.g. a delete).
+type ServiceConfigChange struct {
+	TenantID    string `json:"tenant_id,omitempty"`
+	ServiceName string `json:"service_name,omitempty"`
+	IsDefault   bool   `json:"is_default"`
+}
+
+// ServiceDiscoveryRequest for dynamic service discovery
+type ServiceDiscoveryRequest struct {
+	TenantID    string `json:"tenant_id"`
+	ServiceName string `json:"service_name"`
+	Version     string `json:"version,omitempty"` // Optional version requirement
+}
+
+// ServiceDiscoveryResponse contains discovered service info
+type ServiceDiscoveryResponse struct {
+	ServiceName string            `json:"service_name"`
+	Endpoint    ServiceEndpoint   `json:"endpoint"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// Constants for load balancing strategies
+const (
+	LoadBalanceRoundRobin = "round-robin"
+	LoadBalanceRandom     = "random"
+	LoadBalanceWeighted   = "weighted"
+	LoadBalanceLeastConn  = "least-conn"
+)
+
+// IsValidLoadBalanceStrategy checks if the strategy is a known load-balance strategy
+func IsValidLoadBalanceStrategy(strategy string) bool {
+	switch strategy {
+	case LoadBalanceRoundRobin, LoadBalanceRandom, LoadBalanceWeighted, LoadBalanceLeastConn:
+		return true
+	}
+	return false
+}
+
+// Constants for service names
+const (
+	ServiceAuth          = "auth"
+	ServiceUser          = "user"
+	ServiceTenant        = "tenant"
+	ServiceNotification  = "notification"
+	ServiceCMS           = "cms"
+	ServiceConfigService = "config"
+)
+
+// Constants for health check protocols
+const (
+	HealthCheckProtocolHTTP = "http"
+	HealthCheckProtocolGRPC = "grpc"
+)
+
+// Constants for circuit breaker states
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+// Default circuit breaker values
+const (
+	DefaultCircuitErrorRate        = 0.5
+	DefaultCircuitMinRequests      = 20
+	DefaultCircuitWindow           = 60 // seconds
+	DefaultCircuitOpenDuration     = 30 // seconds
+	DefaultCircuitHalfOpenRequests = 1
+)
+
+// Default health check values
+const (
+	DefaultHealthCheckPath     = "/health"
+	DefaultHealthCheckMethod   = "GET"
+	DefaultHealthCheckInterval = 30 // seconds
+	DefaultHealthCheckTimeout  = 5  // seconds
+	DefaultFailThreshold       = 3  // consecutive failures
+)
+
+// Validate validates the ServiceConfig
+func (sc *ServiceConfig) Validate() error {
+	if sc.TenantID == "" {
+		return ErrTenantIDRequired
+	}
+	if sc.ServiceName == "" {
+		return ErrServiceNameRequired
+	}
+	if sc.PrimaryEndpoint.URL == "" {
+		return ErrPrimaryEndpointRequired
+	}
+	switch sc.HealthCheck.Protocol {
+	case "", HealthCheckProtocolHTTP, HealthCheckProtocolGRPC:
+	default:
+		return ErrInvalidHealthCheckProtocol
+	}
+	if sc.CircuitBreaker.ErrorRateThreshold < 0 || sc.CircuitBreaker.ErrorRateThreshold > 1 {
+		return ErrInvalidErrorRateThreshold
+	}
+	return nil
+}
+
+// GetActiveEndpoints returns all active endpoints (primary + fallbacks)
+func (sc *ServiceConfig) GetActiveEndpoints() []*ServiceEndpoint {
+	endpoints := []*ServiceEndpoint{}
+
+	if sc.PrimaryEndpoint.IsActive {
+		endpoints = append(endpoints, &sc.PrimaryEndpoint)
+	}
+
+	for i := range sc.FallbackChain {
+		if sc.FallbackChain[i].IsActive {
+			endpoints = append(endpoints, &sc.FallbackChain[i])
+		}
+	}
+
+	return endpoints
+}
+
+// GetEndpointByPriority returns endpoints sorted by priority
+func (sc *ServiceConfig) GetEndpointByPriority() []*ServiceEndpoint {
+	endpoints := sc.GetActiveEndpoints()
+
+	// Sort by priority (lower number = higher priority)
+	for i := 0; i < len(endpoints); i++ {
+		for j := i + 1; j < len(endpoints); j++ {
+			if endpoints[j].Priority < endpoints[i].Priority {
+				endpoints[i], endpoints[j] = endpoints[j], endpoints[i]
+			}
+		}
+	}
+
+	return endpoints
+}
+
+// IsHealthCheckEnabled checks if health checking is enabled
+func (sc *ServiceConfig) IsHealthCheckEnabled() bool {
+	return sc.HealthCheck.Enabled
+}
+
+// GetHealthCheckInterval returns health check interval with default
+func (sc *ServiceConfig) GetHealthCheckInterval() int {
+	if sc.HealthCheck.Interval <= 0 {
+		return DefaultHealthCheckInterval
+	}
+	return sc.HealthCheck.Interval
+}
+
+// GetCircuitBreakerConfig returns the circuit breaker configuration with defaults applied
+func (sc *ServiceConfig) GetCircuitBreakerConfig() CircuitBreakerConfig {
+	cb := sc.CircuitBreaker
+	if cb.ErrorRateThreshold <= 0 {
+		cb.ErrorRateThreshold = DefaultCircuitErrorRate
+	}
+	if cb.MinRequests <= 0 {
+		cb.MinRequests = DefaultCircuitMinRequests
+	}
+	if cb.Window <= 0 {
+		cb.Window = DefaultCircuitWindow
+	}
+	if cb.OpenDuration <= 0 {
+		cb.OpenDuration = DefaultCircuitOpenDuration
+	}
+	if cb.HalfOpenRequests <= 0 {
+		cb.HalfOpenRequests = DefaultCircuitHalfOpenRequests
+	}
+	return cb
+}
+
+// GetHealthCheckTimeout returns health check timeout with default
+func (sc *ServiceConfig) GetHealthCheckTimeout() int {
+	if sc.HealthCheck.Timeout <= 0 {
+		return DefaultHealthCheckTimeout
+	}
+	return sc.HealthCheck.Timeout
+}
+
+// GetFailThreshold returns the consecutive failures before an endpoint is unhealthy, with default
+func (sc *ServiceConfig) GetFailThreshold() int {
+	if sc.HealthCheck.FailThreshold <= 0 {
+		return DefaultFailThreshold
+	}
+	return sc.HealthCheck.FailThreshold
+}
+
+// GetHealthCheckPath returns health check path with default
+func (sc *ServiceConfig) GetHealthCheckPath() string {
+	if sc.HealthCheck.Path == "" && sc.GetHealthCheckProtocol() == HealthCheckProtocolHTTP {
+		return DefaultHealthCheckPath
+	}
+	return sc.HealthCheck.Path
+}
+
+// GetHealthCheckMethod returns health check HTTP method with default
+func (sc *ServiceConfig) GetHealthCheckMethod() string {
+	if sc.HealthCheck.Method == "" {
+		return DefaultHealthCheckMethod
+	}
+	return sc.HealthCheck.Method
+}
+
+// GetHealthCheckProtocol returns health check protocol with default
+func (sc *ServiceConfig) GetHealthCheckProtocol() string {
+	if sc.HealthCheck.Protocol == "" {
+		return HealthCheckProtocolHTTP
+	}
+	return sc.HealthCheck.Protocol
+}
+
+// Custom errors
+var (
+	ErrTenantIDRequired           = NewValidationError("tenant_id is required")
+	ErrServiceNameRequired        = NewValidationError("service_name is required")
+	ErrPrimaryEndpointRequired    = NewValidationError("primary_endpoint is required")
+	ErrInvalidErrorRateThreshold  = NewValidationError("circuit_breaker.error_rate_threshold must be between 0 and 1")
+	ErrInvalidHealthCheckProtocol = NewValidationError("health_check.protocol must be \"http\" or \"grpc\"")
+	ErrServiceNotFound            = NewNotFoundError("service configuration not found")
+	ErrNoHealthyEndpoint          = NewServiceError("no healthy endpoint available")
+	ErrServiceConfigModified      = NewConflictError("service configuration has been modified; reload it and retry")
+)
+
+// ValidationError represents a validation error
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func NewValidationError(msg string) *ValidationError {
+	return &ValidationError{Message: msg}
+}
+
+// NotFoundError represents a not found error
+type NotFoundError struct {
+	Message string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Message
+}
+
+func NewNotFoundError(msg string) *NotFoundError {
+	return &NotFoundError{Message: msg}
+}
+
+// ServiceError represents a service error
+type ServiceError struct {
+	Message string
+}
+
+func (e *ServiceError) Error() string {
+	return e.Message
+}
+
+func NewServiceError(msg string) *ServiceError {
+	return &ServiceError{Message: msg}
+}
+
+// ConflictError represents a change that conflicts with the current state, such as an
+// update made against an outdated version
+type ConflictError struct {
+	Message string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+func NewConflictError(msg string) *ConflictError {
+	return &ConflictError{Message: msg}
+}
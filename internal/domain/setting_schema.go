@@ -0,0 +1,323 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// settingSchemaTypes are the JSON Schema types a setting schema can use
+var settingSchemaTypes = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"object":  true,
+	"array":   true,
+	"number":  true,
+	"integer": true,
+	"string":  true,
+}
+
+// settingSchemaAnnotations are keywords that describe a value without constraining it
+var settingSchemaAnnotations = map[string]bool{
+	"title":       true,
+	"description": true,
+	"default":     true,
+	"examples":    true,
+	"$schema":     true,
+	"$id":         true,
+	"$comment":    true,
+}
+
+// CheckSettingSchema checks that a JSON Schema only uses the supported subset of keywords
+// (type, enum, const, numeric and length bounds, pattern, items, properties, required and
+// additionalProperties) with well-formed values. Unsupported keywords are rejected so that
+// no part of a schema is silently left unenforced.
+func CheckSettingSchema(schema map[string]interface{}) error {
+	return checkSettingSchema(NormalizeSettingValue(schema), "#")
+}
+
+func checkSettingSchema(value interface{}, at string) error {
+	schema, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("schema at %s must be an object", at)
+	}
+
+	for _, keyword := range sortedKeys(schema) {
+		value := schema[keyword]
+		switch keyword {
+		case "type":
+			types, ok := schemaTypes(value)
+			if !ok {
+				return fmt.Errorf("type at %s must be a type name or a list of type names", at)
+			}
+			for _, name := range types {
+				if !settingSchemaTypes[name] {
+					return fmt.Errorf("unknown type %q at %s", name, at)
+				}
+			}
+		case "enum":
+			if values, ok := value.([]interface{}); !ok || len(values) == 0 {
+				return fmt.Errorf("enum at %s must be a non-empty list", at)
+			}
+		case "const":
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+			if _, ok := value.(float64); !ok {
+				return fmt.Errorf("%s at %s must be a number", keyword, at)
+			}
+		case "minLength", "maxLength", "minItems", "maxItems", "minProperties", "maxProperties":
+			if n, ok := value.(float64); !ok || n < 0 || n != math.Trunc(n) {
+				return fmt.Errorf("%s at %s must be a non-negative integer", keyword, at)
+			}
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("pattern at %s must be a string", at)
+			}
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("pattern at %s is not a valid regular expression", at)
+			}
+		case "uniqueItems":
+			if _, ok := value.(bool); !ok {
+				return fmt.Errorf("uniqueItems at %s must be a boolean", at)
+			}
+		case "items":
+			if err := checkSettingSchema(value, at+"/items"); err != nil {
+				return err
+			}
+		case "properties":
+			properties, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("properties at %s must be an object", at)
+			}
+			for _, name := range sortedKeys(properties) {
+				if err := checkSettingSchema(properties[name], at+"/properties/"+name); err != nil {
+					return err
+				}
+			}
+		case "required":
+			names, ok := value.([]interface{})
+			if !ok {
+				return fmt.Errorf("required at %s must be a list of property names", at)
+			}
+			for _, name := range names {
+				if _, ok := name.(string); !ok {
+					return fmt.Errorf("required at %s must be a list of property names", at)
+				}
+			}
+		case "additionalProperties":
+			if _, ok := value.(bool); ok {
+				continue
+			}
+			if err := checkSettingSchema(value, at+"/additionalProperties"); err != nil {
+				return err
+			}
+		default:
+			if !settingSchemaAnnotations[keyword] {
+				return fmt.Errorf("unsupported schema keyword %q at %s", keyword, at)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateSettingValue checks a value against a schema accepted by CheckSettingSchema
+func ValidateSettingValue(schema map[string]interface{}, value interface{}) error {
+	normalized, ok := NormalizeSettingValue(schema).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return validateSettingValue(normalized, NormalizeSettingValue(value), "")
+}
+
+func validateSettingValue(schema map[string]interface{}, value interface{}, at string) error {
+	where := func() string {
+		if at == "" {
+			return "value"
+		}
+		return "value at " + at
+	}
+
+	if types, ok := schemaTypes(schema["type"]); ok {
+		matched := false
+		for _, name := range types {
+			if hasSchemaType(value, name) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s must be of type %s", where(), strings.Join(types, " or "))
+		}
+	}
+	if values, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range values {
+			if reflect.DeepEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s must be one of the allowed values", where())
+		}
+	}
+	if constant, ok := schema["const"]; ok && !reflect.DeepEqual(constant, value) {
+		return fmt.Errorf("%s must equal the constant value", where())
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && v < min {
+			return fmt.Errorf("%s must be at least %v", where(), min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && v > max {
+			return fmt.Errorf("%s must be at most %v", where(), max)
+		}
+		if min, ok := schema["exclusiveMinimum"].(float64); ok && v <= min {
+			return fmt.Errorf("%s must be greater than %v", where(), min)
+		}
+		if max, ok := schema["exclusiveMaximum"].(float64); ok && v >= max {
+			return fmt.Errorf("%s must be less than %v", where(), max)
+		}
+
+	case string:
+		length := float64(len([]rune(v)))
+		if min, ok := schema["minLength"].(float64); ok && length < min {
+			return fmt.Errorf("%s must be at least %v characters", where(), min)
+		}
+		if max, ok := schema["maxLength"].(float64); ok && length > max {
+			return fmt.Errorf("%s must be at most %v characters", where(), max)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if matched, _ := regexp.MatchString(pattern, v); !matched {
+				return fmt.Errorf("%s must match the pattern %s", where(), pattern)
+			}
+		}
+
+	case []interface{}:
+		count := float64(len(v))
+		if min, ok := schema["minItems"].(float64); ok && count < min {
+			return fmt.Errorf("%s must have at least %v items", where(), min)
+		}
+		if max, ok := schema["maxItems"].(float64); ok && count > max {
+			return fmt.Errorf("%s must have at most %v items", where(), max)
+		}
+		if unique, _ := schema["uniqueItems"].(bool); unique {
+			for i := range v {
+				for j := i + 1; j < len(v); j++ {
+					if reflect.DeepEqual(v[i], v[j]) {
+						return fmt.Errorf("%s must not contain duplicate items", where())
+					}
+				}
+			}
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := validateSettingValue(items, item, fmt.Sprintf("%s/%d", at, i)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case map[string]interface{}:
+		count := float64(len(v))
+		if min, ok := schema["minProperties"].(float64); ok && count < min {
+			return fmt.Errorf("%s must have at least %v properties", where(), min)
+		}
+		if max, ok := schema["maxProperties"].(float64); ok && count > max {
+			return fmt.Errorf("%s must have at most %v properties", where(), max)
+		}
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, present := v[name.(string)]; !present {
+					return fmt.Errorf("%s is missing the required property %s", where(), name)
+				}
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		for _, name := range sortedKeys(v) {
+			if property, ok := properties[name].(map[string]interface{}); ok {
+				if err := validateSettingValue(property, v[name], at+"/"+name); err != nil {
+					return err
+				}
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					return fmt.Errorf("%s has the unknown property %s", where(), name)
+				}
+			case map[string]interface{}:
+				if err := validateSettingValue(additional, v[name], at+"/"+name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// schemaTypes reads the type keyword, which is a type name or a list of them
+func schemaTypes(value interface{}) ([]string, bool) {
+	switch t := value.(type) {
+	case string:
+		return []string{t}, true
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, name := range t {
+			s, ok := name.(string)
+			if !ok {
+				return nil, false
+			}
+			types = append(types, s)
+		}
+		return types, len(types) > 0
+	default:
+		return nil, false
+	}
+}
+
+// hasSchemaType checks if a normalized value is of a JSON Schema type
+func hasSchemaType(value interface{}, name string) bool {
+	switch name {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "string":
+		_, ok := value.(string)
+		return ok
+	}
+	return false
+}
+
+// NormalizeSettingValue converts a value to the types encoding/json decodes into, so values
+// decoded from BSON or protobuf compare and validate like values read from JSON
+func NormalizeSettingValue(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// parseSchema decodes a JSON schema used by a test case
+func parseSchema(t *testing.T, document string) map[string]interface{} {
+	t.Helper()
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(document), &schema); err != nil {
+		t.Fatalf("invalid test schema %s: %v", document, err)
+	}
+	return schema
+}
+
+func TestCheckSettingSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{name: "type list", schema: `{"type": ["string", "null"], "title": "Locale"}`},
+		{name: "nested", schema: `{"type": "object", "properties": {"color": {"type": "string", "pattern": "^#"}}, "required": ["color"], "additionalProperties": false}`},
+		{name: "unknown type", schema: `{"type": "date"}`, wantErr: `unknown type "date" at #`},
+		{name: "empty enum", schema: `{"enum": []}`, wantErr: "enum at # must be a non-empty list"},
+		{name: "negative length", schema: `{"maxLength": -1}`, wantErr: "maxLength at # must be a non-negative integer"},
+		{name: "fractional length", schema: `{"minItems": 1.5}`, wantErr: "minItems at # must be a non-negative integer"},
+		{name: "invalid pattern", schema: `{"pattern": "("}`, wantErr: "pattern at # is not a valid regular expression"},
+		{name: "unsupported keyword", schema: `{"oneOf": []}`, wantErr: `unsupported schema keyword "oneOf" at #`},
+		{name: "nested error", schema: `{"properties": {"size": {"minimum": "1"}}}`, wantErr: "minimum at #/properties/size must be a number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSettingSchema(parseSchema(t, tt.schema))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CheckSettingSchema() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("CheckSettingSchema() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSettingValue(t *testing.T) {
+	const branding = `{
+		"type": "object",
+		"properties": {
+			"color": {"type": "string", "pattern": "^#[0-9a-f]{6}$"},
+			"size": {"type": "integer", "minimum": 1, "maximum": 10}
+		},
+		"required": ["color"],
+		"additionalProperties": false
+	}`
+
+	tests := []struct {
+		name    string
+		schema  string
+		value   interface{}
+		wantErr string
+	}{
+		{name: "object", schema: branding, value: map[string]interface{}{"color": "#ff0000", "size": 3}},
+		{name: "go integer", schema: `{"type": "integer"}`, value: int32(7)},
+		{name: "enum", schema: `{"enum": ["vi", "en"]}`, value: "vi"},
+		{name: "wrong type", schema: `{"type": "string"}`, value: 1, wantErr: "value must be of type string"},
+		{name: "fraction for integer", schema: `{"type": "integer"}`, value: 1.5, wantErr: "value must be of type integer"},
+		{name: "not in enum", schema: `{"enum": ["vi", "en"]}`, value: "fr", wantErr: "value must be one of the allowed values"},
+		{name: "too long", schema: `{"maxLength": 2}`, value: "abc", wantErr: "value must be at most 2 characters"},
+		{name: "duplicate items", schema: `{"uniqueItems": true}`, value: []interface{}{"a", "a"}, wantErr: "value must not contain duplicate items"},
+		{name: "item type", schema: `{"items": {"type": "number"}}`, value: []interface{}{1, "x"}, wantErr: "value at /1 must be of type number"},
+		{name: "missing property", schema: branding, value: map[string]interface{}{"size": 3}, wantErr: "value is missing the required property color"},
+		{name: "property pattern", schema: branding, value: map[string]interface{}{"color": "red"}, wantErr: "value at /color must match the pattern"},
+		{name: "property bound", schema: branding, value: map[string]interface{}{"color": "#ff0000", "size": 11}, wantErr: "value at /size must be at most 10"},
+		{name: "unknown property", schema: branding, value: map[string]interface{}{"color": "#ff0000", "logo": "a.png"}, wantErr: "value has the unknown property logo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSettingValue(parseSchema(t, tt.schema), tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateSettingValue() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateSettingValue() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
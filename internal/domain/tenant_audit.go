@@ -0,0 +1,311 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kinds of actors that change tenants
+const (
+	ActorTypeUser      = "user"      // End user identified by the gateway
+	ActorTypeService   = "service"   // Platform service calling with its own identity
+	ActorTypeSystem    = "system"    // The tenant service itself, e.g. background jobs
+	ActorTypeAnonymous = "anonymous" // Request that carried no identity
+)
+
+// Actor identifies who made a change
+type Actor struct {
+	ID   string `bson:"id" json:"id"`
+	Type string `bson:"type" json:"type"`
+}
+
+type actorContextKey struct{}
+
+// ContextWithActor returns a context carrying the actor of a request
+func ContextWithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor of a request, or the system actor for work the
+// service started itself
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorContextKey{}).(Actor); ok && actor.ID != "" {
+		return actor
+	}
+	return Actor{ID: SystemActor, Type: ActorTypeSystem}
+}
+
+// anonymousActorID identifies requests that carried no identity
+const anonymousActorID = "anonymous"
+
+// RequestActor identifies the caller of a request from the user or service identity it
+// carries; the user wins when both are present
+func RequestActor(userID, serviceName string) Actor {
+	switch {
+	case userID != "":
+		return Actor{ID: userID, Type: ActorTypeUser}
+	case serviceName != "":
+		return Actor{ID: serviceName, Type: ActorTypeService}
+	default:
+		return Actor{ID: anonymousActorID, Type: ActorTypeAnonymous}
+	}
+}
+
+// Audited actions
+const (
+	AuditActionTenantCreated           = "tenant.created"
+	AuditActionTenantUpdated           = "tenant.updated"
+	AuditActionTenantConfigUpdated     = "tenant.config_updated"
+	AuditActionTenantStatusChanged     = "tenant.status_changed"
+	AuditActionTenantParentChanged     = "tenant.parent_changed"
+	AuditActionTenantDomainChanged     = "tenant.domain_changed" // Primary custom domain set or removed
+	AuditActionTenantPlanChanged       = "tenant.plan_changed"
+	AuditActionTenantPlanScheduled     = "tenant.plan_change_scheduled"
+	AuditActionTenantPlanCancelled     = "tenant.plan_change_cancelled" // Cancelled, or dropped because the tenant no longer fit
+	AuditActionTenantTrialStarted      = "tenant.trial_started"
+	AuditActionTenantTrialEnded        = "tenant.trial_ended"
+	AuditActionTenantEntitlementsSet   = "tenant.entitlements_updated"
+	AuditActionMemberAdded             = "member.added"
+	AuditActionMemberRemoved           = "member.removed"
+	AuditActionMemberRoleChanged       = "member.role_changed"
+	AuditActionRoleCreated             = "role.created"
+	AuditActionRoleUpdated             = "role.updated"
+	AuditActionRoleDeleted             = "role.deleted"
+	AuditActionDomainClaimed           = "domain.claimed"
+	AuditActionDomainStatusChanged     = "domain.status_changed" // Verified, failed or expired
+	AuditActionDomainRemoved           = "domain.removed"
+	AuditActionServiceConfigCreated    = "service_config.created"
+	AuditActionServiceConfigUpdated    = "service_config.updated"
+	AuditActionServiceConfigDeleted    = "service_config.deleted"
+	AuditActionDefaultServiceConfigSet = "default_service_config.set"
+)
+
+// auditActions lists the audited actions, for validating filters
+var auditActions = map[string]bool{
+	AuditActionTenantCreated:           true,
+	AuditActionTenantUpdated:           true,
+	AuditActionTenantConfigUpdated:     true,
+	AuditActionTenantStatusChanged:     true,
+	AuditActionTenantParentChanged:     true,
+	AuditActionTenantDomainChanged:     true,
+	AuditActionTenantPlanChanged:       true,
+	AuditActionTenantPlanScheduled:     true,
+	AuditActionTenantPlanCancelled:     true,
+	AuditActionTenantTrialStarted:      true,
+	AuditActionTenantTrialEnded:        true,
+	AuditActionTenantEntitlementsSet:   true,
+	AuditActionMemberAdded:             true,
+	AuditActionMemberRemoved:           true,
+	AuditActionMemberRoleChanged:       true,
+	AuditActionRoleCreated:             true,
+	AuditActionRoleUpdated:             true,
+	AuditActionRoleDeleted:             true,
+	AuditActionDomainClaimed:           true,
+	AuditActionDomainStatusChanged:     true,
+	AuditActionDomainRemoved:           true,
+	AuditActionServiceConfigCreated:    true,
+	AuditActionServiceConfigUpdated:    true,
+	AuditActionServiceConfigDeleted:    true,
+	AuditActionDefaultServiceConfigSet: true,
+}
+
+// Types of audited targets
+const (
+	AuditTargetTenant               = "tenant"
+	AuditTargetMember               = "member"         // Identified by the user ID
+	AuditTargetRole                 = "role"           // Identified by the role name
+	AuditTargetDomain               = "domain"         // Identified by the domain name
+	AuditTargetServiceConfig        = "service_config" // Identified by the service name
+	AuditTargetDefaultServiceConfig = "default_service_config"
+)
+
+// auditIgnoredFields are bookkeeping fields left out of audit snapshots; they change with
+// every write or are histories that already record their own changes
+var auditIgnoredFields = map[string]bool{
+	"id":                true,
+	"created_at":        true,
+	"updated_at":        true,
+	"version":           true,
+	"status_changed_at": true,
+	"status_history":    true,
+	"plan_history":      true,
+	"history":           true,
+}
+
+// auditRedactedValue replaces values that may hold credentials, such as endpoint headers
+const auditRedactedValue = "[redacted]"
+
+// AuditEvent is an entry of the append-only audit log
+type AuditEvent struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID   string             `bson:"tenantId,omitempty" json:"tenant_id,omitempty"` // Empty for platform-wide targets
+	Actor      Actor              `bson:"actor" json:"actor"`
+	Action     string             `bson:"action" json:"action"`
+	TargetType string             `bson:"targetType" json:"target_type"`
+	TargetID   string             `bson:"targetId" json:"target_id"`
+	Reason     string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	Changes    []AuditChange      `bson:"changes,omitempty" json:"changes,omitempty"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"created_at"`
+}
+
+// ChangedFields lists the fields an audit event changed
+func (e *AuditEvent) ChangedFields() []string {
+	fields := make([]string, len(e.Changes))
+	for i, change := range e.Changes {
+		fields[i] = change.Field
+	}
+	return fields
+}
+
+// AuditChange is the value of a field before and after an audited change. Fields are
+// dotted JSON field names; a missing before or after value means the field was unset.
+type AuditChange struct {
+	Field  string      `bson:"field" json:"field"`
+	Before interface{} `bson:"before,omitempty" json:"before,omitempty"`
+	After  interface{} `bson:"after,omitempty" json:"after,omitempty"`
+}
+
+// AuditSnapshot captures the audited state of a target. Snapshots are taken before a
+// change mutates the target so they can be compared with the state after it.
+type AuditSnapshot map[string]interface{}
+
+// NewAuditSnapshot captures the JSON fields of a target, or nil for a missing target
+func NewAuditSnapshot(target interface{}) AuditSnapshot {
+	if target == nil || (reflect.ValueOf(target).Kind() == reflect.Ptr && reflect.ValueOf(target).IsNil()) {
+		return nil
+	}
+
+	data, err := json.Marshal(target)
+	if err != nil {
+		return nil
+	}
+	var snapshot AuditSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil
+	}
+	for field := range snapshot {
+		if auditIgnoredFields[field] {
+			delete(snapshot, field)
+		}
+	}
+	return snapshot
+}
+
+// NewAuditEvent builds the event of a change from the snapshots of its target. The before
+// snapshot is nil for created targets and the after snapshot nil for deleted ones.
+func NewAuditEvent(action, targetType, targetID, tenantID string, before, after AuditSnapshot) *AuditEvent {
+	return &AuditEvent{
+		TenantID:   tenantID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Changes:    DiffAuditSnapshots(before, after),
+	}
+}
+
+// DiffAuditSnapshots lists the fields that differ between two snapshots, ordered by field.
+// Objects are compared field by field and lists as a whole.
+func DiffAuditSnapshots(before, after AuditSnapshot) []AuditChange {
+	var changes []AuditChange
+	diffAuditValues("", map[string]interface{}(before), map[string]interface{}(after), &changes)
+	return changes
+}
+
+func diffAuditValues(field string, before, after interface{}, changes *[]AuditChange) {
+	beforeObject, beforeIsObject := before.(map[string]interface{})
+	afterObject, afterIsObject := after.(map[string]interface{})
+	if (beforeIsObject || before == nil) && (afterIsObject || after == nil) && (beforeIsObject || afterIsObject) {
+		names := make(map[string]interface{}, len(beforeObject)+len(afterObject))
+		for name := range beforeObject {
+			names[name] = nil
+		}
+		for name := range afterObject {
+			names[name] = nil
+		}
+		for _, name := range sortedKeys(names) {
+			child := name
+			if field != "" {
+				child = field + "." + name
+			}
+			diffAuditValues(child, beforeObject[name], afterObject[name], changes)
+		}
+		return
+	}
+
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+	*changes = append(*changes, AuditChange{
+		Field:  field,
+		Before: redactAuditValue(field, before),
+		After:  redactAuditValue(field, after),
+	})
+}
+
+// redactAuditValue hides endpoint headers, which commonly carry credentials
+func redactAuditValue(field string, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	for _, part := range strings.Split(field, ".") {
+		if part == "headers" {
+			return auditRedactedValue
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for name, item := range v {
+			redacted[name] = redactAuditValue(name, item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redactAuditValue("", item)
+		}
+		return redacted
+	}
+	return value
+}
+
+// AuditEventFilter narrows a listing of audit events. Empty fields do not filter.
+type AuditEventFilter struct {
+	TenantID      string
+	ActorID       string
+	Action        string     // Exact action
+	TargetType    string     // Exact target type
+	TargetID      string     // Requires TargetType
+	CreatedAfter  *time.Time // Recorded at or after
+	CreatedBefore *time.Time // Recorded before
+}
+
+// Validate checks the filter values
+func (f *AuditEventFilter) Validate() error {
+	if f.Action != "" && !auditActions[f.Action] {
+		return NewValidationError("unknown audit action: " + f.Action)
+	}
+	if f.TargetID != "" && f.TargetType == "" {
+		return NewValidationError("target_type is required to filter by target_id")
+	}
+	if f.CreatedAfter != nil && f.CreatedBefore != nil && !f.CreatedAfter.Before(*f.CreatedBefore) {
+		return NewValidationError("created_after must be before created_before")
+	}
+	return nil
+}
+
+// ListAuditEventsResponse represents a paginated list of audit events, newest first
+type ListAuditEventsResponse struct {
+	Events        []*AuditEvent `json:"events"`
+	Total         *int64        `json:"total,omitempty"` // Set when include_total is requested
+	Page          int           `json:"page,omitempty"`
+	PageSize      int           `json:"page_size"`
+	NextPageToken string        `json:"next_page_token,omitempty"`
+	PrevPageToken string        `json:"prev_page_token,omitempty"`
+}
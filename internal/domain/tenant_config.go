@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"net/url"
+)
+
+// TenantConfig defines routing and login configuration for a tenant
+type TenantConfig struct {
+	DefaultServiceURL string            `bson:"defaultServiceUrl,omitempty" json:"default_service_url,omitempty"`
+	ServiceMappings   map[string]string `bson:"serviceMappings,omitempty" json:"service_mappings,omitempty"` // e.g., {"user": "user-service:50052"}
+	FallbackChain     []string          `bson:"fallbackChain,omitempty" json:"fallback_chain,omitempty"`     // e.g., ["tenant-dashboard", "cms", "auth-login"]
+
+	// Login behaviour
+	AllowedLoginIdentifiers []string `bson:"allowedLoginIdentifiers,omitempty" json:"allowed_login_identifiers,omitempty"` // "email", "phone", "username", "document_number"
+	Require2FA              bool     `bson:"require2fa" json:"require_2fa"`
+	AllowRegistration       bool     `bson:"allowRegistration" json:"allow_registration"`
+
+	// Branding
+	CustomLogoURL       string            `bson:"customLogoUrl,omitempty" json:"custom_logo_url,omitempty"`
+	CustomBackgroundURL string            `bson:"customBackgroundUrl,omitempty" json:"custom_background_url,omitempty"`
+	CustomSettings      map[string]string `bson:"customSettings,omitempty" json:"custom_settings,omitempty"`
+}
+
+// DefaultServiceInfo describes where a tenant's traffic goes when no explicit route matches
+type DefaultServiceInfo struct {
+	DefaultServiceURL string   `json:"default_service_url"`
+	FallbackURLs      []string `json:"fallback_urls"`
+}
+
+// Constants for login identifiers
+const (
+	LoginIdentifierEmail          = "email"
+	LoginIdentifierPhone          = "phone"
+	LoginIdentifierUsername       = "username"
+	LoginIdentifierDocumentNumber = "document_number"
+)
+
+// DefaultTenantConfig returns the configuration assigned to newly created tenants
+func DefaultTenantConfig() TenantConfig {
+	return TenantConfig{
+		AllowedLoginIdentifiers: []string{LoginIdentifierEmail},
+		AllowRegistration:       true,
+	}
+}
+
+// Validate validates the TenantConfig
+func (tc *TenantConfig) Validate() error {
+	if tc.DefaultServiceURL != "" && !isValidURL(tc.DefaultServiceURL) {
+		return ErrInvalidDefaultServiceURL
+	}
+
+	for name, target := range tc.ServiceMappings {
+		if name == "" || target == "" {
+			return ErrInvalidServiceMapping
+		}
+	}
+
+	seen := make(map[string]bool, len(tc.FallbackChain))
+	for _, service := range tc.FallbackChain {
+		if service == "" || seen[service] {
+			return ErrInvalidFallbackChain
+		}
+		seen[service] = true
+	}
+
+	if len(tc.AllowedLoginIdentifiers) == 0 {
+		return ErrLoginIdentifierRequired
+	}
+	for _, identifier := range tc.AllowedLoginIdentifiers {
+		if !IsValidLoginIdentifier(identifier) {
+			return NewValidationError("unsupported login identifier: " + identifier)
+		}
+	}
+
+	if tc.CustomLogoURL != "" && !isValidURL(tc.CustomLogoURL) {
+		return ErrInvalidBrandingURL
+	}
+	if tc.CustomBackgroundURL != "" && !isValidURL(tc.CustomBackgroundURL) {
+		return ErrInvalidBrandingURL
+	}
+
+	return nil
+}
+
+// Inherit fills the settings a sub-tenant leaves unset from the configuration of its parent.
+// Service mappings and custom settings are merged with the sub-tenant winning on conflicts.
+// Two-factor authentication is inherited when the parent requires it; registration is not.
+func (tc TenantConfig) Inherit(parent TenantConfig) TenantConfig {
+	if tc.DefaultServiceURL == "" {
+		tc.DefaultServiceURL = parent.DefaultServiceURL
+	}
+	tc.ServiceMappings = mergeStringMaps(parent.ServiceMappings, tc.ServiceMappings)
+	if len(tc.FallbackChain) == 0 {
+		tc.FallbackChain = parent.FallbackChain
+	}
+	if len(tc.AllowedLoginIdentifiers) == 0 {
+		tc.AllowedLoginIdentifiers = parent.AllowedLoginIdentifiers
+	}
+	tc.Require2FA = tc.Require2FA || parent.Require2FA
+	if tc.CustomLogoURL == "" {
+		tc.CustomLogoURL = parent.CustomLogoURL
+	}
+	if tc.CustomBackgroundURL == "" {
+		tc.CustomBackgroundURL = parent.CustomBackgroundURL
+	}
+	tc.CustomSettings = mergeStringMaps(parent.CustomSettings, tc.CustomSettings)
+	return tc
+}
+
+// mergeStringMaps returns the entries of base overridden by those of overrides
+func mergeStringMaps(base, overrides map[string]string) map[string]string {
+	if len(base) == 0 {
+		return overrides
+	}
+	merged := make(map[string]string, len(base)+len(overrides))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+	return merged
+}
+
+// ResolveService returns the target configured for a service name, or the name itself if unmapped
+func (tc *TenantConfig) ResolveService(serviceName string) string {
+	if target, ok := tc.ServiceMappings[serviceName]; ok {
+		return target
+	}
+	return serviceName
+}
+
+// IsValidLoginIdentifier checks if the identifier is a supported login identifier
+func IsValidLoginIdentifier(identifier string) bool {
+	switch identifier {
+	case LoginIdentifierEmail, LoginIdentifierPhone, LoginIdentifierUsername, LoginIdentifierDocumentNumber:
+		return true
+	default:
+		return false
+	}
+}
+
+// isValidURL checks that the value is an absolute http(s) URL
+func isValidURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// Tenant config errors
+var (
+	ErrInvalidDefaultServiceURL = NewValidationError("default_service_url must be an absolute http(s) URL")
+	ErrInvalidServiceMapping    = NewValidationError("service_mappings entries must have a service name and a target")
+	ErrInvalidFallbackChain     = NewValidationError("fallback_chain entries must be non-empty and unique")
+	ErrLoginIdentifierRequired  = NewValidationError("at least one allowed_login_identifier is required")
+	ErrInvalidBrandingURL       = NewValidationError("custom branding URLs must be absolute http(s) URLs")
+)
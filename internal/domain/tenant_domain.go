@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TenantDomain is a custom domain claimed by a tenant. The gateway only routes
+// domains that have been verified through a DNS TXT record or an HTTP well-known file.
+type TenantDomain struct {
+	ID                 primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID           string             `bson:"tenantId" json:"tenant_id"`
+	Domain             string             `bson:"domain" json:"domain"`
+	Status             string             `bson:"status" json:"status"`                          // pending, verified, failed, expired
+	VerificationMethod string             `bson:"verificationMethod" json:"verification_method"` // dns-txt or http
+	Token              string             `bson:"token" json:"token"`
+	IsPrimary          bool               `bson:"isPrimary" json:"is_primary"` // For pending claims: promote to primary once verified
+	Attempts           int                `bson:"attempts" json:"attempts"`
+	LastError          string             `bson:"lastError,omitempty" json:"last_error,omitempty"`
+	LastCheckedAt      time.Time          `bson:"lastCheckedAt,omitempty" json:"last_checked_at,omitempty"`
+	VerifiedAt         time.Time          `bson:"verifiedAt,omitempty" json:"verified_at,omitempty"`
+	ExpiresAt          time.Time          `bson:"expiresAt" json:"expires_at"` // Pending claims expire unverified after this time
+	CreatedAt          time.Time          `bson:"createdAt" json:"created_at"`
+	UpdatedAt          time.Time          `bson:"updatedAt" json:"updated_at"`
+}
+
+// Domain verification statuses
+const (
+	DomainStatusPending  = "pending"
+	DomainStatusVerified = "verified"
+	DomainStatusFailed   = "failed"
+	DomainStatusExpired  = "expired"
+)
+
+// Domain verification methods
+const (
+	DomainVerificationDNS  = "dns-txt"
+	DomainVerificationHTTP = "http"
+)
+
+// Domain verification record locations
+const (
+	DomainTXTRecordPrefix = "_vhv-verification."
+	DomainTXTValuePrefix  = "vhv-verification="
+	DomainWellKnownPath   = "/.well-known/vhv-domain-verification"
+)
+
+// TXTRecordName returns the DNS name where the verification TXT record must be published
+func (d *TenantDomain) TXTRecordName() string {
+	return DomainTXTRecordPrefix + d.Domain
+}
+
+// TXTRecordValue returns the expected content of the verification TXT record
+func (d *TenantDomain) TXTRecordValue() string {
+	return DomainTXTValuePrefix + d.Token
+}
+
+// WellKnownURL returns the URL that must serve the token for HTTP verification
+func (d *TenantDomain) WellKnownURL() string {
+	return "http://" + d.Domain + DomainWellKnownPath
+}
+
+// IsExpired checks if a pending claim has passed its expiry time
+func (d *TenantDomain) IsExpired(now time.Time) bool {
+	return d.Status == DomainStatusPending && !d.ExpiresAt.IsZero() && now.After(d.ExpiresAt)
+}
+
+// IsValidDomainVerificationMethod checks if the method is a supported verification method
+func IsValidDomainVerificationMethod(method string) bool {
+	return method == DomainVerificationDNS || method == DomainVerificationHTTP
+}
+
+// IsValidDomainName checks that a normalized value is a fully qualified host name (not an IP)
+func IsValidDomainName(name string) bool {
+	if len(name) > 253 || net.ParseIP(name) != nil {
+		return false
+	}
+
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if !IsValidSubdomain(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// Tenant domain errors
+var (
+	ErrInvalidDomainName         = NewValidationError("domain must be a fully qualified host name")
+	ErrInvalidVerificationMethod = NewValidationError("verification_method must be \"dns-txt\" or \"http\"")
+)
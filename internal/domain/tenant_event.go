@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Types of tenant events published to other platform services
+const (
+	EventTenantCreated        = "TenantCreated"
+	EventTenantUpdated        = "TenantUpdated"
+	EventTenantConfigUpdated  = "TenantConfigUpdated"
+	EventTenantStatusChanged  = "TenantStatusChanged" // Activated, suspended, scheduled for deletion or deleted
+	EventTenantParentChanged  = "TenantParentChanged"
+	EventTenantPlanChanged    = "TenantPlanChanged"
+	EventMemberAdded          = "MemberAdded"
+	EventMemberRemoved        = "MemberRemoved"
+	EventMemberRoleChanged    = "MemberRoleChanged"
+	EventServiceConfigChanged = "ServiceConfigChanged"
+	EventServiceConfigDeleted = "ServiceConfigDeleted"
+)
+
+// Delivery statuses of outbox events
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusPublished = "published"
+)
+
+// TenantEvent is a change of a tenant announced to other platform services. Events of a
+// tenant are numbered by Sequence in the order their changes were committed; they are
+// delivered in that order and at least once, so consumers should ignore sequences they
+// have already seen.
+type TenantEvent struct {
+	ID         primitive.ObjectID     `bson:"_id" json:"id"`
+	Type       string                 `bson:"type" json:"type"`
+	TenantID   string                 `bson:"tenantId" json:"tenant_id"`
+	Sequence   int64                  `bson:"sequence" json:"sequence"`
+	Actor      Actor                  `bson:"actor" json:"actor"`
+	Payload    map[string]interface{} `bson:"payload,omitempty" json:"payload,omitempty"`
+	OccurredAt time.Time              `bson:"occurredAt" json:"occurred_at"`
+}
+
+// NewTenantEvent creates an event of a tenant with a payload encoded as its JSON fields.
+// The actor and sequence are assigned when the event is added to the outbox.
+func NewTenantEvent(eventType, tenantID string, payload interface{}) *TenantEvent {
+	event := &TenantEvent{
+		ID:         primitive.NewObjectID(),
+		Type:       eventType,
+		TenantID:   tenantID,
+		OccurredAt: time.Now(),
+	}
+	if data, err := json.Marshal(payload); err == nil {
+		_ = json.Unmarshal(data, &event.Payload)
+	}
+	return event
+}
+
+// OutboxEvent is a tenant event stored in the outbox with the state of its delivery
+type OutboxEvent struct {
+	TenantEvent   `bson:",inline"`
+	Status        string     `bson:"status"`
+	Attempts      int        `bson:"attempts"`
+	NextAttemptAt time.Time  `bson:"nextAttemptAt"`
+	LastError     string     `bson:"lastError,omitempty"`
+	PublishedAt   *time.Time `bson:"publishedAt,omitempty"`
+}
+
+// EventTenant is the view of a tenant carried by tenant events
+type EventTenant struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Domain           string `json:"domain,omitempty"`
+	Subdomain        string `json:"subdomain,omitempty"`
+	ParentID         string `json:"parent_id,omitempty"`
+	SubscriptionTier string `json:"subscription_tier"`
+	Status           string `json:"status"`
+	IsActive         bool   `json:"is_active"`
+	DefaultService   string `json:"default_service,omitempty"`
+	Version          int64  `json:"version"`
+}
+
+// NewEventTenant builds the event view of a tenant
+func NewEventTenant(tenant *Tenant) EventTenant {
+	return EventTenant{
+		ID:               tenant.ID.Hex(),
+		Name:             tenant.Name,
+		Domain:           tenant.Domain,
+		Subdomain:        tenant.Subdomain,
+		ParentID:         tenant.ParentID,
+		SubscriptionTier: tenant.SubscriptionTier,
+		Status:           tenant.CurrentStatus(),
+		IsActive:         tenant.IsActive,
+		DefaultService:   tenant.DefaultService,
+		Version:          tenant.Version,
+	}
+}
+
+// TenantChangedPayload is the payload of tenant creation, update, config and parent events
+type TenantChangedPayload struct {
+	Tenant  EventTenant `json:"tenant"`
+	Changed []string    `json:"changed,omitempty"` // Changed fields, as in the audit log
+}
+
+// TenantStatusChangedPayload is the payload of TenantStatusChanged events
+type TenantStatusChangedPayload struct {
+	From   string `json:"from,omitempty"`
+	To     string `json:"to"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// TenantPlanChangedPayload is the payload of TenantPlanChanged events
+type TenantPlanChangedPayload struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// MemberPayload is the payload of membership events
+type MemberPayload struct {
+	UserID       string `json:"user_id"`
+	Role         string `json:"role,omitempty"`
+	PreviousRole string `json:"previous_role,omitempty"`
+}
+
+// ServiceConfigPayload is the payload of service configuration events
+type ServiceConfigPayload struct {
+	ServiceName string `json:"service_name"`
+	IsActive    bool   `json:"is_active"`
+	Version     int64  `json:"version,omitempty"`
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+// Fields tenant listings can be sorted by
+const (
+	TenantSortName             = "name"
+	TenantSortCreatedAt        = "created_at"
+	TenantSortUpdatedAt        = "updated_at"
+	TenantSortSubscriptionTier = "subscription_tier"
+	TenantSortStatus           = "status"
+)
+
+// Sort orders
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// tenantSortFields maps each sort field to the stored field it sorts on
+var tenantSortFields = map[string]string{
+	TenantSortName:             "name",
+	TenantSortCreatedAt:        "createdAt",
+	TenantSortUpdatedAt:        "updatedAt",
+	TenantSortSubscriptionTier: "subscriptionTier",
+	TenantSortStatus:           "status",
+}
+
+var settingsKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+
+// TenantFilter narrows and orders a listing of tenants. Empty fields do not filter.
+type TenantFilter struct {
+	Name             string     // Name substring, case-insensitive
+	NamePrefix       string     // Name prefix, case-insensitive
+	Domain           string     // Exact custom domain or subdomain
+	SubscriptionTier string     // Exact tier
+	Status           string     // Exact lifecycle status
+	IsActive         *bool      // Active flag
+	CreatedAfter     *time.Time // Created at or after
+	CreatedBefore    *time.Time // Created before
+	SettingsKey      string     // Tenants with this settings key set; dots address nested settings
+	Search           string     // Case-insensitive text matched against name, domain and subdomain
+	SortBy           string     // One of the tenant sort fields; defaults to created_at
+	SortOrder        string     // asc or desc; defaults to desc for dates and asc otherwise
+}
+
+// Validate checks the filter values and sort options
+func (f *TenantFilter) Validate() error {
+	if f.SubscriptionTier != "" && !IsValidSubscriptionTier(f.SubscriptionTier) {
+		return NewValidationError("unknown subscription tier: " + f.SubscriptionTier)
+	}
+	if f.Status != "" && !IsValidTenantStatus(f.Status) {
+		return NewValidationError("unknown tenant status: " + f.Status)
+	}
+	if f.CreatedAfter != nil && f.CreatedBefore != nil && !f.CreatedAfter.Before(*f.CreatedBefore) {
+		return NewValidationError("created_after must be before created_before")
+	}
+	if f.SettingsKey != "" && !settingsKeyPattern.MatchString(f.SettingsKey) {
+		return NewValidationError("invalid settings key: " + f.SettingsKey)
+	}
+	if f.SortBy != "" {
+		if _, ok := tenantSortFields[f.SortBy]; !ok {
+			return NewValidationError("unsupported sort field: " + f.SortBy)
+		}
+	}
+	if f.SortOrder != "" && f.SortOrder != SortAsc && f.SortOrder != SortDesc {
+		return NewValidationError("sort order must be asc or desc")
+	}
+	return nil
+}
+
+// Sort returns the stored field the listing is sorted on and whether it sorts descending
+func (f *TenantFilter) Sort() (string, bool) {
+	sortBy := f.SortBy
+	if sortBy == "" {
+		sortBy = TenantSortCreatedAt
+	}
+
+	descending := sortBy == TenantSortCreatedAt || sortBy == TenantSortUpdatedAt
+	if f.SortOrder != "" {
+		descending = f.SortOrder == SortDesc
+	}
+	return tenantSortFields[sortBy], descending
+}
@@ -0,0 +1,16 @@
+package domain
+
+// MaxTenantDepth is the deepest a tenant may be nested below a top-level tenant
+const MaxTenantDepth = 4
+
+// SetParentRequest represents moving a tenant under a parent tenant. An empty parent
+// makes it a top-level tenant.
+type SetParentRequest struct {
+	ParentID        string `json:"parent_id"`
+	ExpectedVersion int64  `json:"expected_version"` // Rejects the move unless the tenant is at this version; 0 skips the check
+}
+
+// IsSubTenant checks if the tenant belongs to a parent tenant
+func (t *Tenant) IsSubTenant() bool {
+	return t.ParentID != ""
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// subdomainPattern matches a single lowercase DNS label
+var subdomainPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// NormalizeHost lowercases a host and strips any port and trailing dot
+func NormalizeHost(host string) string {
+	host = strings.TrimSpace(strings.ToLower(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(host, ".")
+}
+
+// IsValidSubdomain checks that the value is a single DNS label usable as a tenant subdomain
+func IsValidSubdomain(subdomain string) bool {
+	return subdomainPattern.MatchString(subdomain)
+}
+
+// SubdomainFromHost extracts the tenant label of a wildcard host such as
+// acme.platform.example for base domain platform.example. Only a single label is accepted.
+func SubdomainFromHost(host, baseDomain string) (string, bool) {
+	host = NormalizeHost(host)
+	baseDomain = NormalizeHost(baseDomain)
+	if baseDomain == "" || !strings.HasSuffix(host, "."+baseDomain) {
+		return "", false
+	}
+
+	label := strings.TrimSuffix(host, "."+baseDomain)
+	if !IsValidSubdomain(label) {
+		return "", false
+	}
+	return label, true
+}
+
+// Tenant host errors
+var (
+	ErrInvalidSubdomain = NewValidationError("subdomain must be a single lowercase DNS label")
+)
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TenantInvitation invites a person, identified by email or phone, to join a tenant with a role.
+// The invitee proves receipt with a signed single-use token; only a hash of its nonce is stored.
+type TenantInvitation struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID    string             `bson:"tenantId" json:"tenant_id"`
+	Email       string             `bson:"email,omitempty" json:"email,omitempty"`
+	Phone       string             `bson:"phone,omitempty" json:"phone,omitempty"`
+	Role        string             `bson:"role" json:"role"`
+	Status      string             `bson:"status" json:"status"` // pending, accepted, declined, revoked
+	NonceHash   string             `bson:"nonceHash" json:"-"`
+	InvitedBy   string             `bson:"invitedBy,omitempty" json:"invited_by,omitempty"`
+	AcceptedBy  string             `bson:"acceptedBy,omitempty" json:"accepted_by,omitempty"` // User ID that accepted
+	SendCount   int                `bson:"sendCount" json:"send_count"`
+	LastSentAt  time.Time          `bson:"lastSentAt" json:"last_sent_at"`
+	ExpiresAt   time.Time          `bson:"expiresAt" json:"expires_at"`
+	RespondedAt time.Time          `bson:"respondedAt,omitempty" json:"responded_at,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updated_at"`
+}
+
+// Invitation statuses. Pending invitations past their expiry are reported as expired.
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusDeclined = "declined"
+	InvitationStatusRevoked  = "revoked"
+	InvitationStatusExpired  = "expired"
+)
+
+// CreateInvitationRequest represents a tenant invitation request
+type CreateInvitationRequest struct {
+	Email     string `json:"email"`
+	Phone     string `json:"phone"` // E.164, e.g. +84901234567
+	Role      string `json:"role"`  // Defaults to member
+	InvitedBy string `json:"invited_by"`
+}
+
+// InvitationTokenRequest represents accepting or declining an invitation
+type InvitationTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// InvitationResponse represents an invitation in API responses. The token is only
+// returned when it is issued, on creation and resend, and only if the service does not
+// deliver invitations itself.
+type InvitationResponse struct {
+	ID         string `json:"id"`
+	TenantID   string `json:"tenant_id"`
+	Email      string `json:"email,omitempty"`
+	Phone      string `json:"phone,omitempty"`
+	Role       string `json:"role"`
+	Status     string `json:"status"`
+	InvitedBy  string `json:"invited_by,omitempty"`
+	AcceptedBy string `json:"accepted_by,omitempty"`
+	SendCount  int    `json:"send_count"`
+	Token      string `json:"token,omitempty"`
+	ExpiresAt  string `json:"expires_at"`
+	CreatedAt  string `json:"created_at"`
+}
+
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// CurrentStatus returns the status of the invitation at a point in time
+func (i *TenantInvitation) CurrentStatus(now time.Time) string {
+	if i.Status == InvitationStatusPending && now.After(i.ExpiresAt) {
+		return InvitationStatusExpired
+	}
+	return i.Status
+}
+
+// Normalize trims the recipient and lowercases the email
+func (r *CreateInvitationRequest) Normalize() {
+	r.Email = strings.ToLower(strings.TrimSpace(r.Email))
+	r.Phone = strings.TrimSpace(r.Phone)
+}
+
+// Validate validates the recipient of an invitation
+func (r *CreateInvitationRequest) Validate() error {
+	if r.Email == "" && r.Phone == "" {
+		return ErrInvitationRecipientRequired
+	}
+	if r.Email != "" {
+		if addr, err := mail.ParseAddress(r.Email); err != nil || addr.Address != r.Email {
+			return ErrInvalidInvitationEmail
+		}
+	}
+	if r.Phone != "" && !phonePattern.MatchString(r.Phone) {
+		return ErrInvalidInvitationPhone
+	}
+	return nil
+}
+
+// Tenant invitation errors
+var (
+	ErrInvitationRecipientRequired = NewValidationError("email or phone is required")
+	ErrInvalidInvitationEmail      = NewValidationError("email must be a valid address")
+	ErrInvalidInvitationPhone      = NewValidationError("phone must be in E.164 format")
+)
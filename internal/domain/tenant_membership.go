@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// Membership event types
+const (
+	MembershipJoined      = "joined"
+	MembershipLeft        = "left"
+	MembershipRoleChanged = "role_changed"
+)
+
+// MembershipEvent records a change to a user's membership of a tenant
+type MembershipEvent struct {
+	Type         string    `bson:"type" json:"type"`
+	Role         string    `bson:"role" json:"role"` // Role held after the event
+	PreviousRole string    `bson:"previousRole,omitempty" json:"previous_role,omitempty"`
+	At           time.Time `bson:"at" json:"at"`
+}
+
+// MemberHistoryResponse represents the membership history of a user in a tenant
+type MemberHistoryResponse struct {
+	UserID   string            `json:"user_id"`
+	Role     string            `json:"role"`
+	IsActive bool              `json:"is_active"`
+	History  []MembershipEvent `json:"history"`
+}
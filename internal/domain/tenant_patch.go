@@ -0,0 +1,383 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tenant fields a partial update can change. Settings keys are addressed below the
+// settings field, e.g. "settings.branding.color".
+const (
+	TenantFieldName                = "name"
+	TenantFieldDomain              = "domain"
+	TenantFieldSubdomain           = "subdomain"
+	TenantFieldDefaultService      = "default_service"
+	TenantFieldAuthSettings        = "auth_settings"
+	TenantFieldAllowedLoginMethods = "auth_settings.allowed_login_methods"
+	TenantFieldSettings            = "settings"
+)
+
+// readOnlyTenantFields explains how tenant fields that cannot be patched are changed
+var readOnlyTenantFields = map[string]string{
+	"id":                "id cannot be changed",
+	"subscription_tier": "subscription_tier can only be changed through a plan change",
+	"status":            "status can only be changed by suspending, reactivating or deleting the tenant",
+	"is_active":         "is_active can only be changed by suspending or reactivating the tenant",
+	"parent_id":         "parent_id can only be changed through the tenant parent endpoint",
+	"config":            "config can only be changed through the tenant config endpoint",
+	"trial":             "trial can only be changed through the tenant trial endpoint",
+	"version":           "version is read-only; send it as If-Match or expected_version",
+	"created_at":        "created_at is read-only",
+	"updated_at":        "updated_at is read-only",
+}
+
+// serviceNamePattern matches a service name such as "tenant-dashboard"
+var serviceNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// TenantPatch is a partial update of a tenant. Only the fields named in Paths change; a
+// named field left empty is cleared. Settings values are held nested by key.
+type TenantPatch struct {
+	Paths           []string
+	Name            string
+	Domain          string
+	Subdomain       string
+	DefaultService  string
+	AuthSettings    *AuthSettings
+	Settings        map[string]interface{}
+	ExpectedVersion int64 // Rejects the patch unless the tenant is at this version; 0 skips the check
+}
+
+// DefaultAuthSettings returns the authentication settings of a new tenant
+func DefaultAuthSettings() AuthSettings {
+	return AuthSettings{AllowedLoginMethods: []string{LoginIdentifierEmail}}
+}
+
+// NewTenantMergePatch reads a JSON Merge Patch (RFC 7396) of a tenant. A null member
+// clears the field; nested settings objects are merged key by key.
+func NewTenantMergePatch(document []byte) (*TenantPatch, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(document, &members); err != nil || members == nil {
+		return nil, NewValidationError("merge patch must be a JSON object")
+	}
+
+	patch := &TenantPatch{}
+	for _, name := range sortedKeys(members) {
+		value := members[name]
+		null := isJSONNull(value)
+
+		switch name {
+		case TenantFieldName, TenantFieldDomain, TenantFieldSubdomain, TenantFieldDefaultService:
+			var text string
+			if !null {
+				if err := json.Unmarshal(value, &text); err != nil {
+					return nil, NewValidationError(name + " must be a string")
+				}
+			}
+			patch.setString(name, text)
+			patch.Paths = append(patch.Paths, name)
+
+		case TenantFieldAuthSettings:
+			if err := patch.mergeAuthSettings(value, null); err != nil {
+				return nil, err
+			}
+
+		case TenantFieldSettings:
+			if null {
+				patch.Paths = append(patch.Paths, TenantFieldSettings)
+				continue
+			}
+			var settings map[string]interface{}
+			if err := json.Unmarshal(value, &settings); err != nil || settings == nil {
+				return nil, NewValidationError("settings must be an object")
+			}
+			patch.Settings = map[string]interface{}{}
+			patch.mergeSettings("", settings)
+
+		default:
+			// Validate reports read-only and unknown fields
+			patch.Paths = append(patch.Paths, name)
+		}
+	}
+	return patch, nil
+}
+
+// setString sets the value of a string field
+func (p *TenantPatch) setString(field, value string) {
+	switch field {
+	case TenantFieldName:
+		p.Name = value
+	case TenantFieldDomain:
+		p.Domain = value
+	case TenantFieldSubdomain:
+		p.Subdomain = value
+	case TenantFieldDefaultService:
+		p.DefaultService = value
+	}
+}
+
+// mergeAuthSettings reads the auth_settings member of a merge patch
+func (p *TenantPatch) mergeAuthSettings(value json.RawMessage, null bool) error {
+	if null {
+		p.Paths = append(p.Paths, TenantFieldAuthSettings)
+		return nil
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(value, &members); err != nil || members == nil {
+		return NewValidationError("auth_settings must be an object")
+	}
+	for _, name := range sortedKeys(members) {
+		if name != "allowed_login_methods" {
+			return NewValidationError("unknown field: auth_settings." + name)
+		}
+
+		p.AuthSettings = &AuthSettings{}
+		if !isJSONNull(members[name]) {
+			if err := json.Unmarshal(members[name], &p.AuthSettings.AllowedLoginMethods); err != nil {
+				return NewValidationError("auth_settings.allowed_login_methods must be a list of strings")
+			}
+		}
+		p.Paths = append(p.Paths, TenantFieldAllowedLoginMethods)
+	}
+	return nil
+}
+
+// mergeSettings turns a settings merge patch into one path per changed key. Objects are
+// merged into the existing settings; any other value, including null, replaces the key.
+func (p *TenantPatch) mergeSettings(prefix string, settings map[string]interface{}) {
+	for _, key := range sortedKeys(settings) {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		if nested, ok := settings[key].(map[string]interface{}); ok && len(nested) > 0 {
+			p.mergeSettings(path, nested)
+			continue
+		}
+		if settings[key] != nil {
+			setSetting(p.Settings, path, settings[key])
+		}
+		p.Paths = append(p.Paths, TenantFieldSettings+"."+path)
+	}
+}
+
+// Has checks if the patch changes a field
+func (p *TenantPatch) Has(field string) bool {
+	for _, path := range p.Paths {
+		if path == field {
+			return true
+		}
+	}
+	return false
+}
+
+// SettingsKeys returns the settings keys the patch changes; an empty key means the settings
+// are replaced as a whole
+func (p *TenantPatch) SettingsKeys() []string {
+	var keys []string
+	for _, path := range p.Paths {
+		if path == TenantFieldSettings {
+			keys = append(keys, "")
+		} else if strings.HasPrefix(path, TenantFieldSettings+".") {
+			keys = append(keys, strings.TrimPrefix(path, TenantFieldSettings+"."))
+		}
+	}
+	return keys
+}
+
+// Validate checks the patched fields and their values
+func (p *TenantPatch) Validate() error {
+	seen := make(map[string]bool, len(p.Paths))
+	for _, path := range p.Paths {
+		if seen[path] {
+			return NewValidationError("field listed more than once: " + path)
+		}
+		seen[path] = true
+
+		switch {
+		case path == TenantFieldName:
+			if strings.TrimSpace(p.Name) == "" {
+				return NewValidationError("name cannot be empty")
+			}
+		case path == TenantFieldDomain:
+			if p.Domain != "" && !IsValidDomainName(NormalizeHost(p.Domain)) {
+				return ErrInvalidDomainName
+			}
+		case path == TenantFieldSubdomain:
+			if p.Subdomain != "" && !IsValidSubdomain(p.Subdomain) {
+				return ErrInvalidSubdomain
+			}
+		case path == TenantFieldDefaultService:
+			if p.DefaultService != "" && !serviceNamePattern.MatchString(p.DefaultService) {
+				return NewValidationError("default_service must be a service name such as \"tenant-dashboard\"")
+			}
+		case path == TenantFieldAuthSettings, path == TenantFieldAllowedLoginMethods:
+			if err := p.validateAuthSettings(); err != nil {
+				return err
+			}
+		case path == TenantFieldSettings:
+		case strings.HasPrefix(path, TenantFieldSettings+"."):
+			key := strings.TrimPrefix(path, TenantFieldSettings+".")
+			if !settingsKeyPattern.MatchString(key) {
+				return NewValidationError("invalid settings key: " + key)
+			}
+		default:
+			if reason, ok := readOnlyTenantFields[path]; ok {
+				return NewValidationError(reason)
+			}
+			return NewValidationError("unknown field: " + path)
+		}
+	}
+
+	if seen[TenantFieldAuthSettings] && seen[TenantFieldAllowedLoginMethods] {
+		return NewValidationError("auth_settings cannot be patched together with its fields")
+	}
+	if seen[TenantFieldSettings] && len(seen) > 1 {
+		for path := range seen {
+			if strings.HasPrefix(path, TenantFieldSettings+".") {
+				return NewValidationError("settings cannot be patched together with its keys")
+			}
+		}
+	}
+	return nil
+}
+
+// validateAuthSettings checks the login methods of patched auth settings; unset settings
+// restore the defaults
+func (p *TenantPatch) validateAuthSettings() error {
+	if p.AuthSettings == nil || p.AuthSettings.AllowedLoginMethods == nil {
+		return nil
+	}
+
+	methods := p.AuthSettings.AllowedLoginMethods
+	if len(methods) == 0 {
+		return NewValidationError("auth_settings.allowed_login_methods cannot be empty; clear it to restore the default")
+	}
+	seen := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		if !IsValidLoginIdentifier(method) {
+			return NewValidationError("unsupported login method: " + method)
+		}
+		if seen[method] {
+			return NewValidationError("duplicate login method: " + method)
+		}
+		seen[method] = true
+	}
+	return nil
+}
+
+// Apply changes the patched fields of a tenant. Setting a domain is left to the caller,
+// since a domain only becomes the tenant domain once its claim is verified.
+func (p *TenantPatch) Apply(tenant *Tenant) {
+	for _, path := range p.Paths {
+		switch path {
+		case TenantFieldName:
+			tenant.Name = p.Name
+		case TenantFieldDomain:
+			if p.Domain == "" {
+				tenant.Domain = ""
+			}
+		case TenantFieldSubdomain:
+			tenant.Subdomain = p.Subdomain
+		case TenantFieldDefaultService:
+			tenant.DefaultService = p.DefaultService
+		case TenantFieldAuthSettings, TenantFieldAllowedLoginMethods:
+			tenant.AuthSettings = DefaultAuthSettings()
+			if p.AuthSettings != nil && len(p.AuthSettings.AllowedLoginMethods) > 0 {
+				tenant.AuthSettings.AllowedLoginMethods = p.AuthSettings.AllowedLoginMethods
+			}
+		case TenantFieldSettings:
+			tenant.Settings = p.Settings
+		default:
+			key := strings.TrimPrefix(path, TenantFieldSettings+".")
+			if tenant.Settings == nil {
+				tenant.Settings = map[string]interface{}{}
+			}
+			if value, ok := lookupSetting(p.Settings, key); ok {
+				setSetting(tenant.Settings, key, value)
+			} else {
+				deleteSetting(tenant.Settings, key)
+			}
+		}
+	}
+}
+
+// lookupSetting finds the value of a dotted settings key
+func lookupSetting(settings map[string]interface{}, key string) (interface{}, bool) {
+	parts := strings.Split(key, ".")
+	current := settings
+	for _, part := range parts[:len(parts)-1] {
+		nested, ok := settingsObject(current[part])
+		if !ok {
+			return nil, false
+		}
+		current = nested
+	}
+	value, ok := current[parts[len(parts)-1]]
+	return value, ok
+}
+
+// setSetting sets a dotted settings key, replacing non-object values on the way
+func setSetting(settings map[string]interface{}, key string, value interface{}) {
+	parts := strings.Split(key, ".")
+	current := settings
+	for _, part := range parts[:len(parts)-1] {
+		nested, ok := settingsObject(current[part])
+		if !ok {
+			nested = map[string]interface{}{}
+		}
+		current[part] = nested
+		current = nested
+	}
+	current[parts[len(parts)-1]] = value
+}
+
+// deleteSetting removes a dotted settings key if present
+func deleteSetting(settings map[string]interface{}, key string) {
+	parts := strings.Split(key, ".")
+	current := settings
+	for _, part := range parts[:len(parts)-1] {
+		nested, ok := settingsObject(current[part])
+		if !ok {
+			return
+		}
+		current[part] = nested
+		current = nested
+	}
+	delete(current, parts[len(parts)-1])
+}
+
+// settingsObject returns a nested settings object as a map, whichever way it was decoded
+func settingsObject(value interface{}) (map[string]interface{}, bool) {
+	switch object := value.(type) {
+	case map[string]interface{}:
+		return object, true
+	case primitive.M:
+		return object, true
+	case primitive.D:
+		return object.Map(), true
+	default:
+		return nil, false
+	}
+}
+
+// isJSONNull checks if a raw JSON value is null
+func isJSONNull(value json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(value), []byte("null"))
+}
+
+// sortedKeys returns the keys of a map in order, so patches apply deterministically
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewTenantMergePatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		document     string
+		wantPaths    []string
+		wantSettings []string
+		wantErr      string
+	}{
+		{
+			name:      "string fields",
+			document:  `{"name": "Acme", "subdomain": null}`,
+			wantPaths: []string{TenantFieldName, TenantFieldSubdomain},
+		},
+		{
+			name:         "nested settings",
+			document:     `{"settings": {"branding": {"color": "red", "logo": null}, "locale": "vi"}}`,
+			wantPaths:    []string{"settings.branding.color", "settings.branding.logo", "settings.locale"},
+			wantSettings: []string{"branding.color", "branding.logo", "locale"},
+		},
+		{
+			name:         "settings cleared",
+			document:     `{"settings": null}`,
+			wantPaths:    []string{TenantFieldSettings},
+			wantSettings: []string{""},
+		},
+		{
+			name:      "login methods",
+			document:  `{"auth_settings": {"allowed_login_methods": ["email"]}}`,
+			wantPaths: []string{TenantFieldAllowedLoginMethods},
+		},
+		{name: "not an object", document: `[]`, wantErr: "merge patch must be a JSON object"},
+		{name: "wrong type", document: `{"name": 1}`, wantErr: "name must be a string"},
+		{name: "settings not an object", document: `{"settings": "x"}`, wantErr: "settings must be an object"},
+		{name: "unknown auth field", document: `{"auth_settings": {"mfa": true}}`, wantErr: "unknown field: auth_settings.mfa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := NewTenantMergePatch([]byte(tt.document))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewTenantMergePatch: %v", err)
+			}
+			if !reflect.DeepEqual(patch.Paths, tt.wantPaths) {
+				t.Errorf("Paths = %v, want %v", patch.Paths, tt.wantPaths)
+			}
+			if !reflect.DeepEqual(patch.SettingsKeys(), tt.wantSettings) {
+				t.Errorf("SettingsKeys() = %v, want %v", patch.SettingsKeys(), tt.wantSettings)
+			}
+		})
+	}
+}
+
+func TestTenantPatchValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   TenantPatch
+		wantErr string
+	}{
+		{name: "valid name", patch: TenantPatch{Paths: []string{TenantFieldName}, Name: "Acme"}},
+		{name: "cleared domain", patch: TenantPatch{Paths: []string{TenantFieldDomain}}},
+		{name: "empty name", patch: TenantPatch{Paths: []string{TenantFieldName}, Name: " "}, wantErr: "name cannot be empty"},
+		{name: "duplicate path", patch: TenantPatch{Paths: []string{TenantFieldName, TenantFieldName}, Name: "Acme"}, wantErr: "field listed more than once"},
+		{name: "read-only field", patch: TenantPatch{Paths: []string{"status"}}, wantErr: "status can only be changed"},
+		{name: "unknown field", patch: TenantPatch{Paths: []string{"color"}}, wantErr: "unknown field: color"},
+		{name: "invalid service", patch: TenantPatch{Paths: []string{TenantFieldDefaultService}, DefaultService: "Bad Service"}, wantErr: "default_service must be"},
+		{name: "invalid settings key", patch: TenantPatch{Paths: []string{"settings.a..b"}}, wantErr: "invalid settings key"},
+		{
+			name:    "settings with keys",
+			patch:   TenantPatch{Paths: []string{TenantFieldSettings, "settings.locale"}},
+			wantErr: "settings cannot be patched together with its keys",
+		},
+		{
+			name:    "empty login methods",
+			patch:   TenantPatch{Paths: []string{TenantFieldAllowedLoginMethods}, AuthSettings: &AuthSettings{AllowedLoginMethods: []string{}}},
+			wantErr: "cannot be empty",
+		},
+		{
+			name:    "duplicate login method",
+			patch:   TenantPatch{Paths: []string{TenantFieldAllowedLoginMethods}, AuthSettings: &AuthSettings{AllowedLoginMethods: []string{"email", "email"}}},
+			wantErr: "duplicate login method: email",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.patch.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTenantPatchApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		tenant   Tenant
+		want     Tenant
+	}{
+		{
+			name:     "merge settings",
+			document: `{"settings": {"branding": {"color": "red", "logo": null}}}`,
+			tenant: Tenant{Settings: map[string]interface{}{
+				"branding": map[string]interface{}{"color": "blue", "logo": "a.png"},
+				"locale":   "vi",
+			}},
+			want: Tenant{Settings: map[string]interface{}{
+				"branding": map[string]interface{}{"color": "red"},
+				"locale":   "vi",
+			}},
+		},
+		{
+			name:     "replace settings",
+			document: `{"settings": null}`,
+			tenant:   Tenant{Settings: map[string]interface{}{"locale": "vi"}},
+			want:     Tenant{},
+		},
+		{
+			name:     "clear login methods",
+			document: `{"name": "Acme", "auth_settings": null}`,
+			tenant:   Tenant{Name: "Old", AuthSettings: AuthSettings{AllowedLoginMethods: []string{"phone"}}},
+			want:     Tenant{Name: "Acme", AuthSettings: DefaultAuthSettings()},
+		},
+		{
+			name:     "domain left to caller",
+			document: `{"domain": "example.com"}`,
+			tenant:   Tenant{Domain: "old.example.com"},
+			want:     Tenant{Domain: "old.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := NewTenantMergePatch([]byte(tt.document))
+			if err != nil {
+				t.Fatalf("NewTenantMergePatch: %v", err)
+			}
+			if err := patch.Validate(); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+
+			tenant := tt.tenant
+			patch.Apply(&tenant)
+			if !reflect.DeepEqual(tenant, tt.want) {
+				t.Errorf("tenant = %+v, want %+v", tenant, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// Plan change outcomes recorded in the plan history
+const (
+	PlanChangeApplied   = "applied"
+	PlanChangeCancelled = "cancelled"
+	PlanChangeFailed    = "failed"
+)
+
+// PlanChange records a subscription tier change of a tenant. A change effective in the
+// future is held as the scheduled change until it is applied, cancelled or fails.
+type PlanChange struct {
+	From        string    `bson:"from" json:"from"`
+	To          string    `bson:"to" json:"to"`
+	Reason      string    `bson:"reason,omitempty" json:"reason,omitempty"`
+	Actor       string    `bson:"actor,omitempty" json:"actor,omitempty"`
+	Outcome     string    `bson:"outcome,omitempty" json:"outcome,omitempty"`       // Empty while scheduled
+	Violations  []string  `bson:"violations,omitempty" json:"violations,omitempty"` // Why a scheduled change failed
+	RequestedAt time.Time `bson:"requestedAt" json:"requested_at"`
+	EffectiveAt time.Time `bson:"effectiveAt" json:"effective_at"`
+	CompletedAt time.Time `bson:"completedAt,omitempty" json:"completed_at,omitempty"`
+}
+
+// ChangePlanRequest represents a subscription tier change request. Without an effective
+// time, or with one in the past, the change is applied immediately.
+type ChangePlanRequest struct {
+	Tier        string     `json:"tier" binding:"required"`
+	EffectiveAt *time.Time `json:"effective_at"`
+	Reason      string     `json:"reason"`
+}
+
+// CancelPlanChangeRequest represents cancelling a scheduled plan change
+type CancelPlanChangeRequest struct {
+	Reason string `json:"reason"`
+}
+
+// PlanResponse represents the plan of a tenant with its scheduled change and history
+type PlanResponse struct {
+	TenantID        string       `json:"tenant_id"`
+	Tier            string       `json:"tier"`
+	ScheduledChange *PlanChange  `json:"scheduled_change,omitempty"`
+	History         []PlanChange `json:"history"`
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Purge report statuses
+const (
+	PurgeStatusCompleted = "completed"
+	PurgeStatusFailed    = "failed" // Some data could not be deleted; the purge is retried
+)
+
+// PurgeReport records the data removed when a tenant was purged. Reports outlive the
+// tenant and serve as evidence for data deletion requests.
+type PurgeReport struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID      string             `bson:"tenantId" json:"tenant_id"`
+	TenantName    string             `bson:"tenantName" json:"tenant_name"`
+	Status        string             `bson:"status" json:"status"`
+	Reason        string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	Actor         string             `bson:"actor,omitempty" json:"actor,omitempty"`
+	PurgeAfter    *time.Time         `bson:"purgeAfter,omitempty" json:"purge_after,omitempty"`
+	DeletedCounts map[string]int64   `bson:"deletedCounts" json:"deleted_counts"` // Documents deleted per collection
+	Errors        []string           `bson:"errors,omitempty" json:"errors,omitempty"`
+	StartedAt     time.Time          `bson:"startedAt" json:"started_at"`
+	CompletedAt   time.Time          `bson:"completedAt" json:"completed_at"`
+}
@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Permissions are "resource:action" strings. An action of "*" grants every action
+// on the resource and PermissionAll grants every permission.
+const (
+	PermissionAll = "*"
+
+	PermissionTenantRead     = "tenant:read"
+	PermissionTenantUpdate   = "tenant:update"
+	PermissionTenantDelete   = "tenant:delete"
+	PermissionMembersRead    = "members:read"
+	PermissionMembersManage  = "members:manage"
+	PermissionRolesManage    = "roles:manage"
+	PermissionServicesRead   = "services:read"
+	PermissionServicesManage = "services:manage"
+	PermissionDomainsRead    = "domains:read"
+	PermissionDomainsManage  = "domains:manage"
+	PermissionBillingRead    = "billing:read"
+	PermissionBillingManage  = "billing:manage"
+)
+
+// Built-in roles available in every tenant
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+	RoleViewer = "viewer"
+)
+
+// builtInRoles maps each built-in role to its permissions
+var builtInRoles = map[string][]string{
+	RoleOwner: {PermissionAll},
+	RoleAdmin: {
+		PermissionTenantRead, PermissionTenantUpdate,
+		"members:*", "roles:*", "services:*", "domains:*",
+		PermissionBillingRead,
+	},
+	RoleMember: {PermissionTenantRead, PermissionMembersRead, PermissionServicesRead, PermissionDomainsRead},
+	RoleViewer: {PermissionTenantRead, PermissionServicesRead},
+}
+
+// builtInRoleOrder lists built-in roles from most to least privileged
+var builtInRoleOrder = []string{RoleOwner, RoleAdmin, RoleMember, RoleViewer}
+
+var (
+	roleNamePattern   = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,62}$`)
+	permissionPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*(\.[a-z][a-z0-9_-]*)*:([a-z][a-z0-9_-]*|\*)$`)
+)
+
+// TenantRole is a named set of permissions assigned to tenant members. Built-in
+// roles are defined in code; custom roles are stored per tenant.
+type TenantRole struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	TenantID    string             `bson:"tenantId" json:"tenant_id,omitempty"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Permissions []string           `bson:"permissions" json:"permissions"`
+	IsBuiltIn   bool               `bson:"-" json:"is_built_in"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"created_at,omitempty"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updated_at,omitempty"`
+}
+
+// TenantRoleRequest represents a custom role creation or update request.
+// The name is taken from the path on update.
+type TenantRoleRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// PermissionCheck is the result of checking a permission for a tenant member
+type PermissionCheck struct {
+	Allowed       bool     `json:"allowed"`
+	Role          string   `json:"role,omitempty"`           // Empty when the user is not a member
+	Permissions   []string `json:"permissions,omitempty"`    // Permissions granted by the role
+	InheritedFrom string   `json:"inherited_from,omitempty"` // Parent tenant whose membership granted the role
+}
+
+// BuiltInRole returns a built-in role, or nil if the name is not a built-in role
+func BuiltInRole(name string) *TenantRole {
+	permissions, ok := builtInRoles[name]
+	if !ok {
+		return nil
+	}
+	return &TenantRole{
+		Name:        name,
+		Permissions: append([]string(nil), permissions...),
+		IsBuiltIn:   true,
+	}
+}
+
+// BuiltInRoles returns every built-in role, most privileged first
+func BuiltInRoles() []*TenantRole {
+	roles := make([]*TenantRole, 0, len(builtInRoleOrder))
+	for _, name := range builtInRoleOrder {
+		roles = append(roles, BuiltInRole(name))
+	}
+	return roles
+}
+
+// IsBuiltInRole checks if the name is a built-in role
+func IsBuiltInRole(name string) bool {
+	_, ok := builtInRoles[name]
+	return ok
+}
+
+// IsValidRoleName checks that a role name is a lowercase identifier
+func IsValidRoleName(name string) bool {
+	return roleNamePattern.MatchString(name)
+}
+
+// IsValidPermission checks that a permission is "*" or "resource:action"
+func IsValidPermission(permission string) bool {
+	return permission == PermissionAll || permissionPattern.MatchString(permission)
+}
+
+// GrantsPermission checks if a set of granted permissions covers the required permission
+func GrantsPermission(granted []string, required string) bool {
+	resource, _, _ := strings.Cut(required, ":")
+	for _, permission := range granted {
+		if permission == PermissionAll || permission == required || permission == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission checks if the role grants a permission
+func (r *TenantRole) HasPermission(permission string) bool {
+	return GrantsPermission(r.Permissions, permission)
+}
+
+// Validate validates a custom role definition
+func (r *TenantRole) Validate() error {
+	if !IsValidRoleName(r.Name) {
+		return ErrInvalidRoleName
+	}
+	if IsBuiltInRole(r.Name) {
+		return ErrBuiltInRole
+	}
+	if len(r.Permissions) == 0 {
+		return ErrPermissionsRequired
+	}
+	for _, permission := range r.Permissions {
+		if permission == PermissionAll {
+			return ErrPermissionAllReserved
+		}
+		if !IsValidPermission(permission) {
+			return NewValidationError("invalid permission: " + permission)
+		}
+	}
+	return nil
+}
+
+// Tenant role errors
+var (
+	ErrInvalidRoleName       = NewValidationError("role name must be a lowercase identifier of at most 63 characters")
+	ErrBuiltInRole           = NewValidationError("built-in roles cannot be created, changed or deleted")
+	ErrPermissionsRequired   = NewValidationError("at least one permission is required")
+	ErrPermissionAllReserved = NewValidationError("the * permission is reserved for the owner role")
+)
@@ -0,0 +1,356 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Setting visibilities, from the widest audience to the narrowest
+const (
+	SettingVisibilityPublic   = "public"   // Readable by clients of the tenant
+	SettingVisibilityAdmin    = "admin"    // Readable by tenant administrators
+	SettingVisibilityInternal = "internal" // Only readable by platform services
+)
+
+// settingVisibilityLevels orders the visibilities; an audience reads every setting at or below its level
+var settingVisibilityLevels = map[string]int{
+	SettingVisibilityPublic:   0,
+	SettingVisibilityAdmin:    1,
+	SettingVisibilityInternal: 2,
+}
+
+// SettingDefinition describes a tenant setting registered by a platform service. The key
+// addresses the setting in the tenant settings, with dots separating nested objects.
+type SettingDefinition struct {
+	ID          primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	Key         string                 `bson:"key" json:"key"`
+	Service     string                 `bson:"service" json:"service"` // Service that registered the setting
+	Description string                 `bson:"description,omitempty" json:"description,omitempty"`
+	Schema      map[string]interface{} `bson:"schema" json:"schema"`                       // JSON Schema of the value
+	Default     interface{}            `bson:"default,omitempty" json:"default,omitempty"` // Value of tenants that have not set it
+	Visibility  string                 `bson:"visibility" json:"visibility"`
+	CreatedAt   time.Time              `bson:"createdAt" json:"created_at"`
+	UpdatedAt   time.Time              `bson:"updatedAt" json:"updated_at"`
+}
+
+// RegisterSettingRequest represents registering or replacing a setting definition
+type RegisterSettingRequest struct {
+	Service     string                 `json:"service" binding:"required"`
+	Description string                 `json:"description"`
+	Schema      map[string]interface{} `json:"schema" binding:"required"`
+	Default     interface{}            `json:"default"`
+	Visibility  string                 `json:"visibility"` // Defaults to admin
+}
+
+// Definition builds the definition of a setting key from the request
+func (r *RegisterSettingRequest) Definition(key string) *SettingDefinition {
+	definition := &SettingDefinition{
+		Key:         key,
+		Service:     r.Service,
+		Description: r.Description,
+		Default:     NormalizeSettingValue(r.Default),
+		Visibility:  r.Visibility,
+	}
+	if definition.Visibility == "" {
+		definition.Visibility = SettingVisibilityAdmin
+	}
+	if schema, ok := NormalizeSettingValue(r.Schema).(map[string]interface{}); ok {
+		definition.Schema = stripSchemaIdentifiers(schema)
+	}
+	return definition
+}
+
+// stripSchemaIdentifiers drops the $-prefixed annotations of a schema and its subschemas,
+// which describe the schema document itself and cannot be stored as field names
+func stripSchemaIdentifiers(schema map[string]interface{}) map[string]interface{} {
+	for keyword, value := range schema {
+		if strings.HasPrefix(keyword, "$") {
+			delete(schema, keyword)
+			continue
+		}
+
+		switch keyword {
+		case "items", "additionalProperties":
+			if subschema, ok := value.(map[string]interface{}); ok {
+				stripSchemaIdentifiers(subschema)
+			}
+		case "properties":
+			if properties, ok := value.(map[string]interface{}); ok {
+				for _, property := range properties {
+					if subschema, ok := property.(map[string]interface{}); ok {
+						stripSchemaIdentifiers(subschema)
+					}
+				}
+			}
+		}
+	}
+	return schema
+}
+
+// Validate checks the key, visibility and schema of a definition and its default against the schema
+func (d *SettingDefinition) Validate() error {
+	if !settingsKeyPattern.MatchString(d.Key) {
+		return NewValidationError("invalid setting key: " + d.Key)
+	}
+	if d.Service == "" {
+		return NewValidationError("service is required")
+	}
+	if _, ok := settingVisibilityLevels[d.Visibility]; !ok {
+		return NewValidationError("visibility must be public, admin or internal")
+	}
+	if d.Schema == nil {
+		return NewValidationError("schema is required")
+	}
+	if err := CheckSettingSchema(d.Schema); err != nil {
+		return NewValidationError(err.Error())
+	}
+	if d.Default != nil {
+		if err := ValidateSettingValue(d.Schema, d.Default); err != nil {
+			return NewValidationError("default: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// Overlaps checks if one of two setting keys addresses a value nested in the other
+func (d *SettingDefinition) Overlaps(key string) bool {
+	return d.Key == key || strings.HasPrefix(key, d.Key+".") || strings.HasPrefix(d.Key, key+".")
+}
+
+// IsValidSettingVisibility checks if the value is a setting visibility
+func IsValidSettingVisibility(visibility string) bool {
+	_, ok := settingVisibilityLevels[visibility]
+	return ok
+}
+
+// SettingsRegistry is the set of registered setting definitions
+type SettingsRegistry struct {
+	definitions map[string]*SettingDefinition
+}
+
+// NewSettingsRegistry creates a registry of setting definitions
+func NewSettingsRegistry(definitions []*SettingDefinition) *SettingsRegistry {
+	registry := &SettingsRegistry{definitions: make(map[string]*SettingDefinition, len(definitions))}
+	for _, definition := range definitions {
+		registry.definitions[definition.Key] = definition
+	}
+	return registry
+}
+
+// Definitions returns the definitions ordered by key
+func (r *SettingsRegistry) Definitions() []*SettingDefinition {
+	definitions := make([]*SettingDefinition, 0, len(r.definitions))
+	for _, key := range sortedKeys(r.definitions) {
+		definitions = append(definitions, r.definitions[key])
+	}
+	return definitions
+}
+
+// covering finds the definition of a key or of the object containing it
+func (r *SettingsRegistry) covering(key string) *SettingDefinition {
+	parts := strings.Split(key, ".")
+	for i := 1; i <= len(parts); i++ {
+		if definition, ok := r.definitions[strings.Join(parts[:i], ".")]; ok {
+			return definition
+		}
+	}
+	return nil
+}
+
+// ValidateChanges checks the settings changed by a write against their definitions. Each
+// changed key is a dotted settings key, or empty when the settings were replaced as a whole.
+// Removed settings are always accepted; setting a key nobody registered is not.
+func (r *SettingsRegistry) ValidateChanges(settings map[string]interface{}, changed []string) error {
+	checked := make(map[string]bool)
+	for _, key := range changed {
+		if definition := r.covering(key); key != "" && definition != nil {
+			if err := r.validateDefinition(definition, settings, checked); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, ok := interface{}(settings), true
+		if key != "" {
+			value, ok = lookupSetting(settings, key)
+		}
+		if !ok {
+			continue
+		}
+		if err := r.validateTree(key, value, settings, checked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateTree validates the registered settings within a changed value and rejects
+// values at keys nobody registered
+func (r *SettingsRegistry) validateTree(key string, value interface{}, settings map[string]interface{}, checked map[string]bool) error {
+	if definition, ok := r.definitions[key]; ok {
+		return r.validateDefinition(definition, settings, checked)
+	}
+
+	object, isObject := settingsObject(value)
+	if key != "" && (!isObject || !r.hasDefinitionsUnder(key)) {
+		return NewValidationError("unknown setting: " + key)
+	}
+	for _, name := range sortedKeys(object) {
+		child := name
+		if key != "" {
+			child = key + "." + name
+		}
+		if err := r.validateTree(child, object[name], settings, checked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasDefinitionsUnder checks if any definition is nested below a key; every definition is
+// below the empty key
+func (r *SettingsRegistry) hasDefinitionsUnder(key string) bool {
+	if key == "" {
+		return len(r.definitions) > 0
+	}
+	for definitionKey := range r.definitions {
+		if strings.HasPrefix(definitionKey, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDefinition validates the current value of a registered setting once
+func (r *SettingsRegistry) validateDefinition(definition *SettingDefinition, settings map[string]interface{}, checked map[string]bool) error {
+	if checked[definition.Key] {
+		return nil
+	}
+	checked[definition.Key] = true
+
+	value, ok := lookupSetting(settings, definition.Key)
+	if !ok {
+		return nil
+	}
+	if err := ValidateSettingValue(definition.Schema, value); err != nil {
+		return NewValidationError(fmt.Sprintf("setting %s: %s", definition.Key, err.Error()))
+	}
+	return nil
+}
+
+// Resolve merges the defaults of the registered settings into the stored settings of a
+// tenant and keeps what the audience of the visibility may read. Settings nobody
+// registered are treated as admin settings.
+func (r *SettingsRegistry) Resolve(settings map[string]interface{}, visibility string) *EffectiveSettings {
+	level := settingVisibilityLevels[visibility]
+	effective := &EffectiveSettings{Values: map[string]interface{}{}}
+
+	for _, definition := range r.Definitions() {
+		if settingVisibilityLevels[definition.Visibility] > level {
+			continue
+		}
+		if value, ok := lookupSetting(settings, definition.Key); ok {
+			setSetting(effective.Values, definition.Key, NormalizeSettingValue(value))
+		} else if definition.Default != nil {
+			setSetting(effective.Values, definition.Key, NormalizeSettingValue(definition.Default))
+			effective.Defaulted = append(effective.Defaulted, definition.Key)
+		}
+	}
+
+	if level >= settingVisibilityLevels[SettingVisibilityAdmin] {
+		r.collectUnregistered("", settings, effective)
+	}
+	return effective
+}
+
+// collectUnregistered copies the stored settings no definition covers
+func (r *SettingsRegistry) collectUnregistered(key string, value interface{}, effective *EffectiveSettings) {
+	if _, ok := r.definitions[key]; ok && key != "" {
+		return
+	}
+
+	object, isObject := settingsObject(value)
+	if key != "" && (!isObject || !r.hasDefinitionsUnder(key)) {
+		setSetting(effective.Values, key, NormalizeSettingValue(value))
+		return
+	}
+	for _, name := range sortedKeys(object) {
+		child := name
+		if key != "" {
+			child = key + "." + name
+		}
+		r.collectUnregistered(child, object[name], effective)
+	}
+}
+
+// EffectiveSettings is the resolved view of the settings of a tenant
+type EffectiveSettings struct {
+	Values    map[string]interface{} // Nested by key, with JSON value types
+	Defaulted []string               // Keys resolved from their registered default
+}
+
+// Get returns the value of a dotted settings key
+func (e *EffectiveSettings) Get(key string) (interface{}, bool) {
+	return lookupSetting(e.Values, key)
+}
+
+// String returns a string setting
+func (e *EffectiveSettings) String(key string) (string, bool) {
+	value, _ := e.Get(key)
+	s, ok := value.(string)
+	return s, ok
+}
+
+// Bool returns a boolean setting
+func (e *EffectiveSettings) Bool(key string) (bool, bool) {
+	value, _ := e.Get(key)
+	b, ok := value.(bool)
+	return b, ok
+}
+
+// Float returns a numeric setting
+func (e *EffectiveSettings) Float(key string) (float64, bool) {
+	value, _ := e.Get(key)
+	f, ok := value.(float64)
+	return f, ok
+}
+
+// Int returns an integer setting; numbers with a fraction are not integers
+func (e *EffectiveSettings) Int(key string) (int64, bool) {
+	f, ok := e.Float(key)
+	if !ok || f != math.Trunc(f) {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// Strings returns a setting holding a list of strings
+func (e *EffectiveSettings) Strings(key string) ([]string, bool) {
+	value, _ := e.Get(key)
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, s)
+	}
+	return values, true
+}
+
+// EffectiveSettingsResponse represents the resolved settings of a tenant
+type EffectiveSettingsResponse struct {
+	TenantID   string                 `json:"tenant_id"`
+	Visibility string                 `json:"visibility"`
+	Settings   map[string]interface{} `json:"settings"`
+	Defaulted  []string               `json:"defaulted,omitempty"` // Keys resolved from their registered default
+}
@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// Tenant lifecycle statuses
+const (
+	TenantStatusProvisioning    = "provisioning"
+	TenantStatusActive          = "active"
+	TenantStatusSuspended       = "suspended"
+	TenantStatusPendingDeletion = "pending_deletion"
+	TenantStatusDeleted         = "deleted"
+)
+
+// Actor recorded for transitions made by the service itself
+const SystemActor = "system"
+
+// tenantStatusTransitions lists the statuses each status may move to
+var tenantStatusTransitions = map[string][]string{
+	TenantStatusProvisioning:    {TenantStatusActive, TenantStatusDeleted},
+	TenantStatusActive:          {TenantStatusSuspended, TenantStatusPendingDeletion, TenantStatusDeleted},
+	TenantStatusSuspended:       {TenantStatusActive, TenantStatusPendingDeletion, TenantStatusDeleted},
+	TenantStatusPendingDeletion: {TenantStatusActive, TenantStatusSuspended, TenantStatusDeleted},
+	TenantStatusDeleted:         {},
+}
+
+// TenantStatusChange records a single lifecycle transition of a tenant
+type TenantStatusChange struct {
+	From       string     `bson:"from" json:"from"`
+	To         string     `bson:"to" json:"to"`
+	Reason     string     `bson:"reason,omitempty" json:"reason,omitempty"`
+	Actor      string     `bson:"actor,omitempty" json:"actor,omitempty"`
+	ChangedAt  time.Time  `bson:"changedAt" json:"changed_at"`
+	PurgeAfter *time.Time `bson:"purgeAfter,omitempty" json:"purge_after,omitempty"` // Purge deadline set by this transition
+}
+
+// TenantStatusRequest represents a suspend or reactivate request
+type TenantStatusRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// CanTransitionTenantStatus checks if a tenant may move from one status to another
+func CanTransitionTenantStatus(from, to string) bool {
+	for _, allowed := range tenantStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidTenantStatus checks if the status is a known lifecycle status
+func IsValidTenantStatus(status string) bool {
+	_, ok := tenantStatusTransitions[status]
+	return ok
+}
+
+// CurrentStatus returns the lifecycle status of the tenant, deriving it from
+// IsActive for records created before statuses existed
+func (t *Tenant) CurrentStatus() string {
+	if t.Status != "" {
+		return t.Status
+	}
+	if t.IsActive {
+		return TenantStatusActive
+	}
+	return TenantStatusDeleted
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "testing"
+
+func TestCanTransitionTenantStatus(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{TenantStatusProvisioning, TenantStatusActive, true},
+		{TenantStatusProvisioning, TenantStatusDeleted, true},
+		{TenantStatusProvisioning, TenantStatusSuspended, false},
+		{TenantStatusActive, TenantStatusSuspended, true},
+		{TenantStatusActive, TenantStatusPendingDeletion, true},
+		{TenantStatusActive, TenantStatusProvisioning, false},
+		{TenantStatusActive, TenantStatusActive, false},
+		{TenantStatusSuspended, TenantStatusActive, true},
+		{TenantStatusPendingDeletion, TenantStatusActive, true},
+		{TenantStatusPendingDeletion, TenantStatusDeleted, true},
+		{TenantStatusDeleted, TenantStatusActive, false},
+		{"unknown", TenantStatusActive, false},
+		{TenantStatusActive, "unknown", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			if got := CanTransitionTenantStatus(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransitionTenantStatus(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTenantCurrentStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		tenant Tenant
+		want   string
+	}{
+		{"explicit status", Tenant{Status: TenantStatusSuspended, IsActive: true}, TenantStatusSuspended},
+		{"legacy active", Tenant{IsActive: true}, TenantStatusActive},
+		{"legacy inactive", Tenant{}, TenantStatusDeleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tenant.CurrentStatus(); got != tt.want {
+				t.Errorf("CurrentStatus() = %q, want %q", got, tt.want)
+			}
+			if !IsValidTenantStatus(tt.want) {
+				t.Errorf("IsValidTenantStatus(%q) = false", tt.want)
+			}
+		})
+	}
+}
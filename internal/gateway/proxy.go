@@ -1,138 +1,369 @@
-package gateway
-
-import (
-	"net/http"
-	"net/http/httputil"
-	"net/url"
-	"strings"
-
-	"github.com/gin-gonic/gin"
-	"github.com/vhvplatform/go-shared/logger"
-	"go.uber.org/zap"
-)
-
-type ProxyHandler struct {
-	logger *logger.Logger
-}
-
-func NewProxyHandler(log *logger.Logger) *ProxyHandler {
-	return &ProxyHandler{
-		logger: log,
-	}
-}
-
-func (h *ProxyHandler) HandleRequest(c *gin.Context) {
-	path := c.Request.URL.Path
-	var targetURL string
-
-	switch {
-	case strings.HasPrefix(path, "/api/"):
-		// Format: /api/service-name/api-path
-		parts := strings.SplitN(strings.TrimPrefix(path, "/api/"), "/", 2)
-		if len(parts) >= 1 {
-			serviceName := parts[0]
-			apiPath := ""
-			if len(parts) > 1 {
-				apiPath = "/" + parts[1]
-			}
-			targetURL = h.resolveServiceURL(serviceName, apiPath)
-		}
-
-	case strings.HasPrefix(path, "/page/"):
-		// Format: /page/service-name/page-path
-		parts := strings.SplitN(strings.TrimPrefix(path, "/page/"), "/", 2)
-		if len(parts) >= 1 {
-			serviceName := parts[0]
-			pagePath := ""
-			if len(parts) > 1 {
-				pagePath = "/" + parts[1]
-			}
-			targetURL = h.resolvePageURL(serviceName, pagePath)
-		}
-
-	case strings.HasPrefix(path, "/upload/"):
-		// Format: /upload/file-key
-		fileKey := strings.TrimPrefix(path, "/upload/")
-		targetURL = h.resolveUploadURL(fileKey)
-
-	default:
-		// Slug processing or default service
-		targetURL = h.resolveSlugURL(path, c)
-	}
-
-	if targetURL == "" {
-		h.handleFailover(c)
-		return
-	}
-
-	h.proxyTo(c, targetURL)
-}
-
-func (h *ProxyHandler) resolveServiceURL(serviceName, apiPath string) string {
-	// In a real scenario, this would use service discovery (Consul, K8s DNS)
-	// For this demo, we'll use env vars or defaults
-	port := "8080" // Default
-	return "http://" + serviceName + ":" + port + apiPath
-}
-
-func (h *ProxyHandler) resolvePageURL(serviceName, pagePath string) string {
-	return "http://" + serviceName + "-web:3000" + pagePath
-}
-
-func (h *ProxyHandler) resolveUploadURL(fileKey string) string {
-	return "http://file-service:8080/files/" + fileKey
-}
-
-func (h *ProxyHandler) resolveSlugURL(path string, c *gin.Context) string {
-	// Point 3: "đường dẫn dạng còn lại thì xử lý như dạng đường dẫn đẹp (slug)"
-	// This usually means routing to a CMS or a specific service that handles slugs
-	return "http://cms-service:8080/slug" + path
-}
-
-func (h *ProxyHandler) proxyTo(c *gin.Context, target string) {
-	remote, err := url.Parse(target)
-	if err != nil {
-		h.logger.Error("Failed to parse target URL", zap.Error(err), zap.String("target", target))
-		c.AbortWithStatus(http.StatusInternalServerError)
-		return
-	}
-
-	proxy := httputil.NewSingleHostReverseProxy(remote)
-	proxy.Director = func(req *http.Request) {
-		req.Header = c.Request.Header
-		req.Host = remote.Host
-		req.URL.Scheme = remote.Scheme
-		req.URL.Host = remote.Host
-		req.URL.Path = remote.Path
-	}
-
-	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
-		h.logger.Error("Proxy error", zap.Error(err), zap.String("target", target))
-		h.handleFailover(c)
-	}
-
-	proxy.ServeHTTP(c.Writer, c.Request)
-}
-
-func (h *ProxyHandler) handleFailover(c *gin.Context) {
-	// Point 7: "Nếu gateway điều hướng mà lỗi thì sẽ chuyển hướng tiếp về default service của tenant.
-	// Nếu tiếp tục lỗi sẽ chuyển về default service của hệ thống"
-
-	tenantInfoRaw, exists := c.Get("tenant_info")
-	if exists && tenantInfoRaw != nil {
-		tenantInfo := tenantInfoRaw.(*TenantInfo)
-		if tenantInfo.DefaultService != "" {
-			h.logger.Info("Failing over to tenant default service", zap.String("service", tenantInfo.DefaultService))
-			// Recursive call or redirect
-			// For simplicity, we'll mock a redirect or re-proxy
-			// target := h.resolveServiceURL(tenantInfo.DefaultService, c.Request.URL.Path)
-			// h.proxyTo(c, target)
-			return
-		}
-	}
-
-	// Default system service
-	h.logger.Info("Failing over to system default service")
-	// target := h.resolveServiceURL("system-default", c.Request.URL.Path)
-	// h.proxyTo(c, target)
-}
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vhvplatform/go-shared/logger"
+	"go.uber.org/zap"
+)
+
+// Fallback levels reported in the X-Gateway-Fallback-Level response header
+const (
+	FallbackLevelPrimary       = "primary"
+	FallbackLevelTenantChain   = "tenant-fallback"
+	FallbackLevelTenantDefault = "tenant-default"
+	FallbackLevelSystemDefault = "system-default"
+)
+
+// Response headers describing how the gateway served a request
+const (
+	HeaderFallbackLevel   = "X-Gateway-Fallback-Level"
+	HeaderUpstreamService = "X-Gateway-Upstream-Service"
+	HeaderAttempts        = "X-Gateway-Attempts"
+)
+
+// FailoverConfig controls how failed upstream requests are retried
+type FailoverConfig struct {
+	RetryStatusCodes     []int  // Upstream statuses that trigger failover, e.g. 502, 503, 504
+	MaxAttempts          int    // Total upstream attempts per request, including the first
+	MaxBodyBytes         int64  // Bodies larger than this are not buffered and cannot be replayed
+	SystemDefaultService string // Service whose DefaultServiceConfig is the last resort
+}
+
+// DefaultFailoverConfig returns the default failover settings
+func DefaultFailoverConfig() FailoverConfig {
+	return FailoverConfig{
+		RetryStatusCodes:     []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		MaxAttempts:          3,
+		MaxBodyBytes:         1 << 20, // 1 MiB
+		SystemDefaultService: "system-default",
+	}
+}
+
+// errRetryableStatus rejects an upstream response so that the next candidate is tried
+var errRetryableStatus = errors.New("upstream returned retryable status")
+
+// upstreamCandidate is one target in the failover sequence
+type upstreamCandidate struct {
+	level       string
+	tenantID    string
+	serviceName string // Empty for fixed URLs from the tenant config
+	url         string // Fixed upstream URL; when set the service is not resolved
+	targetPath  string
+}
+
+type ProxyHandler struct {
+	resolver ServiceResolver
+	failover FailoverConfig
+	logger   *logger.Logger
+}
+
+func NewProxyHandler(resolver ServiceResolver, failover FailoverConfig, log *logger.Logger) *ProxyHandler {
+	if failover.MaxAttempts < 1 {
+		failover.MaxAttempts = 1
+	}
+
+	return &ProxyHandler{
+		resolver: resolver,
+		failover: failover,
+		logger:   log,
+	}
+}
+
+func (h *ProxyHandler) HandleRequest(c *gin.Context) {
+	path := c.Request.URL.Path
+	var serviceName, targetPath string
+
+	switch {
+	case strings.HasPrefix(path, "/api/"):
+		// Format: /api/service-name/api-path
+		parts := strings.SplitN(strings.TrimPrefix(path, "/api/"), "/", 2)
+		serviceName = parts[0]
+		if len(parts) > 1 {
+			targetPath = "/" + parts[1]
+		}
+
+	case strings.HasPrefix(path, "/page/"):
+		// Format: /page/service-name/page-path
+		parts := strings.SplitN(strings.TrimPrefix(path, "/page/"), "/", 2)
+		if parts[0] != "" {
+			serviceName = parts[0] + PageServiceSuffix
+		}
+		if len(parts) > 1 {
+			targetPath = "/" + parts[1]
+		}
+
+	case strings.HasPrefix(path, "/upload/"):
+		// Format: /upload/file-key
+		fileKey := strings.TrimPrefix(path, "/upload/")
+		serviceName = UploadServiceName
+		targetPath = "/files/" + fileKey
+
+	default:
+		// Point 3: "đường dẫn dạng còn lại thì xử lý như dạng đường dẫn đẹp (slug)"
+		// Slugs are routed to the CMS service of the tenant
+		serviceName = SlugServiceName
+		targetPath = "/slug" + path
+	}
+
+	tenantID := c.GetHeader("X-Tenant-ID")
+	candidates := []upstreamCandidate{}
+	if serviceName != "" {
+		candidates = append(candidates, upstreamCandidate{
+			level:       FallbackLevelPrimary,
+			tenantID:    tenantID,
+			serviceName: serviceName,
+			targetPath:  targetPath,
+		})
+	}
+	candidates = append(candidates, h.fallbackCandidates(c, tenantID, targetPath)...)
+
+	h.serveWithFailover(c, candidates)
+}
+
+// fallbackCandidates builds the failover sequence after the primary target.
+// Point 7: "Nếu gateway điều hướng mà lỗi thì sẽ chuyển hướng tiếp về default service của tenant.
+// Nếu tiếp tục lỗi sẽ chuyển về default service của hệ thống"
+// Fallback targets receive the same path as the primary service, without the routing prefix.
+func (h *ProxyHandler) fallbackCandidates(c *gin.Context, tenantID, path string) []upstreamCandidate {
+	candidates := []upstreamCandidate{}
+
+	if tenantInfoRaw, exists := c.Get("tenant_info"); exists && tenantInfoRaw != nil {
+		if tenantInfo, ok := tenantInfoRaw.(*TenantInfo); ok && tenantInfo != nil {
+			for _, url := range tenantInfo.FallbackChain {
+				candidates = append(candidates, upstreamCandidate{
+					level:      FallbackLevelTenantChain,
+					tenantID:   tenantID,
+					url:        url,
+					targetPath: path,
+				})
+			}
+			if tenantInfo.DefaultServiceURL != "" {
+				candidates = append(candidates, upstreamCandidate{
+					level:       FallbackLevelTenantDefault,
+					tenantID:    tenantID,
+					serviceName: tenantInfo.DefaultService,
+					url:         tenantInfo.DefaultServiceURL,
+					targetPath:  path,
+				})
+			} else if tenantInfo.DefaultService != "" {
+				candidates = append(candidates, upstreamCandidate{
+					level:       FallbackLevelTenantDefault,
+					tenantID:    tenantID,
+					serviceName: tenantInfo.DefaultService,
+					targetPath:  path,
+				})
+			}
+		}
+	}
+
+	if h.failover.SystemDefaultService != "" {
+		// An empty tenant ID makes the registry resolve the system-wide DefaultServiceConfig
+		candidates = append(candidates, upstreamCandidate{
+			level:       FallbackLevelSystemDefault,
+			serviceName: h.failover.SystemDefaultService,
+			targetPath:  path,
+		})
+	}
+
+	return candidates
+}
+
+// serveWithFailover tries candidates in order until one serves the request or the attempt cap is reached
+func (h *ProxyHandler) serveWithFailover(c *gin.Context, candidates []upstreamCandidate) {
+	body, replayable, err := h.bufferBody(c)
+	if err != nil {
+		h.logger.Error("Failed to read request body", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	// nextRoute resolves the first candidate from an index that has a route
+	nextRoute := func(from int) (int, *Route) {
+		for i := from; i < len(candidates); i++ {
+			if route := h.resolveRoute(c, candidates[i]); route != nil {
+				return i, route
+			}
+		}
+		return -1, nil
+	}
+
+	attempts := 0
+	i, route := nextRoute(0)
+	for route != nil {
+		candidate := candidates[i]
+		if replayable {
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		attempts++
+
+		// The next candidate is only resolved once this attempt fails, so an attempt is the last
+		// one exactly when no later candidate resolves; its response is then passed through
+		canRetry := attempts < h.failover.MaxAttempts && replayable
+		nextIndex, next, resolved := -1, (*Route)(nil), false
+		hasNext := func() bool {
+			if canRetry && !resolved {
+				nextIndex, next = nextRoute(i + 1)
+				resolved = true
+			}
+			return next != nil
+		}
+
+		if h.proxyTo(c, route, candidate, attempts, hasNext) {
+			return
+		}
+		if !hasNext() {
+			break
+		}
+
+		h.logger.Warn("Upstream failed, failing over",
+			zap.String("tenant_id", candidate.tenantID),
+			zap.String("service", candidate.serviceName),
+			zap.String("level", candidate.level),
+			zap.Int("attempt", attempts))
+		i, route = nextIndex, next
+	}
+
+	c.Header(HeaderAttempts, strconv.Itoa(attempts))
+	if attempts == 0 {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "No upstream service available"})
+		return
+	}
+	c.AbortWithStatusJSON(http.StatusBadGateway, gin.H{"error": "All upstream services failed"})
+}
+
+// bufferBody reads the request body into memory so it can be replayed on failover.
+// Bodies above MaxBodyBytes are streamed through untouched and disable replay.
+func (h *ProxyHandler) bufferBody(c *gin.Context) ([]byte, bool, error) {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return nil, true, nil
+	}
+
+	original := c.Request.Body
+	buf, err := io.ReadAll(io.LimitReader(original, h.failover.MaxBodyBytes+1))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if int64(len(buf)) > h.failover.MaxBodyBytes {
+		c.Request.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(buf), original), original}
+		return nil, false, nil
+	}
+
+	original.Close()
+	return buf, true, nil
+}
+
+// resolveRoute resolves the upstream route of a candidate
+func (h *ProxyHandler) resolveRoute(c *gin.Context, candidate upstreamCandidate) *Route {
+	if candidate.url != "" {
+		// Fixed URLs come from the tenant config and have no per-endpoint circuit
+		return &Route{URL: candidate.url, IsDefault: true}
+	}
+
+	route, err := h.resolver.Resolve(c.Request.Context(), candidate.tenantID, candidate.serviceName)
+	if err != nil {
+		h.logger.Error("Failed to resolve service route",
+			zap.Error(err),
+			zap.String("tenant_id", candidate.tenantID),
+			zap.String("service", candidate.serviceName),
+			zap.String("level", candidate.level))
+		return nil
+	}
+
+	return route
+}
+
+// proxyTo forwards the request to a route and reports whether a response was written.
+// Transport errors, and retryable statuses while hasNext reports another candidate, leave
+// the response untouched so the caller can try the next candidate.
+func (h *ProxyHandler) proxyTo(c *gin.Context, route *Route, candidate upstreamCandidate, attempt int, hasNext func() bool) bool {
+	if route.release != nil {
+		defer route.release()
+	}
+
+	target := strings.TrimRight(route.URL, "/") + candidate.targetPath
+	remote, err := url.Parse(target)
+	if err != nil {
+		h.logger.Error("Failed to parse target URL", zap.Error(err), zap.String("target", target))
+		// Settle the circuit probe the route may hold, since no request is made
+		h.reportOutcome(c, route, candidate, false)
+		return false
+	}
+
+	served := true
+	proxy := httputil.NewSingleHostReverseProxy(remote)
+	proxy.Director = func(req *http.Request) {
+		req.Header = c.Request.Header.Clone()
+		for name, value := range route.Headers {
+			req.Header.Set(name, value)
+		}
+		req.Host = remote.Host
+		req.URL.Scheme = remote.Scheme
+		req.URL.Host = remote.Host
+		req.URL.Path = remote.Path
+	}
+
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		h.reportOutcome(c, route, candidate, resp.StatusCode < http.StatusInternalServerError)
+		if h.isRetryableStatus(resp.StatusCode) && hasNext() {
+			return errRetryableStatus
+		}
+		resp.Header.Set(HeaderFallbackLevel, candidate.level)
+		if candidate.serviceName != "" {
+			resp.Header.Set(HeaderUpstreamService, candidate.serviceName)
+		}
+		resp.Header.Set(HeaderAttempts, strconv.Itoa(attempt))
+		return nil
+	}
+
+	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		h.logger.Error("Proxy error", zap.Error(err), zap.String("target", target))
+		if !errors.Is(err, errRetryableStatus) {
+			h.reportOutcome(c, route, candidate, false)
+		}
+		served = false
+	}
+
+	req := c.Request
+	if route.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), route.Timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	proxy.ServeHTTP(c.Writer, req)
+	return served
+}
+
+// reportOutcome reports the result of an upstream request to the resolver, if it tracks outcomes.
+// Default routes are not reported since they have no per-endpoint circuit.
+func (h *ProxyHandler) reportOutcome(c *gin.Context, route *Route, candidate upstreamCandidate, success bool) {
+	reporter, ok := h.resolver.(OutcomeReporter)
+	if !ok || route.IsDefault {
+		return
+	}
+	reporter.ReportOutcome(c.Request.Context(), route.ConfigTenantID, candidate.serviceName, route.URL, success)
+}
+
+// isRetryableStatus checks if an upstream status should trigger failover
+func (h *ProxyHandler) isRetryableStatus(statusCode int) bool {
+	for _, code := range h.failover.RetryStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/service"
+	pb "github.com/vhvplatform/go-tenant-service/proto"
+)
+
+// Service names the gateway routes to for non-API paths
+const (
+	PageServiceSuffix = "-web" // /page/{service}/... is served by "{service}-web"
+	UploadServiceName = "file"
+	SlugServiceName   = domain.ServiceCMS
+)
+
+// Route is a resolved upstream target for a tenant and service
+type Route struct {
+	URL       string
+	Headers   map[string]string
+	Timeout   time.Duration
+	IsDefault bool
+	// ConfigTenantID is the tenant whose service config resolved the route. It differs from the
+	// requesting tenant for configs inherited from a parent, and keys the endpoint's circuit breaker.
+	ConfigTenantID string
+
+	release func() // Completes the in-flight request the resolver recorded for the route, if any
+}
+
+// ServiceResolver resolves the upstream route for a tenant's service
+type ServiceResolver interface {
+	Resolve(ctx context.Context, tenantID, serviceName string) (*Route, error)
+}
+
+// OutcomeReporter receives the outcome of proxied requests, e.g. to drive circuit breakers.
+// tenantID is the Route's ConfigTenantID.
+type OutcomeReporter interface {
+	ReportOutcome(ctx context.Context, tenantID, serviceName, url string, success bool)
+}
+
+// RegistryResolver resolves routes through an in-process ServiceRegistry
+type RegistryResolver struct {
+	registry *service.ServiceRegistry
+}
+
+// NewRegistryResolver creates a resolver backed by an in-process registry
+func NewRegistryResolver(registry *service.ServiceRegistry) *RegistryResolver {
+	return &RegistryResolver{registry: registry}
+}
+
+// Resolve resolves the route using the registry's load balancing and fallback rules.
+// The request is counted against the selected endpoint until the route is released.
+func (r *RegistryResolver) Resolve(ctx context.Context, tenantID, serviceName string) (*Route, error) {
+	result, err := r.registry.AcquireServiceURL(ctx, tenantID, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success || result.ResolvedURL == "" {
+		return nil, fmt.Errorf("failed to resolve %s for tenant %s: %s", serviceName, tenantID, result.Error)
+	}
+
+	route := &Route{
+		URL:            result.ResolvedURL,
+		IsDefault:      result.IsDefault,
+		ConfigTenantID: result.ConfigTenantID(),
+		release:        func() { r.registry.ReleaseServiceURL(result) },
+	}
+	if result.UsedEndpoint != nil {
+		route.Headers = result.UsedEndpoint.Headers
+		route.Timeout = time.Duration(result.UsedEndpoint.Timeout) * time.Second
+	}
+
+	return route, nil
+}
+
+// ReportOutcome feeds a live request outcome into the registry's circuit breakers
+func (r *RegistryResolver) ReportOutcome(ctx context.Context, tenantID, serviceName, url string, success bool) {
+	r.registry.ReportOutcome(ctx, tenantID, serviceName, url, success)
+}
+
+// grpcReportTimeout bounds the RPCs that report outcomes and connections to the tenant service
+const grpcReportTimeout = 2 * time.Second
+
+// GRPCResolver resolves routes through the tenant service's GetServiceURL RPC
+type GRPCResolver struct {
+	client pb.TenantServiceClient
+}
+
+// NewGRPCResolver creates a resolver backed by the tenant service gRPC API
+func NewGRPCResolver(client pb.TenantServiceClient) *GRPCResolver {
+	return &GRPCResolver{client: client}
+}
+
+// Resolve resolves the route remotely; load balancing happens in the tenant service, which
+// counts the request against the selected endpoint until the route is released
+func (r *GRPCResolver) Resolve(ctx context.Context, tenantID, serviceName string) (*Route, error) {
+	resp, err := r.client.GetServiceURL(ctx, &pb.GetServiceURLRequest{
+		TenantId:          tenantID,
+		ServiceName:       serviceName,
+		AcquireConnection: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success || resp.Url == "" {
+		return nil, fmt.Errorf("failed to resolve %s for tenant %s: %s", serviceName, tenantID, resp.Error)
+	}
+
+	route := &Route{
+		URL:            resp.Url,
+		IsDefault:      resp.IsDefault,
+		ConfigTenantID: tenantID,
+	}
+	if resp.InheritedFrom != "" {
+		route.ConfigTenantID = resp.InheritedFrom
+	}
+	if !resp.IsDefault {
+		route.release = func() { r.release(route.ConfigTenantID, serviceName, resp.Url) }
+	}
+	if resp.Endpoint != nil {
+		route.Headers = resp.Endpoint.Headers
+		route.Timeout = time.Duration(resp.Endpoint.Timeout) * time.Second
+	}
+
+	return route, nil
+}
+
+// ReportOutcome feeds a live request outcome into the tenant service's circuit breakers.
+// Reports are best effort and outlive the client request, so a half-open probe is always settled.
+func (r *GRPCResolver) ReportOutcome(ctx context.Context, tenantID, serviceName, url string, success bool) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), grpcReportTimeout)
+	defer cancel()
+	r.client.ReportServiceOutcome(ctx, &pb.ReportServiceOutcomeRequest{
+		TenantId:    tenantID,
+		ServiceName: serviceName,
+		Url:         url,
+		Success:     success,
+	})
+}
+
+// release completes an in-flight request in the tenant service
+func (r *GRPCResolver) release(tenantID, serviceName, url string) {
+	ctx, cancel := context.WithTimeout(context.Background(), grpcReportTimeout)
+	defer cancel()
+	r.client.ReleaseServiceConnection(ctx, &pb.ServiceConnectionRequest{TenantId: tenantID, ServiceName: serviceName, Url: url})
+}
@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"context"
+	"crypto/x509"
+	"time"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Metadata keys identifying the caller of a request
+const (
+	internalTokenMetadataKey = "x-internal-token"
+	userIDMetadataKey        = "x-user-id"
+)
+
+// ActorInterceptor stores the actor of a request in its context so changes are attributed
+// in the audit log. The actor is taken from an internal token signed with the key, or else
+// from the mTLS client certificate: the service named by its common name, or the user that
+// authenticated service names in the request metadata. Other requests are anonymous.
+func ActorInterceptor(internalTokenKey []byte) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		actor := domain.RequestActor("", "")
+		if token := firstMetadataValue(ctx, internalTokenMetadataKey); token != "" {
+			if verified, err := domain.ParseInternalToken(internalTokenKey, token, time.Now()); err == nil {
+				actor = verified
+			}
+		} else if cert := peerCertificate(ctx); cert != nil {
+			actor = domain.RequestActor(firstMetadataValue(ctx, userIDMetadataKey), cert.Subject.CommonName)
+		}
+
+		ctx = domain.ContextWithActor(ctx, actor)
+		return handler(ctx, req)
+	}
+}
+
+// firstMetadataValue returns the first value of an incoming metadata key
+func firstMetadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// peerCertificate returns the verified client certificate of an mTLS connection
+func peerCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return nil
+	}
+	return tlsInfo.State.PeerCertificates[0]
+}
@@ -1,347 +1,1655 @@
-package grpc
-
-import (
-	"context"
-	"time"
-
-	"github.com/vhvplatform/go-shared/logger"
-	"github.com/vhvplatform/go-tenant-service/internal/domain"
-	"github.com/vhvplatform/go-tenant-service/internal/service"
-	pb "github.com/vhvplatform/go-tenant-service/proto"
-	"go.uber.org/zap"
-)
-
-// TenantServiceServer implements the gRPC tenant service
-type TenantServiceServer struct {
-	pb.UnimplementedTenantServiceServer
-	tenantService   *service.TenantService
-	registryService *service.ServiceRegistry
-	logger          *logger.Logger
-}
-
-// NewTenantServiceServer creates a new gRPC tenant service server
-func NewTenantServiceServer(tenantService *service.TenantService, registryService *service.ServiceRegistry, log *logger.Logger) *TenantServiceServer {
-	return &TenantServiceServer{
-		tenantService:   tenantService,
-		registryService: registryService,
-		logger:          log,
-	}
-}
-
-// GetTenant retrieves a tenant by ID
-func (s *TenantServiceServer) GetTenant(ctx context.Context, req *pb.GetTenantRequest) (*pb.GetTenantResponse, error) {
-	tenant, err := s.tenantService.GetTenant(ctx, req.TenantId)
-	if err != nil {
-		s.logger.Error("Failed to get tenant", zap.Error(err))
-		return nil, err
-	}
-
-	return &pb.GetTenantResponse{
-		Tenant: s.toProtoTenant(tenant),
-	}, nil
-}
-
-// ListTenants lists all tenants
-func (s *TenantServiceServer) ListTenants(ctx context.Context, req *pb.ListTenantsRequest) (*pb.ListTenantsResponse, error) {
-	page := int(req.Page)
-	pageSize := int(req.PageSize)
-
-	tenants, total, err := s.tenantService.ListTenants(ctx, page, pageSize)
-	if err != nil {
-		s.logger.Error("Failed to list tenants", zap.Error(err))
-		return nil, err
-	}
-
-	protoTenants := make([]*pb.Tenant, len(tenants))
-	for i, tenant := range tenants {
-		protoTenants[i] = s.toProtoTenant(tenant)
-	}
-
-	return &pb.ListTenantsResponse{
-		Tenants: protoTenants,
-		Total:   int32(total),
-	}, nil
-}
-
-// CreateTenant creates a new tenant
-func (s *TenantServiceServer) CreateTenant(ctx context.Context, req *pb.CreateTenantRequest) (*pb.CreateTenantResponse, error) {
-	createReq := &domain.CreateTenantRequest{
-		Name:             req.Name,
-		Domain:           req.Domain,
-		SubscriptionTier: req.SubscriptionTier,
-	}
-
-	tenant, err := s.tenantService.CreateTenant(ctx, createReq)
-	if err != nil {
-		s.logger.Error("Failed to create tenant", zap.Error(err))
-		return nil, err
-	}
-
-	return &pb.CreateTenantResponse{
-		Tenant: s.toProtoTenant(tenant),
-	}, nil
-}
-
-// UpdateTenant updates a tenant
-func (s *TenantServiceServer) UpdateTenant(ctx context.Context, req *pb.UpdateTenantRequest) (*pb.UpdateTenantResponse, error) {
-	updateReq := &domain.UpdateTenantRequest{
-		Name:             req.Name,
-		Domain:           req.Domain,
-		SubscriptionTier: req.SubscriptionTier,
-	}
-
-	tenant, err := s.tenantService.UpdateTenant(ctx, req.TenantId, updateReq)
-	if err != nil {
-		s.logger.Error("Failed to update tenant", zap.Error(err))
-		return nil, err
-	}
-
-	return &pb.UpdateTenantResponse{
-		Tenant: s.toProtoTenant(tenant),
-	}, nil
-}
-
-// DeleteTenant deletes a tenant
-func (s *TenantServiceServer) DeleteTenant(ctx context.Context, req *pb.DeleteTenantRequest) (*pb.DeleteTenantResponse, error) {
-	err := s.tenantService.DeleteTenant(ctx, req.TenantId)
-	if err != nil {
-		s.logger.Error("Failed to delete tenant", zap.Error(err))
-		return nil, err
-	}
-
-	return &pb.DeleteTenantResponse{
-		Success: true,
-	}, nil
-}
-
-// AddUserToTenant adds a user to a tenant
-func (s *TenantServiceServer) AddUserToTenant(ctx context.Context, req *pb.AddUserToTenantRequest) (*pb.AddUserToTenantResponse, error) {
-	err := s.tenantService.AddUserToTenant(ctx, req.TenantId, req.UserId, req.Role)
-	if err != nil {
-		s.logger.Error("Failed to add user to tenant", zap.Error(err))
-		return nil, err
-	}
-
-	return &pb.AddUserToTenantResponse{
-		Success: true,
-	}, nil
-}
-
-// RemoveUserFromTenant removes a user from a tenant
-func (s *TenantServiceServer) RemoveUserFromTenant(ctx context.Context, req *pb.RemoveUserFromTenantRequest) (*pb.RemoveUserFromTenantResponse, error) {
-	err := s.tenantService.RemoveUserFromTenant(ctx, req.TenantId, req.UserId)
-	if err != nil {
-		s.logger.Error("Failed to remove user from tenant", zap.Error(err))
-		return nil, err
-	}
-
-	return &pb.RemoveUserFromTenantResponse{
-		Success: true,
-	}, nil
-}
-
-func (s *TenantServiceServer) toProtoTenant(tenant *domain.Tenant) *pb.Tenant {
-	return &pb.Tenant{
-		Id:               tenant.ID.Hex(),
-		Name:             tenant.Name,
-		Domain:           tenant.Domain,
-		SubscriptionTier: tenant.SubscriptionTier,
-		IsActive:         tenant.IsActive,
-		CreatedAt:        tenant.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:        tenant.UpdatedAt.Format(time.RFC3339),
-	}
-}
-
-// === Service Registry Handlers ===
-
-// GetServiceConfig gets service configuration for a tenant
-func (s *TenantServiceServer) GetServiceConfig(ctx context.Context, req *pb.GetServiceConfigRequest) (*pb.GetServiceConfigResponse, error) {
-	config, err := s.registryService.GetServiceConfig(ctx, req.TenantId, req.ServiceName)
-	if err != nil {
-		s.logger.Error("Failed to get service config", zap.Error(err))
-		return nil, err
-	}
-
-	return &pb.GetServiceConfigResponse{
-		Config: s.toProtoServiceConfig(config),
-	}, nil
-}
-
-// UpdateServiceConfig creates or updates service configuration for a tenant
-func (s *TenantServiceServer) UpdateServiceConfig(ctx context.Context, req *pb.UpdateServiceConfigRequest) (*pb.UpdateServiceConfigResponse, error) {
-	config := s.fromProtoServiceConfig(req.Config)
-	config.TenantID = req.TenantId
-	config.ServiceName = req.ServiceName
-
-	err := s.registryService.CreateOrUpdateServiceConfig(ctx, config)
-	if err != nil {
-		s.logger.Error("Failed to update service config", zap.Error(err))
-		return nil, err
-	}
-
-	return &pb.UpdateServiceConfigResponse{
-		Config: s.toProtoServiceConfig(config),
-	}, nil
-}
-
-// GetServiceURL resolves the service URL for a tenant
-func (s *TenantServiceServer) GetServiceURL(ctx context.Context, req *pb.GetServiceURLRequest) (*pb.GetServiceURLResponse, error) {
-	result, err := s.registryService.GetServiceURL(ctx, req.TenantId, req.ServiceName)
-	if err != nil {
-		s.logger.Error("Failed to get service URL", zap.Error(err))
-		return &pb.GetServiceURLResponse{
-			Success: false,
-			Error:   err.Error(),
-		}, nil
-	}
-
-	return &pb.GetServiceURLResponse{
-		Url:           result.ResolvedURL,
-		IsDefault:     result.IsDefault,
-		Success:       result.Success,
-		Error:         result.Error,
-		AttemptedUrls: result.AttemptedURLs,
-	}, nil
-}
-
-// ListTenantServices lists all service configurations for a tenant
-func (s *TenantServiceServer) ListTenantServices(ctx context.Context, req *pb.ListTenantServicesRequest) (*pb.ListTenantServicesResponse, error) {
-	configs, err := s.registryService.GetTenantServices(ctx, req.TenantId)
-	if err != nil {
-		s.logger.Error("Failed to list tenant services", zap.Error(err))
-		return nil, err
-	}
-
-	protoConfigs := make([]*pb.ServiceConfig, len(configs))
-	for i, config := range configs {
-		protoConfigs[i] = s.toProtoServiceConfig(config)
-	}
-
-	return &pb.ListTenantServicesResponse{
-		Services: protoConfigs,
-	}, nil
-}
-
-// GetServiceHealth gets health status for a service
-func (s *TenantServiceServer) GetServiceHealth(ctx context.Context, req *pb.GetServiceHealthRequest) (*pb.GetServiceHealthResponse, error) {
-	config, err := s.registryService.GetServiceConfig(ctx, req.TenantId, req.ServiceName)
-	if err != nil {
-		s.logger.Error("Failed to get service config for health", zap.Error(err))
-		return nil, err
-	}
-
-	healths := []*pb.ServiceHealth{}
-
-	// Primary endpoint health
-	primaryStatus := s.registryService.GetHealthStatus(req.TenantId, req.ServiceName, config.PrimaryEndpoint.URL)
-	healths = append(healths, s.toProtoServiceHealth(primaryStatus))
-
-	// Fallback chain health
-	for _, endpoint := range config.FallbackChain {
-		status := s.registryService.GetHealthStatus(req.TenantId, req.ServiceName, endpoint.URL)
-		healths = append(healths, s.toProtoServiceHealth(status))
-	}
-
-	return &pb.GetServiceHealthResponse{
-		Healths: healths,
-	}, nil
-}
-
-// === Proto Conversion Helpers ===
-
-func (s *TenantServiceServer) toProtoServiceConfig(config *domain.ServiceConfig) *pb.ServiceConfig {
-	fallbackChain := make([]*pb.ServiceEndpoint, len(config.FallbackChain))
-	for i, endpoint := range config.FallbackChain {
-		fallbackChain[i] = s.toProtoServiceEndpoint(&endpoint)
-	}
-
-	return &pb.ServiceConfig{
-		Id:                  config.ID.Hex(),
-		TenantId:            config.TenantID,
-		ServiceName:         config.ServiceName,
-		PrimaryEndpoint:     s.toProtoServiceEndpoint(&config.PrimaryEndpoint),
-		FallbackChain:       fallbackChain,
-		DefaultServiceUrl:   config.DefaultServiceURL,
-		HealthCheck:         s.toProtoHealthCheck(&config.HealthCheck),
-		LoadBalanceStrategy: config.LoadBalanceStrategy,
-		IsActive:            config.IsActive,
-		Metadata:            config.Metadata,
-		CreatedAt:           config.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:           config.UpdatedAt.Format(time.RFC3339),
-	}
-}
-
-func (s *TenantServiceServer) toProtoServiceEndpoint(endpoint *domain.ServiceEndpoint) *pb.ServiceEndpoint {
-	return &pb.ServiceEndpoint{
-		Url:      endpoint.URL,
-		Priority: int32(endpoint.Priority),
-		Weight:   int32(endpoint.Weight),
-		Timeout:  int32(endpoint.Timeout),
-		Headers:  endpoint.Headers,
-		IsActive: endpoint.IsActive,
-	}
-}
-
-func (s *TenantServiceServer) toProtoHealthCheck(config *domain.HealthCheckConfig) *pb.HealthCheckConfig {
-	return &pb.HealthCheckConfig{
-		Enabled:       config.Enabled,
-		Path:          config.Path,
-		Method:        config.Method,
-		Interval:      int32(config.Interval),
-		Timeout:       int32(config.Timeout),
-		FailThreshold: int32(config.FailThreshold),
-	}
-}
-
-func (s *TenantServiceServer) toProtoServiceHealth(status *domain.ServiceStatus) *pb.ServiceHealth {
-	return &pb.ServiceHealth{
-		EndpointUrl:      status.EndpointURL,
-		IsHealthy:        status.IsHealthy,
-		LastChecked:      status.LastChecked.Format(time.RFC3339),
-		LastSuccessful:   status.LastSuccessful.Format(time.RFC3339),
-		LastFailure:      status.LastFailure.Format(time.RFC3339),
-		ConsecutiveFails: int32(status.ConsecutiveFails),
-		LastError:        status.LastError,
-	}
-}
-
-func (s *TenantServiceServer) fromProtoServiceConfig(proto *pb.ServiceConfig) *domain.ServiceConfig {
-	fallbackChain := make([]domain.ServiceEndpoint, len(proto.FallbackChain))
-	for i, endpoint := range proto.FallbackChain {
-		fallbackChain[i] = *s.fromProtoServiceEndpoint(endpoint)
-	}
-
-	return &domain.ServiceConfig{
-		TenantID:            proto.TenantId,
-		ServiceName:         proto.ServiceName,
-		PrimaryEndpoint:     *s.fromProtoServiceEndpoint(proto.PrimaryEndpoint),
-		FallbackChain:       fallbackChain,
-		DefaultServiceURL:   proto.DefaultServiceUrl,
-		HealthCheck:         *s.fromProtoHealthCheck(proto.HealthCheck),
-		LoadBalanceStrategy: proto.LoadBalanceStrategy,
-		IsActive:            proto.IsActive,
-		Metadata:            proto.Metadata,
-	}
-}
-
-func (s *TenantServiceServer) fromProtoServiceEndpoint(proto *pb.ServiceEndpoint) *domain.ServiceEndpoint {
-	return &domain.ServiceEndpoint{
-		URL:      proto.Url,
-		Priority: int(proto.Priority),
-		Weight:   int(proto.Weight),
-		Timeout:  int(proto.Timeout),
-		Headers:  proto.Headers,
-		IsActive: proto.IsActive,
-	}
-}
-
-func (s *TenantServiceServer) fromProtoHealthCheck(proto *pb.HealthCheckConfig) *domain.HealthCheckConfig {
-	return &domain.HealthCheckConfig{
-		Enabled:       proto.Enabled,
-		Path:          proto.Path,
-		Method:        proto.Method,
-		Interval:      int(proto.Interval),
-		Timeout:       int(proto.Timeout),
-		FailThreshold: int(proto.FailThreshold),
-	}
-}
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/vhvplatform/go-shared/errors"
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/service"
+	pb "github.com/vhvplatform/go-tenant-service/proto"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// TenantServiceServer implements the gRPC tenant service
+type TenantServiceServer struct {
+	pb.UnimplementedTenantServiceServer
+	tenantService      *service.TenantService
+	registryService    *service.ServiceRegistry
+	domainService      *service.DomainService
+	purgeService       *service.PurgeService
+	roleService        *service.RoleService
+	invitationService  *service.InvitationService
+	entitlementService *service.EntitlementService
+	planService        *service.PlanService
+	trialService       *service.TrialService
+	settingsService    *service.SettingsService
+	auditService       *service.AuditService
+	logger             *logger.Logger
+}
+
+// NewTenantServiceServer creates a new gRPC tenant service server
+func NewTenantServiceServer(
+	tenantService *service.TenantService,
+	registryService *service.ServiceRegistry,
+	domainService *service.DomainService,
+	purgeService *service.PurgeService,
+	roleService *service.RoleService,
+	invitationService *service.InvitationService,
+	entitlementService *service.EntitlementService,
+	planService *service.PlanService,
+	trialService *service.TrialService,
+	settingsService *service.SettingsService,
+	auditService *service.AuditService,
+	log *logger.Logger,
+) *TenantServiceServer {
+	return &TenantServiceServer{
+		tenantService:      tenantService,
+		registryService:    registryService,
+		domainService:      domainService,
+		purgeService:       purgeService,
+		roleService:        roleService,
+		invitationService:  invitationService,
+		entitlementService: entitlementService,
+		planService:        planService,
+		trialService:       trialService,
+		settingsService:    settingsService,
+		auditService:       auditService,
+		logger:             log,
+	}
+}
+
+// GetTenant retrieves a tenant by ID
+func (s *TenantServiceServer) GetTenant(ctx context.Context, req *pb.GetTenantRequest) (*pb.GetTenantResponse, error) {
+	tenant, err := s.tenantService.GetTenant(ctx, req.TenantId)
+	if err != nil {
+		s.logger.Error("Failed to get tenant", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.GetTenantResponse{
+		Tenant: s.toProtoTenant(tenant),
+	}, nil
+}
+
+// ListTenants lists a page of the tenants matching the request filters
+func (s *TenantServiceServer) ListTenants(ctx context.Context, req *pb.ListTenantsRequest) (*pb.ListTenantsResponse, error) {
+	page := domain.PageRequest{
+		PageToken:    req.PageToken,
+		Page:         int(req.Page),
+		PageSize:     int(req.PageSize),
+		IncludeTotal: req.IncludeTotal,
+	}
+	filter, err := s.fromProtoTenantFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	tenants, info, err := s.tenantService.ListTenants(ctx, filter, page)
+	if err != nil {
+		s.logger.Error("Failed to list tenants", zap.Error(err))
+		return nil, err
+	}
+
+	protoTenants := make([]*pb.Tenant, len(tenants))
+	for i, tenant := range tenants {
+		protoTenants[i] = s.toProtoTenant(tenant)
+	}
+
+	return &pb.ListTenantsResponse{
+		Tenants:       protoTenants,
+		Total:         pageTotal(info),
+		NextPageToken: info.NextPageToken,
+		PrevPageToken: info.PrevPageToken,
+	}, nil
+}
+
+// pageTotal returns the total of a listing, or zero when it was not requested
+func pageTotal(info *domain.PageInfo) int32 {
+	if info.Total == nil {
+		return 0
+	}
+	return int32(*info.Total)
+}
+
+// fromProtoTenantFilter converts the filters and sort options of a tenant listing request
+func (s *TenantServiceServer) fromProtoTenantFilter(req *pb.ListTenantsRequest) (domain.TenantFilter, error) {
+	filter := domain.TenantFilter{
+		Name:             req.Name,
+		NamePrefix:       req.NamePrefix,
+		Domain:           req.Domain,
+		SubscriptionTier: req.SubscriptionTier,
+		Status:           req.Status,
+		IsActive:         req.IsActive,
+		SettingsKey:      req.SettingsKey,
+		Search:           req.Search,
+		SortBy:           req.SortBy,
+		SortOrder:        req.SortOrder,
+	}
+
+	if req.CreatedAfter != "" {
+		createdAfter, err := time.Parse(time.RFC3339, req.CreatedAfter)
+		if err != nil {
+			return filter, errors.BadRequest("created_after must be an RFC 3339 time")
+		}
+		filter.CreatedAfter = &createdAfter
+	}
+	if req.CreatedBefore != "" {
+		createdBefore, err := time.Parse(time.RFC3339, req.CreatedBefore)
+		if err != nil {
+			return filter, errors.BadRequest("created_before must be an RFC 3339 time")
+		}
+		filter.CreatedBefore = &createdBefore
+	}
+
+	return filter, nil
+}
+
+// CreateTenant creates a new tenant
+func (s *TenantServiceServer) CreateTenant(ctx context.Context, req *pb.CreateTenantRequest) (*pb.CreateTenantResponse, error) {
+	createReq := &domain.CreateTenantRequest{
+		Name:             req.Name,
+		Domain:           req.Domain,
+		Subdomain:        req.Subdomain,
+		SubscriptionTier: req.SubscriptionTier,
+		ParentID:         req.ParentId,
+	}
+
+	tenant, err := s.tenantService.CreateTenant(ctx, createReq)
+	if err != nil {
+		s.logger.Error("Failed to create tenant", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.CreateTenantResponse{
+		Tenant: s.toProtoTenant(tenant),
+	}, nil
+}
+
+// UpdateTenant updates a tenant. With an update mask, exactly the listed fields change and
+// listed fields left unset are cleared; without one, only non-empty fields are applied.
+func (s *TenantServiceServer) UpdateTenant(ctx context.Context, req *pb.UpdateTenantRequest) (*pb.UpdateTenantResponse, error) {
+	if req.UpdateMask != nil {
+		tenant, err := s.tenantService.PatchTenant(ctx, req.TenantId, s.fromProtoTenantPatch(req))
+		if err != nil {
+			s.logger.Error("Failed to update tenant", zap.Error(err))
+			return nil, err
+		}
+		return &pb.UpdateTenantResponse{Tenant: s.toProtoTenant(tenant)}, nil
+	}
+
+	updateReq := &domain.UpdateTenantRequest{
+		Name:             req.Name,
+		Domain:           req.Domain,
+		Subdomain:        req.Subdomain,
+		SubscriptionTier: req.SubscriptionTier,
+		ExpectedVersion:  req.ExpectedVersion,
+	}
+
+	tenant, err := s.tenantService.UpdateTenant(ctx, req.TenantId, updateReq)
+	if err != nil {
+		s.logger.Error("Failed to update tenant", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.UpdateTenantResponse{
+		Tenant: s.toProtoTenant(tenant),
+	}, nil
+}
+
+// DeleteTenant deletes a tenant
+func (s *TenantServiceServer) DeleteTenant(ctx context.Context, req *pb.DeleteTenantRequest) (*pb.DeleteTenantResponse, error) {
+	statusReq := &domain.TenantStatusRequest{Reason: req.Reason}
+	tenant, err := s.purgeService.ScheduleDeletion(ctx, req.TenantId, statusReq)
+	if err != nil {
+		s.logger.Error("Failed to delete tenant", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.DeleteTenantResponse{
+		Success:    true,
+		PurgeAfter: formatOptionalTime(tenant.PurgeAfter),
+	}, nil
+}
+
+// CancelTenantDeletion cancels the scheduled deletion of a tenant
+func (s *TenantServiceServer) CancelTenantDeletion(ctx context.Context, req *pb.CancelTenantDeletionRequest) (*pb.CancelTenantDeletionResponse, error) {
+	statusReq := &domain.TenantStatusRequest{Reason: req.Reason}
+	tenant, err := s.purgeService.CancelDeletion(ctx, req.TenantId, statusReq)
+	if err != nil {
+		s.logger.Error("Failed to cancel tenant deletion", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.CancelTenantDeletionResponse{
+		Tenant: s.toProtoTenant(tenant),
+	}, nil
+}
+
+// PurgeTenant purges all data of a tenant immediately
+func (s *TenantServiceServer) PurgeTenant(ctx context.Context, req *pb.PurgeTenantRequest) (*pb.PurgeTenantResponse, error) {
+	statusReq := &domain.TenantStatusRequest{Reason: req.Reason}
+	report, err := s.purgeService.PurgeTenant(ctx, req.TenantId, statusReq)
+	if err != nil {
+		s.logger.Error("Failed to purge tenant", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.PurgeTenantResponse{
+		Report: s.toProtoPurgeReport(report),
+	}, nil
+}
+
+// GetPurgeReport returns the latest purge report of a tenant
+func (s *TenantServiceServer) GetPurgeReport(ctx context.Context, req *pb.GetPurgeReportRequest) (*pb.GetPurgeReportResponse, error) {
+	report, err := s.purgeService.GetPurgeReport(ctx, req.TenantId)
+	if err != nil {
+		s.logger.Error("Failed to get purge report", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.GetPurgeReportResponse{
+		Report: s.toProtoPurgeReport(report),
+	}, nil
+}
+
+// toProtoPurgeReport converts a purge report to protobuf
+func (s *TenantServiceServer) toProtoPurgeReport(report *domain.PurgeReport) *pb.PurgeReport {
+	return &pb.PurgeReport{
+		Id:            report.ID.Hex(),
+		TenantId:      report.TenantID,
+		TenantName:    report.TenantName,
+		Status:        report.Status,
+		Reason:        report.Reason,
+		Actor:         report.Actor,
+		PurgeAfter:    formatOptionalTime(report.PurgeAfter),
+		DeletedCounts: report.DeletedCounts,
+		Errors:        report.Errors,
+		StartedAt:     report.StartedAt.Format(time.RFC3339),
+		CompletedAt:   report.CompletedAt.Format(time.RFC3339),
+	}
+}
+
+// fromProtoTenantPatch converts a masked tenant update to a patch
+func (s *TenantServiceServer) fromProtoTenantPatch(req *pb.UpdateTenantRequest) *domain.TenantPatch {
+	patch := &domain.TenantPatch{
+		Paths:           req.UpdateMask.GetPaths(),
+		Name:            req.Name,
+		Domain:          req.Domain,
+		Subdomain:       req.Subdomain,
+		DefaultService:  req.DefaultService,
+		ExpectedVersion: req.ExpectedVersion,
+	}
+	if req.AuthSettings != nil {
+		patch.AuthSettings = &domain.AuthSettings{AllowedLoginMethods: req.AuthSettings.AllowedLoginMethods}
+	}
+	if req.Settings != nil {
+		patch.Settings = req.Settings.AsMap()
+	}
+	return patch
+}
+
+// toProtoSettings converts tenant settings to a protobuf struct. Settings are normalized
+// through JSON, since stored values may use BSON types a struct cannot hold.
+func toProtoSettings(settings map[string]interface{}) *structpb.Struct {
+	if len(settings) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil
+	}
+	protoSettings, err := structpb.NewStruct(normalized)
+	if err != nil {
+		return nil
+	}
+	return protoSettings
+}
+
+// formatOptionalTime formats a time that may be unset, returning "" when it is
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// SuspendTenant suspends a tenant
+func (s *TenantServiceServer) SuspendTenant(ctx context.Context, req *pb.SuspendTenantRequest) (*pb.SuspendTenantResponse, error) {
+	statusReq := &domain.TenantStatusRequest{Reason: req.Reason}
+	tenant, err := s.tenantService.SuspendTenant(ctx, req.TenantId, statusReq)
+	if err != nil {
+		s.logger.Error("Failed to suspend tenant", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.SuspendTenantResponse{
+		Tenant: s.toProtoTenant(tenant),
+	}, nil
+}
+
+// ReactivateTenant reactivates a suspended tenant
+func (s *TenantServiceServer) ReactivateTenant(ctx context.Context, req *pb.ReactivateTenantRequest) (*pb.ReactivateTenantResponse, error) {
+	statusReq := &domain.TenantStatusRequest{Reason: req.Reason}
+	tenant, err := s.tenantService.ReactivateTenant(ctx, req.TenantId, statusReq)
+	if err != nil {
+		s.logger.Error("Failed to reactivate tenant", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.ReactivateTenantResponse{
+		Tenant: s.toProtoTenant(tenant),
+	}, nil
+}
+
+// AddUserToTenant adds a user to a tenant
+func (s *TenantServiceServer) AddUserToTenant(ctx context.Context, req *pb.AddUserToTenantRequest) (*pb.AddUserToTenantResponse, error) {
+	err := s.tenantService.AddUserToTenant(ctx, req.TenantId, req.UserId, req.Role)
+	if err != nil {
+		s.logger.Error("Failed to add user to tenant", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.AddUserToTenantResponse{
+		Success: true,
+	}, nil
+}
+
+// RemoveUserFromTenant removes a user from a tenant
+func (s *TenantServiceServer) RemoveUserFromTenant(ctx context.Context, req *pb.RemoveUserFromTenantRequest) (*pb.RemoveUserFromTenantResponse, error) {
+	err := s.tenantService.RemoveUserFromTenant(ctx, req.TenantId, req.UserId)
+	if err != nil {
+		s.logger.Error("Failed to remove user from tenant", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.RemoveUserFromTenantResponse{
+		Success: true,
+	}, nil
+}
+
+// ListTenantMembers lists a page of the members of a tenant
+func (s *TenantServiceServer) ListTenantMembers(ctx context.Context, req *pb.ListTenantMembersRequest) (*pb.ListTenantMembersResponse, error) {
+	filter := domain.MemberFilter{Role: req.Role, Search: req.Search}
+
+	page := domain.PageRequest{
+		PageToken:    req.PageToken,
+		Page:         int(req.Page),
+		PageSize:     int(req.PageSize),
+		IncludeTotal: req.IncludeTotal,
+	}
+
+	members, info, err := s.tenantService.ListMembers(ctx, req.TenantId, filter, page)
+	if err != nil {
+		s.logger.Error("Failed to list tenant members", zap.Error(err))
+		return nil, err
+	}
+
+	protoMembers := make([]*pb.TenantMember, len(members))
+	for i, member := range members {
+		protoMembers[i] = s.toProtoTenantMember(member)
+	}
+
+	return &pb.ListTenantMembersResponse{
+		Members:       protoMembers,
+		Total:         pageTotal(info),
+		NextPageToken: info.NextPageToken,
+		PrevPageToken: info.PrevPageToken,
+	}, nil
+}
+
+// ListUserTenants lists a page of the tenants a user belongs to
+func (s *TenantServiceServer) ListUserTenants(ctx context.Context, req *pb.ListUserTenantsRequest) (*pb.ListUserTenantsResponse, error) {
+	filter := domain.MemberFilter{Role: req.Role}
+
+	page := domain.PageRequest{
+		PageToken:    req.PageToken,
+		Page:         int(req.Page),
+		PageSize:     int(req.PageSize),
+		IncludeTotal: req.IncludeTotal,
+	}
+
+	userTenants, info, err := s.tenantService.ListUserTenants(ctx, req.UserId, filter, page)
+	if err != nil {
+		s.logger.Error("Failed to list user tenants", zap.Error(err))
+		return nil, err
+	}
+
+	protoTenants := make([]*pb.UserTenant, len(userTenants))
+	for i, userTenant := range userTenants {
+		protoTenants[i] = &pb.UserTenant{
+			TenantId: userTenant.TenantID,
+			Role:     userTenant.Role,
+			JoinedAt: userTenant.JoinedAt.Format(time.RFC3339),
+		}
+		if userTenant.Tenant != nil {
+			protoTenants[i].TenantName = userTenant.Tenant.Name
+			protoTenants[i].Subdomain = userTenant.Tenant.Subdomain
+			protoTenants[i].Status = userTenant.Tenant.CurrentStatus()
+		}
+	}
+
+	return &pb.ListUserTenantsResponse{
+		Tenants:       protoTenants,
+		Total:         pageTotal(info),
+		NextPageToken: info.NextPageToken,
+		PrevPageToken: info.PrevPageToken,
+	}, nil
+}
+
+// GetMemberHistory returns the membership history of a user in a tenant
+func (s *TenantServiceServer) GetMemberHistory(ctx context.Context, req *pb.GetMemberHistoryRequest) (*pb.GetMemberHistoryResponse, error) {
+	membership, err := s.tenantService.GetMemberHistory(ctx, req.TenantId, req.UserId)
+	if err != nil {
+		s.logger.Error("Failed to get member history", zap.Error(err))
+		return nil, err
+	}
+
+	history := make([]*pb.MembershipEvent, len(membership.History))
+	for i, event := range membership.History {
+		history[i] = &pb.MembershipEvent{
+			Type:         event.Type,
+			Role:         event.Role,
+			PreviousRole: event.PreviousRole,
+			At:           event.At.Format(time.RFC3339),
+		}
+	}
+
+	return &pb.GetMemberHistoryResponse{
+		UserId:   membership.UserID,
+		Role:     membership.Role,
+		IsActive: membership.IsActive,
+		History:  history,
+	}, nil
+}
+
+// UpdateMemberRole changes the role of a tenant member
+func (s *TenantServiceServer) UpdateMemberRole(ctx context.Context, req *pb.UpdateMemberRoleRequest) (*pb.UpdateMemberRoleResponse, error) {
+	member, err := s.tenantService.UpdateMemberRole(ctx, req.TenantId, req.UserId, req.Role)
+	if err != nil {
+		s.logger.Error("Failed to update member role", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.UpdateMemberRoleResponse{
+		Member: s.toProtoTenantMember(member),
+	}, nil
+}
+
+// TransferOwnership hands ownership of a tenant to another member
+func (s *TenantServiceServer) TransferOwnership(ctx context.Context, req *pb.TransferOwnershipRequest) (*pb.TransferOwnershipResponse, error) {
+	err := s.tenantService.TransferOwnership(ctx, req.TenantId, &domain.TransferOwnershipRequest{
+		FromUserID: req.FromUserId,
+		ToUserID:   req.ToUserId,
+		FromRole:   req.FromRole,
+	})
+	if err != nil {
+		s.logger.Error("Failed to transfer ownership", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.TransferOwnershipResponse{
+		Success: true,
+	}, nil
+}
+
+func (s *TenantServiceServer) toProtoTenantMember(member *domain.TenantUser) *pb.TenantMember {
+	return &pb.TenantMember{
+		UserId:    member.UserID,
+		Role:      member.Role,
+		JoinedAt:  member.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: member.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func (s *TenantServiceServer) toProtoTenant(tenant *domain.Tenant) *pb.Tenant {
+	return &pb.Tenant{
+		Id:               tenant.ID.Hex(),
+		Name:             tenant.Name,
+		Domain:           tenant.Domain,
+		Subdomain:        tenant.Subdomain,
+		ParentId:         tenant.ParentID,
+		SubscriptionTier: tenant.SubscriptionTier,
+		IsActive:         tenant.IsActive,
+		CreatedAt:        tenant.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        tenant.UpdatedAt.Format(time.RFC3339),
+		Config:           s.toProtoTenantConfig(&tenant.Config),
+		Status:           tenant.CurrentStatus(),
+		StatusReason:     tenant.StatusReason,
+		StatusHistory:    s.toProtoStatusHistory(tenant.StatusHistory),
+		PurgeAfter:       formatOptionalTime(tenant.PurgeAfter),
+		Trial:            s.toProtoTenantTrial(tenant.Trial),
+		Version:          tenant.Version,
+		DefaultService:   tenant.DefaultService,
+		AuthSettings:     &pb.AuthSettings{AllowedLoginMethods: tenant.AuthSettings.AllowedLoginMethods},
+		Settings:         toProtoSettings(tenant.Settings),
+	}
+}
+
+// toProtoTenantTrial converts the trial of a tenant to protobuf; nil if it never had one
+func (s *TenantServiceServer) toProtoTenantTrial(trial *domain.TenantTrial) *pb.TenantTrial {
+	if trial == nil {
+		return nil
+	}
+
+	now := time.Now()
+	protoTrial := &pb.TenantTrial{
+		Tier:          trial.Tier,
+		Status:        trial.CurrentStatus(now),
+		StartedAt:     trial.StartedAt.Format(time.RFC3339),
+		EndsAt:        trial.EndsAt.Format(time.RFC3339),
+		GraceEndsAt:   trial.GraceEndsAt.Format(time.RFC3339),
+		DaysRemaining: int32(trial.DaysRemaining(now)),
+	}
+	if !trial.EndedAt.IsZero() {
+		protoTrial.EndedAt = trial.EndedAt.Format(time.RFC3339)
+	}
+	return protoTrial
+}
+
+// toProtoStatusHistory converts the lifecycle history of a tenant to protobuf
+func (s *TenantServiceServer) toProtoStatusHistory(history []domain.TenantStatusChange) []*pb.TenantStatusChange {
+	pbHistory := make([]*pb.TenantStatusChange, 0, len(history))
+	for _, change := range history {
+		pbHistory = append(pbHistory, &pb.TenantStatusChange{
+			From:       change.From,
+			To:         change.To,
+			Reason:     change.Reason,
+			Actor:      change.Actor,
+			ChangedAt:  change.ChangedAt.Format(time.RFC3339),
+			PurgeAfter: formatOptionalTime(change.PurgeAfter),
+		})
+	}
+	return pbHistory
+}
+
+// ResolveTenantByHost finds the tenant serving a request host. The response has no
+// tenant when the host is unknown, so callers can cache the miss.
+func (s *TenantServiceServer) ResolveTenantByHost(ctx context.Context, req *pb.ResolveTenantByHostRequest) (*pb.ResolveTenantByHostResponse, error) {
+	tenant, err := s.domainService.ResolveTenantByHost(ctx, req.Host, req.BaseDomain)
+	if err != nil {
+		if errors.FromError(err).StatusCode == http.StatusNotFound {
+			return &pb.ResolveTenantByHostResponse{}, nil
+		}
+		s.logger.Error("Failed to resolve tenant by host", zap.String("host", req.Host), zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.ResolveTenantByHostResponse{
+		Tenant: s.toProtoTenant(tenant),
+	}, nil
+}
+
+// ReportServiceOutcome feeds the outcome of a request proxied by a gateway into the circuit breakers
+func (s *TenantServiceServer) ReportServiceOutcome(ctx context.Context, req *pb.ReportServiceOutcomeRequest) (*pb.ReportServiceOutcomeResponse, error) {
+	s.registryService.ReportOutcome(ctx, req.TenantId, req.ServiceName, req.Url, req.Success)
+	return &pb.ReportServiceOutcomeResponse{}, nil
+}
+
+// ReleaseServiceConnection completes a request a gateway proxied to an endpoint acquired through GetServiceURL
+func (s *TenantServiceServer) ReleaseServiceConnection(ctx context.Context, req *pb.ServiceConnectionRequest) (*pb.ServiceConnectionResponse, error) {
+	s.registryService.ReleaseConnection(req.TenantId, req.ServiceName, req.Url)
+	return &pb.ServiceConnectionResponse{}, nil
+}
+
+// === Hierarchy Handlers ===
+
+// ListChildTenants lists the direct sub-tenants of a tenant
+func (s *TenantServiceServer) ListChildTenants(ctx context.Context, req *pb.ListChildTenantsRequest) (*pb.ListChildTenantsResponse, error) {
+	tenants, total, err := s.tenantService.ListChildren(ctx, req.TenantId, int(req.Page), int(req.PageSize))
+	if err != nil {
+		s.logger.Error("Failed to list child tenants", zap.Error(err))
+		return nil, err
+	}
+
+	protoTenants := make([]*pb.Tenant, len(tenants))
+	for i, tenant := range tenants {
+		protoTenants[i] = s.toProtoTenant(tenant)
+	}
+
+	return &pb.ListChildTenantsResponse{
+		Tenants: protoTenants,
+		Total:   int32(total),
+	}, nil
+}
+
+// GetTenantAncestors lists the parent tenants of a tenant, nearest first
+func (s *TenantServiceServer) GetTenantAncestors(ctx context.Context, req *pb.GetTenantAncestorsRequest) (*pb.GetTenantAncestorsResponse, error) {
+	ancestors, err := s.tenantService.GetAncestors(ctx, req.TenantId)
+	if err != nil {
+		s.logger.Error("Failed to get parent tenants", zap.Error(err))
+		return nil, err
+	}
+
+	protoTenants := make([]*pb.Tenant, len(ancestors))
+	for i, tenant := range ancestors {
+		protoTenants[i] = s.toProtoTenant(tenant)
+	}
+
+	return &pb.GetTenantAncestorsResponse{
+		Tenants: protoTenants,
+	}, nil
+}
+
+// SetTenantParent moves a tenant under a parent tenant, or to the top level
+func (s *TenantServiceServer) SetTenantParent(ctx context.Context, req *pb.SetTenantParentRequest) (*pb.SetTenantParentResponse, error) {
+	tenant, err := s.tenantService.SetParent(ctx, req.TenantId, &domain.SetParentRequest{
+		ParentID:        req.ParentId,
+		ExpectedVersion: req.ExpectedVersion,
+	})
+	if err != nil {
+		s.logger.Error("Failed to set tenant parent", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.SetTenantParentResponse{
+		Tenant: s.toProtoTenant(tenant),
+	}, nil
+}
+
+// === Custom Domain Handlers ===
+
+// ClaimDomain claims a custom domain for a tenant
+func (s *TenantServiceServer) ClaimDomain(ctx context.Context, req *pb.ClaimDomainRequest) (*pb.ClaimDomainResponse, error) {
+	claim, err := s.domainService.ClaimDomain(ctx, req.TenantId, &domain.ClaimDomainRequest{
+		Domain:             req.Domain,
+		VerificationMethod: req.VerificationMethod,
+		IsPrimary:          req.IsPrimary,
+	})
+	if err != nil {
+		s.logger.Error("Failed to claim domain", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.ClaimDomainResponse{
+		Domain: s.toProtoTenantDomain(claim),
+	}, nil
+}
+
+// ListDomains lists the custom domains of a tenant
+func (s *TenantServiceServer) ListDomains(ctx context.Context, req *pb.ListDomainsRequest) (*pb.ListDomainsResponse, error) {
+	claims, err := s.domainService.ListDomains(ctx, req.TenantId)
+	if err != nil {
+		s.logger.Error("Failed to list domains", zap.Error(err))
+		return nil, err
+	}
+
+	protoDomains := make([]*pb.TenantDomain, len(claims))
+	for i, claim := range claims {
+		protoDomains[i] = s.toProtoTenantDomain(claim)
+	}
+
+	return &pb.ListDomainsResponse{
+		Domains: protoDomains,
+	}, nil
+}
+
+// VerifyDomain checks a domain claim immediately
+func (s *TenantServiceServer) VerifyDomain(ctx context.Context, req *pb.VerifyDomainRequest) (*pb.VerifyDomainResponse, error) {
+	claim, err := s.domainService.VerifyDomain(ctx, req.TenantId, req.DomainId)
+	if err != nil {
+		s.logger.Error("Failed to verify domain", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.VerifyDomainResponse{
+		Domain: s.toProtoTenantDomain(claim),
+	}, nil
+}
+
+// SetPrimaryDomain makes a verified domain the primary domain of a tenant
+func (s *TenantServiceServer) SetPrimaryDomain(ctx context.Context, req *pb.SetPrimaryDomainRequest) (*pb.SetPrimaryDomainResponse, error) {
+	claim, err := s.domainService.SetPrimaryDomain(ctx, req.TenantId, req.DomainId)
+	if err != nil {
+		s.logger.Error("Failed to set primary domain", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.SetPrimaryDomainResponse{
+		Domain: s.toProtoTenantDomain(claim),
+	}, nil
+}
+
+// RemoveDomain removes a custom domain from a tenant
+func (s *TenantServiceServer) RemoveDomain(ctx context.Context, req *pb.RemoveDomainRequest) (*pb.RemoveDomainResponse, error) {
+	if err := s.domainService.RemoveDomain(ctx, req.TenantId, req.DomainId); err != nil {
+		s.logger.Error("Failed to remove domain", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.RemoveDomainResponse{
+		Success: true,
+	}, nil
+}
+
+func (s *TenantServiceServer) toProtoTenantDomain(claim *domain.TenantDomain) *pb.TenantDomain {
+	protoDomain := &pb.TenantDomain{
+		Id:                 claim.ID.Hex(),
+		TenantId:           claim.TenantID,
+		Domain:             claim.Domain,
+		Status:             claim.Status,
+		VerificationMethod: claim.VerificationMethod,
+		Token:              claim.Token,
+		IsPrimary:          claim.IsPrimary,
+		Attempts:           int32(claim.Attempts),
+		LastError:          claim.LastError,
+		TxtRecordName:      claim.TXTRecordName(),
+		TxtRecordValue:     claim.TXTRecordValue(),
+		WellKnownUrl:       claim.WellKnownURL(),
+		ExpiresAt:          claim.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:          claim.CreatedAt.Format(time.RFC3339),
+	}
+	if !claim.VerifiedAt.IsZero() {
+		protoDomain.VerifiedAt = claim.VerifiedAt.Format(time.RFC3339)
+	}
+	return protoDomain
+}
+
+// === Role Handlers ===
+
+// ListTenantRoles lists the built-in and custom roles of a tenant
+func (s *TenantServiceServer) ListTenantRoles(ctx context.Context, req *pb.ListTenantRolesRequest) (*pb.ListTenantRolesResponse, error) {
+	roles, err := s.roleService.ListRoles(ctx, req.TenantId)
+	if err != nil {
+		s.logger.Error("Failed to list tenant roles", zap.Error(err))
+		return nil, err
+	}
+
+	protoRoles := make([]*pb.TenantRole, len(roles))
+	for i, role := range roles {
+		protoRoles[i] = s.toProtoTenantRole(role)
+	}
+
+	return &pb.ListTenantRolesResponse{
+		Roles: protoRoles,
+	}, nil
+}
+
+// CreateTenantRole defines a custom role for a tenant
+func (s *TenantServiceServer) CreateTenantRole(ctx context.Context, req *pb.CreateTenantRoleRequest) (*pb.CreateTenantRoleResponse, error) {
+	role, err := s.roleService.CreateRole(ctx, req.TenantId, &domain.TenantRoleRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	})
+	if err != nil {
+		s.logger.Error("Failed to create tenant role", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.CreateTenantRoleResponse{
+		Role: s.toProtoTenantRole(role),
+	}, nil
+}
+
+// UpdateTenantRole replaces the permissions of a custom role
+func (s *TenantServiceServer) UpdateTenantRole(ctx context.Context, req *pb.UpdateTenantRoleRequest) (*pb.UpdateTenantRoleResponse, error) {
+	role, err := s.roleService.UpdateRole(ctx, req.TenantId, req.Name, &domain.TenantRoleRequest{
+		Description: req.Description,
+		Permissions: req.Permissions,
+	})
+	if err != nil {
+		s.logger.Error("Failed to update tenant role", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.UpdateTenantRoleResponse{
+		Role: s.toProtoTenantRole(role),
+	}, nil
+}
+
+// DeleteTenantRole deletes an unused custom role
+func (s *TenantServiceServer) DeleteTenantRole(ctx context.Context, req *pb.DeleteTenantRoleRequest) (*pb.DeleteTenantRoleResponse, error) {
+	if err := s.roleService.DeleteRole(ctx, req.TenantId, req.Name); err != nil {
+		s.logger.Error("Failed to delete tenant role", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.DeleteTenantRoleResponse{
+		Success: true,
+	}, nil
+}
+
+// CheckPermission checks if a user holds a permission in a tenant
+func (s *TenantServiceServer) CheckPermission(ctx context.Context, req *pb.CheckPermissionRequest) (*pb.CheckPermissionResponse, error) {
+	check, err := s.roleService.CheckPermission(ctx, req.TenantId, req.UserId, req.Permission)
+	if err != nil {
+		s.logger.Error("Failed to check permission", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.CheckPermissionResponse{
+		Allowed:       check.Allowed,
+		Role:          check.Role,
+		Permissions:   check.Permissions,
+		InheritedFrom: check.InheritedFrom,
+	}, nil
+}
+
+func (s *TenantServiceServer) toProtoTenantRole(role *domain.TenantRole) *pb.TenantRole {
+	protoRole := &pb.TenantRole{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.Permissions,
+		IsBuiltIn:   role.IsBuiltIn,
+	}
+	if !role.IsBuiltIn {
+		protoRole.CreatedAt = role.CreatedAt.Format(time.RFC3339)
+		protoRole.UpdatedAt = role.UpdatedAt.Format(time.RFC3339)
+	}
+	return protoRole
+}
+
+// === Invitation Handlers ===
+
+// CreateInvitation invites a person to a tenant by email or phone
+func (s *TenantServiceServer) CreateInvitation(ctx context.Context, req *pb.CreateInvitationRequest) (*pb.CreateInvitationResponse, error) {
+	invitation, token, err := s.invitationService.CreateInvitation(ctx, req.TenantId, &domain.CreateInvitationRequest{
+		Email:     req.Email,
+		Phone:     req.Phone,
+		Role:      req.Role,
+		InvitedBy: req.InvitedBy,
+	})
+	if err != nil {
+		s.logger.Error("Failed to create invitation", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.CreateInvitationResponse{
+		Invitation: s.toProtoTenantInvitation(invitation),
+		Token:      token,
+	}, nil
+}
+
+// ListInvitations lists the invitations of a tenant
+func (s *TenantServiceServer) ListInvitations(ctx context.Context, req *pb.ListInvitationsRequest) (*pb.ListInvitationsResponse, error) {
+	invitations, err := s.invitationService.ListInvitations(ctx, req.TenantId, req.Status)
+	if err != nil {
+		s.logger.Error("Failed to list invitations", zap.Error(err))
+		return nil, err
+	}
+
+	protoInvitations := make([]*pb.TenantInvitation, len(invitations))
+	for i, invitation := range invitations {
+		protoInvitations[i] = s.toProtoTenantInvitation(invitation)
+	}
+
+	return &pb.ListInvitationsResponse{
+		Invitations: protoInvitations,
+	}, nil
+}
+
+// ResendInvitation sends a pending invitation again with a new token
+func (s *TenantServiceServer) ResendInvitation(ctx context.Context, req *pb.ResendInvitationRequest) (*pb.ResendInvitationResponse, error) {
+	invitation, token, err := s.invitationService.ResendInvitation(ctx, req.TenantId, req.InvitationId)
+	if err != nil {
+		s.logger.Error("Failed to resend invitation", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.ResendInvitationResponse{
+		Invitation: s.toProtoTenantInvitation(invitation),
+		Token:      token,
+	}, nil
+}
+
+// RevokeInvitation revokes a pending invitation
+func (s *TenantServiceServer) RevokeInvitation(ctx context.Context, req *pb.RevokeInvitationRequest) (*pb.RevokeInvitationResponse, error) {
+	if err := s.invitationService.RevokeInvitation(ctx, req.TenantId, req.InvitationId); err != nil {
+		s.logger.Error("Failed to revoke invitation", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.RevokeInvitationResponse{
+		Success: true,
+	}, nil
+}
+
+// AcceptInvitation redeems an invitation token and adds the user to the tenant
+func (s *TenantServiceServer) AcceptInvitation(ctx context.Context, req *pb.AcceptInvitationRequest) (*pb.AcceptInvitationResponse, error) {
+	invitation, err := s.invitationService.AcceptInvitation(ctx, req.Token)
+	if err != nil {
+		s.logger.Error("Failed to accept invitation", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.AcceptInvitationResponse{
+		Invitation: s.toProtoTenantInvitation(invitation),
+	}, nil
+}
+
+// DeclineInvitation declines an invitation token
+func (s *TenantServiceServer) DeclineInvitation(ctx context.Context, req *pb.DeclineInvitationRequest) (*pb.DeclineInvitationResponse, error) {
+	invitation, err := s.invitationService.DeclineInvitation(ctx, req.Token)
+	if err != nil {
+		s.logger.Error("Failed to decline invitation", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.DeclineInvitationResponse{
+		Invitation: s.toProtoTenantInvitation(invitation),
+	}, nil
+}
+
+func (s *TenantServiceServer) toProtoTenantInvitation(invitation *domain.TenantInvitation) *pb.TenantInvitation {
+	return &pb.TenantInvitation{
+		Id:         invitation.ID.Hex(),
+		TenantId:   invitation.TenantID,
+		Email:      invitation.Email,
+		Phone:      invitation.Phone,
+		Role:       invitation.Role,
+		Status:     invitation.CurrentStatus(time.Now()),
+		InvitedBy:  invitation.InvitedBy,
+		AcceptedBy: invitation.AcceptedBy,
+		SendCount:  int32(invitation.SendCount),
+		ExpiresAt:  invitation.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:  invitation.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// === Entitlement Handlers ===
+
+// GetEntitlements returns the effective entitlements and usage of a tenant
+func (s *TenantServiceServer) GetEntitlements(ctx context.Context, req *pb.GetEntitlementsRequest) (*pb.GetEntitlementsResponse, error) {
+	entitlements, err := s.entitlementService.GetEntitlements(ctx, req.TenantId)
+	if err != nil {
+		s.logger.Error("Failed to get entitlements", zap.Error(err))
+		return nil, err
+	}
+
+	return s.toProtoEntitlementsResponse(entitlements), nil
+}
+
+// UpdateEntitlementOverrides replaces the entitlement overrides of a tenant
+func (s *TenantServiceServer) UpdateEntitlementOverrides(ctx context.Context, req *pb.UpdateEntitlementOverridesRequest) (*pb.GetEntitlementsResponse, error) {
+	entitlements, err := s.entitlementService.UpdateOverrides(ctx, req.TenantId, s.fromProtoEntitlementOverrides(req.Overrides))
+	if err != nil {
+		s.logger.Error("Failed to update entitlement overrides", zap.Error(err))
+		return nil, err
+	}
+
+	return s.toProtoEntitlementsResponse(entitlements), nil
+}
+
+func (s *TenantServiceServer) toProtoEntitlementsResponse(response *domain.EntitlementsResponse) *pb.GetEntitlementsResponse {
+	entitlements := response.Entitlements
+	return &pb.GetEntitlementsResponse{
+		TenantId: response.TenantID,
+		Tier:     response.Tier,
+		Entitlements: &pb.Entitlements{
+			MaxMembers:            int32(entitlements.MaxMembers),
+			MaxServiceConfigs:     int32(entitlements.MaxServiceConfigs),
+			LoadBalanceStrategies: entitlements.LoadBalanceStrategies,
+			CustomDomains:         entitlements.CustomDomains,
+			RateLimitClass:        entitlements.RateLimitClass,
+			Features:              entitlements.Features,
+		},
+		Members:        response.Usage.Members,
+		ServiceConfigs: response.Usage.ServiceConfigs,
+	}
+}
+
+// fromProtoEntitlementOverrides converts entitlement overrides; a nil message clears them
+func (s *TenantServiceServer) fromProtoEntitlementOverrides(proto *pb.EntitlementOverrides) *domain.EntitlementOverrides {
+	if proto == nil {
+		return nil
+	}
+
+	overrides := &domain.EntitlementOverrides{
+		LoadBalanceStrategies: proto.LoadBalanceStrategies,
+		CustomDomains:         proto.CustomDomains,
+		RateLimitClass:        proto.RateLimitClass,
+		Features:              proto.Features,
+	}
+	if proto.MaxMembers != nil {
+		maxMembers := int(*proto.MaxMembers)
+		overrides.MaxMembers = &maxMembers
+	}
+	if proto.MaxServiceConfigs != nil {
+		maxServiceConfigs := int(*proto.MaxServiceConfigs)
+		overrides.MaxServiceConfigs = &maxServiceConfigs
+	}
+	return overrides
+}
+
+// === Plan Handlers ===
+
+// GetPlan returns the subscription tier of a tenant with its scheduled change and plan history
+func (s *TenantServiceServer) GetPlan(ctx context.Context, req *pb.GetPlanRequest) (*pb.GetPlanResponse, error) {
+	plan, err := s.planService.GetPlan(ctx, req.TenantId)
+	if err != nil {
+		s.logger.Error("Failed to get plan", zap.Error(err))
+		return nil, err
+	}
+
+	return s.toProtoPlanResponse(plan), nil
+}
+
+// ChangePlan changes the subscription tier of a tenant, now or at a future time
+func (s *TenantServiceServer) ChangePlan(ctx context.Context, req *pb.ChangePlanRequest) (*pb.GetPlanResponse, error) {
+	changeReq := &domain.ChangePlanRequest{
+		Tier:   req.Tier,
+		Reason: req.Reason,
+	}
+	if req.EffectiveAt != "" {
+		effectiveAt, err := time.Parse(time.RFC3339, req.EffectiveAt)
+		if err != nil {
+			return nil, errors.BadRequest("effective_at must be an RFC 3339 time")
+		}
+		changeReq.EffectiveAt = &effectiveAt
+	}
+
+	plan, err := s.planService.ChangePlan(ctx, req.TenantId, changeReq)
+	if err != nil {
+		s.logger.Error("Failed to change plan", zap.Error(err))
+		return nil, err
+	}
+
+	return s.toProtoPlanResponse(plan), nil
+}
+
+// CancelScheduledPlanChange cancels the scheduled plan change of a tenant
+func (s *TenantServiceServer) CancelScheduledPlanChange(ctx context.Context, req *pb.CancelScheduledPlanChangeRequest) (*pb.GetPlanResponse, error) {
+	plan, err := s.planService.CancelScheduledChange(ctx, req.TenantId, &domain.CancelPlanChangeRequest{
+		Reason: req.Reason,
+	})
+	if err != nil {
+		s.logger.Error("Failed to cancel plan change", zap.Error(err))
+		return nil, err
+	}
+
+	return s.toProtoPlanResponse(plan), nil
+}
+
+func (s *TenantServiceServer) toProtoPlanResponse(plan *domain.PlanResponse) *pb.GetPlanResponse {
+	response := &pb.GetPlanResponse{
+		TenantId: plan.TenantID,
+		Tier:     plan.Tier,
+		History:  make([]*pb.PlanChange, 0, len(plan.History)),
+	}
+	if plan.ScheduledChange != nil {
+		response.ScheduledChange = s.toProtoPlanChange(*plan.ScheduledChange)
+	}
+	for _, change := range plan.History {
+		response.History = append(response.History, s.toProtoPlanChange(change))
+	}
+	return response
+}
+
+func (s *TenantServiceServer) toProtoPlanChange(change domain.PlanChange) *pb.PlanChange {
+	proto := &pb.PlanChange{
+		From:        change.From,
+		To:          change.To,
+		Reason:      change.Reason,
+		Actor:       change.Actor,
+		Outcome:     change.Outcome,
+		Violations:  change.Violations,
+		RequestedAt: change.RequestedAt.Format(time.RFC3339),
+		EffectiveAt: change.EffectiveAt.Format(time.RFC3339),
+	}
+	if !change.CompletedAt.IsZero() {
+		proto.CompletedAt = change.CompletedAt.Format(time.RFC3339)
+	}
+	return proto
+}
+
+// === Trial Handlers ===
+
+// StartTrial starts a trial of a paid tier for a tenant
+func (s *TenantServiceServer) StartTrial(ctx context.Context, req *pb.StartTrialRequest) (*pb.StartTrialResponse, error) {
+	tenant, err := s.trialService.StartTrial(ctx, req.TenantId, &domain.StartTrialRequest{
+		Tier:         req.Tier,
+		DurationDays: int(req.DurationDays),
+	})
+	if err != nil {
+		s.logger.Error("Failed to start trial", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.StartTrialResponse{
+		Tenant: s.toProtoTenant(tenant),
+	}, nil
+}
+
+// ListExpiringTrials lists tenants whose trial ends within the requested number of days
+func (s *TenantServiceServer) ListExpiringTrials(ctx context.Context, req *pb.ListExpiringTrialsRequest) (*pb.ListExpiringTrialsResponse, error) {
+	withinDays := int(req.WithinDays)
+	if withinDays == 0 {
+		withinDays = 7
+	}
+
+	tenants, total, err := s.trialService.ListExpiringTrials(ctx, time.Duration(withinDays)*24*time.Hour, int(req.Page), int(req.PageSize))
+	if err != nil {
+		s.logger.Error("Failed to list expiring trials", zap.Error(err))
+		return nil, err
+	}
+
+	protoTenants := make([]*pb.Tenant, len(tenants))
+	for i, tenant := range tenants {
+		protoTenants[i] = s.toProtoTenant(tenant)
+	}
+
+	return &pb.ListExpiringTrialsResponse{
+		Tenants: protoTenants,
+		Total:   int32(total),
+	}, nil
+}
+
+// === Settings Registry Handlers ===
+
+// RegisterSetting registers or replaces the definition of a setting key
+func (s *TenantServiceServer) RegisterSetting(ctx context.Context, req *pb.RegisterSettingRequest) (*pb.RegisterSettingResponse, error) {
+	registerReq := &domain.RegisterSettingRequest{
+		Service:     req.Service,
+		Description: req.Description,
+		Visibility:  req.Visibility,
+	}
+	if req.Schema != nil {
+		registerReq.Schema = req.Schema.AsMap()
+	}
+	if req.DefaultValue != nil {
+		registerReq.Default = req.DefaultValue.AsInterface()
+	}
+
+	definition, err := s.settingsService.RegisterSetting(ctx, req.Key, registerReq)
+	if err != nil {
+		s.logger.Error("Failed to register setting", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.RegisterSettingResponse{
+		Definition: s.toProtoSettingDefinition(definition),
+	}, nil
+}
+
+// ListSettingDefinitions lists the registered settings
+func (s *TenantServiceServer) ListSettingDefinitions(ctx context.Context, req *pb.ListSettingDefinitionsRequest) (*pb.ListSettingDefinitionsResponse, error) {
+	definitions, err := s.settingsService.ListSettingDefinitions(ctx, req.Service)
+	if err != nil {
+		s.logger.Error("Failed to list setting definitions", zap.Error(err))
+		return nil, err
+	}
+
+	protoDefinitions := make([]*pb.SettingDefinition, len(definitions))
+	for i, definition := range definitions {
+		protoDefinitions[i] = s.toProtoSettingDefinition(definition)
+	}
+
+	return &pb.ListSettingDefinitionsResponse{
+		Definitions: protoDefinitions,
+	}, nil
+}
+
+// DeleteSettingDefinition unregisters a setting key
+func (s *TenantServiceServer) DeleteSettingDefinition(ctx context.Context, req *pb.DeleteSettingDefinitionRequest) (*pb.DeleteSettingDefinitionResponse, error) {
+	if err := s.settingsService.DeleteSettingDefinition(ctx, req.Key); err != nil {
+		s.logger.Error("Failed to delete setting definition", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.DeleteSettingDefinitionResponse{Success: true}, nil
+}
+
+// GetEffectiveSettings resolves the settings of a tenant with the registered defaults
+func (s *TenantServiceServer) GetEffectiveSettings(ctx context.Context, req *pb.GetEffectiveSettingsRequest) (*pb.GetEffectiveSettingsResponse, error) {
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = domain.SettingVisibilityPublic
+	}
+
+	settings, err := s.settingsService.GetEffectiveSettings(ctx, req.TenantId, visibility)
+	if err != nil {
+		s.logger.Error("Failed to get effective settings", zap.Error(err))
+		return nil, err
+	}
+
+	protoSettings, err := structpb.NewStruct(settings.Values)
+	if err != nil {
+		s.logger.Error("Failed to convert effective settings", zap.Error(err))
+		return nil, errors.Internal("Failed to get tenant settings")
+	}
+
+	return &pb.GetEffectiveSettingsResponse{
+		Settings:   protoSettings,
+		Defaulted:  settings.Defaulted,
+		Visibility: visibility,
+	}, nil
+}
+
+// toProtoSettingDefinition converts a setting definition to protobuf
+func (s *TenantServiceServer) toProtoSettingDefinition(definition *domain.SettingDefinition) *pb.SettingDefinition {
+	protoDefinition := &pb.SettingDefinition{
+		Key:         definition.Key,
+		Service:     definition.Service,
+		Description: definition.Description,
+		Schema:      toProtoSettings(definition.Schema),
+		Visibility:  definition.Visibility,
+		CreatedAt:   definition.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   definition.UpdatedAt.Format(time.RFC3339),
+	}
+	if definition.Default != nil {
+		if value, err := structpb.NewValue(domain.NormalizeSettingValue(definition.Default)); err == nil {
+			protoDefinition.DefaultValue = value
+		}
+	}
+	return protoDefinition
+}
+
+// === Audit Log Handlers ===
+
+// ListAuditEvents lists a page of the audit log, newest first
+func (s *TenantServiceServer) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	filter := domain.AuditEventFilter{
+		TenantID:   req.TenantId,
+		ActorID:    req.Actor,
+		Action:     req.Action,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetId,
+	}
+	if req.CreatedAfter != "" {
+		createdAfter, err := time.Parse(time.RFC3339, req.CreatedAfter)
+		if err != nil {
+			return nil, errors.BadRequest("created_after must be an RFC 3339 time")
+		}
+		filter.CreatedAfter = &createdAfter
+	}
+	if req.CreatedBefore != "" {
+		createdBefore, err := time.Parse(time.RFC3339, req.CreatedBefore)
+		if err != nil {
+			return nil, errors.BadRequest("created_before must be an RFC 3339 time")
+		}
+		filter.CreatedBefore = &createdBefore
+	}
+
+	page := domain.PageRequest{
+		PageToken:    req.PageToken,
+		Page:         int(req.Page),
+		PageSize:     int(req.PageSize),
+		IncludeTotal: req.IncludeTotal,
+	}
+
+	events, info, err := s.auditService.ListAuditEvents(ctx, filter, page)
+	if err != nil {
+		s.logger.Error("Failed to list audit events", zap.Error(err))
+		return nil, err
+	}
+
+	protoEvents := make([]*pb.AuditEvent, len(events))
+	for i, event := range events {
+		protoEvents[i] = s.toProtoAuditEvent(event)
+	}
+
+	return &pb.ListAuditEventsResponse{
+		Events:        protoEvents,
+		Total:         pageTotal(info),
+		NextPageToken: info.NextPageToken,
+		PrevPageToken: info.PrevPageToken,
+	}, nil
+}
+
+// toProtoAuditEvent converts an audit event to protobuf
+func (s *TenantServiceServer) toProtoAuditEvent(event *domain.AuditEvent) *pb.AuditEvent {
+	changes := make([]*pb.AuditChange, len(event.Changes))
+	for i, change := range event.Changes {
+		changes[i] = &pb.AuditChange{Field: change.Field}
+		if change.Before != nil {
+			changes[i].Before, _ = structpb.NewValue(domain.NormalizeSettingValue(change.Before))
+		}
+		if change.After != nil {
+			changes[i].After, _ = structpb.NewValue(domain.NormalizeSettingValue(change.After))
+		}
+	}
+
+	return &pb.AuditEvent{
+		Id:         event.ID.Hex(),
+		TenantId:   event.TenantID,
+		Actor:      &pb.AuditActor{Id: event.Actor.ID, Type: event.Actor.Type},
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetId:   event.TargetID,
+		Reason:     event.Reason,
+		Changes:    changes,
+		CreatedAt:  event.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// === Tenant Config Handlers ===
+
+// GetTenantConfig gets the configuration of a tenant
+func (s *TenantServiceServer) GetTenantConfig(ctx context.Context, req *pb.GetTenantConfigRequest) (*pb.GetTenantConfigResponse, error) {
+	config, err := s.tenantService.GetTenantConfig(ctx, req.TenantId)
+	if err != nil {
+		s.logger.Error("Failed to get tenant config", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.GetTenantConfigResponse{
+		Config: s.toProtoTenantConfig(config),
+	}, nil
+}
+
+// GetEffectiveTenantConfig gets the configuration that applies to a tenant, including inherited settings
+func (s *TenantServiceServer) GetEffectiveTenantConfig(ctx context.Context, req *pb.GetTenantConfigRequest) (*pb.GetTenantConfigResponse, error) {
+	config, err := s.tenantService.GetEffectiveTenantConfig(ctx, req.TenantId)
+	if err != nil {
+		s.logger.Error("Failed to get effective tenant config", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.GetTenantConfigResponse{
+		Config: s.toProtoTenantConfig(config),
+	}, nil
+}
+
+// UpdateTenantConfig replaces the configuration of a tenant
+func (s *TenantServiceServer) UpdateTenantConfig(ctx context.Context, req *pb.UpdateTenantConfigRequest) (*pb.UpdateTenantConfigResponse, error) {
+	config, err := s.tenantService.UpdateTenantConfig(ctx, req.TenantId, s.fromProtoTenantConfig(req.Config), req.ExpectedVersion)
+	if err != nil {
+		s.logger.Error("Failed to update tenant config", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.UpdateTenantConfigResponse{
+		Config: s.toProtoTenantConfig(config),
+	}, nil
+}
+
+// GetDefaultService resolves the default service of a tenant
+func (s *TenantServiceServer) GetDefaultService(ctx context.Context, req *pb.GetDefaultServiceRequest) (*pb.GetDefaultServiceResponse, error) {
+	info, err := s.tenantService.GetDefaultService(ctx, req.TenantId)
+	if err != nil {
+		s.logger.Error("Failed to get default service", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.GetDefaultServiceResponse{
+		DefaultServiceUrl: info.DefaultServiceURL,
+		FallbackUrls:      info.FallbackURLs,
+	}, nil
+}
+
+func (s *TenantServiceServer) toProtoTenantConfig(config *domain.TenantConfig) *pb.TenantConfig {
+	return &pb.TenantConfig{
+		DefaultServiceUrl:       config.DefaultServiceURL,
+		ServiceMappings:         config.ServiceMappings,
+		FallbackChain:           config.FallbackChain,
+		AllowedLoginIdentifiers: config.AllowedLoginIdentifiers,
+		Require_2Fa:             config.Require2FA,
+		AllowRegistration:       config.AllowRegistration,
+		CustomLogoUrl:           config.CustomLogoURL,
+		CustomBackgroundUrl:     config.CustomBackgroundURL,
+		CustomSettings:          config.CustomSettings,
+	}
+}
+
+func (s *TenantServiceServer) fromProtoTenantConfig(proto *pb.TenantConfig) *domain.TenantConfig {
+	if proto == nil {
+		return &domain.TenantConfig{}
+	}
+
+	return &domain.TenantConfig{
+		DefaultServiceURL:       proto.DefaultServiceUrl,
+		ServiceMappings:         proto.ServiceMappings,
+		FallbackChain:           proto.FallbackChain,
+		AllowedLoginIdentifiers: proto.AllowedLoginIdentifiers,
+		Require2FA:              proto.Require_2Fa,
+		AllowRegistration:       proto.AllowRegistration,
+		CustomLogoURL:           proto.CustomLogoUrl,
+		CustomBackgroundURL:     proto.CustomBackgroundUrl,
+		CustomSettings:          proto.CustomSettings,
+	}
+}
+
+// === Service Registry Handlers ===
+
+// GetServiceConfig gets service configuration for a tenant
+func (s *TenantServiceServer) GetServiceConfig(ctx context.Context, req *pb.GetServiceConfigRequest) (*pb.GetServiceConfigResponse, error) {
+	config, err := s.registryService.GetServiceConfig(ctx, req.TenantId, req.ServiceName)
+	if err != nil {
+		s.logger.Error("Failed to get service config", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.GetServiceConfigResponse{
+		Config: s.toProtoServiceConfig(config),
+	}, nil
+}
+
+// UpdateServiceConfig creates or updates service configuration for a tenant
+func (s *TenantServiceServer) UpdateServiceConfig(ctx context.Context, req *pb.UpdateServiceConfigRequest) (*pb.UpdateServiceConfigResponse, error) {
+	config := s.fromProtoServiceConfig(req.Config)
+	config.TenantID = req.TenantId
+	config.ServiceName = req.ServiceName
+
+	err := s.registryService.CreateOrUpdateServiceConfig(ctx, config, req.ExpectedVersion)
+	if err != nil {
+		s.logger.Error("Failed to update service config", zap.Error(err))
+		return nil, err
+	}
+
+	return &pb.UpdateServiceConfigResponse{
+		Config: s.toProtoServiceConfig(config),
+	}, nil
+}
+
+// GetServiceURL resolves the service URL for a tenant
+func (s *TenantServiceServer) GetServiceURL(ctx context.Context, req *pb.GetServiceURLRequest) (*pb.GetServiceURLResponse, error) {
+	resolve := s.registryService.GetServiceURL
+	if req.AcquireConnection {
+		resolve = s.registryService.AcquireServiceURL
+	}
+	result, err := resolve(ctx, req.TenantId, req.ServiceName)
+	if err != nil {
+		s.logger.Error("Failed to get service URL", zap.Error(err))
+		return &pb.GetServiceURLResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	resp := &pb.GetServiceURLResponse{
+		Url:           result.ResolvedURL,
+		IsDefault:     result.IsDefault,
+		Success:       result.Success,
+		Error:         result.Error,
+		AttemptedUrls: result.AttemptedURLs,
+		InheritedFrom: result.InheritedFrom,
+	}
+	if result.UsedEndpoint != nil {
+		resp.Endpoint = s.toProtoServiceEndpoint(result.UsedEndpoint)
+	}
+
+	return resp, nil
+}
+
+// ListTenantServices lists a page of the service configurations of a tenant
+func (s *TenantServiceServer) ListTenantServices(ctx context.Context, req *pb.ListTenantServicesRequest) (*pb.ListTenantServicesResponse, error) {
+	page := domain.PageRequest{
+		PageToken:    req.PageToken,
+		PageSize:     int(req.PageSize),
+		IncludeTotal: req.IncludeTotal,
+	}
+
+	configs, info, err := s.registryService.GetTenantServices(ctx, req.TenantId, page)
+	if err != nil {
+		s.logger.Error("Failed to list tenant services", zap.Error(err))
+		return nil, err
+	}
+
+	protoConfigs := make([]*pb.ServiceConfig, len(configs))
+	for i, config := range configs {
+		protoConfigs[i] = s.toProtoServiceConfig(config)
+	}
+
+	return &pb.ListTenantServicesResponse{
+		Services:      protoConfigs,
+		Total:         pageTotal(info),
+		NextPageToken: info.NextPageToken,
+		PrevPageToken: info.PrevPageToken,
+	}, nil
+}
+
+// GetServiceHealth gets health status for a service
+func (s *TenantServiceServer) GetServiceHealth(ctx context.Context, req *pb.GetServiceHealthRequest) (*pb.GetServiceHealthResponse, error) {
+	config, err := s.registryService.GetServiceConfig(ctx, req.TenantId, req.ServiceName)
+	if err != nil {
+		s.logger.Error("Failed to get service config for health", zap.Error(err))
+		return nil, err
+	}
+
+	healths := []*pb.ServiceHealth{}
+
+	// Primary endpoint health
+	primaryStatus := s.registryService.GetHealthStatus(req.TenantId, req.ServiceName, config.PrimaryEndpoint.URL)
+	healths = append(healths, s.toProtoServiceHealth(primaryStatus))
+
+	// Fallback chain health
+	for _, endpoint := range config.FallbackChain {
+		status := s.registryService.GetHealthStatus(req.TenantId, req.ServiceName, endpoint.URL)
+		healths = append(healths, s.toProtoServiceHealth(status))
+	}
+
+	return &pb.GetServiceHealthResponse{
+		Healths: healths,
+	}, nil
+}
+
+// === Proto Conversion Helpers ===
+
+func (s *TenantServiceServer) toProtoServiceConfig(config *domain.ServiceConfig) *pb.ServiceConfig {
+	fallbackChain := make([]*pb.ServiceEndpoint, len(config.FallbackChain))
+	for i, endpoint := range config.FallbackChain {
+		fallbackChain[i] = s.toProtoServiceEndpoint(&endpoint)
+	}
+
+	return &pb.ServiceConfig{
+		Id:                  config.ID.Hex(),
+		TenantId:            config.TenantID,
+		ServiceName:         config.ServiceName,
+		PrimaryEndpoint:     s.toProtoServiceEndpoint(&config.PrimaryEndpoint),
+		FallbackChain:       fallbackChain,
+		DefaultServiceUrl:   config.DefaultServiceURL,
+		HealthCheck:         s.toProtoHealthCheck(&config.HealthCheck),
+		LoadBalanceStrategy: config.LoadBalanceStrategy,
+		IsActive:            config.IsActive,
+		Metadata:            config.Metadata,
+		CreatedAt:           config.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           config.UpdatedAt.Format(time.RFC3339),
+		CircuitBreaker:      s.toProtoCircuitBreaker(&config.CircuitBreaker),
+		Version:             config.Version,
+	}
+}
+
+func (s *TenantServiceServer) toProtoServiceEndpoint(endpoint *domain.ServiceEndpoint) *pb.ServiceEndpoint {
+	return &pb.ServiceEndpoint{
+		Url:      endpoint.URL,
+		Priority: int32(endpoint.Priority),
+		Weight:   int32(endpoint.Weight),
+		Timeout:  int32(endpoint.Timeout),
+		Headers:  endpoint.Headers,
+		IsActive: endpoint.IsActive,
+	}
+}
+
+func (s *TenantServiceServer) toProtoHealthCheck(config *domain.HealthCheckConfig) *pb.HealthCheckConfig {
+	return &pb.HealthCheckConfig{
+		Enabled:       config.Enabled,
+		Path:          config.Path,
+		Method:        config.Method,
+		Interval:      int32(config.Interval),
+		Timeout:       int32(config.Timeout),
+		FailThreshold: int32(config.FailThreshold),
+		Protocol:      config.Protocol,
+	}
+}
+
+func (s *TenantServiceServer) toProtoCircuitBreaker(config *domain.CircuitBreakerConfig) *pb.CircuitBreakerConfig {
+	return &pb.CircuitBreakerConfig{
+		Enabled:            config.Enabled,
+		ErrorRateThreshold: config.ErrorRateThreshold,
+		MinRequests:        int32(config.MinRequests),
+		Window:             int32(config.Window),
+		OpenDuration:       int32(config.OpenDuration),
+		HalfOpenRequests:   int32(config.HalfOpenRequests),
+	}
+}
+
+func (s *TenantServiceServer) toProtoServiceHealth(status *domain.ServiceStatus) *pb.ServiceHealth {
+	return &pb.ServiceHealth{
+		EndpointUrl:       status.EndpointURL,
+		IsHealthy:         status.IsHealthy,
+		LastChecked:       status.LastChecked.Format(time.RFC3339),
+		LastSuccessful:    status.LastSuccessful.Format(time.RFC3339),
+		LastFailure:       status.LastFailure.Format(time.RFC3339),
+		ConsecutiveFails:  int32(status.ConsecutiveFails),
+		LastError:         status.LastError,
+		CircuitState:      status.CircuitState,
+		ActiveConnections: int32(status.ActiveConnections),
+	}
+}
+
+func (s *TenantServiceServer) fromProtoServiceConfig(proto *pb.ServiceConfig) *domain.ServiceConfig {
+	fallbackChain := make([]domain.ServiceEndpoint, len(proto.FallbackChain))
+	for i, endpoint := range proto.FallbackChain {
+		fallbackChain[i] = *s.fromProtoServiceEndpoint(endpoint)
+	}
+
+	return &domain.ServiceConfig{
+		TenantID:            proto.TenantId,
+		ServiceName:         proto.ServiceName,
+		PrimaryEndpoint:     *s.fromProtoServiceEndpoint(proto.PrimaryEndpoint),
+		FallbackChain:       fallbackChain,
+		DefaultServiceURL:   proto.DefaultServiceUrl,
+		HealthCheck:         *s.fromProtoHealthCheck(proto.HealthCheck),
+		LoadBalanceStrategy: proto.LoadBalanceStrategy,
+		IsActive:            proto.IsActive,
+		Metadata:            proto.Metadata,
+		CircuitBreaker:      s.fromProtoCircuitBreaker(proto.CircuitBreaker),
+	}
+}
+
+func (s *TenantServiceServer) fromProtoServiceEndpoint(proto *pb.ServiceEndpoint) *domain.ServiceEndpoint {
+	return &domain.ServiceEndpoint{
+		URL:      proto.Url,
+		Priority: int(proto.Priority),
+		Weight:   int(proto.Weight),
+		Timeout:  int(proto.Timeout),
+		Headers:  proto.Headers,
+		IsActive: proto.IsActive,
+	}
+}
+
+func (s *TenantServiceServer) fromProtoHealthCheck(proto *pb.HealthCheckConfig) *domain.HealthCheckConfig {
+	return &domain.HealthCheckConfig{
+		Enabled:       proto.Enabled,
+		Path:          proto.Path,
+		Method:        proto.Method,
+		Interval:      int(proto.Interval),
+		Timeout:       int(proto.Timeout),
+		FailThreshold: int(proto.FailThreshold),
+		Protocol:      proto.Protocol,
+	}
+}
+
+func (s *TenantServiceServer) fromProtoCircuitBreaker(proto *pb.CircuitBreakerConfig) domain.CircuitBreakerConfig {
+	if proto == nil {
+		return domain.CircuitBreakerConfig{}
+	}
+	return domain.CircuitBreakerConfig{
+		Enabled:            proto.Enabled,
+		ErrorRateThreshold: proto.ErrorRateThreshold,
+		MinRequests:        int(proto.MinRequests),
+		Window:             int(proto.Window),
+		OpenDuration:       int(proto.OpenDuration),
+		HalfOpenRequests:   int(proto.HalfOpenRequests),
+	}
+}
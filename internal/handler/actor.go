@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+)
+
+// internalTokenHeader carries the signed identity of the caller. The gateway strips it
+// from client requests and issues one for the verified user.
+const internalTokenHeader = "X-Internal-Token"
+
+// ActorMiddleware stores the actor of a request in its context so changes are attributed
+// in the audit log. The actor is only taken from an internal token signed with the key;
+// requests without a valid token are anonymous.
+func ActorMiddleware(internalTokenKey []byte) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := domain.RequestActor("", "")
+		if token := c.GetHeader(internalTokenHeader); token != "" {
+			if verified, err := domain.ParseInternalToken(internalTokenKey, token, time.Now()); err == nil {
+				actor = verified
+			}
+		}
+
+		c.Request = c.Request.WithContext(domain.ContextWithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
@@ -1,170 +1,1373 @@
-package handler
-
-import (
-	"net/http"
-	"strconv"
-
-	"github.com/gin-gonic/gin"
-	"github.com/vhvplatform/go-shared/errors"
-	"github.com/vhvplatform/go-shared/logger"
-	"github.com/vhvplatform/go-tenant-service/internal/domain"
-	"github.com/vhvplatform/go-tenant-service/internal/service"
-	"go.uber.org/zap"
-)
-
-// TenantHandler handles HTTP requests for tenants
-type TenantHandler struct {
-	tenantService *service.TenantService
-	logger        *logger.Logger
-}
-
-// NewTenantHandler creates a new tenant handler
-func NewTenantHandler(tenantService *service.TenantService, log *logger.Logger) *TenantHandler {
-	return &TenantHandler{
-		tenantService: tenantService,
-		logger:        log,
-	}
-}
-
-// CreateTenant handles tenant creation
-func (h *TenantHandler) CreateTenant(c *gin.Context) {
-	var req domain.CreateTenantRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.respondError(c, errors.BadRequest("Invalid request body"))
-		return
-	}
-
-	tenant, err := h.tenantService.CreateTenant(c.Request.Context(), &req)
-	if err != nil {
-		h.respondError(c, err)
-		return
-	}
-
-	c.JSON(http.StatusCreated, gin.H{"data": h.toTenantResponse(tenant)})
-}
-
-// GetTenant handles getting a tenant by ID
-func (h *TenantHandler) GetTenant(c *gin.Context) {
-	tenantID := c.Param("id")
-
-	tenant, err := h.tenantService.GetTenant(c.Request.Context(), tenantID)
-	if err != nil {
-		h.respondError(c, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"data": h.toTenantResponse(tenant)})
-}
-
-// ListTenants handles listing tenants
-func (h *TenantHandler) ListTenants(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
-
-	tenants, total, err := h.tenantService.ListTenants(c.Request.Context(), page, pageSize)
-	if err != nil {
-		h.respondError(c, err)
-		return
-	}
-
-	tenantResponses := make([]domain.TenantResponse, len(tenants))
-	for i, tenant := range tenants {
-		tenantResponses[i] = h.toTenantResponse(tenant)
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"data": domain.ListTenantsResponse{
-			Tenants:  tenantResponses,
-			Total:    total,
-			Page:     page,
-			PageSize: pageSize,
-		},
-	})
-}
-
-// UpdateTenant handles updating a tenant
-func (h *TenantHandler) UpdateTenant(c *gin.Context) {
-	tenantID := c.Param("id")
-
-	var req domain.UpdateTenantRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.respondError(c, errors.BadRequest("Invalid request body"))
-		return
-	}
-
-	tenant, err := h.tenantService.UpdateTenant(c.Request.Context(), tenantID, &req)
-	if err != nil {
-		h.respondError(c, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"data": h.toTenantResponse(tenant)})
-}
-
-// DeleteTenant handles deleting a tenant
-func (h *TenantHandler) DeleteTenant(c *gin.Context) {
-	tenantID := c.Param("id")
-
-	if err := h.tenantService.DeleteTenant(c.Request.Context(), tenantID); err != nil {
-		h.respondError(c, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Tenant deleted successfully"})
-}
-
-// AddUserToTenant handles adding a user to a tenant
-func (h *TenantHandler) AddUserToTenant(c *gin.Context) {
-	tenantID := c.Param("id")
-
-	var req domain.AddUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.respondError(c, errors.BadRequest("Invalid request body"))
-		return
-	}
-
-	if err := h.tenantService.AddUserToTenant(c.Request.Context(), tenantID, req.UserID, req.Role); err != nil {
-		h.respondError(c, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "User added to tenant successfully"})
-}
-
-// RemoveUserFromTenant handles removing a user from a tenant
-func (h *TenantHandler) RemoveUserFromTenant(c *gin.Context) {
-	tenantID := c.Param("id")
-	userID := c.Param("user_id")
-
-	if err := h.tenantService.RemoveUserFromTenant(c.Request.Context(), tenantID, userID); err != nil {
-		h.respondError(c, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "User removed from tenant successfully"})
-}
-
-// toTenantResponse converts a tenant domain model to a response
-func (h *TenantHandler) toTenantResponse(tenant *domain.Tenant) domain.TenantResponse {
-	return domain.TenantResponse{
-		ID:               tenant.ID.Hex(),
-		Name:             tenant.Name,
-		Domain:           tenant.Domain,
-		SubscriptionTier: tenant.SubscriptionTier,
-		IsActive:         tenant.IsActive,
-		Settings:         tenant.Settings,
-		CreatedAt:        tenant.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:        tenant.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}
-}
-
-// respondError responds with an error
-func (h *TenantHandler) respondError(c *gin.Context, err error) {
-	appErr := errors.FromError(err)
-	h.logger.Error("Request failed",
-		zap.String("path", c.Request.URL.Path),
-		zap.String("method", c.Request.Method),
-		zap.String("error", appErr.Message),
-	)
-	c.JSON(appErr.StatusCode, gin.H{"error": appErr})
-}
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vhvplatform/go-shared/errors"
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/service"
+	"go.uber.org/zap"
+)
+
+// TenantHandler handles HTTP requests for tenants
+type TenantHandler struct {
+	tenantService      *service.TenantService
+	domainService      *service.DomainService
+	purgeService       *service.PurgeService
+	roleService        *service.RoleService
+	invitationService  *service.InvitationService
+	entitlementService *service.EntitlementService
+	planService        *service.PlanService
+	trialService       *service.TrialService
+	settingsService    *service.SettingsService
+	auditService       *service.AuditService
+	logger             *logger.Logger
+}
+
+// NewTenantHandler creates a new tenant handler
+func NewTenantHandler(
+	tenantService *service.TenantService,
+	domainService *service.DomainService,
+	purgeService *service.PurgeService,
+	roleService *service.RoleService,
+	invitationService *service.InvitationService,
+	entitlementService *service.EntitlementService,
+	planService *service.PlanService,
+	trialService *service.TrialService,
+	settingsService *service.SettingsService,
+	auditService *service.AuditService,
+	log *logger.Logger,
+) *TenantHandler {
+	return &TenantHandler{
+		tenantService:      tenantService,
+		domainService:      domainService,
+		purgeService:       purgeService,
+		roleService:        roleService,
+		invitationService:  invitationService,
+		entitlementService: entitlementService,
+		planService:        planService,
+		trialService:       trialService,
+		settingsService:    settingsService,
+		auditService:       auditService,
+		logger:             log,
+	}
+}
+
+// CreateTenant handles tenant creation
+func (h *TenantHandler) CreateTenant(c *gin.Context) {
+	var req domain.CreateTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	tenant, err := h.tenantService.CreateTenant(c.Request.Context(), &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": h.toTenantResponse(tenant)})
+}
+
+// GetTenant handles getting a tenant by ID
+func (h *TenantHandler) GetTenant(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	tenant, err := h.tenantService.GetTenant(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.setETag(c, tenant.Version)
+	c.JSON(http.StatusOK, gin.H{"data": h.toTenantResponse(tenant)})
+}
+
+// ListTenants handles listing tenants
+func (h *TenantHandler) ListTenants(c *gin.Context) {
+	page, err := h.parsePageRequest(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	filter, err := h.parseTenantFilter(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	tenants, info, err := h.tenantService.ListTenants(c.Request.Context(), filter, page)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	tenantResponses := make([]domain.TenantResponse, len(tenants))
+	for i, tenant := range tenants {
+		tenantResponses[i] = h.toTenantResponse(tenant)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": domain.ListTenantsResponse{
+			Tenants:       tenantResponses,
+			Total:         info.Total,
+			Page:          page.Page,
+			PageSize:      page.PageSize,
+			NextPageToken: info.NextPageToken,
+			PrevPageToken: info.PrevPageToken,
+		},
+	})
+}
+
+// parsePageRequest reads the page token, page number, page size and total option from the
+// query string. The page number only applies without a page token.
+func (h *TenantHandler) parsePageRequest(c *gin.Context) (domain.PageRequest, error) {
+	page := domain.PageRequest{PageToken: c.Query("page_token")}
+	page.PageSize, _ = strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page.PageToken == "" {
+		page.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	}
+
+	if value := c.Query("include_total"); value != "" {
+		includeTotal, err := strconv.ParseBool(value)
+		if err != nil {
+			return page, errors.BadRequest("include_total must be true or false")
+		}
+		page.IncludeTotal = includeTotal
+	}
+
+	return page, nil
+}
+
+// parseTenantFilter reads the tenant listing filters and sort options from the query string
+func (h *TenantHandler) parseTenantFilter(c *gin.Context) (domain.TenantFilter, error) {
+	filter := domain.TenantFilter{
+		Name:             c.Query("name"),
+		NamePrefix:       c.Query("name_prefix"),
+		Domain:           c.Query("domain"),
+		SubscriptionTier: c.Query("subscription_tier"),
+		Status:           c.Query("status"),
+		SettingsKey:      c.Query("settings_key"),
+		Search:           c.Query("search"),
+		SortBy:           c.Query("sort_by"),
+		SortOrder:        c.Query("sort_order"),
+	}
+
+	if value := c.Query("is_active"); value != "" {
+		isActive, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, errors.BadRequest("is_active must be true or false")
+		}
+		filter.IsActive = &isActive
+	}
+	if value := c.Query("created_after"); value != "" {
+		createdAfter, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, errors.BadRequest("created_after must be an RFC 3339 time")
+		}
+		filter.CreatedAfter = &createdAfter
+	}
+	if value := c.Query("created_before"); value != "" {
+		createdBefore, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, errors.BadRequest("created_before must be an RFC 3339 time")
+		}
+		filter.CreatedBefore = &createdBefore
+	}
+
+	return filter, nil
+}
+
+// setETag sets the ETag header to the version of a resource
+func (h *TenantHandler) setETag(c *gin.Context, version int64) {
+	c.Header("ETag", fmt.Sprintf("%q", strconv.FormatInt(version, 10)))
+}
+
+// ifMatchVersion reads the version a change is conditional on from the If-Match header.
+// It returns false without the header; "*" matches any version, which reads as zero.
+func (h *TenantHandler) ifMatchVersion(c *gin.Context) (int64, bool, error) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" {
+		return 0, false, nil
+	}
+	if value == "*" {
+		return 0, true, nil
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(value, "W/"), `"`), 10, 64)
+	if err != nil || version < 1 {
+		return 0, false, errors.BadRequest("If-Match must be an ETag returned by this API")
+	}
+	return version, true, nil
+}
+
+// respondConditionalError responds to a failed change. A change that was conditional on an
+// If-Match header and found the tenant at another version fails with 412 Precondition Failed.
+func (h *TenantHandler) respondConditionalError(c *gin.Context, err error, conditional bool) {
+	appErr := errors.FromError(err)
+	if conditional && appErr.StatusCode == http.StatusConflict && appErr.Message == domain.TenantModifiedMessage {
+		precondition := *appErr
+		precondition.StatusCode = http.StatusPreconditionFailed
+		err = &precondition
+	}
+	h.respondError(c, err)
+}
+
+// UpdateTenant handles updating a tenant. An If-Match header takes precedence over the
+// expected version in the body.
+func (h *TenantHandler) UpdateTenant(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.UpdateTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	version, ok, err := h.ifMatchVersion(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	if ok {
+		req.ExpectedVersion = version
+	}
+
+	tenant, err := h.tenantService.UpdateTenant(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.respondConditionalError(c, err, ok)
+		return
+	}
+
+	h.setETag(c, tenant.Version)
+	c.JSON(http.StatusOK, gin.H{"data": h.toTenantResponse(tenant)})
+}
+
+// PatchTenant handles a JSON Merge Patch (RFC 7396) of a tenant. Members set to null
+// clear the field, and If-Match makes the patch conditional on the tenant version.
+func (h *TenantHandler) PatchTenant(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	body, err := c.GetRawData()
+	if err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+	patch, err := domain.NewTenantMergePatch(body)
+	if err != nil {
+		h.respondError(c, errors.BadRequest(err.Error()))
+		return
+	}
+
+	var conditional bool
+	patch.ExpectedVersion, conditional, err = h.ifMatchVersion(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	tenant, err := h.tenantService.PatchTenant(c.Request.Context(), tenantID, patch)
+	if err != nil {
+		h.respondConditionalError(c, err, conditional)
+		return
+	}
+
+	h.setETag(c, tenant.Version)
+	c.JSON(http.StatusOK, gin.H{"data": h.toTenantResponse(tenant)})
+}
+
+// ListChildTenants handles listing the direct sub-tenants of a tenant
+func (h *TenantHandler) ListChildTenants(c *gin.Context) {
+	tenantID := c.Param("id")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	tenants, total, err := h.tenantService.ListChildren(c.Request.Context(), tenantID, page, pageSize)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	tenantResponses := make([]domain.TenantResponse, len(tenants))
+	for i, tenant := range tenants {
+		tenantResponses[i] = h.toTenantResponse(tenant)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": domain.ListTenantsResponse{
+			Tenants:  tenantResponses,
+			Total:    &total,
+			Page:     page,
+			PageSize: pageSize,
+		},
+	})
+}
+
+// GetTenantAncestors handles listing the parent tenants of a tenant, nearest first
+func (h *TenantHandler) GetTenantAncestors(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	ancestors, err := h.tenantService.GetAncestors(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	tenantResponses := make([]domain.TenantResponse, len(ancestors))
+	for i, tenant := range ancestors {
+		tenantResponses[i] = h.toTenantResponse(tenant)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tenantResponses})
+}
+
+// SetTenantParent handles moving a tenant under a parent tenant, or to the top level. An
+// If-Match header takes precedence over the expected version in the body.
+func (h *TenantHandler) SetTenantParent(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.SetParentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	version, ok, err := h.ifMatchVersion(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	if ok {
+		req.ExpectedVersion = version
+	}
+
+	tenant, err := h.tenantService.SetParent(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.respondConditionalError(c, err, ok)
+		return
+	}
+
+	h.setETag(c, tenant.Version)
+	c.JSON(http.StatusOK, gin.H{"data": h.toTenantResponse(tenant)})
+}
+
+// DeleteTenant handles scheduling a tenant for deletion. The reason and actor body is optional.
+func (h *TenantHandler) DeleteTenant(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.TenantStatusRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.respondError(c, errors.BadRequest("Invalid request body"))
+			return
+		}
+	}
+
+	tenant, err := h.purgeService.ScheduleDeletion(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.toTenantResponse(tenant)})
+}
+
+// CancelTenantDeletion handles cancelling the scheduled deletion of a tenant
+func (h *TenantHandler) CancelTenantDeletion(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.TenantStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	tenant, err := h.purgeService.CancelDeletion(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.toTenantResponse(tenant)})
+}
+
+// PurgeTenant handles purging all data of a tenant immediately
+func (h *TenantHandler) PurgeTenant(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.TenantStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	report, err := h.purgeService.PurgeTenant(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.toPurgeReportResponse(report)})
+}
+
+// GetPurgeReport handles getting the latest purge report of a tenant
+func (h *TenantHandler) GetPurgeReport(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	report, err := h.purgeService.GetPurgeReport(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.toPurgeReportResponse(report)})
+}
+
+// SuspendTenant handles suspending a tenant
+func (h *TenantHandler) SuspendTenant(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.TenantStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	tenant, err := h.tenantService.SuspendTenant(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.toTenantResponse(tenant)})
+}
+
+// ReactivateTenant handles reactivating a suspended tenant
+func (h *TenantHandler) ReactivateTenant(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.TenantStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	tenant, err := h.tenantService.ReactivateTenant(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.toTenantResponse(tenant)})
+}
+
+// AddUserToTenant handles adding a user to a tenant
+func (h *TenantHandler) AddUserToTenant(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.AddUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	if err := h.tenantService.AddUserToTenant(c.Request.Context(), tenantID, req.UserID, req.Role); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User added to tenant successfully"})
+}
+
+// RemoveUserFromTenant handles removing a user from a tenant
+func (h *TenantHandler) RemoveUserFromTenant(c *gin.Context) {
+	tenantID := c.Param("id")
+	userID := c.Param("user_id")
+
+	if err := h.tenantService.RemoveUserFromTenant(c.Request.Context(), tenantID, userID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User removed from tenant successfully"})
+}
+
+// ListMembers handles listing the members of a tenant
+func (h *TenantHandler) ListMembers(c *gin.Context) {
+	tenantID := c.Param("id")
+	page, err := h.parsePageRequest(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	filter := domain.MemberFilter{
+		Role:   c.Query("role"),
+		Search: c.Query("search"),
+	}
+
+	members, info, err := h.tenantService.ListMembers(c.Request.Context(), tenantID, filter, page)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	memberResponses := make([]domain.TenantMemberResponse, len(members))
+	for i, member := range members {
+		memberResponses[i] = h.toTenantMemberResponse(member)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": domain.ListMembersResponse{
+			Members:       memberResponses,
+			Total:         info.Total,
+			Page:          page.Page,
+			PageSize:      page.PageSize,
+			NextPageToken: info.NextPageToken,
+			PrevPageToken: info.PrevPageToken,
+		},
+	})
+}
+
+// ListUserTenants handles listing the tenants a user belongs to
+func (h *TenantHandler) ListUserTenants(c *gin.Context) {
+	userID := c.Param("id")
+	page, err := h.parsePageRequest(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	filter := domain.MemberFilter{Role: c.Query("role")}
+
+	userTenants, info, err := h.tenantService.ListUserTenants(c.Request.Context(), userID, filter, page)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	tenantResponses := make([]domain.UserTenantResponse, len(userTenants))
+	for i, userTenant := range userTenants {
+		tenantResponses[i] = h.toUserTenantResponse(userTenant)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": domain.ListUserTenantsResponse{
+			Tenants:       tenantResponses,
+			Total:         info.Total,
+			Page:          page.Page,
+			PageSize:      page.PageSize,
+			NextPageToken: info.NextPageToken,
+			PrevPageToken: info.PrevPageToken,
+		},
+	})
+}
+
+// GetMemberHistory handles getting the membership history of a user in a tenant
+func (h *TenantHandler) GetMemberHistory(c *gin.Context) {
+	tenantID := c.Param("id")
+	userID := c.Param("user_id")
+
+	membership, err := h.tenantService.GetMemberHistory(c.Request.Context(), tenantID, userID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": domain.MemberHistoryResponse{
+			UserID:   membership.UserID,
+			Role:     membership.Role,
+			IsActive: membership.IsActive,
+			History:  membership.History,
+		},
+	})
+}
+
+// UpdateMemberRole handles changing the role of a tenant member
+func (h *TenantHandler) UpdateMemberRole(c *gin.Context) {
+	tenantID := c.Param("id")
+	userID := c.Param("user_id")
+
+	var req domain.UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	member, err := h.tenantService.UpdateMemberRole(c.Request.Context(), tenantID, userID, req.Role)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.toTenantMemberResponse(member)})
+}
+
+// TransferOwnership handles handing ownership of a tenant to another member
+func (h *TenantHandler) TransferOwnership(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	if err := h.tenantService.TransferOwnership(c.Request.Context(), tenantID, &req); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ownership transferred successfully"})
+}
+
+// ListRoles handles listing the built-in and custom roles of a tenant
+func (h *TenantHandler) ListRoles(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	roles, err := h.roleService.ListRoles(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": roles})
+}
+
+// CreateRole handles defining a custom role for a tenant
+func (h *TenantHandler) CreateRole(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.TenantRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	role, err := h.roleService.CreateRole(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": role})
+}
+
+// UpdateRole handles replacing the permissions of a custom role
+func (h *TenantHandler) UpdateRole(c *gin.Context) {
+	tenantID := c.Param("id")
+	name := c.Param("role")
+
+	var req domain.TenantRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	role, err := h.roleService.UpdateRole(c.Request.Context(), tenantID, name, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": role})
+}
+
+// DeleteRole handles deleting a custom role
+func (h *TenantHandler) DeleteRole(c *gin.Context) {
+	tenantID := c.Param("id")
+	name := c.Param("role")
+
+	if err := h.roleService.DeleteRole(c.Request.Context(), tenantID, name); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role deleted successfully"})
+}
+
+// CheckPermission handles checking if a user holds a permission in a tenant
+func (h *TenantHandler) CheckPermission(c *gin.Context) {
+	tenantID := c.Param("id")
+	userID := c.Param("user_id")
+	permission := c.Param("permission")
+
+	check, err := h.roleService.CheckPermission(c.Request.Context(), tenantID, userID, permission)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": check})
+}
+
+// CreateInvitation handles inviting a person to a tenant by email or phone
+func (h *TenantHandler) CreateInvitation(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	invitation, token, err := h.invitationService.CreateInvitation(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": h.toInvitationResponse(invitation, token)})
+}
+
+// ListInvitations handles listing the invitations of a tenant
+func (h *TenantHandler) ListInvitations(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	invitations, err := h.invitationService.ListInvitations(c.Request.Context(), tenantID, c.Query("status"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	responses := make([]domain.InvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		responses[i] = h.toInvitationResponse(invitation, "")
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": responses})
+}
+
+// ResendInvitation handles sending a pending invitation again with a new token
+func (h *TenantHandler) ResendInvitation(c *gin.Context) {
+	tenantID := c.Param("id")
+	invitationID := c.Param("invitation_id")
+
+	invitation, token, err := h.invitationService.ResendInvitation(c.Request.Context(), tenantID, invitationID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.toInvitationResponse(invitation, token)})
+}
+
+// RevokeInvitation handles revoking a pending invitation
+func (h *TenantHandler) RevokeInvitation(c *gin.Context) {
+	tenantID := c.Param("id")
+	invitationID := c.Param("invitation_id")
+
+	if err := h.invitationService.RevokeInvitation(c.Request.Context(), tenantID, invitationID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation revoked successfully"})
+}
+
+// AcceptInvitation handles redeeming an invitation token
+func (h *TenantHandler) AcceptInvitation(c *gin.Context) {
+	var req domain.InvitationTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	invitation, err := h.invitationService.AcceptInvitation(c.Request.Context(), req.Token)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.toInvitationResponse(invitation, "")})
+}
+
+// DeclineInvitation handles declining an invitation token
+func (h *TenantHandler) DeclineInvitation(c *gin.Context) {
+	var req domain.InvitationTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	invitation, err := h.invitationService.DeclineInvitation(c.Request.Context(), req.Token)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.toInvitationResponse(invitation, "")})
+}
+
+// GetEntitlements handles getting the effective entitlements and usage of a tenant
+func (h *TenantHandler) GetEntitlements(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	entitlements, err := h.entitlementService.GetEntitlements(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": entitlements})
+}
+
+// UpdateEntitlementOverrides handles replacing the entitlement overrides of a tenant.
+// A null body restores the tier entitlements.
+func (h *TenantHandler) UpdateEntitlementOverrides(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var overrides *domain.EntitlementOverrides
+	if err := c.ShouldBindJSON(&overrides); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	entitlements, err := h.entitlementService.UpdateOverrides(c.Request.Context(), tenantID, overrides)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": entitlements})
+}
+
+// GetPlan handles getting the subscription tier of a tenant with its scheduled change and plan history
+func (h *TenantHandler) GetPlan(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	plan, err := h.planService.GetPlan(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": plan})
+}
+
+// ChangePlan handles changing the subscription tier of a tenant, now or at a future date
+func (h *TenantHandler) ChangePlan(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.ChangePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	plan, err := h.planService.ChangePlan(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": plan})
+}
+
+// CancelScheduledPlanChange handles cancelling the scheduled plan change of a tenant
+func (h *TenantHandler) CancelScheduledPlanChange(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.CancelPlanChangeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.respondError(c, errors.BadRequest("Invalid request body"))
+			return
+		}
+	}
+
+	plan, err := h.planService.CancelScheduledChange(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": plan})
+}
+
+// StartTrial handles starting a trial of a paid tier for a tenant
+func (h *TenantHandler) StartTrial(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.StartTrialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	tenant, err := h.trialService.StartTrial(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.toTenantResponse(tenant)})
+}
+
+// ListExpiringTrials handles listing tenants whose trial ends within the next within_days days
+func (h *TenantHandler) ListExpiringTrials(c *gin.Context) {
+	withinDays, _ := strconv.Atoi(c.DefaultQuery("within_days", "7"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	within := time.Duration(withinDays) * 24 * time.Hour
+	tenants, total, err := h.trialService.ListExpiringTrials(c.Request.Context(), within, page, pageSize)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	tenantResponses := make([]domain.TenantResponse, len(tenants))
+	for i, tenant := range tenants {
+		tenantResponses[i] = h.toTenantResponse(tenant)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": domain.ListTenantsResponse{
+			Tenants:  tenantResponses,
+			Total:    &total,
+			Page:     page,
+			PageSize: pageSize,
+		},
+	})
+}
+
+// GetEffectiveSettings handles resolving the settings of a tenant with the registered
+// defaults. The visibility query parameter selects the audience and defaults to public.
+func (h *TenantHandler) GetEffectiveSettings(c *gin.Context) {
+	tenantID := c.Param("id")
+	visibility := c.DefaultQuery("visibility", domain.SettingVisibilityPublic)
+
+	settings, err := h.settingsService.GetEffectiveSettings(c.Request.Context(), tenantID, visibility)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": domain.EffectiveSettingsResponse{
+		TenantID:   tenantID,
+		Visibility: visibility,
+		Settings:   settings.Values,
+		Defaulted:  settings.Defaulted,
+	}})
+}
+
+// ListSettingDefinitions handles listing the registered settings, optionally of one service
+func (h *TenantHandler) ListSettingDefinitions(c *gin.Context) {
+	definitions, err := h.settingsService.ListSettingDefinitions(c.Request.Context(), c.Query("service"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": definitions})
+}
+
+// GetSettingDefinition handles getting the definition of a setting key
+func (h *TenantHandler) GetSettingDefinition(c *gin.Context) {
+	definition, err := h.settingsService.GetSettingDefinition(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": definition})
+}
+
+// RegisterSetting handles registering or replacing the definition of a setting key
+func (h *TenantHandler) RegisterSetting(c *gin.Context) {
+	var req domain.RegisterSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	definition, err := h.settingsService.RegisterSetting(c.Request.Context(), c.Param("key"), &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": definition})
+}
+
+// DeleteSettingDefinition handles unregistering a setting key
+func (h *TenantHandler) DeleteSettingDefinition(c *gin.Context) {
+	if err := h.settingsService.DeleteSettingDefinition(c.Request.Context(), c.Param("key")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Setting deleted successfully"})
+}
+
+// ListAuditEvents handles listing the audit log, newest first. Events are filtered by the
+// tenant_id, actor, action, target_type, target_id, created_after and created_before
+// query parameters.
+func (h *TenantHandler) ListAuditEvents(c *gin.Context) {
+	h.listAuditEvents(c, c.Query("tenant_id"))
+}
+
+// ListTenantAuditEvents handles listing the audit log of a tenant, newest first
+func (h *TenantHandler) ListTenantAuditEvents(c *gin.Context) {
+	h.listAuditEvents(c, c.Param("id"))
+}
+
+// listAuditEvents lists a page of the audit events of a tenant, or of every tenant
+func (h *TenantHandler) listAuditEvents(c *gin.Context, tenantID string) {
+	page, err := h.parsePageRequest(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	filter, err := h.parseAuditEventFilter(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	filter.TenantID = tenantID
+
+	events, info, err := h.auditService.ListAuditEvents(c.Request.Context(), filter, page)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": domain.ListAuditEventsResponse{
+			Events:        events,
+			Total:         info.Total,
+			Page:          page.Page,
+			PageSize:      page.PageSize,
+			NextPageToken: info.NextPageToken,
+			PrevPageToken: info.PrevPageToken,
+		},
+	})
+}
+
+// parseAuditEventFilter reads the audit log filters from the query string
+func (h *TenantHandler) parseAuditEventFilter(c *gin.Context) (domain.AuditEventFilter, error) {
+	filter := domain.AuditEventFilter{
+		ActorID:    c.Query("actor"),
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+	}
+
+	if value := c.Query("created_after"); value != "" {
+		createdAfter, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, errors.BadRequest("created_after must be an RFC 3339 time")
+		}
+		filter.CreatedAfter = &createdAfter
+	}
+	if value := c.Query("created_before"); value != "" {
+		createdBefore, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, errors.BadRequest("created_before must be an RFC 3339 time")
+		}
+		filter.CreatedBefore = &createdBefore
+	}
+
+	return filter, nil
+}
+
+// GetTenantConfig handles getting the configuration of a tenant
+func (h *TenantHandler) GetTenantConfig(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	config, err := h.tenantService.GetTenantConfig(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": config})
+}
+
+// GetEffectiveTenantConfig handles getting the configuration that applies to a tenant,
+// including settings inherited from parent tenants
+func (h *TenantHandler) GetEffectiveTenantConfig(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	config, err := h.tenantService.GetEffectiveTenantConfig(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": config})
+}
+
+// UpdateTenantConfig handles replacing the configuration of a tenant. An If-Match header
+// makes the change conditional on the tenant version.
+func (h *TenantHandler) UpdateTenantConfig(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.TenantConfig
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	version, conditional, err := h.ifMatchVersion(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	config, err := h.tenantService.UpdateTenantConfig(c.Request.Context(), tenantID, &req, version)
+	if err != nil {
+		h.respondConditionalError(c, err, conditional)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": config})
+}
+
+// GetDefaultService handles resolving the default service of a tenant
+func (h *TenantHandler) GetDefaultService(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	info, err := h.tenantService.GetDefaultService(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": info})
+}
+
+// ClaimDomain handles claiming a custom domain for a tenant
+func (h *TenantHandler) ClaimDomain(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	var req domain.ClaimDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, errors.BadRequest("Invalid request body"))
+		return
+	}
+
+	claim, err := h.domainService.ClaimDomain(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": h.toTenantDomainResponse(claim)})
+}
+
+// ListDomains handles listing the custom domains of a tenant
+func (h *TenantHandler) ListDomains(c *gin.Context) {
+	tenantID := c.Param("id")
+
+	claims, err := h.domainService.ListDomains(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	responses := make([]domain.TenantDomainResponse, len(claims))
+	for i, claim := range claims {
+		responses[i] = h.toTenantDomainResponse(claim)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": responses})
+}
+
+// VerifyDomain handles checking a domain claim immediately
+func (h *TenantHandler) VerifyDomain(c *gin.Context) {
+	tenantID := c.Param("id")
+	domainID := c.Param("domain_id")
+
+	claim, err := h.domainService.VerifyDomain(c.Request.Context(), tenantID, domainID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.toTenantDomainResponse(claim)})
+}
+
+// SetPrimaryDomain handles making a verified domain the primary domain of a tenant
+func (h *TenantHandler) SetPrimaryDomain(c *gin.Context) {
+	tenantID := c.Param("id")
+	domainID := c.Param("domain_id")
+
+	claim, err := h.domainService.SetPrimaryDomain(c.Request.Context(), tenantID, domainID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.toTenantDomainResponse(claim)})
+}
+
+// RemoveDomain handles removing a custom domain from a tenant
+func (h *TenantHandler) RemoveDomain(c *gin.Context) {
+	tenantID := c.Param("id")
+	domainID := c.Param("domain_id")
+
+	if err := h.domainService.RemoveDomain(c.Request.Context(), tenantID, domainID); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Domain removed successfully"})
+}
+
+// toTenantDomainResponse converts a domain claim to a response with its verification instructions
+func (h *TenantHandler) toTenantDomainResponse(claim *domain.TenantDomain) domain.TenantDomainResponse {
+	response := domain.TenantDomainResponse{
+		ID:                 claim.ID.Hex(),
+		Domain:             claim.Domain,
+		Status:             claim.Status,
+		VerificationMethod: claim.VerificationMethod,
+		IsPrimary:          claim.IsPrimary,
+		Attempts:           claim.Attempts,
+		LastError:          claim.LastError,
+		CreatedAt:          claim.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	if claim.Status == domain.DomainStatusVerified {
+		response.VerifiedAt = claim.VerifiedAt.Format("2006-01-02T15:04:05Z07:00")
+		return response
+	}
+
+	response.ExpiresAt = claim.ExpiresAt.Format("2006-01-02T15:04:05Z07:00")
+	if claim.VerificationMethod == domain.DomainVerificationHTTP {
+		response.WellKnownURL = claim.WellKnownURL()
+		response.WellKnownContent = claim.Token
+	} else {
+		response.TXTRecordName = claim.TXTRecordName()
+		response.TXTRecordValue = claim.TXTRecordValue()
+	}
+	return response
+}
+
+// toTenantMemberResponse converts a tenant membership to a member response
+func (h *TenantHandler) toTenantMemberResponse(member *domain.TenantUser) domain.TenantMemberResponse {
+	return domain.TenantMemberResponse{
+		UserID:    member.UserID,
+		Role:      member.Role,
+		JoinedAt:  member.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: member.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// toUserTenantResponse converts a tenant of a user to a response
+func (h *TenantHandler) toUserTenantResponse(userTenant *domain.UserTenant) domain.UserTenantResponse {
+	response := domain.UserTenantResponse{
+		TenantID: userTenant.TenantID,
+		Role:     userTenant.Role,
+		JoinedAt: userTenant.JoinedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if userTenant.Tenant != nil {
+		response.TenantName = userTenant.Tenant.Name
+		response.Subdomain = userTenant.Tenant.Subdomain
+		response.Status = userTenant.Tenant.CurrentStatus()
+	}
+	return response
+}
+
+// toInvitationResponse converts an invitation to a response, including the token when one was just issued
+func (h *TenantHandler) toInvitationResponse(invitation *domain.TenantInvitation, token string) domain.InvitationResponse {
+	return domain.InvitationResponse{
+		ID:         invitation.ID.Hex(),
+		TenantID:   invitation.TenantID,
+		Email:      invitation.Email,
+		Phone:      invitation.Phone,
+		Role:       invitation.Role,
+		Status:     invitation.CurrentStatus(time.Now()),
+		InvitedBy:  invitation.InvitedBy,
+		AcceptedBy: invitation.AcceptedBy,
+		SendCount:  invitation.SendCount,
+		Token:      token,
+		ExpiresAt:  invitation.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedAt:  invitation.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// toPurgeReportResponse converts a purge report to a response
+func (h *TenantHandler) toPurgeReportResponse(report *domain.PurgeReport) domain.PurgeReportResponse {
+	response := domain.PurgeReportResponse{
+		ID:            report.ID.Hex(),
+		TenantID:      report.TenantID,
+		TenantName:    report.TenantName,
+		Status:        report.Status,
+		Reason:        report.Reason,
+		Actor:         report.Actor,
+		DeletedCounts: report.DeletedCounts,
+		Errors:        report.Errors,
+		StartedAt:     report.StartedAt.Format("2006-01-02T15:04:05Z07:00"),
+		CompletedAt:   report.CompletedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if report.PurgeAfter != nil {
+		response.PurgeAfter = report.PurgeAfter.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return response
+}
+
+// toTenantResponse converts a tenant domain model to a response
+func (h *TenantHandler) toTenantResponse(tenant *domain.Tenant) domain.TenantResponse {
+	response := domain.TenantResponse{
+		ID:               tenant.ID.Hex(),
+		Name:             tenant.Name,
+		Domain:           tenant.Domain,
+		Subdomain:        tenant.Subdomain,
+		ParentID:         tenant.ParentID,
+		SubscriptionTier: tenant.SubscriptionTier,
+		IsActive:         tenant.IsActive,
+		Status:           tenant.CurrentStatus(),
+		StatusReason:     tenant.StatusReason,
+		StatusHistory:    tenant.StatusHistory,
+		Config:           tenant.Config,
+		DefaultService:   tenant.DefaultService,
+		AuthSettings:     tenant.AuthSettings,
+		Settings:         tenant.Settings,
+		CreatedAt:        tenant.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:        tenant.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Version:          tenant.Version,
+	}
+	if tenant.PurgeAfter != nil {
+		response.PurgeAfter = tenant.PurgeAfter.Format("2006-01-02T15:04:05Z07:00")
+	}
+	if tenant.Trial != nil {
+		response.Trial = h.toTrialResponse(tenant.Trial)
+	}
+	return response
+}
+
+// toTrialResponse converts the trial of a tenant to a response
+func (h *TenantHandler) toTrialResponse(trial *domain.TenantTrial) *domain.TrialResponse {
+	now := time.Now()
+	response := &domain.TrialResponse{
+		Tier:          trial.Tier,
+		Status:        trial.CurrentStatus(now),
+		StartedAt:     trial.StartedAt.Format("2006-01-02T15:04:05Z07:00"),
+		EndsAt:        trial.EndsAt.Format("2006-01-02T15:04:05Z07:00"),
+		GraceEndsAt:   trial.GraceEndsAt.Format("2006-01-02T15:04:05Z07:00"),
+		DaysRemaining: trial.DaysRemaining(now),
+	}
+	if !trial.EndedAt.IsZero() {
+		response.EndedAt = trial.EndedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return response
+}
+
+// respondError responds with an error
+func (h *TenantHandler) respondError(c *gin.Context, err error) {
+	appErr := errors.FromError(err)
+	h.logger.Error("Request failed",
+		zap.String("path", c.Request.URL.Path),
+		zap.String("method", c.Request.Method),
+		zap.String("error", appErr.Message),
+	)
+	c.JSON(appErr.StatusCode, gin.H{"error": appErr})
+}
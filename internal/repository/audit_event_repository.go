@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// auditRetentionIndex is the name of the TTL index that expires old audit events
+const auditRetentionIndex = "createdAt_retention"
+
+// AuditEventRepository handles audit log data access. The audit log is append-only:
+// events are never updated, and only removed by the retention index.
+type AuditEventRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAuditEventRepository creates a new audit event repository. Events older than the
+// retention are expired by MongoDB; a zero retention keeps them forever.
+func NewAuditEventRepository(db *mongo.Database, retention time.Duration) *AuditEventRepository {
+	collection := db.Collection("audit_events")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "createdAt", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "actor.id", Value: 1}, {Key: "createdAt", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "action", Value: 1}, {Key: "createdAt", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "targetType", Value: 1}, {Key: "targetId", Value: 1}, {Key: "createdAt", Value: -1}},
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(ctx, indexes)
+
+	_ = setRetentionIndex(ctx, collection, auditRetentionIndex, "createdAt", retention)
+
+	return &AuditEventRepository{collection: collection}
+}
+
+// setRetentionIndex creates, changes or drops the TTL index that expires documents of a
+// collection once a time field is older than the retention
+func setRetentionIndex(ctx context.Context, collection *mongo.Collection, name, field string, retention time.Duration) error {
+	if retention <= 0 {
+		_, err := collection.Indexes().DropOne(ctx, name)
+		return err
+	}
+
+	seconds := int32(retention / time.Second)
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: field, Value: 1}},
+		Options: options.Index().SetName(name).SetExpireAfterSeconds(seconds),
+	})
+	if err == nil {
+		return nil
+	}
+
+	// The index exists with another retention; collMod changes it in place
+	return collection.Database().RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: collection.Name()},
+		{Key: "index", Value: bson.M{"name": name, "expireAfterSeconds": seconds}},
+	}).Err()
+}
+
+// Insert appends an event to the audit log
+func (r *AuditEventRepository) Insert(ctx context.Context, event *domain.AuditEvent) error {
+	event.ID = primitive.NewObjectID()
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	if _, err := r.collection.InsertOne(ctx, event); err != nil {
+		return fmt.Errorf("failed to insert audit event: %w", err)
+	}
+	return nil
+}
+
+// List lists a page of the audit events matching a filter, newest first
+func (r *AuditEventRepository) List(ctx context.Context, filter domain.AuditEventFilter, page domain.PageRequest) ([]*domain.AuditEvent, *domain.PageInfo, error) {
+	query := bson.M{}
+	if filter.TenantID != "" {
+		query["tenantId"] = filter.TenantID
+	}
+	if filter.ActorID != "" {
+		query["actor.id"] = filter.ActorID
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	if filter.TargetType != "" {
+		query["targetType"] = filter.TargetType
+	}
+	if filter.TargetID != "" {
+		query["targetId"] = filter.TargetID
+	}
+	if filter.CreatedAfter != nil || filter.CreatedBefore != nil {
+		createdAt := bson.M{}
+		if filter.CreatedAfter != nil {
+			createdAt["$gte"] = *filter.CreatedAfter
+		}
+		if filter.CreatedBefore != nil {
+			createdAt["$lt"] = *filter.CreatedBefore
+		}
+		query["createdAt"] = createdAt
+	}
+
+	keyset, err := newKeysetPage(page, "createdAt", true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events, info, err := findPage(ctx, r.collection, query, keyset,
+		func(event *domain.AuditEvent) (interface{}, primitive.ObjectID) {
+			return event.CreatedAt, event.ID
+		})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	return events, info, nil
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// outboxRetentionIndex is the name of the TTL index that expires published events
+const outboxRetentionIndex = "publishedAt_retention"
+
+// OutboxRepository handles the outbox of tenant events waiting to be published. Events
+// are added in the transaction of the change they announce and numbered per tenant.
+type OutboxRepository struct {
+	collection *mongo.Collection
+	sequences  *mongo.Collection // Last sequence number of each tenant
+	leases     *mongo.Collection // Relay instance currently publishing each tenant
+}
+
+// NewOutboxRepository creates a new outbox repository. Published events are expired by
+// MongoDB after the retention; a zero retention keeps them forever.
+func NewOutboxRepository(db *mongo.Database, retention time.Duration) *OutboxRepository {
+	collection := db.Collection("outbox_events")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tenantId", Value: 1}, {Key: "sequence", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "tenantId", Value: 1}, {Key: "sequence", Value: 1}},
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(ctx, indexes)
+	_ = setRetentionIndex(ctx, collection, outboxRetentionIndex, "publishedAt", retention)
+
+	return &OutboxRepository{
+		collection: collection,
+		sequences:  db.Collection("outbox_sequences"),
+		leases:     db.Collection("outbox_leases"),
+	}
+}
+
+// Insert adds an event to the outbox with the next sequence number of its tenant. Called
+// in a transaction, the sequence update also orders concurrent changes of the tenant: a
+// second transaction waits for or conflicts with the first, so sequence numbers follow
+// commit order.
+func (r *OutboxRepository) Insert(ctx context.Context, event *domain.TenantEvent) error {
+	var counter struct {
+		Sequence int64 `bson:"sequence"`
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := r.sequences.FindOneAndUpdate(ctx,
+		bson.M{"_id": event.TenantID},
+		bson.M{"$inc": bson.M{"sequence": 1}},
+		opts,
+	).Decode(&counter)
+	if err != nil {
+		return fmt.Errorf("failed to allocate event sequence: %w", err)
+	}
+	event.Sequence = counter.Sequence
+
+	outboxEvent := &domain.OutboxEvent{
+		TenantEvent:   *event,
+		Status:        domain.OutboxStatusPending,
+		NextAttemptAt: event.OccurredAt,
+	}
+	if _, err := r.collection.InsertOne(ctx, outboxEvent); err != nil {
+		return fmt.Errorf("failed to insert outbox event: %w", err)
+	}
+	return nil
+}
+
+// FindPendingTenants lists up to limit tenants whose oldest pending event is due at now,
+// the tenants waiting longest first
+func (r *OutboxRepository) FindPendingTenants(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": domain.OutboxStatusPending}}},
+		{{Key: "$sort", Value: bson.D{{Key: "tenantId", Value: 1}, {Key: "sequence", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":           "$tenantId",
+			"nextAttemptAt": bson.M{"$first": "$nextAttemptAt"},
+			"occurredAt":    bson.M{"$first": "$occurredAt"},
+		}}},
+		{{Key: "$match", Value: bson.M{"nextAttemptAt": bson.M{"$lte": now}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "occurredAt", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tenants with pending events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		TenantID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode tenants with pending events: %w", err)
+	}
+
+	tenantIDs := make([]string, len(groups))
+	for i, group := range groups {
+		tenantIDs[i] = group.TenantID
+	}
+	return tenantIDs, nil
+}
+
+// FindPending lists up to limit pending events of a tenant in sequence order
+func (r *OutboxRepository) FindPending(ctx context.Context, tenantID string, limit int) ([]*domain.OutboxEvent, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "sequence", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, bson.M{"tenantId": tenantID, "status": domain.OutboxStatusPending}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var events []*domain.OutboxEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("failed to decode pending events: %w", err)
+	}
+	return events, nil
+}
+
+// MarkPublished records the delivery of an event
+func (r *OutboxRepository) MarkPublished(ctx context.Context, event *domain.OutboxEvent) error {
+	now := time.Now()
+	update := bson.M{
+		"$set":   bson.M{"status": domain.OutboxStatusPublished, "publishedAt": now},
+		"$inc":   bson.M{"attempts": 1},
+		"$unset": bson.M{"lastError": ""},
+	}
+
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": event.ID}, update); err != nil {
+		return fmt.Errorf("failed to mark event published: %w", err)
+	}
+	event.Status = domain.OutboxStatusPublished
+	event.PublishedAt = &now
+	event.Attempts++
+	return nil
+}
+
+// MarkFailed records a failed delivery of an event and when to try it again
+func (r *OutboxRepository) MarkFailed(ctx context.Context, event *domain.OutboxEvent, cause error, nextAttemptAt time.Time) error {
+	update := bson.M{
+		"$set": bson.M{"nextAttemptAt": nextAttemptAt, "lastError": cause.Error()},
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": event.ID}, update); err != nil {
+		return fmt.Errorf("failed to mark event failed: %w", err)
+	}
+	event.NextAttemptAt = nextAttemptAt
+	event.LastError = cause.Error()
+	event.Attempts++
+	return nil
+}
+
+// AcquireLease gives a relay instance the exclusive right to publish the events of a
+// tenant until the lease expires. Returns false if another instance holds the lease.
+func (r *OutboxRepository) AcquireLease(ctx context.Context, tenantID, owner string, duration time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": tenantID,
+		"$or": []bson.M{
+			{"owner": owner},
+			{"expiresAt": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"owner": owner, "expiresAt": now.Add(duration)}}
+
+	// A held lease does not match, so the upsert collides with it on _id
+	_, err := r.leases.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire outbox lease: %w", err)
+	}
+	return true, nil
+}
+
+// ReleaseLease gives up the lease of a relay instance on a tenant
+func (r *OutboxRepository) ReleaseLease(ctx context.Context, tenantID, owner string) error {
+	if _, err := r.leases.DeleteOne(ctx, bson.M{"_id": tenantID, "owner": owner}); err != nil {
+		return fmt.Errorf("failed to release outbox lease: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// pageCursor is the listing position a page token continues from: the sort key and ID
+// of the last item of the previous page, or of the first item when paging backward
+type pageCursor struct {
+	SortField  string             `bson:"f"`
+	Descending bool               `bson:"d"`
+	Value      interface{}        `bson:"v"`
+	ID         primitive.ObjectID `bson:"i"`
+	Backward   bool               `bson:"b"`
+}
+
+// keysetPage is a page of a listing ordered by a sort field with the ID breaking ties
+type keysetPage struct {
+	sortField  string
+	descending bool
+	cursor     *pageCursor
+	skip       int64
+	size       int
+	countTotal bool
+}
+
+// newKeysetPage prepares a page request for a listing in the given order
+func newKeysetPage(page domain.PageRequest, sortField string, descending bool) (*keysetPage, error) {
+	p := &keysetPage{
+		sortField:  sortField,
+		descending: descending,
+		size:       page.PageSize,
+		countTotal: page.IncludeTotal,
+	}
+	if p.size < 1 {
+		p.size = domain.DefaultPageSize
+	}
+	if p.size > domain.MaxPageSize {
+		p.size = domain.MaxPageSize
+	}
+
+	if page.PageToken != "" {
+		cursor, err := decodePageToken(page.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.SortField != sortField || cursor.Descending != descending {
+			return nil, domain.ErrInvalidPageToken
+		}
+		p.cursor = cursor
+	} else if page.Page > 1 {
+		p.skip = int64(page.Page-1) * int64(p.size)
+	}
+	return p, nil
+}
+
+// findPage runs a keyset paginated query. The key function returns the sort key and ID
+// of an item, from which the tokens of the neighbouring pages are built.
+func findPage[T any](
+	ctx context.Context,
+	collection *mongo.Collection,
+	query bson.M,
+	page *keysetPage,
+	key func(T) (interface{}, primitive.ObjectID),
+) ([]T, *domain.PageInfo, error) {
+	info := &domain.PageInfo{}
+	if page.countTotal {
+		total, err := collection.CountDocuments(ctx, query)
+		if err != nil {
+			return nil, nil, err
+		}
+		info.Total = &total
+	}
+
+	backward := page.cursor != nil && page.cursor.Backward
+	// Paging backward walks the listing in reverse from the cursor
+	descending := page.descending != backward
+	direction := 1
+	if descending {
+		direction = -1
+	}
+
+	if page.cursor != nil {
+		operator := "$gt"
+		if descending {
+			operator = "$lt"
+		}
+		query = bson.M{"$and": []bson.M{query, {"$or": []bson.M{
+			{page.sortField: bson.M{operator: page.cursor.Value}},
+			{page.sortField: page.cursor.Value, "_id": bson.M{operator: page.cursor.ID}},
+		}}}}
+	}
+
+	// One extra item tells whether there is a page beyond this one
+	opts := options.Find().
+		SetSkip(page.skip).
+		SetLimit(int64(page.size + 1)).
+		SetSort(bson.D{{Key: page.sortField, Value: direction}, {Key: "_id", Value: direction}})
+
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []T
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, nil, err
+	}
+
+	hasMore := len(items) > page.size
+	if hasMore {
+		items = items[:page.size]
+	}
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	if len(items) == 0 {
+		return items, info, nil
+	}
+
+	hasNext := hasMore
+	hasPrev := page.cursor != nil || page.skip > 0
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+	if hasNext {
+		value, id := key(items[len(items)-1])
+		info.NextPageToken = page.token(value, id, false)
+	}
+	if hasPrev {
+		value, id := key(items[0])
+		info.PrevPageToken = page.token(value, id, true)
+	}
+
+	return items, info, nil
+}
+
+// token encodes the position next to an item as a page token
+func (p *keysetPage) token(value interface{}, id primitive.ObjectID, backward bool) string {
+	data, err := bson.Marshal(pageCursor{
+		SortField:  p.sortField,
+		Descending: p.descending,
+		Value:      value,
+		ID:         id,
+		Backward:   backward,
+	})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodePageToken decodes a page token issued by findPage
+func decodePageToken(token string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, domain.ErrInvalidPageToken
+	}
+
+	var cursor pageCursor
+	if err := bson.Unmarshal(data, &cursor); err != nil || cursor.SortField == "" || cursor.ID.IsZero() {
+		return nil, domain.ErrInvalidPageToken
+	}
+	return &cursor, nil
+}
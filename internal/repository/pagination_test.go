@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	tests := []struct {
+		name       string
+		sortField  string
+		descending bool
+		value      interface{}
+		backward   bool
+	}{
+		{"string ascending", "name", false, "acme", false},
+		{"string descending backward", "name", true, "acme", true},
+		{"integer", "version", false, int64(42), false},
+		{"object ID", "_id", true, id, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := newKeysetPage(domain.PageRequest{}, tt.sortField, tt.descending)
+			if err != nil {
+				t.Fatalf("newKeysetPage: %v", err)
+			}
+
+			token := page.token(tt.value, id, tt.backward)
+			if token == "" {
+				t.Fatal("token is empty")
+			}
+
+			next, err := newKeysetPage(domain.PageRequest{PageToken: token}, tt.sortField, tt.descending)
+			if err != nil {
+				t.Fatalf("newKeysetPage with token: %v", err)
+			}
+			cursor := next.cursor
+			if cursor == nil {
+				t.Fatal("cursor not decoded")
+			}
+			if cursor.ID != id || cursor.Backward != tt.backward {
+				t.Errorf("cursor = {ID: %s, Backward: %v}, want {ID: %s, Backward: %v}", cursor.ID.Hex(), cursor.Backward, id.Hex(), tt.backward)
+			}
+			if cursor.Value != tt.value {
+				t.Errorf("cursor value = %#v, want %#v", cursor.Value, tt.value)
+			}
+		})
+	}
+}
+
+func TestPageTokenRejected(t *testing.T) {
+	page, err := newKeysetPage(domain.PageRequest{}, "name", false)
+	if err != nil {
+		t.Fatalf("newKeysetPage: %v", err)
+	}
+	valid := page.token("acme", primitive.NewObjectID(), false)
+
+	noID, _ := bson.Marshal(pageCursor{SortField: "name", Value: "acme"})
+	noField, _ := bson.Marshal(pageCursor{Value: "acme", ID: primitive.NewObjectID()})
+
+	tests := []struct {
+		name       string
+		token      string
+		sortField  string
+		descending bool
+	}{
+		{"not base64", "!!!", "name", false},
+		{"not bson", base64.RawURLEncoding.EncodeToString([]byte("garbage")), "name", false},
+		{"missing ID", base64.RawURLEncoding.EncodeToString(noID), "name", false},
+		{"missing sort field", base64.RawURLEncoding.EncodeToString(noField), "name", false},
+		{"other sort field", valid, "createdAt", false},
+		{"other direction", valid, "name", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newKeysetPage(domain.PageRequest{PageToken: tt.token}, tt.sortField, tt.descending)
+			if !domain.IsInvalidPageToken(err) {
+				t.Errorf("err = %v, want invalid page token", err)
+			}
+		})
+	}
+}
+
+func TestKeysetPageSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  domain.PageRequest
+		wantSize int
+		wantSkip int64
+	}{
+		{"default size", domain.PageRequest{}, domain.DefaultPageSize, 0},
+		{"requested size", domain.PageRequest{PageSize: 5}, 5, 0},
+		{"clamped size", domain.PageRequest{PageSize: domain.MaxPageSize + 1}, domain.MaxPageSize, 0},
+		{"page offset", domain.PageRequest{Page: 3, PageSize: 5}, 5, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := newKeysetPage(tt.request, "name", false)
+			if err != nil {
+				t.Fatalf("newKeysetPage: %v", err)
+			}
+			if page.size != tt.wantSize || page.skip != tt.wantSkip {
+				t.Errorf("size, skip = %d, %d, want %d, %d", page.size, page.skip, tt.wantSize, tt.wantSkip)
+			}
+		})
+	}
+}
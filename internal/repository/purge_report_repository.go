@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PurgeReportRepository handles tenant purge report data access
+type PurgeReportRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPurgeReportRepository creates a new purge report repository
+func NewPurgeReportRepository(db *mongo.Database) *PurgeReportRepository {
+	collection := db.Collection("tenant_purge_reports")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "completedAt", Value: -1},
+			},
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(ctx, indexes)
+
+	return &PurgeReportRepository{collection: collection}
+}
+
+// Create stores a purge report
+func (r *PurgeReportRepository) Create(ctx context.Context, report *domain.PurgeReport) error {
+	result, err := r.collection.InsertOne(ctx, report)
+	if err != nil {
+		return fmt.Errorf("failed to create purge report: %w", err)
+	}
+
+	report.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindLatestByTenant finds the most recent purge report of a tenant
+func (r *PurgeReportRepository) FindLatestByTenant(ctx context.Context, tenantID string) (*domain.PurgeReport, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "completedAt", Value: -1}})
+
+	var report domain.PurgeReport
+	err := r.collection.FindOne(ctx, bson.M{"tenantId": tenantID}, opts).Decode(&report)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find purge report: %w", err)
+	}
+	return &report, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SettingDefinitionRepository handles tenant setting definition data access
+type SettingDefinitionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewSettingDefinitionRepository creates a new setting definition repository
+func NewSettingDefinitionRepository(db *mongo.Database) *SettingDefinitionRepository {
+	collection := db.Collection("setting_definitions")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "service", Value: 1}},
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(ctx, indexes)
+
+	return &SettingDefinitionRepository{collection: collection}
+}
+
+// Upsert creates or replaces the definition of a setting key
+func (r *SettingDefinitionRepository) Upsert(ctx context.Context, definition *domain.SettingDefinition) error {
+	now := time.Now()
+	definition.UpdatedAt = now
+
+	update := bson.M{
+		"$set": bson.M{
+			"service":     definition.Service,
+			"description": definition.Description,
+			"schema":      definition.Schema,
+			"default":     definition.Default,
+			"visibility":  definition.Visibility,
+			"updatedAt":   now,
+		},
+		"$setOnInsert": bson.M{
+			"key":       definition.Key,
+			"createdAt": now,
+		},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"key": definition.Key}, update, opts).Decode(definition)
+	if err != nil {
+		return fmt.Errorf("failed to upsert setting definition: %w", err)
+	}
+	return nil
+}
+
+// FindByKey finds the definition of a setting key
+func (r *SettingDefinitionRepository) FindByKey(ctx context.Context, key string) (*domain.SettingDefinition, error) {
+	var definition domain.SettingDefinition
+	err := r.collection.FindOne(ctx, bson.M{"key": key}).Decode(&definition)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find setting definition: %w", err)
+	}
+	return &definition, nil
+}
+
+// List lists the setting definitions ordered by key, optionally of a single service
+func (r *SettingDefinitionRepository) List(ctx context.Context, service string) ([]*domain.SettingDefinition, error) {
+	filter := bson.M{}
+	if service != "" {
+		filter["service"] = service
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "key", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list setting definitions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var definitions []*domain.SettingDefinition
+	if err := cursor.All(ctx, &definitions); err != nil {
+		return nil, fmt.Errorf("failed to decode setting definitions: %w", err)
+	}
+	return definitions, nil
+}
+
+// Delete deletes the definition of a setting key; it returns false if there was none
+func (r *SettingDefinitionRepository) Delete(ctx context.Context, key string) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"key": key})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete setting definition: %w", err)
+	}
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TenantDomainRepository handles custom domain claim data access
+type TenantDomainRepository struct {
+	collection *mongo.Collection
+}
+
+// NewTenantDomainRepository creates a new tenant domain repository
+func NewTenantDomainRepository(db *mongo.Database) *TenantDomainRepository {
+	collection := db.Collection("tenant_domains")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "domain", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Many tenants may claim a domain, but only one can hold it verified
+			Keys: bson.D{{Key: "domain", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": domain.DomainStatusVerified}),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "lastCheckedAt", Value: 1},
+			},
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(ctx, indexes)
+
+	return &TenantDomainRepository{collection: collection}
+}
+
+// Create creates a new domain claim
+func (r *TenantDomainRepository) Create(ctx context.Context, tenantDomain *domain.TenantDomain) error {
+	tenantDomain.CreatedAt = time.Now()
+	tenantDomain.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, tenantDomain)
+	if err != nil {
+		return fmt.Errorf("failed to create tenant domain: %w", err)
+	}
+
+	tenantDomain.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByID finds a domain claim by ID
+func (r *TenantDomainRepository) FindByID(ctx context.Context, id string) (*domain.TenantDomain, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid domain ID: %w", err)
+	}
+
+	return r.findOne(ctx, bson.M{"_id": objectID})
+}
+
+// FindByTenantAndDomain finds the claim of a tenant for a domain
+func (r *TenantDomainRepository) FindByTenantAndDomain(ctx context.Context, tenantID, domainName string) (*domain.TenantDomain, error) {
+	return r.findOne(ctx, bson.M{"tenantId": tenantID, "domain": domainName})
+}
+
+// FindVerifiedByDomain finds the verified claim for a domain, if any
+func (r *TenantDomainRepository) FindVerifiedByDomain(ctx context.Context, domainName string) (*domain.TenantDomain, error) {
+	return r.findOne(ctx, bson.M{"domain": domainName, "status": domain.DomainStatusVerified})
+}
+
+// ListByTenant lists all domain claims of a tenant
+func (r *TenantDomainRepository) ListByTenant(ctx context.Context, tenantID string) ([]*domain.TenantDomain, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	return r.find(ctx, bson.M{"tenantId": tenantID}, opts)
+}
+
+// FindPending finds pending claims, least recently checked first
+func (r *TenantDomainRepository) FindPending(ctx context.Context, limit int) ([]*domain.TenantDomain, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "lastCheckedAt", Value: 1}}).
+		SetLimit(int64(limit))
+	return r.find(ctx, bson.M{"status": domain.DomainStatusPending}, opts)
+}
+
+// Update updates the verification state of a domain claim
+func (r *TenantDomainRepository) Update(ctx context.Context, tenantDomain *domain.TenantDomain) error {
+	tenantDomain.UpdatedAt = time.Now()
+
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": tenantDomain.ID},
+		bson.M{"$set": bson.M{
+			"status":             tenantDomain.Status,
+			"verificationMethod": tenantDomain.VerificationMethod,
+			"token":              tenantDomain.Token,
+			"isPrimary":          tenantDomain.IsPrimary,
+			"attempts":           tenantDomain.Attempts,
+			"lastError":          tenantDomain.LastError,
+			"lastCheckedAt":      tenantDomain.LastCheckedAt,
+			"verifiedAt":         tenantDomain.VerifiedAt,
+			"expiresAt":          tenantDomain.ExpiresAt,
+			"updatedAt":          tenantDomain.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update tenant domain: %w", err)
+	}
+	return nil
+}
+
+// SetPrimary marks a claim as the primary domain of its tenant and clears the flag on the others
+func (r *TenantDomainRepository) SetPrimary(ctx context.Context, tenantID string, id primitive.ObjectID) error {
+	now := time.Now()
+
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"tenantId": tenantID, "_id": bson.M{"$ne": id}, "isPrimary": true},
+		bson.M{"$set": bson.M{"isPrimary": false, "updatedAt": now}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to clear primary domain: %w", err)
+	}
+
+	_, err = r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "tenantId": tenantID},
+		bson.M{"$set": bson.M{"isPrimary": true, "updatedAt": now}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set primary domain: %w", err)
+	}
+	return nil
+}
+
+// ClearPrimary clears the primary flag on every claim of a tenant
+func (r *TenantDomainRepository) ClearPrimary(ctx context.Context, tenantID string) error {
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"tenantId": tenantID, "isPrimary": true},
+		bson.M{"$set": bson.M{"isPrimary": false, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to clear primary domain: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a domain claim
+func (r *TenantDomainRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete tenant domain: %w", err)
+	}
+	return nil
+}
+
+// DeleteByTenant deletes every domain claim of a tenant
+func (r *TenantDomainRepository) DeleteByTenant(ctx context.Context, tenantID string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"tenantId": tenantID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete tenant domains: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
+// findOne finds a single claim, returning nil if none matches
+func (r *TenantDomainRepository) findOne(ctx context.Context, filter bson.M) (*domain.TenantDomain, error) {
+	var tenantDomain domain.TenantDomain
+	err := r.collection.FindOne(ctx, filter).Decode(&tenantDomain)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find tenant domain: %w", err)
+	}
+	return &tenantDomain, nil
+}
+
+// find finds all claims matching a filter
+func (r *TenantDomainRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*domain.TenantDomain, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tenant domains: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var domains []*domain.TenantDomain
+	if err := cursor.All(ctx, &domains); err != nil {
+		return nil, fmt.Errorf("failed to decode tenant domains: %w", err)
+	}
+	return domains, nil
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TenantInvitationRepository handles tenant invitation data access
+type TenantInvitationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewTenantInvitationRepository creates a new tenant invitation repository
+func NewTenantInvitationRepository(db *mongo.Database) *TenantInvitationRepository {
+	collection := db.Collection("tenant_invitations")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "email", Value: 1},
+			},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"status": domain.InvitationStatusPending}),
+		},
+		{
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "phone", Value: 1},
+			},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"status": domain.InvitationStatusPending}),
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(ctx, indexes)
+
+	return &TenantInvitationRepository{collection: collection}
+}
+
+// Create stores an invitation
+func (r *TenantInvitationRepository) Create(ctx context.Context, invitation *domain.TenantInvitation) error {
+	invitation.CreatedAt = time.Now()
+	invitation.UpdatedAt = invitation.CreatedAt
+
+	result, err := r.collection.InsertOne(ctx, invitation)
+	if err != nil {
+		return fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	invitation.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByID finds an invitation by ID
+func (r *TenantInvitationRepository) FindByID(ctx context.Context, id string) (*domain.TenantInvitation, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid invitation ID: %w", err)
+	}
+
+	return r.findOne(ctx, bson.M{"_id": objectID})
+}
+
+// FindPendingByRecipient finds an unexpired pending invitation of a tenant for an email or phone
+func (r *TenantInvitationRepository) FindPendingByRecipient(ctx context.Context, tenantID, email, phone string) (*domain.TenantInvitation, error) {
+	recipients := bson.A{}
+	if email != "" {
+		recipients = append(recipients, bson.M{"email": email})
+	}
+	if phone != "" {
+		recipients = append(recipients, bson.M{"phone": phone})
+	}
+
+	return r.findOne(ctx, bson.M{
+		"tenantId":  tenantID,
+		"status":    domain.InvitationStatusPending,
+		"expiresAt": bson.M{"$gt": time.Now()},
+		"$or":       recipients,
+	})
+}
+
+// ListByTenant lists the invitations of a tenant, newest first. Filtering on pending
+// excludes expired invitations and filtering on expired returns only those.
+func (r *TenantInvitationRepository) ListByTenant(ctx context.Context, tenantID, status string) ([]*domain.TenantInvitation, error) {
+	filter := bson.M{"tenantId": tenantID}
+	switch status {
+	case "":
+	case domain.InvitationStatusPending:
+		filter["status"] = status
+		filter["expiresAt"] = bson.M{"$gt": time.Now()}
+	case domain.InvitationStatusExpired:
+		filter["status"] = domain.InvitationStatusPending
+		filter["expiresAt"] = bson.M{"$lte": time.Now()}
+	default:
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var invitations []*domain.TenantInvitation
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, fmt.Errorf("failed to decode invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// Respond moves a pending invitation carrying the given nonce to a final status.
+// It returns false if the invitation was no longer pending or the nonce was rotated.
+func (r *TenantInvitationRepository) Respond(ctx context.Context, id primitive.ObjectID, nonceHash, status, userID string) (bool, error) {
+	now := time.Now()
+	set := bson.M{
+		"status":      status,
+		"respondedAt": now,
+		"updatedAt":   now,
+	}
+	if userID != "" {
+		set["acceptedBy"] = userID
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": domain.InvitationStatusPending, "nonceHash": nonceHash},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update invitation: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// Reopen returns an accepted invitation to pending, e.g. when the membership could not be created
+func (r *TenantInvitationRepository) Reopen(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": domain.InvitationStatusAccepted},
+		bson.M{
+			"$set":   bson.M{"status": domain.InvitationStatusPending, "updatedAt": time.Now()},
+			"$unset": bson.M{"acceptedBy": "", "respondedAt": ""},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reopen invitation: %w", err)
+	}
+	return nil
+}
+
+// Revoke revokes a pending invitation. It returns false if the invitation was not pending.
+func (r *TenantInvitationRepository) Revoke(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": domain.InvitationStatusPending},
+		bson.M{"$set": bson.M{
+			"status":      domain.InvitationStatusRevoked,
+			"respondedAt": now,
+			"updatedAt":   now,
+		}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// Reissue rotates the token nonce of a pending invitation and records the send
+func (r *TenantInvitationRepository) Reissue(ctx context.Context, invitation *domain.TenantInvitation) (bool, error) {
+	invitation.UpdatedAt = time.Now()
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": invitation.ID, "status": domain.InvitationStatusPending},
+		bson.M{"$set": bson.M{
+			"nonceHash":  invitation.NonceHash,
+			"sendCount":  invitation.SendCount,
+			"lastSentAt": invitation.LastSentAt,
+			"expiresAt":  invitation.ExpiresAt,
+			"updatedAt":  invitation.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to reissue invitation: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// DeleteByTenant deletes every invitation of a tenant
+func (r *TenantInvitationRepository) DeleteByTenant(ctx context.Context, tenantID string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"tenantId": tenantID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete invitations: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
+// findOne finds a single invitation, returning nil if none matches
+func (r *TenantInvitationRepository) findOne(ctx context.Context, filter bson.M) (*domain.TenantInvitation, error) {
+	var invitation domain.TenantInvitation
+	err := r.collection.FindOne(ctx, filter).Decode(&invitation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find invitation: %w", err)
+	}
+	return &invitation, nil
+}
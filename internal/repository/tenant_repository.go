@@ -1,180 +1,816 @@
-package repository
-
-import (
-	"context"
-	"fmt"
-	"time"
-
-	"github.com/vhvplatform/go-tenant-service/internal/domain"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-)
-
-// TenantRepository handles tenant data access
-type TenantRepository struct {
-	collection *mongo.Collection
-}
-
-// NewTenantRepository creates a new tenant repository
-func NewTenantRepository(db *mongo.Database) *TenantRepository {
-	collection := db.Collection("tenants")
-
-	// Create indexes
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	indexes := []mongo.IndexModel{
-		{
-			Keys:    bson.D{{Key: "name", Value: 1}},
-			Options: options.Index().SetUnique(true),
-		},
-		{
-			Keys:    bson.D{{Key: "domain", Value: 1}},
-			Options: options.Index().SetUnique(true).SetSparse(true),
-		},
-	}
-
-	_, _ = collection.Indexes().CreateMany(ctx, indexes)
-
-	return &TenantRepository{collection: collection}
-}
-
-// Create creates a new tenant
-func (r *TenantRepository) Create(ctx context.Context, tenant *domain.Tenant) error {
-	tenant.CreatedAt = time.Now()
-	tenant.UpdatedAt = time.Now()
-	tenant.IsActive = true
-
-	if tenant.SubscriptionTier == "" {
-		tenant.SubscriptionTier = domain.SubscriptionFree
-	}
-
-	result, err := r.collection.InsertOne(ctx, tenant)
-	if err != nil {
-		return fmt.Errorf("failed to create tenant: %w", err)
-	}
-
-	tenant.ID = result.InsertedID.(primitive.ObjectID)
-	return nil
-}
-
-// FindByID finds a tenant by ID
-func (r *TenantRepository) FindByID(ctx context.Context, id string) (*domain.Tenant, error) {
-	objectID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return nil, fmt.Errorf("invalid tenant ID: %w", err)
-	}
-
-	var tenant domain.Tenant
-	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&tenant)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to find tenant: %w", err)
-	}
-	return &tenant, nil
-}
-
-// FindByName finds a tenant by name
-func (r *TenantRepository) FindByName(ctx context.Context, name string) (*domain.Tenant, error) {
-	var tenant domain.Tenant
-	err := r.collection.FindOne(ctx, bson.M{"name": name}).Decode(&tenant)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to find tenant by name: %w", err)
-	}
-	return &tenant, nil
-}
-
-// FindByDomain finds a tenant by domain
-func (r *TenantRepository) FindByDomain(ctx context.Context, domainName string) (*domain.Tenant, error) {
-	var tenant domain.Tenant
-	err := r.collection.FindOne(ctx, bson.M{"domain": domainName}).Decode(&tenant)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to find tenant by domain: %w", err)
-	}
-	return &tenant, nil
-}
-
-// List lists all tenants with pagination
-func (r *TenantRepository) List(ctx context.Context, page, pageSize int) ([]*domain.Tenant, int64, error) {
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 20
-	}
-
-	skip := (page - 1) * pageSize
-
-	// Get total count
-	total, err := r.collection.CountDocuments(ctx, bson.M{})
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count tenants: %w", err)
-	}
-
-	// Get tenants
-	opts := options.Find().
-		SetSkip(int64(skip)).
-		SetLimit(int64(pageSize)).
-		SetSort(bson.D{{Key: "createdAt", Value: -1}})
-
-	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list tenants: %w", err)
-	}
-	defer cursor.Close(ctx)
-
-	var tenants []*domain.Tenant
-	if err := cursor.All(ctx, &tenants); err != nil {
-		return nil, 0, fmt.Errorf("failed to decode tenants: %w", err)
-	}
-
-	return tenants, total, nil
-}
-
-// Update updates a tenant
-func (r *TenantRepository) Update(ctx context.Context, tenant *domain.Tenant) error {
-	tenant.UpdatedAt = time.Now()
-
-	_, err := r.collection.UpdateOne(
-		ctx,
-		bson.M{"_id": tenant.ID},
-		bson.M{"$set": tenant},
-	)
-	if err != nil {
-		return fmt.Errorf("failed to update tenant: %w", err)
-	}
-	return nil
-}
-
-// Delete soft deletes a tenant
-func (r *TenantRepository) Delete(ctx context.Context, id string) error {
-	objectID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return fmt.Errorf("invalid tenant ID: %w", err)
-	}
-
-	_, err = r.collection.UpdateOne(
-		ctx,
-		bson.M{"_id": objectID},
-		bson.M{
-			"$set": bson.M{
-				"isActive":  false,
-				"updatedAt": time.Now(),
-			},
-		},
-	)
-	if err != nil {
-		return fmt.Errorf("failed to delete tenant: %w", err)
-	}
-	return nil
-}
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TenantRepository handles tenant data access
+type TenantRepository struct {
+	collection *mongo.Collection
+}
+
+// NewTenantRepository creates a new tenant repository
+func NewTenantRepository(db *mongo.Database) *TenantRepository {
+	collection := db.Collection("tenants")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "domain", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "subdomain", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "purgeAfter", Value: 1},
+			},
+		},
+		{
+			Keys:    bson.D{{Key: "parentId", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys: bson.D{{Key: "createdAt", Value: -1}},
+		},
+		{
+			Keys: bson.D{
+				{Key: "subscriptionTier", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+		},
+		{
+			Keys:    bson.D{{Key: "scheduledPlanChange.effectiveAt", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "trial.status", Value: 1},
+				{Key: "trial.endsAt", Value: 1},
+			},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "trial.status", Value: 1},
+				{Key: "trial.graceEndsAt", Value: 1},
+			},
+			Options: options.Index().SetSparse(true),
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(ctx, indexes)
+
+	return &TenantRepository{collection: collection}
+}
+
+// Create creates a new tenant
+func (r *TenantRepository) Create(ctx context.Context, tenant *domain.Tenant) error {
+	tenant.CreatedAt = time.Now()
+	tenant.UpdatedAt = time.Now()
+	if tenant.Status == "" {
+		tenant.Status = domain.TenantStatusProvisioning
+	}
+	tenant.IsActive = tenant.Status == domain.TenantStatusActive
+	tenant.StatusChangedAt = tenant.CreatedAt
+
+	if tenant.SubscriptionTier == "" {
+		tenant.SubscriptionTier = domain.SubscriptionFree
+	}
+	tenant.Version = 1
+
+	result, err := r.collection.InsertOne(ctx, tenant)
+	if err != nil {
+		return fmt.Errorf("failed to create tenant: %w", err)
+	}
+
+	tenant.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByID finds a tenant by ID
+func (r *TenantRepository) FindByID(ctx context.Context, id string) (*domain.Tenant, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tenant ID: %w", err)
+	}
+
+	var tenant domain.Tenant
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&tenant)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find tenant: %w", err)
+	}
+	return &tenant, nil
+}
+
+// FindByIDs finds the tenants with the given IDs, skipping invalid and unknown IDs
+func (r *TenantRepository) FindByIDs(ctx context.Context, ids []string) ([]*domain.Tenant, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+	if len(objectIDs) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": objectIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tenants: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var tenants []*domain.Tenant
+	if err := cursor.All(ctx, &tenants); err != nil {
+		return nil, fmt.Errorf("failed to decode tenants: %w", err)
+	}
+	return tenants, nil
+}
+
+// FindByName finds a tenant by name
+func (r *TenantRepository) FindByName(ctx context.Context, name string) (*domain.Tenant, error) {
+	var tenant domain.Tenant
+	err := r.collection.FindOne(ctx, bson.M{"name": name}).Decode(&tenant)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find tenant by name: %w", err)
+	}
+	return &tenant, nil
+}
+
+// FindByDomain finds a tenant by domain
+func (r *TenantRepository) FindByDomain(ctx context.Context, domainName string) (*domain.Tenant, error) {
+	var tenant domain.Tenant
+	err := r.collection.FindOne(ctx, bson.M{"domain": domainName}).Decode(&tenant)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find tenant by domain: %w", err)
+	}
+	return &tenant, nil
+}
+
+// FindBySubdomain finds a tenant by its platform subdomain
+func (r *TenantRepository) FindBySubdomain(ctx context.Context, subdomain string) (*domain.Tenant, error) {
+	var tenant domain.Tenant
+	err := r.collection.FindOne(ctx, bson.M{"subdomain": subdomain}).Decode(&tenant)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find tenant by subdomain: %w", err)
+	}
+	return &tenant, nil
+}
+
+// List lists a page of the tenants matching a filter, in the order the filter selects
+func (r *TenantRepository) List(ctx context.Context, filter domain.TenantFilter, page domain.PageRequest) ([]*domain.Tenant, *domain.PageInfo, error) {
+	sortField, descending := filter.Sort()
+	keyset, err := newKeysetPage(page, sortField, descending)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tenants, info, err := findPage(ctx, r.collection, tenantFilterQuery(filter), keyset,
+		func(tenant *domain.Tenant) (interface{}, primitive.ObjectID) {
+			return tenantSortValue(tenant, sortField), tenant.ID
+		})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	return tenants, info, nil
+}
+
+// tenantSortValue returns the stored value of the field a tenant listing is sorted on
+func tenantSortValue(tenant *domain.Tenant, sortField string) interface{} {
+	switch sortField {
+	case "name":
+		return tenant.Name
+	case "updatedAt":
+		return tenant.UpdatedAt
+	case "subscriptionTier":
+		return tenant.SubscriptionTier
+	case "status":
+		return tenant.Status
+	default:
+		return tenant.CreatedAt
+	}
+}
+
+// tenantFilterQuery builds the query selecting the tenants matching a filter
+func tenantFilterQuery(filter domain.TenantFilter) bson.M {
+	var clauses []bson.M
+
+	if filter.Name != "" {
+		clauses = append(clauses, bson.M{"name": bson.M{"$regex": regexp.QuoteMeta(filter.Name), "$options": "i"}})
+	}
+	if filter.NamePrefix != "" {
+		clauses = append(clauses, bson.M{"name": bson.M{"$regex": "^" + regexp.QuoteMeta(filter.NamePrefix), "$options": "i"}})
+	}
+	if filter.Domain != "" {
+		host := strings.ToLower(filter.Domain)
+		clauses = append(clauses, bson.M{"$or": []bson.M{{"domain": host}, {"subdomain": host}}})
+	}
+	if filter.SubscriptionTier == domain.SubscriptionFree {
+		// Tenants without a tier are treated as free
+		clauses = append(clauses, bson.M{"subscriptionTier": bson.M{"$in": []string{domain.SubscriptionFree, ""}}})
+	} else if filter.SubscriptionTier != "" {
+		clauses = append(clauses, bson.M{"subscriptionTier": filter.SubscriptionTier})
+	}
+	if filter.Status != "" {
+		clauses = append(clauses, bson.M{"status": filter.Status})
+	}
+	if filter.IsActive != nil {
+		clauses = append(clauses, bson.M{"isActive": *filter.IsActive})
+	}
+	if filter.CreatedAfter != nil || filter.CreatedBefore != nil {
+		createdAt := bson.M{}
+		if filter.CreatedAfter != nil {
+			createdAt["$gte"] = *filter.CreatedAfter
+		}
+		if filter.CreatedBefore != nil {
+			createdAt["$lt"] = *filter.CreatedBefore
+		}
+		clauses = append(clauses, bson.M{"createdAt": createdAt})
+	}
+	if filter.SettingsKey != "" {
+		clauses = append(clauses, bson.M{"settings." + filter.SettingsKey: bson.M{"$exists": true}})
+	}
+	if filter.Search != "" {
+		pattern := bson.M{"$regex": regexp.QuoteMeta(filter.Search), "$options": "i"}
+		clauses = append(clauses, bson.M{"$or": []bson.M{
+			{"name": pattern},
+			{"domain": pattern},
+			{"subdomain": pattern},
+		}})
+	}
+
+	if len(clauses) == 0 {
+		return bson.M{}
+	}
+	return bson.M{"$and": clauses}
+}
+
+// ListChildren lists the direct sub-tenants of a tenant with pagination
+func (r *TenantRepository) ListChildren(ctx context.Context, parentID string, page, pageSize int) ([]*domain.Tenant, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = domain.DefaultPageSize
+	}
+	if pageSize > domain.MaxPageSize {
+		pageSize = domain.MaxPageSize
+	}
+
+	filter := bson.M{"parentId": parentID}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count child tenants: %w", err)
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list child tenants: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var tenants []*domain.Tenant
+	if err := cursor.All(ctx, &tenants); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode tenants: %w", err)
+	}
+	return tenants, total, nil
+}
+
+// FindChildIDs finds the IDs of the direct sub-tenants of the given tenants
+func (r *TenantRepository) FindChildIDs(ctx context.Context, parentIDs []string) ([]string, error) {
+	if len(parentIDs) == 0 {
+		return nil, nil
+	}
+
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := r.collection.Find(ctx, bson.M{"parentId": bson.M{"$in": parentIDs}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find child tenants: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var ids []string
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode tenant: %w", err)
+		}
+		ids = append(ids, doc.ID.Hex())
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find child tenants: %w", err)
+	}
+	return ids, nil
+}
+
+// CountChildren counts the direct sub-tenants of a tenant
+func (r *TenantRepository) CountChildren(ctx context.Context, parentID string) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"parentId": parentID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count child tenants: %w", err)
+	}
+	return count, nil
+}
+
+// FindAncestors finds the parent tenants of a tenant, nearest first. The walk stops after
+// maxDepth tenants, at a missing parent or when a tenant repeats.
+func (r *TenantRepository) FindAncestors(ctx context.Context, tenant *domain.Tenant, maxDepth int) ([]*domain.Tenant, error) {
+	var ancestors []*domain.Tenant
+	seen := map[string]bool{tenant.ID.Hex(): true}
+
+	parentID := tenant.ParentID
+	for parentID != "" && len(ancestors) < maxDepth && !seen[parentID] {
+		parent, err := r.FindByID(ctx, parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			break
+		}
+		seen[parentID] = true
+		ancestors = append(ancestors, parent)
+		parentID = parent.ParentID
+	}
+	return ancestors, nil
+}
+
+// UpdateParent moves a tenant under a parent tenant; an empty parent makes it a top-level tenant.
+// The move only applies at the version the tenant was read at; it returns false if the tenant
+// has moved on.
+func (r *TenantRepository) UpdateParent(ctx context.Context, id, parentID string, version int64) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid tenant ID: %w", err)
+	}
+
+	update := bson.M{"$set": bson.M{"parentId": parentID, "updatedAt": time.Now()}}
+	if parentID == "" {
+		update = bson.M{
+			"$set":   bson.M{"updatedAt": time.Now()},
+			"$unset": bson.M{"parentId": ""},
+		}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID, "version": versionFilter(version)}, bumpVersion(update))
+	if err != nil {
+		return false, fmt.Errorf("failed to update tenant parent: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// AddOwners changes the number of owners counted on a tenant by delta. The count is kept
+// next to the tenant for ReleaseOwner; it is not part of the tenant and leaves its version as is.
+func (r *TenantRepository) AddOwners(ctx context.Context, id string, delta int64) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid tenant ID: %w", err)
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$inc": bson.M{"ownerCount": delta}})
+	if err != nil {
+		return fmt.Errorf("failed to update tenant owner count: %w", err)
+	}
+	return nil
+}
+
+// ReleaseOwner takes one owner off the count of a tenant unless it is the last one. The check
+// and the decrement are a single write, so concurrent removals of owners cannot both pass it
+// with one owner left. It returns false if no other owner remains.
+func (r *TenantRepository) ReleaseOwner(ctx context.Context, id string) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid tenant ID: %w", err)
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "ownerCount": bson.M{"$gt": 1}},
+		bson.M{"$inc": bson.M{"ownerCount": -1}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update tenant owner count: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// Update replaces a tenant if it is still at the version it was read at, moving it to the
+// next version. It returns false if the tenant was changed since it was read.
+func (r *TenantRepository) Update(ctx context.Context, tenant *domain.Tenant) (bool, error) {
+	expected := tenant.Version
+	tenant.UpdatedAt = time.Now()
+	tenant.Version = expected + 1
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": tenant.ID, "version": versionFilter(expected)},
+		bson.M{"$set": tenant},
+	)
+	if err != nil {
+		tenant.Version = expected
+		return false, fmt.Errorf("failed to update tenant: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		tenant.Version = expected
+		return false, nil
+	}
+	return true, nil
+}
+
+// UpdateConfig replaces the configuration sub-document of a tenant. With a non-zero expected
+// version the update only applies at that version; it returns false if the tenant has moved on.
+func (r *TenantRepository) UpdateConfig(ctx context.Context, id string, config *domain.TenantConfig, expectedVersion int64) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid tenant ID: %w", err)
+	}
+
+	filter := bson.M{"_id": objectID}
+	if expectedVersion > 0 {
+		filter["version"] = expectedVersion
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"config":    config,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bumpVersion(update))
+	if err != nil {
+		return false, fmt.Errorf("failed to update tenant config: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// UpdateEntitlementOverrides replaces the entitlement overrides of a tenant; nil removes them
+func (r *TenantRepository) UpdateEntitlementOverrides(ctx context.Context, id string, overrides *domain.EntitlementOverrides) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid tenant ID: %w", err)
+	}
+
+	update := bson.M{"$set": bson.M{"entitlementOverrides": overrides, "updatedAt": time.Now()}}
+	if overrides == nil {
+		update = bson.M{
+			"$set":   bson.M{"updatedAt": time.Now()},
+			"$unset": bson.M{"entitlementOverrides": ""},
+		}
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bumpVersion(update))
+	if err != nil {
+		return fmt.Errorf("failed to update tenant entitlement overrides: %w", err)
+	}
+	return nil
+}
+
+// UpdateDomain sets the primary domain of a tenant; an empty domain removes it
+func (r *TenantRepository) UpdateDomain(ctx context.Context, id, domainName string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid tenant ID: %w", err)
+	}
+
+	update := bson.M{"$set": bson.M{"domain": domainName, "updatedAt": time.Now()}}
+	if domainName == "" {
+		update = bson.M{
+			"$set":   bson.M{"updatedAt": time.Now()},
+			"$unset": bson.M{"domain": ""},
+		}
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bumpVersion(update))
+	if err != nil {
+		return fmt.Errorf("failed to update tenant domain: %w", err)
+	}
+	return nil
+}
+
+// UpdateStatus moves a tenant from one lifecycle status to another and appends the change
+// to its history. It returns false if the tenant was no longer in the expected status.
+func (r *TenantRepository) UpdateStatus(ctx context.Context, id string, change domain.TenantStatusChange) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid tenant ID: %w", err)
+	}
+
+	filter := bson.M{"_id": objectID, "status": change.From}
+	if change.From == domain.TenantStatusActive {
+		// Records created before statuses existed only carry isActive
+		filter = bson.M{"_id": objectID, "$or": bson.A{
+			bson.M{"status": change.From},
+			bson.M{"status": bson.M{"$exists": false}, "isActive": true},
+		}}
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":          change.To,
+			"statusReason":    change.Reason,
+			"statusChangedAt": change.ChangedAt,
+			"isActive":        change.To == domain.TenantStatusActive,
+			"updatedAt":       change.ChangedAt,
+		},
+		"$push": bson.M{"statusHistory": change},
+	}
+	if change.PurgeAfter != nil {
+		update["$set"].(bson.M)["purgeAfter"] = change.PurgeAfter
+	} else {
+		update["$unset"] = bson.M{"purgeAfter": ""}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bumpVersion(update))
+	if err != nil {
+		return false, fmt.Errorf("failed to update tenant status: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// FindDuePurges finds tenants whose data is due to be purged: tenants pending deletion
+// past their deadline, and deleted tenants whose earlier purge did not complete
+func (r *TenantRepository) FindDuePurges(ctx context.Context, now time.Time, limit int) ([]*domain.Tenant, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"status": domain.TenantStatusPendingDeletion, "purgeAfter": bson.M{"$lte": now}},
+		bson.M{"status": domain.TenantStatusDeleted, "purgeAfter": bson.M{"$exists": true}},
+	}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "purgeAfter", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tenants due for purge: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var tenants []*domain.Tenant
+	if err := cursor.All(ctx, &tenants); err != nil {
+		return nil, fmt.Errorf("failed to decode tenants: %w", err)
+	}
+	return tenants, nil
+}
+
+// SchedulePlanChange stores a plan change to apply later. It returns false if the tenant
+// changed tier or already had a scheduled change.
+func (r *TenantRepository) SchedulePlanChange(ctx context.Context, id string, change domain.PlanChange) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid tenant ID: %w", err)
+	}
+
+	filter := bson.M{
+		"_id":                 objectID,
+		"subscriptionTier":    change.From,
+		"scheduledPlanChange": bson.M{"$exists": false},
+	}
+	update := bson.M{"$set": bson.M{"scheduledPlanChange": change, "updatedAt": time.Now()}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bumpVersion(update))
+	if err != nil {
+		return false, fmt.Errorf("failed to schedule tenant plan change: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// ApplyPlanChange moves a tenant to the target tier of a plan change and appends the change to
+// its plan history. A scheduled change is applied only while it is still the scheduled change;
+// otherwise the tenant must have none. It returns false if the tenant no longer matched.
+func (r *TenantRepository) ApplyPlanChange(ctx context.Context, id string, change domain.PlanChange, scheduled bool) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid tenant ID: %w", err)
+	}
+
+	filter := bson.M{"_id": objectID, "subscriptionTier": change.From}
+	if scheduled {
+		filter["scheduledPlanChange.requestedAt"] = change.RequestedAt
+	} else {
+		filter["scheduledPlanChange"] = bson.M{"$exists": false}
+	}
+	update := bson.M{
+		"$set":   bson.M{"subscriptionTier": change.To, "updatedAt": change.CompletedAt},
+		"$unset": bson.M{"scheduledPlanChange": ""},
+		"$push":  bson.M{"planHistory": change},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bumpVersion(update))
+	if err != nil {
+		return false, fmt.Errorf("failed to apply tenant plan change: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// CloseScheduledPlanChange removes the scheduled plan change of a tenant without applying it,
+// recording it in the plan history. It returns false if the change was no longer scheduled.
+func (r *TenantRepository) CloseScheduledPlanChange(ctx context.Context, id string, change domain.PlanChange) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid tenant ID: %w", err)
+	}
+
+	filter := bson.M{"_id": objectID, "scheduledPlanChange.requestedAt": change.RequestedAt}
+	update := bson.M{
+		"$set":   bson.M{"updatedAt": change.CompletedAt},
+		"$unset": bson.M{"scheduledPlanChange": ""},
+		"$push":  bson.M{"planHistory": change},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bumpVersion(update))
+	if err != nil {
+		return false, fmt.Errorf("failed to close tenant plan change: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// FindDuePlanChanges finds tenants whose scheduled plan change is due
+func (r *TenantRepository) FindDuePlanChanges(ctx context.Context, now time.Time, limit int) ([]*domain.Tenant, error) {
+	filter := bson.M{"scheduledPlanChange.effectiveAt": bson.M{"$lte": now}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "scheduledPlanChange.effectiveAt", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tenants with due plan changes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var tenants []*domain.Tenant
+	if err := cursor.All(ctx, &tenants); err != nil {
+		return nil, fmt.Errorf("failed to decode tenants: %w", err)
+	}
+	return tenants, nil
+}
+
+// StartTrial moves a tenant that never had a trial to the trial tier and records the change in
+// its plan history. It returns false if the tenant changed tier, has a scheduled plan change or
+// already had a trial.
+func (r *TenantRepository) StartTrial(ctx context.Context, id string, trial *domain.TenantTrial, change domain.PlanChange) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid tenant ID: %w", err)
+	}
+
+	filter := bson.M{
+		"_id":                 objectID,
+		"subscriptionTier":    change.From,
+		"scheduledPlanChange": bson.M{"$exists": false},
+		"trial":               bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set":  bson.M{"subscriptionTier": change.To, "trial": trial, "updatedAt": trial.StartedAt},
+		"$push": bson.M{"planHistory": change},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bumpVersion(update))
+	if err != nil {
+		return false, fmt.Errorf("failed to start tenant trial: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// EndTrial ends the running trial of a tenant with the given status. A plan change, when given,
+// moves the tenant off the trial tier in the same update and is recorded in its plan history;
+// the scheduled plan change the tenant was loaded with, if any, is kept and starts from the new tier.
+// It returns false if the trial was no longer running or the tenant changed tier or schedule.
+func (r *TenantRepository) EndTrial(ctx context.Context, id, status string, endedAt time.Time, change, scheduled *domain.PlanChange) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid tenant ID: %w", err)
+	}
+
+	filter := bson.M{"_id": objectID, "trial.status": domain.TrialStatusActive}
+	set := bson.M{"trial.status": status, "trial.endedAt": endedAt, "updatedAt": endedAt}
+	update := bson.M{"$set": set}
+	if change != nil {
+		filter["subscriptionTier"] = change.From
+		set["subscriptionTier"] = change.To
+		update["$push"] = bson.M{"planHistory": change}
+		if scheduled == nil {
+			filter["scheduledPlanChange"] = bson.M{"$exists": false}
+		} else {
+			rebased := *scheduled
+			rebased.From = change.To
+			filter["scheduledPlanChange.requestedAt"] = scheduled.RequestedAt
+			set["scheduledPlanChange"] = rebased
+		}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bumpVersion(update))
+	if err != nil {
+		return false, fmt.Errorf("failed to end tenant trial: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// FindExpiredTrials finds tenants whose running trial has passed the end of its grace period
+func (r *TenantRepository) FindExpiredTrials(ctx context.Context, now time.Time, limit int) ([]*domain.Tenant, error) {
+	filter := bson.M{"trial.status": domain.TrialStatusActive, "trial.graceEndsAt": bson.M{"$lte": now}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "trial.graceEndsAt", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tenants with expired trials: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var tenants []*domain.Tenant
+	if err := cursor.All(ctx, &tenants); err != nil {
+		return nil, fmt.Errorf("failed to decode tenants: %w", err)
+	}
+	return tenants, nil
+}
+
+// ListExpiringTrials lists tenants whose running trial ends before the given time, soonest first.
+// Trials already in their grace period are included.
+func (r *TenantRepository) ListExpiringTrials(ctx context.Context, before time.Time, page, pageSize int) ([]*domain.Tenant, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = domain.DefaultPageSize
+	}
+	if pageSize > domain.MaxPageSize {
+		pageSize = domain.MaxPageSize
+	}
+
+	filter := bson.M{"trial.status": domain.TrialStatusActive, "trial.endsAt": bson.M{"$lte": before}}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count expiring trials: %w", err)
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "trial.endsAt", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list expiring trials: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var tenants []*domain.Tenant
+	if err := cursor.All(ctx, &tenants); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode tenants: %w", err)
+	}
+	return tenants, total, nil
+}
+
+// Delete permanently removes a tenant
+func (r *TenantRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid tenant ID: %w", err)
+	}
+
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID}); err != nil {
+		return fmt.Errorf("failed to delete tenant: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TenantRoleRepository handles custom tenant role data access
+type TenantRoleRepository struct {
+	collection *mongo.Collection
+}
+
+// NewTenantRoleRepository creates a new tenant role repository
+func NewTenantRoleRepository(db *mongo.Database) *TenantRoleRepository {
+	collection := db.Collection("tenant_roles")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "name", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(ctx, indexes)
+
+	return &TenantRoleRepository{collection: collection}
+}
+
+// Create stores a custom role
+func (r *TenantRoleRepository) Create(ctx context.Context, role *domain.TenantRole) error {
+	role.CreatedAt = time.Now()
+	role.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, role)
+	if err != nil {
+		return fmt.Errorf("failed to create tenant role: %w", err)
+	}
+
+	role.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByTenantAndName finds a custom role of a tenant
+func (r *TenantRoleRepository) FindByTenantAndName(ctx context.Context, tenantID, name string) (*domain.TenantRole, error) {
+	var role domain.TenantRole
+	err := r.collection.FindOne(ctx, bson.M{"tenantId": tenantID, "name": name}).Decode(&role)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find tenant role: %w", err)
+	}
+	return &role, nil
+}
+
+// ListByTenant lists the custom roles of a tenant ordered by name
+func (r *TenantRoleRepository) ListByTenant(ctx context.Context, tenantID string) ([]*domain.TenantRole, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"tenantId": tenantID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenant roles: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var roles []*domain.TenantRole
+	if err := cursor.All(ctx, &roles); err != nil {
+		return nil, fmt.Errorf("failed to decode tenant roles: %w", err)
+	}
+	return roles, nil
+}
+
+// Update replaces the description and permissions of a custom role
+func (r *TenantRoleRepository) Update(ctx context.Context, role *domain.TenantRole) error {
+	role.UpdatedAt = time.Now()
+
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"tenantId": role.TenantID, "name": role.Name},
+		bson.M{"$set": bson.M{
+			"description": role.Description,
+			"permissions": role.Permissions,
+			"updatedAt":   role.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update tenant role: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a custom role
+func (r *TenantRoleRepository) Delete(ctx context.Context, tenantID, name string) error {
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"tenantId": tenantID, "name": name}); err != nil {
+		return fmt.Errorf("failed to delete tenant role: %w", err)
+	}
+	return nil
+}
+
+// DeleteByTenant deletes every custom role of a tenant
+func (r *TenantRoleRepository) DeleteByTenant(ctx context.Context, tenantID string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"tenantId": tenantID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete tenant roles: %w", err)
+	}
+	return result.DeletedCount, nil
+}
@@ -1,138 +1,329 @@
-package repository
-
-import (
-	"context"
-	"fmt"
-	"time"
-
-	"github.com/vhvplatform/go-tenant-service/internal/domain"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-)
-
-// TenantUserRepository handles tenant-user relationship data access
-type TenantUserRepository struct {
-	collection *mongo.Collection
-}
-
-// NewTenantUserRepository creates a new tenant-user repository
-func NewTenantUserRepository(db *mongo.Database) *TenantUserRepository {
-	collection := db.Collection("tenant_users")
-
-	// Create indexes
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	indexes := []mongo.IndexModel{
-		{
-			Keys: bson.D{
-				{Key: "tenantId", Value: 1},
-				{Key: "userId", Value: 1},
-			},
-			Options: options.Index().SetUnique(true),
-		},
-		{
-			Keys: bson.D{{Key: "tenantId", Value: 1}},
-		},
-		{
-			Keys: bson.D{{Key: "userId", Value: 1}},
-		},
-	}
-
-	_, _ = collection.Indexes().CreateMany(ctx, indexes)
-
-	return &TenantUserRepository{collection: collection}
-}
-
-// AddUser adds a user to a tenant
-func (r *TenantUserRepository) AddUser(ctx context.Context, tenantUser *domain.TenantUser) error {
-	tenantUser.CreatedAt = time.Now()
-	tenantUser.UpdatedAt = time.Now()
-	tenantUser.IsActive = true
-
-	result, err := r.collection.InsertOne(ctx, tenantUser)
-	if err != nil {
-		return fmt.Errorf("failed to add user to tenant: %w", err)
-	}
-
-	tenantUser.ID = result.InsertedID.(primitive.ObjectID)
-	return nil
-}
-
-// RemoveUser removes a user from a tenant
-func (r *TenantUserRepository) RemoveUser(ctx context.Context, tenantID, userID string) error {
-	_, err := r.collection.UpdateOne(
-		ctx,
-		bson.M{
-			"tenantId": tenantID,
-			"userId":   userID,
-		},
-		bson.M{
-			"$set": bson.M{
-				"isActive":  false,
-				"updatedAt": time.Now(),
-			},
-		},
-	)
-	if err != nil {
-		return fmt.Errorf("failed to remove user from tenant: %w", err)
-	}
-	return nil
-}
-
-// FindByTenantAndUser finds a tenant-user relationship
-func (r *TenantUserRepository) FindByTenantAndUser(ctx context.Context, tenantID, userID string) (*domain.TenantUser, error) {
-	var tenantUser domain.TenantUser
-	err := r.collection.FindOne(ctx, bson.M{
-		"tenantId": tenantID,
-		"userId":   userID,
-		"isActive": true,
-	}).Decode(&tenantUser)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to find tenant-user: %w", err)
-	}
-	return &tenantUser, nil
-}
-
-// ListUsersByTenant lists all users in a tenant
-func (r *TenantUserRepository) ListUsersByTenant(ctx context.Context, tenantID string) ([]*domain.TenantUser, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{
-		"tenantId": tenantID,
-		"isActive": true,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list tenant users: %w", err)
-	}
-	defer cursor.Close(ctx)
-
-	var tenantUsers []*domain.TenantUser
-	if err := cursor.All(ctx, &tenantUsers); err != nil {
-		return nil, fmt.Errorf("failed to decode tenant users: %w", err)
-	}
-
-	return tenantUsers, nil
-}
-
-// ListTenantsByUser lists all tenants for a user
-func (r *TenantUserRepository) ListTenantsByUser(ctx context.Context, userID string) ([]*domain.TenantUser, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{
-		"userId":   userID,
-		"isActive": true,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list user tenants: %w", err)
-	}
-	defer cursor.Close(ctx)
-
-	var tenantUsers []*domain.TenantUser
-	if err := cursor.All(ctx, &tenantUsers); err != nil {
-		return nil, fmt.Errorf("failed to decode user tenants: %w", err)
-	}
-
-	return tenantUsers, nil
-}
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TenantUserRepository handles tenant-user relationship data access
+type TenantUserRepository struct {
+	collection *mongo.Collection
+}
+
+// NewTenantUserRepository creates a new tenant-user repository
+func NewTenantUserRepository(db *mongo.Database) *TenantUserRepository {
+	collection := db.Collection("tenant_users")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "userId", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "tenantId", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "userId", Value: 1}},
+		},
+		{
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "isActive", Value: 1},
+				{Key: "createdAt", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "isActive", Value: 1},
+				{Key: "createdAt", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(ctx, indexes)
+
+	return &TenantUserRepository{collection: collection}
+}
+
+// AddUser adds a user to a tenant. A previously removed membership is reactivated
+// with the new role rather than inserted again.
+func (r *TenantUserRepository) AddUser(ctx context.Context, tenantUser *domain.TenantUser) error {
+	now := time.Now()
+	joined := domain.MembershipEvent{Type: domain.MembershipJoined, Role: tenantUser.Role, At: now}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"tenantId": tenantUser.TenantID,
+			"userId":   tenantUser.UserID,
+			"isActive": false,
+		},
+		bson.M{
+			"$set": bson.M{
+				"role":      tenantUser.Role,
+				"isActive":  true,
+				"updatedAt": now,
+			},
+			"$push": bson.M{"history": joined},
+		},
+		opts,
+	).Decode(tenantUser)
+	if err == nil {
+		return nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return fmt.Errorf("failed to reactivate tenant user: %w", err)
+	}
+
+	tenantUser.CreatedAt = now
+	tenantUser.UpdatedAt = now
+	tenantUser.IsActive = true
+	tenantUser.History = []domain.MembershipEvent{joined}
+
+	result, err := r.collection.InsertOne(ctx, tenantUser)
+	if err != nil {
+		return fmt.Errorf("failed to add user to tenant: %w", err)
+	}
+
+	tenantUser.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// RemoveUser removes a user from a tenant, keeping the record and its history. It returns
+// false if the user is not an active member holding the role.
+func (r *TenantUserRepository) RemoveUser(ctx context.Context, tenantID, userID, role string) (bool, error) {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"tenantId": tenantID,
+			"userId":   userID,
+			"role":     role,
+			"isActive": true,
+		},
+		bson.M{
+			"$set": bson.M{
+				"isActive":  false,
+				"updatedAt": now,
+			},
+			"$push": bson.M{"history": domain.MembershipEvent{Type: domain.MembershipLeft, Role: role, At: now}},
+		},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove user from tenant: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// RestoreUser undoes a removal by RemoveUser that could not be completed. The membership is
+// made active again and the left event dropped, so its history reads as if it never left.
+// Memberships that are active again or hold another role are left unchanged.
+func (r *TenantUserRepository) RestoreUser(ctx context.Context, tenantID, userID, role string) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"tenantId": tenantID,
+			"userId":   userID,
+			"role":     role,
+			"isActive": false,
+		},
+		bson.M{
+			"$set": bson.M{"isActive": true},
+			"$pop": bson.M{"history": 1},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore tenant user: %w", err)
+	}
+	return nil
+}
+
+// FindMembership finds the membership record of a user in a tenant, including a removed one
+func (r *TenantUserRepository) FindMembership(ctx context.Context, tenantID, userID string) (*domain.TenantUser, error) {
+	var tenantUser domain.TenantUser
+	err := r.collection.FindOne(ctx, bson.M{
+		"tenantId": tenantID,
+		"userId":   userID,
+	}).Decode(&tenantUser)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find tenant-user: %w", err)
+	}
+	return &tenantUser, nil
+}
+
+// FindByTenantAndUser finds a tenant-user relationship
+func (r *TenantUserRepository) FindByTenantAndUser(ctx context.Context, tenantID, userID string) (*domain.TenantUser, error) {
+	var tenantUser domain.TenantUser
+	err := r.collection.FindOne(ctx, bson.M{
+		"tenantId": tenantID,
+		"userId":   userID,
+		"isActive": true,
+	}).Decode(&tenantUser)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find tenant-user: %w", err)
+	}
+	return &tenantUser, nil
+}
+
+// ListUsersByTenant lists a page of the active members of a tenant, newest first
+func (r *TenantUserRepository) ListUsersByTenant(ctx context.Context, tenantID string, filter domain.MemberFilter, page domain.PageRequest) ([]*domain.TenantUser, *domain.PageInfo, error) {
+	query := bson.M{
+		"tenantId": tenantID,
+		"isActive": true,
+	}
+	if filter.Role != "" {
+		query["role"] = filter.Role
+	}
+	if filter.Search != "" {
+		query["userId"] = bson.M{"$regex": "^" + regexp.QuoteMeta(filter.Search)}
+	}
+
+	tenantUsers, info, err := r.listPage(ctx, query, page)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list tenant users: %w", err)
+	}
+	return tenantUsers, info, nil
+}
+
+// ListTenantsByUser lists a page of the active memberships of a user, newest first
+func (r *TenantUserRepository) ListTenantsByUser(ctx context.Context, userID string, filter domain.MemberFilter, page domain.PageRequest) ([]*domain.TenantUser, *domain.PageInfo, error) {
+	query := bson.M{
+		"userId":   userID,
+		"isActive": true,
+	}
+	if filter.Role != "" {
+		query["role"] = filter.Role
+	}
+
+	tenantUsers, info, err := r.listPage(ctx, query, page)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list user tenants: %w", err)
+	}
+	return tenantUsers, info, nil
+}
+
+// listPage runs a paginated membership query, newest first
+func (r *TenantUserRepository) listPage(ctx context.Context, query bson.M, page domain.PageRequest) ([]*domain.TenantUser, *domain.PageInfo, error) {
+	keyset, err := newKeysetPage(page, "createdAt", true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return findPage(ctx, r.collection, query, keyset,
+		func(tenantUser *domain.TenantUser) (interface{}, primitive.ObjectID) {
+			return tenantUser.CreatedAt, tenantUser.ID
+		})
+}
+
+// UpdateRole changes the role of an active member and records the change. It returns
+// false if the user is not an active member holding the expected previous role.
+func (r *TenantUserRepository) UpdateRole(ctx context.Context, tenantID, userID, previousRole, role string) (bool, error) {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"tenantId": tenantID,
+			"userId":   userID,
+			"role":     previousRole,
+			"isActive": true,
+		},
+		bson.M{
+			"$set": bson.M{
+				"role":      role,
+				"updatedAt": now,
+			},
+			"$push": bson.M{"history": domain.MembershipEvent{
+				Type:         domain.MembershipRoleChanged,
+				Role:         role,
+				PreviousRole: previousRole,
+				At:           now,
+			}},
+		},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update tenant user role: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// RestoreRole undoes a role change by UpdateRole that could not be completed. The previous
+// role is set back and the role change event dropped, so the history does not record it.
+// Members that no longer hold the role are left unchanged.
+func (r *TenantUserRepository) RestoreRole(ctx context.Context, tenantID, userID, role, previousRole string) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"tenantId": tenantID,
+			"userId":   userID,
+			"role":     role,
+			"isActive": true,
+		},
+		bson.M{
+			"$set": bson.M{"role": previousRole},
+			"$pop": bson.M{"history": 1},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore tenant user role: %w", err)
+	}
+	return nil
+}
+
+// DeleteByTenant permanently removes every membership of a tenant, including removed ones
+func (r *TenantUserRepository) DeleteByTenant(ctx context.Context, tenantID string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"tenantId": tenantID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete tenant users: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
+// CountActive counts the active members of a tenant
+func (r *TenantUserRepository) CountActive(ctx context.Context, tenantID string) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"tenantId": tenantID,
+		"isActive": true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tenant users: %w", err)
+	}
+	return count, nil
+}
+
+// CountByRole counts the active members of a tenant holding a role
+func (r *TenantUserRepository) CountByRole(ctx context.Context, tenantID, role string) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"tenantId": tenantID,
+		"role":     role,
+		"isActive": true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tenant users: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TransactionRunner runs groups of writes in MongoDB transactions. Repository calls made
+// with the context passed to the function take part in the transaction.
+type TransactionRunner struct {
+	client    *mongo.Client
+	supported bool
+}
+
+// Attempts and delay between them when asking the server about its topology
+const (
+	helloAttempts   = 3
+	helloRetryDelay = 2 * time.Second
+)
+
+// NewTransactionRunner creates a transaction runner for the client of a database.
+// Transactions need a replica set or sharded cluster; on a standalone server the
+// functions run without one. It fails if the server cannot be asked about its topology,
+// rather than silently running without transactions.
+func NewTransactionRunner(db *mongo.Database) (*TransactionRunner, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+
+	var err error
+	for attempt := 1; attempt <= helloAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(helloRetryDelay)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err = db.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+		cancel()
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect transaction support: %w", err)
+	}
+
+	return &TransactionRunner{
+		client:    db.Client(),
+		supported: hello.SetName != "" || hello.Msg == "isdbgrid",
+	}, nil
+}
+
+// Supported reports whether the server supports transactions
+func (t *TransactionRunner) Supported() bool {
+	return t.supported
+}
+
+// WithTransaction runs fn in a transaction and commits it if fn succeeds. The function
+// is retried on transient transaction errors, so it must only write through the context
+// it is given. Errors of fn are returned unchanged.
+func (t *TransactionRunner) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !t.supported {
+		return fn(ctx)
+	}
+
+	session, err := t.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx)
+	})
+	return err
+}
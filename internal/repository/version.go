@@ -0,0 +1,19 @@
+package repository
+
+import "go.mongodb.org/mongo-driver/bson"
+
+// bumpVersion adds the version increment to an update, so that conditional updates made
+// against the version a document was read at fail once any other change lands
+func bumpVersion(update bson.M) bson.M {
+	update["$inc"] = bson.M{"version": 1}
+	return update
+}
+
+// versionFilter matches documents at a version. Documents written before versioning have no
+// version field and match version zero.
+func versionFilter(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{int64(0), nil}}
+	}
+	return version
+}
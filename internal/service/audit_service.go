@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/vhvplatform/go-shared/errors"
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+// AuditConfig controls the audit log
+type AuditConfig struct {
+	Retention time.Duration // How long audit events are kept; zero keeps them forever
+}
+
+// DefaultAuditConfig returns the default audit settings
+func DefaultAuditConfig() AuditConfig {
+	return AuditConfig{
+		Retention: 365 * 24 * time.Hour,
+	}
+}
+
+// AuditService records who changed tenants, members and service configurations in the
+// append-only audit log
+type AuditService struct {
+	repo   *repository.AuditEventRepository
+	logger *logger.Logger
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(repo *repository.AuditEventRepository, log *logger.Logger) *AuditService {
+	return &AuditService{
+		repo:   repo,
+		logger: log,
+	}
+}
+
+// Record appends an event for a change made by the actor of the context. Updates that
+// changed no audited field are not recorded. A failure to record is logged and does not
+// fail the change, which has already been applied.
+func (s *AuditService) Record(ctx context.Context, event *domain.AuditEvent) {
+	if len(event.Changes) == 0 {
+		return
+	}
+	event.Actor = domain.ActorFromContext(ctx)
+
+	if err := s.repo.Insert(ctx, event); err != nil {
+		s.logger.Error("Failed to record audit event",
+			zap.String("action", event.Action),
+			zap.String("target_type", event.TargetType),
+			zap.String("target_id", event.TargetID),
+			zap.String("actor", event.Actor.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// ListAuditEvents lists a page of the audit events matching a filter, newest first
+func (s *AuditService) ListAuditEvents(ctx context.Context, filter domain.AuditEventFilter, page domain.PageRequest) ([]*domain.AuditEvent, *domain.PageInfo, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, nil, errors.BadRequest(err.Error())
+	}
+	if err := checkPageRequest(page); err != nil {
+		return nil, nil, err
+	}
+
+	events, info, err := s.repo.List(ctx, filter, page)
+	if err != nil {
+		if domain.IsInvalidPageToken(err) {
+			return nil, nil, errors.BadRequest("Invalid page token")
+		}
+		s.logger.Error("Failed to list audit events", zap.Error(err))
+		return nil, nil, errors.Internal("Failed to list audit events")
+	}
+	return events, info, nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// backgroundJob runs a function in the background, once when started and then on every tick
+// of an interval, until it is stopped. Services with a periodic job keep one as a field.
+type backgroundJob struct {
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+}
+
+// start launches the job unless it is already running and reports whether it was launched
+func (j *backgroundJob) start(ctx context.Context, interval time.Duration, run func(ctx context.Context)) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.cancel != nil {
+		return false
+	}
+	ctx, j.cancel = context.WithCancel(ctx)
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return true
+}
+
+// stop cancels the job, waits for a run in progress to exit and reports whether the job was running
+func (j *backgroundJob) stop() bool {
+	j.mu.Lock()
+	cancel := j.cancel
+	j.cancel = nil
+	j.mu.Unlock()
+
+	if cancel == nil {
+		return false
+	}
+	cancel()
+	j.wg.Wait()
+	return true
+}
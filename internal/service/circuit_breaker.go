@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// circuitBreaker tracks live request outcomes for a single endpoint
+type circuitBreaker struct {
+	state            string
+	windowStart      time.Time
+	requests         int
+	failures         int
+	openedAt         time.Time
+	halfOpenInFlight int
+	probedAt         time.Time // When the last half-open trial slot was taken
+}
+
+// available reports whether the endpoint can currently accept a request, without reserving it
+func (cb *circuitBreaker) available(cfg domain.CircuitBreakerConfig, now time.Time) bool {
+	switch cb.state {
+	case domain.CircuitOpen:
+		return now.Sub(cb.openedAt) >= time.Duration(cfg.OpenDuration)*time.Second
+	case domain.CircuitHalfOpen:
+		return cb.halfOpenInFlight < cfg.HalfOpenRequests || cb.probesExpired(cfg, now)
+	default:
+		return true
+	}
+}
+
+// allow reports whether a request may be sent, reserving a trial slot when half-open
+func (cb *circuitBreaker) allow(cfg domain.CircuitBreakerConfig, now time.Time) bool {
+	switch cb.state {
+	case domain.CircuitOpen:
+		if now.Sub(cb.openedAt) < time.Duration(cfg.OpenDuration)*time.Second {
+			return false
+		}
+		cb.state = domain.CircuitHalfOpen
+		cb.halfOpenInFlight = 0
+		fallthrough
+	case domain.CircuitHalfOpen:
+		if cb.probesExpired(cfg, now) {
+			// The outcomes of the earlier trials never arrived; let new trials through
+			cb.halfOpenInFlight = 0
+		}
+		if cb.halfOpenInFlight >= cfg.HalfOpenRequests {
+			return false
+		}
+		cb.halfOpenInFlight++
+		cb.probedAt = now
+		return true
+	default:
+		return true
+	}
+}
+
+// probesExpired reports whether the half-open trial slots have been taken for a whole open
+// duration without an outcome, e.g. because the request was abandoned
+func (cb *circuitBreaker) probesExpired(cfg domain.CircuitBreakerConfig, now time.Time) bool {
+	return cb.halfOpenInFlight > 0 && now.Sub(cb.probedAt) >= time.Duration(cfg.OpenDuration)*time.Second
+}
+
+// record applies a request outcome and returns true if the state changed
+func (cb *circuitBreaker) record(cfg domain.CircuitBreakerConfig, success bool, now time.Time) bool {
+	switch cb.state {
+	case domain.CircuitHalfOpen:
+		if cb.halfOpenInFlight > 0 {
+			cb.halfOpenInFlight--
+		}
+		if success {
+			cb.reset(now)
+		} else {
+			cb.trip(now)
+		}
+		return true
+	case domain.CircuitOpen:
+		// Late outcome from a request admitted before the circuit opened
+		return false
+	}
+
+	if now.Sub(cb.windowStart) > time.Duration(cfg.Window)*time.Second {
+		cb.windowStart = now
+		cb.requests = 0
+		cb.failures = 0
+	}
+
+	cb.requests++
+	if !success {
+		cb.failures++
+	}
+
+	if cb.requests >= cfg.MinRequests && float64(cb.failures)/float64(cb.requests) >= cfg.ErrorRateThreshold {
+		cb.trip(now)
+		return true
+	}
+	return false
+}
+
+// trip opens the circuit
+func (cb *circuitBreaker) trip(now time.Time) {
+	cb.state = domain.CircuitOpen
+	cb.openedAt = now
+	cb.halfOpenInFlight = 0
+}
+
+// reset closes the circuit and starts a new window
+func (cb *circuitBreaker) reset(now time.Time) {
+	cb.state = domain.CircuitClosed
+	cb.windowStart = now
+	cb.requests = 0
+	cb.failures = 0
+	cb.halfOpenInFlight = 0
+}
+
+// ReportOutcome records the outcome of a live request to an endpoint, as observed by the
+// gateway proxy. tenantID is the tenant whose config resolved the endpoint (see
+// FallbackChainResult.ConfigTenantID), which differs from the requesting tenant when the
+// config is inherited from a parent. Outcomes are ignored unless the service has its
+// circuit breaker enabled.
+func (s *ServiceRegistry) ReportOutcome(ctx context.Context, tenantID, serviceName, url string, success bool) {
+	config, err := s.findTenantConfig(ctx, tenantID, serviceName)
+	if err != nil || config == nil || !config.CircuitBreaker.Enabled {
+		return
+	}
+	cfg := config.GetCircuitBreakerConfig()
+	key := fmt.Sprintf("%s:%s:%s", tenantID, serviceName, url)
+
+	s.breakerMutex.Lock()
+	cb, exists := s.breakers[key]
+	if !exists {
+		cb = &circuitBreaker{state: domain.CircuitClosed, windowStart: time.Now()}
+		s.breakers[key] = cb
+	}
+	changed := cb.record(cfg, success, time.Now())
+	state := cb.state
+	s.breakerMutex.Unlock()
+
+	if changed {
+		s.logger.Warn("Circuit breaker state changed",
+			zap.String("tenant_id", tenantID),
+			zap.String("service", serviceName),
+			zap.String("url", url),
+			zap.String("state", state))
+	}
+}
+
+// isCircuitAvailable checks the circuit breaker of an endpoint without reserving a request
+func (s *ServiceRegistry) isCircuitAvailable(config *domain.ServiceConfig, url string) bool {
+	if !config.CircuitBreaker.Enabled {
+		return true
+	}
+	key := fmt.Sprintf("%s:%s:%s", config.TenantID, config.ServiceName, url)
+
+	s.breakerMutex.Lock()
+	defer s.breakerMutex.Unlock()
+
+	cb, exists := s.breakers[key]
+	if !exists {
+		return true
+	}
+	return cb.available(config.GetCircuitBreakerConfig(), time.Now())
+}
+
+// acquireCircuit admits a request to an endpoint, taking a trial slot if its circuit is half-open
+func (s *ServiceRegistry) acquireCircuit(config *domain.ServiceConfig, url string) bool {
+	if !config.CircuitBreaker.Enabled {
+		return true
+	}
+	key := fmt.Sprintf("%s:%s:%s", config.TenantID, config.ServiceName, url)
+
+	s.breakerMutex.Lock()
+	defer s.breakerMutex.Unlock()
+
+	cb, exists := s.breakers[key]
+	if !exists {
+		return true
+	}
+	return cb.allow(config.GetCircuitBreakerConfig(), time.Now())
+}
+
+// GetCircuitState returns the circuit breaker state of an endpoint
+func (s *ServiceRegistry) GetCircuitState(tenantID, serviceName, url string) string {
+	key := fmt.Sprintf("%s:%s:%s", tenantID, serviceName, url)
+
+	s.breakerMutex.Lock()
+	defer s.breakerMutex.Unlock()
+
+	cb, exists := s.breakers[key]
+	if !exists {
+		return domain.CircuitClosed
+	}
+	return cb.state
+}
+
+// ResetCircuit closes the circuit breaker of an endpoint
+func (s *ServiceRegistry) ResetCircuit(tenantID, serviceName, url string) {
+	key := fmt.Sprintf("%s:%s:%s", tenantID, serviceName, url)
+
+	s.breakerMutex.Lock()
+	defer s.breakerMutex.Unlock()
+
+	delete(s.breakers, key)
+}
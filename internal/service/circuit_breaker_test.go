@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	cfg := domain.CircuitBreakerConfig{
+		Enabled:            true,
+		ErrorRateThreshold: 0.5,
+		MinRequests:        4,
+		Window:             60,
+		OpenDuration:       30,
+		HalfOpenRequests:   1,
+	}
+
+	// A step either records an outcome or asks for a request, at an offset from the start
+	type step struct {
+		at        time.Duration
+		allow     bool // Ask for a request instead of recording an outcome
+		success   bool
+		want      bool // Whether the request is allowed or the outcome changed the state
+		wantState string
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "stays closed below the minimum requests",
+			steps: []step{
+				{at: 0, wantState: ""},
+				{at: time.Second, wantState: ""},
+				{at: 2 * time.Second, wantState: ""},
+				{at: 3 * time.Second, allow: true, want: true, wantState: ""},
+			},
+		},
+		{
+			name: "opens at the error rate",
+			steps: []step{
+				{at: 0, success: true, wantState: ""},
+				{at: time.Second, success: true, wantState: ""},
+				{at: 2 * time.Second, wantState: ""},
+				{at: 3 * time.Second, want: true, wantState: domain.CircuitOpen},
+				{at: 4 * time.Second, allow: true, want: false, wantState: domain.CircuitOpen},
+			},
+		},
+		{
+			name: "failures of an old window expire",
+			steps: []step{
+				{at: 0, wantState: ""},
+				{at: time.Second, wantState: ""},
+				{at: 2 * time.Second, wantState: ""},
+				{at: 2 * time.Minute, success: true, wantState: ""},
+				{at: 2*time.Minute + time.Second, allow: true, want: true, wantState: ""},
+			},
+		},
+		{
+			name: "half-open trial closes the circuit",
+			steps: []step{
+				{at: 0, wantState: ""},
+				{at: 0, wantState: ""},
+				{at: 0, wantState: ""},
+				{at: 0, want: true, wantState: domain.CircuitOpen},
+				{at: 30 * time.Second, allow: true, want: true, wantState: domain.CircuitHalfOpen},
+				{at: 30 * time.Second, allow: true, want: false, wantState: domain.CircuitHalfOpen},
+				{at: 31 * time.Second, success: true, want: true, wantState: domain.CircuitClosed},
+			},
+		},
+		{
+			name: "half-open failure reopens the circuit",
+			steps: []step{
+				{at: 0, wantState: ""},
+				{at: 0, wantState: ""},
+				{at: 0, wantState: ""},
+				{at: 0, want: true, wantState: domain.CircuitOpen},
+				{at: 30 * time.Second, allow: true, want: true, wantState: domain.CircuitHalfOpen},
+				{at: 31 * time.Second, want: true, wantState: domain.CircuitOpen},
+				{at: 60 * time.Second, allow: true, want: false, wantState: domain.CircuitOpen},
+			},
+		},
+		{
+			name: "abandoned half-open trial expires",
+			steps: []step{
+				{at: 0, wantState: ""},
+				{at: 0, wantState: ""},
+				{at: 0, wantState: ""},
+				{at: 0, want: true, wantState: domain.CircuitOpen},
+				{at: 30 * time.Second, allow: true, want: true, wantState: domain.CircuitHalfOpen},
+				{at: 45 * time.Second, allow: true, want: false, wantState: domain.CircuitHalfOpen},
+				{at: 60 * time.Second, allow: true, want: true, wantState: domain.CircuitHalfOpen},
+			},
+		},
+	}
+
+	start := time.Now()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := &circuitBreaker{windowStart: start}
+			for i, s := range tt.steps {
+				now := start.Add(s.at)
+				var got bool
+				if s.allow {
+					available := cb.available(cfg, now)
+					got = cb.allow(cfg, now)
+					if available != got {
+						t.Errorf("step %d: available() = %v, allow() = %v", i, available, got)
+					}
+				} else {
+					got = cb.record(cfg, s.success, now)
+				}
+				if got != s.want {
+					t.Errorf("step %d: got %v, want %v", i, got, s.want)
+				}
+				if cb.state != s.wantState {
+					t.Errorf("step %d: state = %q, want %q", i, cb.state, s.wantState)
+				}
+			}
+		})
+	}
+}
+
+func TestInheritedConfigCircuit(t *testing.T) {
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatalf("logger: %v", err)
+	}
+
+	const (
+		parentID    = "650000000000000000000001"
+		childID     = "650000000000000000000002"
+		serviceName = "user-service"
+		endpointURL = "http://parent-users:8080"
+		defaultURL  = "http://users:8080"
+	)
+
+	tests := []struct {
+		name      string
+		failures  int
+		wantState string
+		wantURL   string
+	}{
+		{name: "stays closed below the minimum requests", failures: 2, wantState: domain.CircuitClosed, wantURL: endpointURL},
+		{name: "trips after the minimum requests", failures: 3, wantState: domain.CircuitOpen, wantURL: defaultURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewServiceRegistry(nil, log)
+			// Serve the lookups from the caches, so no database is needed
+			registry.SetTenantRepository(&repository.TenantRepository{})
+			expiresAt := time.Now().Add(time.Hour)
+			registry.ancestorCache[childID] = &cachedAncestors{ids: []string{parentID}, expiresAt: expiresAt}
+			registry.configCache[childID+":"+serviceName] = &cachedServiceConfig{expiresAt: expiresAt}
+			registry.configCache[parentID+":"+serviceName] = &cachedServiceConfig{
+				config: &domain.ServiceConfig{
+					TenantID:        parentID,
+					ServiceName:     serviceName,
+					PrimaryEndpoint: domain.ServiceEndpoint{URL: endpointURL, IsActive: true},
+					CircuitBreaker: domain.CircuitBreakerConfig{
+						Enabled:            true,
+						ErrorRateThreshold: 0.5,
+						MinRequests:        3,
+						Window:             60,
+						OpenDuration:       30,
+						HalfOpenRequests:   1,
+					},
+					IsActive: true,
+				},
+				expiresAt: expiresAt,
+			}
+			registry.defaultCache[serviceName] = &cachedDefaultConfig{
+				config:    &domain.DefaultServiceConfig{ServiceName: serviceName, DefaultURL: defaultURL},
+				expiresAt: expiresAt,
+			}
+
+			ctx := context.Background()
+			for i := 0; i < tt.failures; i++ {
+				result, err := registry.AcquireServiceURL(ctx, childID, serviceName)
+				if err != nil {
+					t.Fatalf("request %d: %v", i, err)
+				}
+				if result.ResolvedURL != endpointURL || result.ConfigTenantID() != parentID {
+					t.Fatalf("request %d: resolved %q from %q, want %q from %q", i, result.ResolvedURL, result.ConfigTenantID(), endpointURL, parentID)
+				}
+				registry.ReportOutcome(ctx, result.ConfigTenantID(), serviceName, result.ResolvedURL, false)
+				registry.ReleaseServiceURL(result)
+			}
+
+			if state := registry.GetCircuitState(parentID, serviceName, endpointURL); state != tt.wantState {
+				t.Errorf("circuit state = %q, want %q", state, tt.wantState)
+			}
+			result, err := registry.GetServiceURL(ctx, childID, serviceName)
+			if err != nil {
+				t.Fatalf("resolve: %v", err)
+			}
+			if result.ResolvedURL != tt.wantURL {
+				t.Errorf("resolved %q, want %q", result.ResolvedURL, tt.wantURL)
+			}
+		})
+	}
+}
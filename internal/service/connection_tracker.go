@@ -0,0 +1,75 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+)
+
+// AcquireConnection marks a request to an endpoint as in flight.
+// Every call must be paired with ReleaseConnection once the upstream request completes.
+func (s *ServiceRegistry) AcquireConnection(tenantID, serviceName, url string) {
+	key := fmt.Sprintf("%s:%s:%s", tenantID, serviceName, url)
+
+	s.inFlightMutex.Lock()
+	defer s.inFlightMutex.Unlock()
+
+	s.inFlight[key]++
+}
+
+// ReleaseConnection marks an in-flight request to an endpoint as completed
+func (s *ServiceRegistry) ReleaseConnection(tenantID, serviceName, url string) {
+	key := fmt.Sprintf("%s:%s:%s", tenantID, serviceName, url)
+
+	s.inFlightMutex.Lock()
+	defer s.inFlightMutex.Unlock()
+
+	if s.inFlight[key] <= 1 {
+		delete(s.inFlight, key)
+		return
+	}
+	s.inFlight[key]--
+}
+
+// GetActiveConnections returns the number of in-flight requests to an endpoint
+func (s *ServiceRegistry) GetActiveConnections(tenantID, serviceName, url string) int {
+	key := fmt.Sprintf("%s:%s:%s", tenantID, serviceName, url)
+
+	s.inFlightMutex.Lock()
+	defer s.inFlightMutex.Unlock()
+
+	return s.inFlight[key]
+}
+
+// leastConnSelect selects the endpoint with the fewest in-flight requests relative to its weight.
+// Ties go to the endpoint with the higher weight, then to the earlier endpoint in the chain.
+// With acquire set, the request is recorded against the selected endpoint under the same lock.
+func (s *ServiceRegistry) leastConnSelect(tenantID, serviceName string, endpoints []*domain.ServiceEndpoint, acquire bool) (string, *domain.ServiceEndpoint) {
+	if len(endpoints) == 0 {
+		return "", nil
+	}
+
+	s.inFlightMutex.Lock()
+	defer s.inFlightMutex.Unlock()
+
+	var selected *domain.ServiceEndpoint
+	minConns, minWeight := 0, 1
+	for _, ep := range endpoints {
+		conns := s.inFlight[fmt.Sprintf("%s:%s:%s", tenantID, serviceName, ep.URL)]
+		weight := ep.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		// conns/weight < minConns/minWeight, compared without division
+		if selected == nil || conns*minWeight < minConns*weight ||
+			(conns*minWeight == minConns*weight && weight > minWeight) {
+			selected = ep
+			minConns, minWeight = conns, weight
+		}
+	}
+
+	if acquire {
+		s.inFlight[fmt.Sprintf("%s:%s:%s", tenantID, serviceName, selected.URL)]++
+	}
+	return selected.URL, selected
+}
@@ -0,0 +1,561 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vhvplatform/go-shared/errors"
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// DomainVerificationConfig controls how pending domain claims are verified
+type DomainVerificationConfig struct {
+	Interval    time.Duration // How often pending claims are checked
+	ClaimTTL    time.Duration // How long a claim may stay pending before it expires
+	MaxAttempts int           // Failed checks before a claim is marked failed
+	BatchSize   int           // Pending claims checked per run
+}
+
+// DefaultDomainVerificationConfig returns the default domain verification settings
+func DefaultDomainVerificationConfig() DomainVerificationConfig {
+	return DomainVerificationConfig{
+		Interval:    15 * time.Minute,
+		ClaimTTL:    72 * time.Hour,
+		MaxAttempts: 96,
+		BatchSize:   100,
+	}
+}
+
+// DomainService manages custom domain claims and their verification
+type DomainService struct {
+	domainRepo *repository.TenantDomainRepository
+	tenantRepo *repository.TenantRepository
+	resolver   DomainVerificationResolver
+	config     DomainVerificationConfig
+	audit      *AuditService
+	logger     *logger.Logger
+
+	job backgroundJob
+}
+
+// NewDomainService creates a new domain service
+func NewDomainService(
+	domainRepo *repository.TenantDomainRepository,
+	tenantRepo *repository.TenantRepository,
+	resolver DomainVerificationResolver,
+	config DomainVerificationConfig,
+	log *logger.Logger,
+) *DomainService {
+	defaults := DefaultDomainVerificationConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.ClaimTTL <= 0 {
+		config.ClaimTTL = defaults.ClaimTTL
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+
+	return &DomainService{
+		domainRepo: domainRepo,
+		tenantRepo: tenantRepo,
+		resolver:   resolver,
+		config:     config,
+		logger:     log,
+	}
+}
+
+// SetAuditService enables recording claims, verification results and primary domain changes
+// in the audit log
+func (s *DomainService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
+// CheckDomainAvailable validates a domain and ensures no other tenant has verified it
+func (s *DomainService) CheckDomainAvailable(ctx context.Context, domainName, tenantID string) error {
+	domainName = domain.NormalizeHost(domainName)
+	if !domain.IsValidDomainName(domainName) {
+		return errors.BadRequest(domain.ErrInvalidDomainName.Error())
+	}
+
+	verified, err := s.domainRepo.FindVerifiedByDomain(ctx, domainName)
+	if err != nil {
+		s.logger.Error("Failed to check existing domain", zap.Error(err))
+		return errors.Internal("Failed to check domain")
+	}
+	if verified != nil && verified.TenantID != tenantID {
+		return errors.Conflict("Domain is already verified by another tenant")
+	}
+	return nil
+}
+
+// CheckDomainClaimable checks that a tenant could claim a domain, without claiming it. The
+// tenant needs custom domains unless it has already verified the domain.
+func (s *DomainService) CheckDomainClaimable(ctx context.Context, tenant *domain.Tenant, domainName string) error {
+	tenantID := tenant.ID.Hex()
+	domainName = domain.NormalizeHost(domainName)
+	if err := s.CheckDomainAvailable(ctx, domainName, tenantID); err != nil {
+		return err
+	}
+	if tenant.Entitlements().CustomDomains {
+		return nil
+	}
+
+	existing, err := s.domainRepo.FindByTenantAndDomain(ctx, tenantID, domainName)
+	if err != nil {
+		s.logger.Error("Failed to find existing domain claim", zap.Error(err))
+		return errors.Internal("Failed to check domain")
+	}
+	if existing == nil || existing.Status != domain.DomainStatusVerified {
+		return errors.Conflict(fmt.Sprintf("Custom domains are not available on the %s tier", tierName(tenant)))
+	}
+	return nil
+}
+
+// ClaimDomain records a pending claim of a tenant on a domain and returns the proof to publish.
+// Claiming an existing domain again refreshes a failed or expired claim.
+func (s *DomainService) ClaimDomain(ctx context.Context, tenantID string, req *domain.ClaimDomainRequest) (*domain.TenantDomain, error) {
+	method := req.VerificationMethod
+	if method == "" {
+		method = domain.DomainVerificationDNS
+	}
+	if !domain.IsValidDomainVerificationMethod(method) {
+		return nil, errors.BadRequest(domain.ErrInvalidVerificationMethod.Error())
+	}
+
+	domainName := domain.NormalizeHost(req.Domain)
+	if err := s.CheckDomainAvailable(ctx, domainName, tenantID); err != nil {
+		return nil, err
+	}
+
+	tenant, err := s.tenantRepo.FindByID(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to find tenant", zap.Error(err))
+		return nil, errors.Internal("Failed to claim domain")
+	}
+	if tenant == nil {
+		return nil, errors.NotFound("Tenant not found")
+	}
+
+	existing, err := s.domainRepo.FindByTenantAndDomain(ctx, tenantID, domainName)
+	if err != nil {
+		s.logger.Error("Failed to find existing domain claim", zap.Error(err))
+		return nil, errors.Internal("Failed to claim domain")
+	}
+
+	if existing != nil && existing.Status == domain.DomainStatusVerified {
+		if req.IsPrimary && !existing.IsPrimary {
+			if err := s.promote(ctx, existing); err != nil {
+				return nil, err
+			}
+		}
+		return existing, nil
+	}
+
+	if !tenant.Entitlements().CustomDomains {
+		return nil, errors.Conflict(fmt.Sprintf("Custom domains are not available on the %s tier", tierName(tenant)))
+	}
+
+	token, err := generateVerificationToken()
+	if err != nil {
+		s.logger.Error("Failed to generate verification token", zap.Error(err))
+		return nil, errors.Internal("Failed to claim domain")
+	}
+
+	now := time.Now()
+	if existing != nil {
+		before := domain.NewAuditSnapshot(existing)
+		if existing.Status != domain.DomainStatusPending || existing.IsExpired(now) {
+			existing.Token = token
+			existing.Attempts = 0
+			existing.LastError = ""
+			existing.ExpiresAt = now.Add(s.config.ClaimTTL)
+		}
+		existing.Status = domain.DomainStatusPending
+		existing.VerificationMethod = method
+		existing.IsPrimary = req.IsPrimary
+
+		if err := s.domainRepo.Update(ctx, existing); err != nil {
+			s.logger.Error("Failed to update domain claim", zap.Error(err))
+			return nil, errors.Internal("Failed to claim domain")
+		}
+		s.recordAudit(ctx, domain.AuditActionDomainClaimed, existing, before, domain.NewAuditSnapshot(existing))
+		return existing, nil
+	}
+
+	claim := &domain.TenantDomain{
+		TenantID:           tenantID,
+		Domain:             domainName,
+		Status:             domain.DomainStatusPending,
+		VerificationMethod: method,
+		Token:              token,
+		IsPrimary:          req.IsPrimary,
+		ExpiresAt:          now.Add(s.config.ClaimTTL),
+	}
+	if err := s.domainRepo.Create(ctx, claim); err != nil {
+		s.logger.Error("Failed to create domain claim", zap.Error(err))
+		return nil, errors.Internal("Failed to claim domain")
+	}
+	s.recordAudit(ctx, domain.AuditActionDomainClaimed, claim, nil, domain.NewAuditSnapshot(claim))
+
+	s.logger.Info("Domain claimed successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("domain", domainName),
+		zap.String("method", method),
+	)
+
+	return claim, nil
+}
+
+// ListDomains lists the domain claims of a tenant
+func (s *DomainService) ListDomains(ctx context.Context, tenantID string) ([]*domain.TenantDomain, error) {
+	domains, err := s.domainRepo.ListByTenant(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to list domains", zap.String("tenant_id", tenantID), zap.Error(err))
+		return nil, errors.Internal("Failed to list domains")
+	}
+	return domains, nil
+}
+
+// VerifyDomain checks the proof of a claim immediately instead of waiting for the verification job
+func (s *DomainService) VerifyDomain(ctx context.Context, tenantID, domainID string) (*domain.TenantDomain, error) {
+	claim, err := s.getTenantDomain(ctx, tenantID, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case claim.Status == domain.DomainStatusVerified:
+		return claim, nil
+	case claim.Status == domain.DomainStatusExpired, claim.IsExpired(time.Now()):
+		return nil, errors.BadRequest("Domain claim has expired; claim the domain again")
+	}
+
+	if err := s.checkClaim(ctx, claim); err != nil {
+		return nil, err
+	}
+	return claim, nil
+}
+
+// SetPrimaryDomain makes a verified domain the primary domain of its tenant
+func (s *DomainService) SetPrimaryDomain(ctx context.Context, tenantID, domainID string) (*domain.TenantDomain, error) {
+	claim, err := s.getTenantDomain(ctx, tenantID, domainID)
+	if err != nil {
+		return nil, err
+	}
+	if claim.Status != domain.DomainStatusVerified {
+		return nil, errors.BadRequest("Only verified domains can be primary")
+	}
+
+	if err := s.promote(ctx, claim); err != nil {
+		return nil, err
+	}
+	return claim, nil
+}
+
+// ClearPrimaryDomain leaves a tenant without a primary domain. Its claims are kept and
+// verified domains still route to the tenant until removed.
+func (s *DomainService) ClearPrimaryDomain(ctx context.Context, tenantID string) error {
+	if err := s.domainRepo.ClearPrimary(ctx, tenantID); err != nil {
+		s.logger.Error("Failed to clear primary domain", zap.String("tenant_id", tenantID), zap.Error(err))
+		return errors.Internal("Failed to clear primary domain")
+	}
+	return nil
+}
+
+// RemoveDomain deletes a domain claim; removing the primary domain leaves the tenant without one
+func (s *DomainService) RemoveDomain(ctx context.Context, tenantID, domainID string) error {
+	claim, err := s.getTenantDomain(ctx, tenantID, domainID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.domainRepo.Delete(ctx, claim.ID); err != nil {
+		s.logger.Error("Failed to delete domain claim", zap.Error(err))
+		return errors.Internal("Failed to remove domain")
+	}
+
+	s.recordAudit(ctx, domain.AuditActionDomainRemoved, claim, domain.NewAuditSnapshot(claim), nil)
+
+	if claim.IsPrimary && claim.Status == domain.DomainStatusVerified {
+		if err := s.tenantRepo.UpdateDomain(ctx, tenantID, ""); err != nil {
+			s.logger.Error("Failed to clear tenant domain", zap.Error(err))
+			return errors.Internal("Failed to remove domain")
+		}
+		s.recordDomainChange(ctx, tenantID, claim.Domain, "")
+	}
+
+	s.logger.Info("Domain removed successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("domain", claim.Domain),
+	)
+
+	return nil
+}
+
+// ResolveTenantByHost finds the tenant serving a request host. A verified custom domain
+// wins; otherwise a single-label host under baseDomain is matched by subdomain.
+func (s *DomainService) ResolveTenantByHost(ctx context.Context, host, baseDomain string) (*domain.Tenant, error) {
+	host = domain.NormalizeHost(host)
+	if host == "" {
+		return nil, errors.BadRequest("Host is required")
+	}
+
+	var tenant *domain.Tenant
+	claim, err := s.domainRepo.FindVerifiedByDomain(ctx, host)
+	if err != nil {
+		s.logger.Error("Failed to find tenant by domain", zap.String("host", host), zap.Error(err))
+		return nil, errors.Internal("Failed to resolve tenant")
+	}
+
+	if claim != nil {
+		tenant, err = s.tenantRepo.FindByID(ctx, claim.TenantID)
+	} else if subdomain, ok := domain.SubdomainFromHost(host, baseDomain); ok {
+		tenant, err = s.tenantRepo.FindBySubdomain(ctx, subdomain)
+	}
+	if err != nil {
+		s.logger.Error("Failed to find tenant by host", zap.String("host", host), zap.Error(err))
+		return nil, errors.Internal("Failed to resolve tenant")
+	}
+
+	if tenant == nil {
+		return nil, errors.NotFound("Tenant not found")
+	}
+	return tenant, nil
+}
+
+// Start launches the background verification job. It returns immediately; call Stop to shut down.
+func (s *DomainService) Start(ctx context.Context) {
+	if !s.job.start(ctx, s.config.Interval, s.RunVerification) {
+		return
+	}
+
+	s.logger.Info("Domain verification started", zap.Duration("interval", s.config.Interval))
+}
+
+// Stop stops the background verification job and waits for it to exit
+func (s *DomainService) Stop() {
+	if !s.job.stop() {
+		return
+	}
+
+	s.logger.Info("Domain verification stopped")
+}
+
+// RunVerification checks a batch of pending claims, expiring those past their TTL
+func (s *DomainService) RunVerification(ctx context.Context) {
+	claims, err := s.domainRepo.FindPending(ctx, s.config.BatchSize)
+	if err != nil {
+		s.logger.Error("Failed to load pending domains", zap.Error(err))
+		return
+	}
+
+	for _, claim := range claims {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.checkClaim(ctx, claim); err != nil {
+			s.logger.Error("Failed to verify domain",
+				zap.String("tenant_id", claim.TenantID),
+				zap.String("domain", claim.Domain),
+				zap.Error(err))
+		}
+	}
+}
+
+// checkClaim checks the proof of a pending claim and records the resulting status
+func (s *DomainService) checkClaim(ctx context.Context, claim *domain.TenantDomain) error {
+	before := domain.NewAuditSnapshot(claim)
+	previousStatus := claim.Status
+
+	now := time.Now()
+	if claim.IsExpired(now) {
+		claim.Status = domain.DomainStatusExpired
+		return s.saveCheckedClaim(ctx, claim, previousStatus, before)
+	}
+
+	claim.Attempts++
+	claim.LastCheckedAt = now
+
+	proofErr := s.checkProof(ctx, claim)
+	if proofErr == nil {
+		claim.Status = domain.DomainStatusVerified
+		claim.VerifiedAt = now
+		claim.LastError = ""
+
+		err := s.saveCheckedClaim(ctx, claim, previousStatus, before)
+		if mongo.IsDuplicateKeyError(err) {
+			proofErr = fmt.Errorf("domain is verified by another tenant")
+			claim.Status = domain.DomainStatusPending
+			claim.VerifiedAt = time.Time{}
+		} else if err != nil {
+			return err
+		} else {
+			s.logger.Info("Domain verified successfully",
+				zap.String("tenant_id", claim.TenantID),
+				zap.String("domain", claim.Domain),
+			)
+			return s.promoteIfNeeded(ctx, claim)
+		}
+	}
+
+	claim.LastError = proofErr.Error()
+	if claim.Attempts >= s.config.MaxAttempts {
+		claim.Status = domain.DomainStatusFailed
+		s.logger.Warn("Domain verification failed",
+			zap.String("tenant_id", claim.TenantID),
+			zap.String("domain", claim.Domain),
+			zap.Int("attempts", claim.Attempts),
+			zap.String("error", claim.LastError))
+	}
+	return s.saveCheckedClaim(ctx, claim, previousStatus, before)
+}
+
+// checkProof looks up the published proof of a claim
+func (s *DomainService) checkProof(ctx context.Context, claim *domain.TenantDomain) error {
+	switch claim.VerificationMethod {
+	case domain.DomainVerificationHTTP:
+		body, err := s.resolver.FetchWellKnown(ctx, claim.WellKnownURL())
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(body) != claim.Token {
+			return fmt.Errorf("well-known file does not contain the verification token")
+		}
+		return nil
+
+	default:
+		records, err := s.resolver.LookupTXT(ctx, claim.TXTRecordName())
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if strings.TrimSpace(record) == claim.TXTRecordValue() {
+				return nil
+			}
+		}
+		return fmt.Errorf("TXT record %s does not contain the verification token", claim.TXTRecordName())
+	}
+}
+
+// promoteIfNeeded makes a newly verified claim primary if requested or if the tenant has none
+func (s *DomainService) promoteIfNeeded(ctx context.Context, claim *domain.TenantDomain) error {
+	if !claim.IsPrimary {
+		tenant, err := s.tenantRepo.FindByID(ctx, claim.TenantID)
+		if err != nil {
+			s.logger.Error("Failed to find tenant", zap.Error(err))
+			return errors.Internal("Failed to verify domain")
+		}
+		if tenant == nil || tenant.Domain != "" {
+			return nil
+		}
+	}
+	return s.promote(ctx, claim)
+}
+
+// promote makes a verified claim the primary domain of its tenant
+func (s *DomainService) promote(ctx context.Context, claim *domain.TenantDomain) error {
+	tenant, err := s.tenantRepo.FindByID(ctx, claim.TenantID)
+	if err != nil {
+		s.logger.Error("Failed to find tenant", zap.Error(err))
+		return errors.Internal("Failed to set primary domain")
+	}
+	if tenant == nil {
+		return errors.NotFound("Tenant not found")
+	}
+
+	if err := s.domainRepo.SetPrimary(ctx, claim.TenantID, claim.ID); err != nil {
+		s.logger.Error("Failed to set primary domain", zap.Error(err))
+		return errors.Internal("Failed to set primary domain")
+	}
+	if err := s.tenantRepo.UpdateDomain(ctx, claim.TenantID, claim.Domain); err != nil {
+		s.logger.Error("Failed to update tenant domain", zap.Error(err))
+		return errors.Internal("Failed to set primary domain")
+	}
+	s.recordDomainChange(ctx, claim.TenantID, tenant.Domain, claim.Domain)
+
+	claim.IsPrimary = true
+	return nil
+}
+
+// saveClaim persists the verification state of a claim
+func (s *DomainService) saveClaim(ctx context.Context, claim *domain.TenantDomain) error {
+	if err := s.domainRepo.Update(ctx, claim); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+		s.logger.Error("Failed to update domain claim", zap.Error(err))
+		return errors.Internal("Failed to verify domain")
+	}
+	return nil
+}
+
+// saveCheckedClaim persists a checked claim, recording it in the audit log if its status changed.
+// Attempts that leave the status unchanged are not audited.
+func (s *DomainService) saveCheckedClaim(ctx context.Context, claim *domain.TenantDomain, previousStatus string, before domain.AuditSnapshot) error {
+	if err := s.saveClaim(ctx, claim); err != nil {
+		return err
+	}
+	if claim.Status != previousStatus {
+		s.recordAudit(ctx, domain.AuditActionDomainStatusChanged, claim, before, domain.NewAuditSnapshot(claim))
+	}
+	return nil
+}
+
+// recordAudit records a change to a domain claim in the audit log
+func (s *DomainService) recordAudit(ctx context.Context, action string, claim *domain.TenantDomain, before, after domain.AuditSnapshot) {
+	if s.audit == nil {
+		return
+	}
+	s.audit.Record(ctx, domain.NewAuditEvent(action, domain.AuditTargetDomain, claim.Domain, claim.TenantID, before, after))
+}
+
+// recordDomainChange records a change to the primary domain of a tenant in the audit log
+func (s *DomainService) recordDomainChange(ctx context.Context, tenantID, from, to string) {
+	if s.audit == nil || from == to {
+		return
+	}
+	before, after := domain.AuditSnapshot{}, domain.AuditSnapshot{}
+	if from != "" {
+		before["domain"] = from
+	}
+	if to != "" {
+		after["domain"] = to
+	}
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionTenantDomainChanged, domain.AuditTargetTenant,
+		tenantID, tenantID, before, after))
+}
+
+// getTenantDomain loads a claim and checks that it belongs to the tenant
+func (s *DomainService) getTenantDomain(ctx context.Context, tenantID, domainID string) (*domain.TenantDomain, error) {
+	claim, err := s.domainRepo.FindByID(ctx, domainID)
+	if err != nil {
+		s.logger.Error("Failed to find domain", zap.String("domain_id", domainID), zap.Error(err))
+		return nil, errors.Internal("Failed to get domain")
+	}
+	if claim == nil || claim.TenantID != tenantID {
+		return nil, errors.NotFound("Domain not found")
+	}
+	return claim, nil
+}
+
+// generateVerificationToken returns a random hex token
+func generateVerificationToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
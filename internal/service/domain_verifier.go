@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DomainVerificationResolver looks up the proofs a tenant publishes to verify a domain
+type DomainVerificationResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+	FetchWellKnown(ctx context.Context, url string) (string, error)
+}
+
+// NetVerificationResolver resolves proofs through DNS and HTTP
+type NetVerificationResolver struct {
+	resolver *net.Resolver
+	client   *http.Client
+}
+
+// NewNetVerificationResolver creates a resolver using the system DNS resolver. Well-known
+// files are fetched directly, without following redirects and only from public addresses,
+// since tenants choose the domains that are fetched.
+func NewNetVerificationResolver() *NetVerificationResolver {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: refuseNonPublicAddress,
+	}
+
+	return &NetVerificationResolver{
+		resolver: net.DefaultResolver,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				Proxy:       nil,
+				DialContext: dialer.DialContext,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return fmt.Errorf("well-known file redirected to %s; redirects are not followed", req.URL.Redacted())
+			},
+		},
+	}
+}
+
+// refuseNonPublicAddress stops connections to loopback, private, link-local and other
+// addresses that are not reachable from the internet. It runs after DNS resolution, so
+// names resolving to internal addresses are refused too.
+func refuseNonPublicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("invalid address %s", address)
+	}
+	if !isPublicIP(ip) {
+		return fmt.Errorf("refusing to connect to non-public address %s", ip)
+	}
+	return nil
+}
+
+// isPublicIP checks if an address is globally routable
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// LookupTXT returns the TXT records published at a DNS name
+func (r *NetVerificationResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return r.resolver.LookupTXT(ctx, name)
+}
+
+// FetchWellKnown returns the body served at a well-known URL
+func (r *NetVerificationResolver) FetchWellKnown(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("well-known file returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// FakeVerificationResolver serves proofs from memory, for tests and local development
+type FakeVerificationResolver struct {
+	txt       map[string][]string
+	wellKnown map[string]string
+	mu        sync.RWMutex
+}
+
+// NewFakeVerificationResolver creates an empty in-memory resolver
+func NewFakeVerificationResolver() *FakeVerificationResolver {
+	return &FakeVerificationResolver{
+		txt:       make(map[string][]string),
+		wellKnown: make(map[string]string),
+	}
+}
+
+// SetTXT publishes TXT records at a DNS name
+func (r *FakeVerificationResolver) SetTXT(name string, values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.txt[strings.ToLower(name)] = values
+}
+
+// SetWellKnown publishes a well-known file body at a URL
+func (r *FakeVerificationResolver) SetWellKnown(url, body string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.wellKnown[url] = body
+}
+
+// LookupTXT returns the TXT records published at a DNS name
+func (r *FakeVerificationResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	values, ok := r.txt[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("no TXT records for %s", name)
+	}
+	return values, nil
+}
+
+// FetchWellKnown returns the body published at a URL
+func (r *FakeVerificationResolver) FetchWellKnown(ctx context.Context, url string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	body, ok := r.wellKnown[url]
+	if !ok {
+		return "", fmt.Errorf("well-known file returned status %d", http.StatusNotFound)
+	}
+	return body, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+)
+
+func TestDomainServiceCheckProof(t *testing.T) {
+	claim := func(method string) *domain.TenantDomain {
+		return &domain.TenantDomain{Domain: "shop.example.com", Token: "secret-token", VerificationMethod: method}
+	}
+	dns := claim(domain.DomainVerificationDNS)
+	web := claim(domain.DomainVerificationHTTP)
+
+	tests := []struct {
+		name    string
+		claim   *domain.TenantDomain
+		publish func(r *FakeVerificationResolver)
+		wantErr bool
+	}{
+		{
+			name:  "TXT record",
+			claim: dns,
+			publish: func(r *FakeVerificationResolver) {
+				r.SetTXT(dns.TXTRecordName(), "unrelated", " "+dns.TXTRecordValue()+" ")
+			},
+		},
+		{
+			name:  "TXT record name is case insensitive",
+			claim: dns,
+			publish: func(r *FakeVerificationResolver) {
+				r.SetTXT(strings.ToUpper(dns.TXTRecordName()), dns.TXTRecordValue())
+			},
+		},
+		{
+			name:    "wrong TXT record",
+			claim:   dns,
+			publish: func(r *FakeVerificationResolver) { r.SetTXT(dns.TXTRecordName(), "other-token") },
+			wantErr: true,
+		},
+		{name: "no TXT record", claim: dns, publish: func(r *FakeVerificationResolver) {}, wantErr: true},
+		{
+			name:    "well-known file",
+			claim:   web,
+			publish: func(r *FakeVerificationResolver) { r.SetWellKnown(web.WellKnownURL(), web.Token+"\n") },
+		},
+		{
+			name:    "wrong well-known file",
+			claim:   web,
+			publish: func(r *FakeVerificationResolver) { r.SetWellKnown(web.WellKnownURL(), "other-token") },
+			wantErr: true,
+		},
+		{
+			name:    "TXT record does not verify an HTTP claim",
+			claim:   web,
+			publish: func(r *FakeVerificationResolver) { r.SetTXT(web.TXTRecordName(), web.TXTRecordValue()) },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := NewFakeVerificationResolver()
+			tt.publish(resolver)
+			s := &DomainService{resolver: resolver}
+
+			err := s.checkProof(context.Background(), tt.claim)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkProof() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
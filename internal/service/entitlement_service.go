@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vhvplatform/go-shared/errors"
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+// EntitlementService resolves and enforces the entitlements of tenants
+type EntitlementService struct {
+	tenantRepo        *repository.TenantRepository
+	tenantUserRepo    *repository.TenantUserRepository
+	serviceConfigRepo *repository.ServiceConfigRepository
+	audit             *AuditService
+	logger            *logger.Logger
+}
+
+// NewEntitlementService creates a new entitlement service
+func NewEntitlementService(
+	tenantRepo *repository.TenantRepository,
+	tenantUserRepo *repository.TenantUserRepository,
+	serviceConfigRepo *repository.ServiceConfigRepository,
+	log *logger.Logger,
+) *EntitlementService {
+	return &EntitlementService{
+		tenantRepo:        tenantRepo,
+		tenantUserRepo:    tenantUserRepo,
+		serviceConfigRepo: serviceConfigRepo,
+		logger:            log,
+	}
+}
+
+// SetAuditService enables recording override changes in the audit log
+func (s *EntitlementService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
+// GetEntitlements returns the effective entitlements of a tenant and its current usage
+func (s *EntitlementService) GetEntitlements(ctx context.Context, tenantID string) (*domain.EntitlementsResponse, error) {
+	tenant, err := s.getTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.tenantUserRepo.CountActive(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to count tenant members", zap.Error(err))
+		return nil, errors.Internal("Failed to get entitlements")
+	}
+	serviceConfigs, err := s.serviceConfigRepo.CountByTenant(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to count service configs", zap.Error(err))
+		return nil, errors.Internal("Failed to get entitlements")
+	}
+
+	return &domain.EntitlementsResponse{
+		TenantID:     tenantID,
+		Tier:         tenant.SubscriptionTier,
+		Entitlements: tenant.Entitlements(),
+		Overrides:    tenant.EntitlementOverrides,
+		Usage: domain.EntitlementUsage{
+			Members:        members,
+			ServiceConfigs: serviceConfigs,
+		},
+	}, nil
+}
+
+// UpdateOverrides replaces the entitlement overrides of a tenant. Nil overrides restore the tier entitlements.
+func (s *EntitlementService) UpdateOverrides(ctx context.Context, tenantID string, overrides *domain.EntitlementOverrides) (*domain.EntitlementsResponse, error) {
+	if overrides != nil {
+		if err := overrides.Validate(); err != nil {
+			return nil, errors.BadRequest(err.Error())
+		}
+	}
+	tenant, err := s.getTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tenantRepo.UpdateEntitlementOverrides(ctx, tenantID, overrides); err != nil {
+		s.logger.Error("Failed to update entitlement overrides", zap.Error(err))
+		return nil, errors.Internal("Failed to update entitlement overrides")
+	}
+
+	if s.audit != nil {
+		after := *tenant
+		after.EntitlementOverrides = overrides
+		s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionTenantEntitlementsSet, domain.AuditTargetTenant,
+			tenantID, tenantID, domain.NewAuditSnapshot(tenant), domain.NewAuditSnapshot(&after)))
+	}
+
+	s.logger.Info("Entitlement overrides updated successfully",
+		zap.String("tenant_id", tenantID),
+	)
+
+	return s.GetEntitlements(ctx, tenantID)
+}
+
+// CheckMemberLimit ensures a tenant has room for another member
+func (s *EntitlementService) CheckMemberLimit(ctx context.Context, tenant *domain.Tenant) error {
+	entitlements := tenant.Entitlements()
+	if entitlements.MaxMembers == domain.Unlimited {
+		return nil
+	}
+
+	members, err := s.tenantUserRepo.CountActive(ctx, tenant.ID.Hex())
+	if err != nil {
+		s.logger.Error("Failed to count tenant members", zap.Error(err))
+		return errors.Internal("Failed to check member limit")
+	}
+	if !domain.AllowsCount(entitlements.MaxMembers, members) {
+		return errors.Conflict(fmt.Sprintf("Member limit of %d reached for the %s tier", entitlements.MaxMembers, tierName(tenant)))
+	}
+	return nil
+}
+
+// CheckServiceConfig ensures a tenant may store a service configuration: a new service
+// must fit in the service config limit and the load-balance strategy must be allowed
+func (s *EntitlementService) CheckServiceConfig(ctx context.Context, config *domain.ServiceConfig) error {
+	tenant, err := s.getTenant(ctx, config.TenantID)
+	if err != nil {
+		return err
+	}
+	entitlements := tenant.Entitlements()
+
+	if !entitlements.AllowsLoadBalanceStrategy(config.LoadBalanceStrategy) {
+		return errors.Conflict(fmt.Sprintf("Load balance strategy %q is not available on the %s tier", config.LoadBalanceStrategy, tierName(tenant)))
+	}
+
+	if entitlements.MaxServiceConfigs == domain.Unlimited {
+		return nil
+	}
+	existing, err := s.serviceConfigRepo.FindByTenantAndService(ctx, config.TenantID, config.ServiceName)
+	if err != nil {
+		s.logger.Error("Failed to find service config", zap.Error(err))
+		return errors.Internal("Failed to check service config limit")
+	}
+	if existing != nil {
+		return nil
+	}
+	count, err := s.serviceConfigRepo.CountByTenant(ctx, config.TenantID)
+	if err != nil {
+		s.logger.Error("Failed to count service configs", zap.Error(err))
+		return errors.Internal("Failed to check service config limit")
+	}
+	if !domain.AllowsCount(entitlements.MaxServiceConfigs, count) {
+		return errors.Conflict(fmt.Sprintf("Service config limit of %d reached for the %s tier", entitlements.MaxServiceConfigs, tierName(tenant)))
+	}
+	return nil
+}
+
+// getTenant loads a tenant, mapping a missing tenant to NotFound
+func (s *EntitlementService) getTenant(ctx context.Context, tenantID string) (*domain.Tenant, error) {
+	tenant, err := s.tenantRepo.FindByID(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to find tenant", zap.String("tenant_id", tenantID), zap.Error(err))
+		return nil, errors.Internal("Failed to get tenant")
+	}
+	if tenant == nil {
+		return nil, errors.NotFound("Tenant not found")
+	}
+	return tenant, nil
+}
+
+// tierName returns the tier a tenant is billed on, for error messages
+func tierName(tenant *domain.Tenant) string {
+	if domain.IsValidSubscriptionTier(tenant.SubscriptionTier) {
+		return tenant.SubscriptionTier
+	}
+	return domain.SubscriptionFree
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// OutboxConfig controls how tenant events are relayed from the outbox
+type OutboxConfig struct {
+	Interval      time.Duration // How often the outbox is polled for pending events
+	BatchSize     int           // Tenants, and events per tenant, relayed per poll
+	LeaseDuration time.Duration // How long an instance may publish a tenant's events before others may take over
+	MinBackoff    time.Duration // Delay before the first retry of a failed event
+	MaxBackoff    time.Duration // Longest delay between retries
+	Retention     time.Duration // How long published events are kept; zero keeps them forever
+}
+
+// DefaultOutboxConfig returns the default outbox settings
+func DefaultOutboxConfig() OutboxConfig {
+	return OutboxConfig{
+		Interval:      2 * time.Second,
+		BatchSize:     100,
+		LeaseDuration: 30 * time.Second,
+		MinBackoff:    time.Second,
+		MaxBackoff:    10 * time.Minute,
+		Retention:     7 * 24 * time.Hour,
+	}
+}
+
+// EventOutbox records tenant events in the same transaction as the changes they announce,
+// so an event is stored if and only if its change is committed
+type EventOutbox struct {
+	repo         *repository.OutboxRepository
+	transactions *repository.TransactionRunner
+}
+
+// NewEventOutbox creates a new event outbox
+func NewEventOutbox(repo *repository.OutboxRepository, transactions *repository.TransactionRunner) *EventOutbox {
+	return &EventOutbox{
+		repo:         repo,
+		transactions: transactions,
+	}
+}
+
+// Transaction runs fn in a transaction. Writes made with the context passed to fn,
+// including events added to the outbox, are committed together. fn may run more than
+// once and should return repository errors unchanged so transient errors are retried.
+// A nil outbox runs fn without a transaction.
+func (o *EventOutbox) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if o == nil {
+		return fn(ctx)
+	}
+	return o.transactions.WithTransaction(ctx, fn)
+}
+
+// Add records an event made by the actor of the context in the outbox. A nil outbox
+// records nothing.
+func (o *EventOutbox) Add(ctx context.Context, event *domain.TenantEvent) error {
+	if o == nil {
+		return nil
+	}
+	event.Actor = domain.ActorFromContext(ctx)
+	return o.repo.Insert(ctx, event)
+}
+
+// OutboxStore holds the pending events the relay publishes and the leases that give one
+// relay instance at a time the events of a tenant. It is implemented by
+// repository.OutboxRepository.
+type OutboxStore interface {
+	FindPendingTenants(ctx context.Context, now time.Time, limit int) ([]string, error)
+	FindPending(ctx context.Context, tenantID string, limit int) ([]*domain.OutboxEvent, error)
+	MarkPublished(ctx context.Context, event *domain.OutboxEvent) error
+	MarkFailed(ctx context.Context, event *domain.OutboxEvent, cause error, nextAttemptAt time.Time) error
+	AcquireLease(ctx context.Context, tenantID, owner string, duration time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, tenantID, owner string) error
+}
+
+// OutboxRelay publishes the events of the outbox to a sink. Events of a tenant are
+// published one at a time in sequence order; a failed event is retried with backoff and
+// holds back the later events of its tenant. Delivery is at least once: an event whose
+// delivery could not be recorded is published again.
+type OutboxRelay struct {
+	repo   OutboxStore
+	sink   EventSink
+	config OutboxConfig
+	owner  string // Identifies this instance in tenant leases
+	logger *logger.Logger
+
+	job backgroundJob
+}
+
+// NewOutboxRelay creates a new outbox relay
+func NewOutboxRelay(repo OutboxStore, sink EventSink, config OutboxConfig, log *logger.Logger) *OutboxRelay {
+	defaults := DefaultOutboxConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.LeaseDuration <= 0 {
+		config.LeaseDuration = defaults.LeaseDuration
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = defaults.MinBackoff
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = config.MinBackoff
+	}
+
+	return &OutboxRelay{
+		repo:   repo,
+		sink:   sink,
+		config: config,
+		owner:  primitive.NewObjectID().Hex(),
+		logger: log,
+	}
+}
+
+// Start runs the relay in the background until Stop is called
+func (r *OutboxRelay) Start(ctx context.Context) {
+	if !r.job.start(ctx, r.config.Interval, r.RunRelay) {
+		return
+	}
+
+	r.logger.Info("Outbox relay started",
+		zap.Duration("interval", r.config.Interval),
+		zap.Int("batch_size", r.config.BatchSize))
+}
+
+// Stop stops the relay and waits for it to exit
+func (r *OutboxRelay) Stop() {
+	if !r.job.stop() {
+		return
+	}
+
+	r.logger.Info("Outbox relay stopped")
+}
+
+// RunRelay publishes the due events of a batch of tenants
+func (r *OutboxRelay) RunRelay(ctx context.Context) {
+	tenantIDs, err := r.repo.FindPendingTenants(ctx, time.Now(), r.config.BatchSize)
+	if err != nil {
+		r.logger.Error("Failed to load tenants with pending events", zap.Error(err))
+		return
+	}
+
+	for _, tenantID := range tenantIDs {
+		if ctx.Err() != nil {
+			return
+		}
+		r.relayTenant(ctx, tenantID)
+	}
+}
+
+// relayTenant publishes the pending events of a tenant in order, stopping at the first
+// event that fails or is not yet due for a retry. Only the instance holding the lease of
+// the tenant publishes, so instances never interleave the events of a tenant.
+func (r *OutboxRelay) relayTenant(ctx context.Context, tenantID string) {
+	acquired, err := r.repo.AcquireLease(ctx, tenantID, r.owner, r.config.LeaseDuration)
+	if err != nil {
+		r.logger.Error("Failed to acquire outbox lease", zap.String("tenant_id", tenantID), zap.Error(err))
+		return
+	}
+	if !acquired {
+		return
+	}
+	leasedAt := time.Now()
+	defer func() {
+		if err := r.repo.ReleaseLease(context.Background(), tenantID, r.owner); err != nil {
+			r.logger.Warn("Failed to release outbox lease", zap.String("tenant_id", tenantID), zap.Error(err))
+		}
+	}()
+
+	events, err := r.repo.FindPending(ctx, tenantID, r.config.BatchSize)
+	if err != nil {
+		r.logger.Error("Failed to load pending events", zap.String("tenant_id", tenantID), zap.Error(err))
+		return
+	}
+
+	for _, event := range events {
+		if ctx.Err() != nil || event.NextAttemptAt.After(time.Now()) {
+			return
+		}
+
+		// Renew the lease before it can expire in the middle of a slow publish
+		if time.Since(leasedAt) > r.config.LeaseDuration/2 {
+			if acquired, err := r.repo.AcquireLease(ctx, tenantID, r.owner, r.config.LeaseDuration); err != nil || !acquired {
+				return
+			}
+			leasedAt = time.Now()
+		}
+
+		if publishErr := r.sink.Publish(ctx, &event.TenantEvent); publishErr != nil {
+			nextAttemptAt := time.Now().Add(r.backoff(event.Attempts))
+			r.logger.Warn("Failed to publish tenant event",
+				zap.String("event_id", event.ID.Hex()),
+				zap.String("type", event.Type),
+				zap.String("tenant_id", tenantID),
+				zap.Int64("sequence", event.Sequence),
+				zap.Int("attempts", event.Attempts+1),
+				zap.Time("next_attempt_at", nextAttemptAt),
+				zap.Error(publishErr))
+			if err := r.repo.MarkFailed(ctx, event, publishErr, nextAttemptAt); err != nil {
+				r.logger.Error("Failed to record event failure", zap.String("event_id", event.ID.Hex()), zap.Error(err))
+			}
+			return
+		}
+
+		if err := r.repo.MarkPublished(ctx, event); err != nil {
+			// The event stays pending and is published again
+			r.logger.Error("Failed to record published event", zap.String("event_id", event.ID.Hex()), zap.Error(err))
+			return
+		}
+	}
+}
+
+// backoff returns the delay before retrying an event that failed after a number of
+// earlier attempts, doubling from the minimum up to the maximum backoff
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	delay := r.config.MinBackoff
+	for i := 0; i < attempts && delay < r.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > r.config.MaxBackoff {
+		delay = r.config.MaxBackoff
+	}
+	return delay
+}
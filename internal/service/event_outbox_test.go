@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryOutbox is an in-memory outbox store holding the events of each tenant in sequence order
+type memoryOutbox struct {
+	events map[string][]*domain.OutboxEvent
+	leases map[string]string
+	mu     sync.Mutex
+}
+
+func newMemoryOutbox() *memoryOutbox {
+	return &memoryOutbox{
+		events: make(map[string][]*domain.OutboxEvent),
+		leases: make(map[string]string),
+	}
+}
+
+func (o *memoryOutbox) add(tenantID string, sequence int64) {
+	o.events[tenantID] = append(o.events[tenantID], &domain.OutboxEvent{
+		TenantEvent: domain.TenantEvent{ID: primitive.NewObjectID(), Type: "tenant.updated", TenantID: tenantID, Sequence: sequence},
+		Status:      domain.OutboxStatusPending,
+	})
+}
+
+func (o *memoryOutbox) pending(tenantID string) []*domain.OutboxEvent {
+	var pending []*domain.OutboxEvent
+	for _, event := range o.events[tenantID] {
+		if event.Status == domain.OutboxStatusPending {
+			pending = append(pending, event)
+		}
+	}
+	return pending
+}
+
+func (o *memoryOutbox) FindPendingTenants(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var tenantIDs []string
+	for tenantID := range o.events {
+		if pending := o.pending(tenantID); len(pending) > 0 && !pending[0].NextAttemptAt.After(now) {
+			tenantIDs = append(tenantIDs, tenantID)
+		}
+	}
+	sort.Strings(tenantIDs)
+	if len(tenantIDs) > limit {
+		tenantIDs = tenantIDs[:limit]
+	}
+	return tenantIDs, nil
+}
+
+func (o *memoryOutbox) FindPending(ctx context.Context, tenantID string, limit int) ([]*domain.OutboxEvent, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	pending := o.pending(tenantID)
+	if len(pending) > limit {
+		pending = pending[:limit]
+	}
+	return pending, nil
+}
+
+func (o *memoryOutbox) MarkPublished(ctx context.Context, event *domain.OutboxEvent) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	event.Status = domain.OutboxStatusPublished
+	event.Attempts++
+	return nil
+}
+
+func (o *memoryOutbox) MarkFailed(ctx context.Context, event *domain.OutboxEvent, cause error, nextAttemptAt time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	event.NextAttemptAt = nextAttemptAt
+	event.LastError = cause.Error()
+	event.Attempts++
+	return nil
+}
+
+func (o *memoryOutbox) AcquireLease(ctx context.Context, tenantID, owner string, duration time.Duration) (bool, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if holder, ok := o.leases[tenantID]; ok && holder != owner {
+		return false, nil
+	}
+	o.leases[tenantID] = owner
+	return true, nil
+}
+
+func (o *memoryOutbox) ReleaseLease(ctx context.Context, tenantID, owner string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.leases[tenantID] == owner {
+		delete(o.leases, tenantID)
+	}
+	return nil
+}
+
+// failingSink fails the events of chosen sequences and passes the others on
+type failingSink struct {
+	next EventSink
+	fail map[int64]bool
+}
+
+func (s *failingSink) Publish(ctx context.Context, event *domain.TenantEvent) error {
+	if s.fail[event.Sequence] {
+		return errors.New("sink unavailable")
+	}
+	return s.next.Publish(ctx, event)
+}
+
+func TestOutboxRelayOrdering(t *testing.T) {
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatalf("logger: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		events   map[string][]int64
+		fail     []int64
+		leasedBy map[string]string
+		runs     int
+		want     map[string][]int64 // Published sequences per tenant, in publish order
+	}{
+		{
+			name:   "publishes each tenant in sequence order",
+			events: map[string][]int64{"t1": {1, 2, 3}, "t2": {1, 2}},
+			runs:   1,
+			want:   map[string][]int64{"t1": {1, 2, 3}, "t2": {1, 2}},
+		},
+		{
+			name:   "failed event holds back later events of its tenant",
+			events: map[string][]int64{"t1": {1, 2, 3}, "t2": {1, 2}},
+			fail:   []int64{2},
+			runs:   2,
+			want:   map[string][]int64{"t1": {1}, "t2": {1}},
+		},
+		{
+			name:     "tenant leased by another instance is skipped",
+			events:   map[string][]int64{"t1": {1, 2}, "t2": {1}},
+			leasedBy: map[string]string{"t1": "other"},
+			runs:     1,
+			want:     map[string][]int64{"t2": {1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryOutbox()
+			for tenantID, sequences := range tt.events {
+				for _, sequence := range sequences {
+					store.add(tenantID, sequence)
+				}
+			}
+			for tenantID, owner := range tt.leasedBy {
+				store.leases[tenantID] = owner
+			}
+
+			channel := NewChannelEventSink(16)
+			fail := make(map[int64]bool)
+			for _, sequence := range tt.fail {
+				fail[sequence] = true
+			}
+
+			relay := NewOutboxRelay(store, &failingSink{next: channel, fail: fail}, OutboxConfig{MinBackoff: time.Hour}, log)
+			for i := 0; i < tt.runs; i++ {
+				relay.RunRelay(context.Background())
+			}
+
+			got := make(map[string][]int64)
+			for len(channel.Events()) > 0 {
+				event := <-channel.Events()
+				got[event.TenantID] = append(got[event.TenantID], event.Sequence)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("published %v, want %v", got, tt.want)
+			}
+
+			for tenantID, events := range store.events {
+				for _, event := range events {
+					if fail[event.Sequence] && event.Attempts != 1 {
+						t.Errorf("tenant %s event %d: attempts = %d, want 1 before its backoff ends", tenantID, event.Sequence, event.Attempts)
+					}
+				}
+			}
+		})
+	}
+}
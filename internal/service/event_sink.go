@@ -0,0 +1,179 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// Headers of webhook and broker deliveries of tenant events
+const (
+	EventIDHeader        = "X-Event-ID"
+	EventTypeHeader      = "X-Event-Type"
+	EventSequenceHeader  = "X-Event-Sequence"
+	EventSignatureHeader = "X-Event-Signature" // sha256=<hex HMAC of the body>, when a secret is configured
+)
+
+// EventSink publishes tenant events to other platform services. An error leaves the event
+// in the outbox to be published again, so sinks may deliver an event more than once.
+type EventSink interface {
+	Publish(ctx context.Context, event *domain.TenantEvent) error
+}
+
+// LogEventSink records events in the log without delivering them
+type LogEventSink struct {
+	logger *logger.Logger
+}
+
+// NewLogEventSink creates a sink that only logs events
+func NewLogEventSink(log *logger.Logger) *LogEventSink {
+	return &LogEventSink{logger: log}
+}
+
+// Publish logs the event
+func (s *LogEventSink) Publish(ctx context.Context, event *domain.TenantEvent) error {
+	s.logger.Info("Tenant event published",
+		zap.String("event_id", event.ID.Hex()),
+		zap.String("type", event.Type),
+		zap.String("tenant_id", event.TenantID),
+		zap.Int64("sequence", event.Sequence),
+	)
+	return nil
+}
+
+// ChannelEventSink delivers events to an in-memory channel, e.g. for tests or consumers
+// running in the same process
+type ChannelEventSink struct {
+	events chan *domain.TenantEvent
+}
+
+// NewChannelEventSink creates a sink delivering to a channel with the given buffer size
+func NewChannelEventSink(buffer int) *ChannelEventSink {
+	return &ChannelEventSink{events: make(chan *domain.TenantEvent, buffer)}
+}
+
+// Events returns the channel events are delivered to
+func (s *ChannelEventSink) Events() <-chan *domain.TenantEvent {
+	return s.events
+}
+
+// Publish sends the event to the channel, failing if the context ends before it is received
+func (s *ChannelEventSink) Publish(ctx context.Context, event *domain.TenantEvent) error {
+	select {
+	case s.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WebhookEventSink posts events as JSON to an HTTP endpoint. Responses other than 2xx
+// fail the delivery.
+type WebhookEventSink struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+// NewWebhookEventSink creates a sink posting to a URL. With a secret, requests carry an
+// HMAC-SHA256 signature of the body so receivers can authenticate them.
+func NewWebhookEventSink(url, secret string, timeout time.Duration) *WebhookEventSink {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &WebhookEventSink{
+		url:    url,
+		secret: []byte(secret),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Publish posts the event to the webhook
+func (s *WebhookEventSink) Publish(ctx context.Context, event *domain.TenantEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range eventHeaders(event) {
+		req.Header.Set(name, value)
+	}
+	if len(s.secret) > 0 {
+		req.Header.Set(EventSignatureHeader, "sha256="+s.sign(body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sign computes the hex HMAC-SHA256 of a request body
+func (s *WebhookEventSink) sign(body []byte) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// MessagePublisher sends a message to a topic of a message broker, such as Kafka, NATS or
+// RabbitMQ. Messages with the same key must be kept in order.
+type MessagePublisher interface {
+	Publish(ctx context.Context, topic, key string, body []byte, headers map[string]string) error
+}
+
+// BrokerEventSink publishes events to a message broker, keyed by tenant so brokers that
+// partition by key keep the events of a tenant in order
+type BrokerEventSink struct {
+	publisher MessagePublisher
+	topic     string
+}
+
+// NewBrokerEventSink creates a sink publishing to a broker topic
+func NewBrokerEventSink(publisher MessagePublisher, topic string) *BrokerEventSink {
+	return &BrokerEventSink{
+		publisher: publisher,
+		topic:     topic,
+	}
+}
+
+// Publish sends the event as JSON to the broker
+func (s *BrokerEventSink) Publish(ctx context.Context, event *domain.TenantEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	return s.publisher.Publish(ctx, s.topic, event.TenantID, body, eventHeaders(event))
+}
+
+// eventHeaders describes an event to receivers that route messages before decoding them
+func eventHeaders(event *domain.TenantEvent) map[string]string {
+	return map[string]string{
+		EventIDHeader:       event.ID.Hex(),
+		EventTypeHeader:     event.Type,
+		EventSequenceHeader: strconv.FormatInt(event.Sequence, 10),
+	}
+}
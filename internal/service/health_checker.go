@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// DefaultHealthCheckRefreshInterval is how often the checker reloads active service configs
+const DefaultHealthCheckRefreshInterval = time.Minute
+
+// HealthProber probes a single endpoint and returns an error if it is unhealthy
+type HealthProber interface {
+	Probe(ctx context.Context, endpoint *domain.ServiceEndpoint, config *domain.ServiceConfig) error
+}
+
+// HealthChecker actively probes the endpoints of every active service configuration
+// and feeds the results into the ServiceRegistry
+type HealthChecker struct {
+	repo            *repository.ServiceConfigRepository
+	registry        *ServiceRegistry
+	probers         map[string]HealthProber // key: health check protocol
+	refreshInterval time.Duration
+	logger          *logger.Logger
+
+	targets map[string]*probeTarget // key: tenantID:serviceName:url
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+}
+
+// probeTarget is a single scheduled endpoint probe
+type probeTarget struct {
+	config   *domain.ServiceConfig
+	endpoint domain.ServiceEndpoint
+	cancel   context.CancelFunc
+}
+
+// NewHealthChecker creates a new health checker with HTTP and gRPC probers
+func NewHealthChecker(repo *repository.ServiceConfigRepository, registry *ServiceRegistry, refreshInterval time.Duration, log *logger.Logger) *HealthChecker {
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultHealthCheckRefreshInterval
+	}
+
+	return &HealthChecker{
+		repo:     repo,
+		registry: registry,
+		probers: map[string]HealthProber{
+			domain.HealthCheckProtocolHTTP: NewHTTPProber(),
+			domain.HealthCheckProtocolGRPC: NewGRPCProber(),
+		},
+		refreshInterval: refreshInterval,
+		logger:          log,
+		targets:         make(map[string]*probeTarget),
+	}
+}
+
+// RegisterProber registers (or replaces) the prober used for a health check protocol
+func (h *HealthChecker) RegisterProber(protocol string, prober HealthProber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.probers[protocol] = prober
+}
+
+// Start launches the supervisor loop. It returns immediately; call Stop to shut down.
+func (h *HealthChecker) Start(ctx context.Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cancel != nil {
+		return
+	}
+
+	h.ctx, h.cancel = context.WithCancel(ctx)
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		h.supervise(h.ctx)
+	}()
+
+	h.logger.Info("Health checker started", zap.Duration("refresh_interval", h.refreshInterval))
+}
+
+// Stop cancels all probes and waits for them to exit
+func (h *HealthChecker) Stop() {
+	h.mu.Lock()
+	cancel := h.cancel
+	h.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	h.wg.Wait()
+
+	h.logger.Info("Health checker stopped")
+}
+
+// Refresh reloads active service configurations and reconciles the scheduled probes.
+// It can be called after a configuration change to pick it up before the next refresh tick.
+func (h *HealthChecker) Refresh(ctx context.Context) error {
+	h.mu.Lock()
+	baseCtx := h.ctx
+	h.mu.Unlock()
+
+	if baseCtx == nil || baseCtx.Err() != nil {
+		return fmt.Errorf("health checker is not running")
+	}
+
+	configs, err := h.repo.GetActiveServices(ctx)
+	if err != nil {
+		return err
+	}
+
+	desired := make(map[string]*probeTarget)
+	for _, config := range configs {
+		if !config.IsHealthCheckEnabled() {
+			continue
+		}
+		for _, endpoint := range config.GetActiveEndpoints() {
+			key := fmt.Sprintf("%s:%s:%s", config.TenantID, config.ServiceName, endpoint.URL)
+			desired[key] = &probeTarget{config: config, endpoint: *endpoint}
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Stop probes that are no longer wanted or whose configuration changed
+	for key, running := range h.targets {
+		want, ok := desired[key]
+		if ok && !running.config.UpdatedAt.Before(want.config.UpdatedAt) {
+			delete(desired, key)
+			continue
+		}
+		running.cancel()
+		delete(h.targets, key)
+	}
+
+	// Start probes for new or changed targets
+	for key, target := range desired {
+		probeCtx, cancel := context.WithCancel(baseCtx)
+		target.cancel = cancel
+		h.targets[key] = target
+
+		h.wg.Add(1)
+		go func(target *probeTarget) {
+			defer h.wg.Done()
+			h.runProbe(probeCtx, target)
+		}(target)
+	}
+
+	return nil
+}
+
+// supervise periodically reconciles probe targets until the context is cancelled
+func (h *HealthChecker) supervise(ctx context.Context) {
+	ticker := time.NewTicker(h.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := h.Refresh(ctx); err != nil {
+			h.logger.Error("Failed to refresh health check targets", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			h.mu.Lock()
+			h.targets = make(map[string]*probeTarget)
+			h.mu.Unlock()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runProbe probes a single target at its configured interval until cancelled
+func (h *HealthChecker) runProbe(ctx context.Context, target *probeTarget) {
+	ticker := time.NewTicker(time.Duration(target.config.GetHealthCheckInterval()) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		h.probeOnce(ctx, target)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probeOnce runs one probe and records the result, recovering from prober panics
+func (h *HealthChecker) probeOnce(ctx context.Context, target *probeTarget) {
+	config := target.config
+	endpoint := &target.endpoint
+
+	timeout := time.Duration(config.GetHealthCheckTimeout()) * time.Second
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := h.safeProbe(probeCtx, endpoint, config)
+	if ctx.Err() != nil {
+		// Shutting down or target replaced; do not record a spurious failure
+		return
+	}
+
+	h.registry.RecordHealthCheck(config.TenantID, config.ServiceName, endpoint.URL, config.GetFailThreshold(), time.Since(start), err)
+}
+
+// safeProbe dispatches to the prober for the config's protocol
+func (h *HealthChecker) safeProbe(ctx context.Context, endpoint *domain.ServiceEndpoint, config *domain.ServiceConfig) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			h.logger.Error("Health prober panicked",
+				zap.String("tenant_id", config.TenantID),
+				zap.String("service", config.ServiceName),
+				zap.String("url", endpoint.URL),
+				zap.Any("panic", r))
+			err = fmt.Errorf("prober panic: %v", r)
+		}
+	}()
+
+	h.mu.Lock()
+	prober, ok := h.probers[config.GetHealthCheckProtocol()]
+	h.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unsupported health check protocol: %s", config.GetHealthCheckProtocol())
+	}
+
+	return prober.Probe(ctx, endpoint, config)
+}
+
+// HTTPProber checks health with an HTTP request, treating 2xx/3xx as healthy
+type HTTPProber struct {
+	client *http.Client
+}
+
+// NewHTTPProber creates a new HTTP prober
+func NewHTTPProber() *HTTPProber {
+	return &HTTPProber{
+		client: &http.Client{
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Probe sends the configured health check request to the endpoint
+func (p *HTTPProber) Probe(ctx context.Context, endpoint *domain.ServiceEndpoint, config *domain.ServiceConfig) error {
+	target := strings.TrimRight(endpoint.URL, "/") + config.GetHealthCheckPath()
+
+	req, err := http.NewRequestWithContext(ctx, config.GetHealthCheckMethod(), target, nil)
+	if err != nil {
+		return fmt.Errorf("invalid health check request: %w", err)
+	}
+	for name, value := range endpoint.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("health check returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// GRPCProber checks health using the standard gRPC health checking protocol
+type GRPCProber struct{}
+
+// NewGRPCProber creates a new gRPC prober
+func NewGRPCProber() *GRPCProber {
+	return &GRPCProber{}
+}
+
+// Probe calls grpc.health.v1.Health/Check on the endpoint. HealthCheck.Path is used
+// as the service name, so an empty path checks the overall server status.
+func (p *GRPCProber) Probe(ctx context.Context, endpoint *domain.ServiceEndpoint, config *domain.ServiceConfig) error {
+	target := endpoint.URL
+	if u, err := url.Parse(endpoint.URL); err == nil && u.Host != "" {
+		target = u.Host
+	}
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: config.GetHealthCheckPath(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("health check returned status %s", resp.Status)
+	}
+	return nil
+}
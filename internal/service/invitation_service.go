@@ -0,0 +1,540 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vhvplatform/go-shared/errors"
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// InvitationConfig controls invitation tokens and resend throttling
+type InvitationConfig struct {
+	SigningKey     []byte        // HMAC key for invitation tokens; a random key is used when empty
+	TTL            time.Duration // How long an invitation token stays valid
+	ResendInterval time.Duration // Minimum time between two sends of an invitation
+	MaxSends       int           // Maximum number of sends, including the first one
+}
+
+// DefaultInvitationConfig returns the default invitation settings
+func DefaultInvitationConfig() InvitationConfig {
+	return InvitationConfig{
+		TTL:            7 * 24 * time.Hour,
+		ResendInterval: 5 * time.Minute,
+		MaxSends:       5,
+	}
+}
+
+// InvitationSender delivers an invitation token to its recipient, e.g. by email or SMS
+type InvitationSender interface {
+	SendInvitation(ctx context.Context, invitation *domain.TenantInvitation, token string) error
+}
+
+// LogInvitationSender records invitations in the log without delivering them. Callers
+// receive the token in the API response and deliver it themselves; with any other sender
+// the token is only given to the recipient.
+type LogInvitationSender struct {
+	logger *logger.Logger
+}
+
+// NewLogInvitationSender creates a sender that only logs invitations
+func NewLogInvitationSender(log *logger.Logger) *LogInvitationSender {
+	return &LogInvitationSender{logger: log}
+}
+
+// SendInvitation logs the invitation; the token is never logged
+func (s *LogInvitationSender) SendInvitation(ctx context.Context, invitation *domain.TenantInvitation, token string) error {
+	s.logger.Info("Invitation issued",
+		zap.String("tenant_id", invitation.TenantID),
+		zap.String("invitation_id", invitation.ID.Hex()),
+		zap.Int("send_count", invitation.SendCount),
+	)
+	return nil
+}
+
+// InvitationService manages invitations to join a tenant
+type InvitationService struct {
+	invitationRepo     *repository.TenantInvitationRepository
+	tenantRepo         *repository.TenantRepository
+	tenantUserRepo     *repository.TenantUserRepository
+	roleService        *RoleService
+	entitlementService *EntitlementService
+	sender             InvitationSender
+	outbox             *EventOutbox
+	audit              *AuditService
+	config             InvitationConfig
+	logger             *logger.Logger
+}
+
+// NewInvitationService creates a new invitation service
+func NewInvitationService(
+	invitationRepo *repository.TenantInvitationRepository,
+	tenantRepo *repository.TenantRepository,
+	tenantUserRepo *repository.TenantUserRepository,
+	roleService *RoleService,
+	entitlementService *EntitlementService,
+	sender InvitationSender,
+	outbox *EventOutbox,
+	config InvitationConfig,
+	log *logger.Logger,
+) *InvitationService {
+	defaults := DefaultInvitationConfig()
+	if config.TTL <= 0 {
+		config.TTL = defaults.TTL
+	}
+	if config.ResendInterval < 0 {
+		config.ResendInterval = defaults.ResendInterval
+	}
+	if config.MaxSends <= 0 {
+		config.MaxSends = defaults.MaxSends
+	}
+	if len(config.SigningKey) == 0 {
+		config.SigningKey = make([]byte, 32)
+		if _, err := rand.Read(config.SigningKey); err != nil {
+			panic(fmt.Sprintf("failed to generate invitation signing key: %v", err))
+		}
+		log.Warn("No invitation signing key configured; invitation tokens will not survive a restart")
+	}
+
+	return &InvitationService{
+		invitationRepo:     invitationRepo,
+		tenantRepo:         tenantRepo,
+		tenantUserRepo:     tenantUserRepo,
+		roleService:        roleService,
+		entitlementService: entitlementService,
+		sender:             sender,
+		outbox:             outbox,
+		config:             config,
+		logger:             log,
+	}
+}
+
+// SetAuditService enables recording members who joined by invitation in the audit log
+func (s *InvitationService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
+// CreateInvitation invites a recipient to a tenant and returns the invitation. The token is a
+// bearer credential: any signed-in user presenting it joins with the invited role, as the
+// accepting user cannot be matched to the invited email or phone. It is therefore only
+// returned when the sender does not deliver it (see exposedToken), and empty otherwise.
+func (s *InvitationService) CreateInvitation(ctx context.Context, tenantID string, req *domain.CreateInvitationRequest) (*domain.TenantInvitation, string, error) {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, "", errors.BadRequest(err.Error())
+	}
+
+	tenant, err := s.tenantRepo.FindByID(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to find tenant", zap.Error(err))
+		return nil, "", errors.Internal("Failed to create invitation")
+	}
+	if tenant == nil {
+		return nil, "", errors.NotFound("Tenant not found")
+	}
+	if tenant.CurrentStatus() != domain.TenantStatusActive {
+		return nil, "", errors.Conflict("Only active tenants can invite members")
+	}
+	if err := s.entitlementService.CheckMemberLimit(ctx, tenant); err != nil {
+		return nil, "", err
+	}
+
+	role := req.Role
+	if role == "" {
+		role = domain.RoleMember
+	}
+	if err := s.roleService.ValidateRole(ctx, tenantID, role); err != nil {
+		return nil, "", err
+	}
+
+	existing, err := s.invitationRepo.FindPendingByRecipient(ctx, tenantID, req.Email, req.Phone)
+	if err != nil {
+		s.logger.Error("Failed to check existing invitation", zap.Error(err))
+		return nil, "", errors.Internal("Failed to create invitation")
+	}
+	if existing != nil {
+		return nil, "", errors.Conflict("A pending invitation already exists for this recipient; resend it instead")
+	}
+
+	now := time.Now()
+	invitation := &domain.TenantInvitation{
+		ID:         primitive.NewObjectID(),
+		TenantID:   tenantID,
+		Email:      req.Email,
+		Phone:      req.Phone,
+		Role:       role,
+		Status:     domain.InvitationStatusPending,
+		InvitedBy:  req.InvitedBy,
+		SendCount:  1,
+		LastSentAt: now,
+		ExpiresAt:  now.Add(s.config.TTL),
+	}
+	token, err := s.issueToken(invitation)
+	if err != nil {
+		s.logger.Error("Failed to generate invitation token", zap.Error(err))
+		return nil, "", errors.Internal("Failed to create invitation")
+	}
+
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		s.logger.Error("Failed to create invitation", zap.Error(err))
+		return nil, "", errors.Internal("Failed to create invitation")
+	}
+
+	s.send(ctx, invitation, token)
+
+	s.logger.Info("Invitation created successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("invitation_id", invitation.ID.Hex()),
+		zap.String("role", role),
+	)
+
+	return invitation, s.exposedToken(token), nil
+}
+
+// ListInvitations lists the invitations of a tenant, optionally filtered by status
+func (s *InvitationService) ListInvitations(ctx context.Context, tenantID, status string) ([]*domain.TenantInvitation, error) {
+	invitations, err := s.invitationRepo.ListByTenant(ctx, tenantID, status)
+	if err != nil {
+		s.logger.Error("Failed to list invitations", zap.String("tenant_id", tenantID), zap.Error(err))
+		return nil, errors.Internal("Failed to list invitations")
+	}
+	return invitations, nil
+}
+
+// ResendInvitation issues a new token for a pending invitation, invalidating the previous one.
+// Resends are throttled by the resend interval and the maximum number of sends. The token
+// is returned like by CreateInvitation.
+func (s *InvitationService) ResendInvitation(ctx context.Context, tenantID, invitationID string) (*domain.TenantInvitation, string, error) {
+	invitation, err := s.getTenantInvitation(ctx, tenantID, invitationID)
+	if err != nil {
+		return nil, "", err
+	}
+	if invitation.Status != domain.InvitationStatusPending {
+		return nil, "", errors.Conflict("Invitation is no longer pending")
+	}
+
+	now := time.Now()
+	if invitation.SendCount >= s.config.MaxSends {
+		return nil, "", errors.Conflict("Invitation has been sent the maximum number of times")
+	}
+	if wait := invitation.LastSentAt.Add(s.config.ResendInterval).Sub(now); wait > 0 {
+		return nil, "", errors.Conflict(fmt.Sprintf("Invitation was sent recently; retry in %s", wait.Round(time.Second)))
+	}
+
+	invitation.SendCount++
+	invitation.LastSentAt = now
+	invitation.ExpiresAt = now.Add(s.config.TTL)
+	token, err := s.issueToken(invitation)
+	if err != nil {
+		s.logger.Error("Failed to generate invitation token", zap.Error(err))
+		return nil, "", errors.Internal("Failed to resend invitation")
+	}
+
+	reissued, err := s.invitationRepo.Reissue(ctx, invitation)
+	if err != nil {
+		s.logger.Error("Failed to reissue invitation", zap.Error(err))
+		return nil, "", errors.Internal("Failed to resend invitation")
+	}
+	if !reissued {
+		return nil, "", errors.Conflict("Invitation is no longer pending")
+	}
+
+	s.send(ctx, invitation, token)
+
+	s.logger.Info("Invitation resent successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("invitation_id", invitationID),
+		zap.Int("send_count", invitation.SendCount),
+	)
+
+	return invitation, s.exposedToken(token), nil
+}
+
+// RevokeInvitation revokes a pending invitation so its token can no longer be used
+func (s *InvitationService) RevokeInvitation(ctx context.Context, tenantID, invitationID string) error {
+	invitation, err := s.getTenantInvitation(ctx, tenantID, invitationID)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := s.invitationRepo.Revoke(ctx, invitation.ID)
+	if err != nil {
+		s.logger.Error("Failed to revoke invitation", zap.Error(err))
+		return errors.Internal("Failed to revoke invitation")
+	}
+	if !revoked {
+		return errors.Conflict("Invitation is no longer pending")
+	}
+
+	s.logger.Info("Invitation revoked successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("invitation_id", invitationID),
+	)
+
+	return nil
+}
+
+// AcceptInvitation redeems an invitation token and adds the calling user to the tenant with
+// the invited role. The token alone authorizes joining, so it must only reach the recipient.
+func (s *InvitationService) AcceptInvitation(ctx context.Context, token string) (*domain.TenantInvitation, error) {
+	actor := domain.ActorFromContext(ctx)
+	if actor.Type != domain.ActorTypeUser {
+		return nil, errors.BadRequest("Invitations can only be accepted by a signed-in user")
+	}
+	userID := actor.ID
+
+	invitation, nonceHash, err := s.redeemableInvitation(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.tenantUserRepo.FindByTenantAndUser(ctx, invitation.TenantID, userID)
+	if err != nil {
+		s.logger.Error("Failed to check existing relationship", zap.Error(err))
+		return nil, errors.Internal("Failed to accept invitation")
+	}
+	if existing != nil {
+		return nil, errors.Conflict("User already belongs to this tenant")
+	}
+
+	tenant, err := s.tenantRepo.FindByID(ctx, invitation.TenantID)
+	if err != nil {
+		s.logger.Error("Failed to find tenant", zap.Error(err))
+		return nil, errors.Internal("Failed to accept invitation")
+	}
+	if tenant == nil {
+		return nil, errors.NotFound("Tenant not found")
+	}
+	if tenant.CurrentStatus() != domain.TenantStatusActive {
+		return nil, errors.Conflict("Only active tenants can accept members")
+	}
+	if err := s.entitlementService.CheckMemberLimit(ctx, tenant); err != nil {
+		return nil, err
+	}
+
+	// Claim the token first so it can only be redeemed once
+	accepted, err := s.invitationRepo.Respond(ctx, invitation.ID, nonceHash, domain.InvitationStatusAccepted, userID)
+	if err != nil {
+		s.logger.Error("Failed to accept invitation", zap.Error(err))
+		return nil, errors.Internal("Failed to accept invitation")
+	}
+	if !accepted {
+		return nil, errors.Conflict("Invitation has already been used")
+	}
+
+	tenantUser := &domain.TenantUser{
+		TenantID: invitation.TenantID,
+		UserID:   userID,
+		Role:     invitation.Role,
+	}
+	err = s.outbox.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.tenantUserRepo.AddUser(ctx, tenantUser); err != nil {
+			return err
+		}
+		if invitation.Role == domain.RoleOwner {
+			if err := s.tenantRepo.AddOwners(ctx, invitation.TenantID, 1); err != nil {
+				return err
+			}
+		}
+		return s.outbox.Add(ctx, domain.NewTenantEvent(domain.EventMemberAdded, invitation.TenantID,
+			domain.MemberPayload{UserID: userID, Role: invitation.Role}))
+	})
+	if err != nil {
+		if reopenErr := s.invitationRepo.Reopen(ctx, invitation.ID); reopenErr != nil {
+			s.logger.Error("Failed to reopen invitation", zap.Error(reopenErr))
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errors.Conflict("User already belongs to this tenant")
+		}
+		s.logger.Error("Failed to add invited user to tenant", zap.Error(err))
+		return nil, errors.Internal("Failed to accept invitation")
+	}
+
+	invitation.Status = domain.InvitationStatusAccepted
+	invitation.AcceptedBy = userID
+	invitation.RespondedAt = time.Now()
+
+	if s.audit != nil {
+		event := domain.NewAuditEvent(domain.AuditActionMemberAdded, domain.AuditTargetMember,
+			userID, invitation.TenantID, nil, domain.NewAuditSnapshot(tenantUser))
+		event.Reason = "Invitation accepted"
+		s.audit.Record(ctx, event)
+	}
+
+	s.logger.Info("Invitation accepted successfully",
+		zap.String("tenant_id", invitation.TenantID),
+		zap.String("invitation_id", invitation.ID.Hex()),
+		zap.String("user_id", userID),
+	)
+
+	return invitation, nil
+}
+
+// DeclineInvitation declines an invitation by its token
+func (s *InvitationService) DeclineInvitation(ctx context.Context, token string) (*domain.TenantInvitation, error) {
+	invitation, nonceHash, err := s.redeemableInvitation(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	declined, err := s.invitationRepo.Respond(ctx, invitation.ID, nonceHash, domain.InvitationStatusDeclined, "")
+	if err != nil {
+		s.logger.Error("Failed to decline invitation", zap.Error(err))
+		return nil, errors.Internal("Failed to decline invitation")
+	}
+	if !declined {
+		return nil, errors.Conflict("Invitation has already been used")
+	}
+
+	invitation.Status = domain.InvitationStatusDeclined
+	invitation.RespondedAt = time.Now()
+
+	s.logger.Info("Invitation declined",
+		zap.String("tenant_id", invitation.TenantID),
+		zap.String("invitation_id", invitation.ID.Hex()),
+	)
+
+	return invitation, nil
+}
+
+// redeemableInvitation verifies a token and returns its pending invitation and nonce hash
+func (s *InvitationService) redeemableInvitation(ctx context.Context, token string) (*domain.TenantInvitation, string, error) {
+	invitationID, nonce, err := s.parseToken(token)
+	if err != nil {
+		return nil, "", errors.BadRequest("Invalid invitation token")
+	}
+
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
+	if err != nil {
+		s.logger.Error("Failed to find invitation", zap.Error(err))
+		return nil, "", errors.Internal("Failed to find invitation")
+	}
+
+	nonceHash := hashNonce(nonce)
+	if invitation == nil || !hmac.Equal([]byte(invitation.NonceHash), []byte(nonceHash)) {
+		return nil, "", errors.BadRequest("Invalid invitation token")
+	}
+
+	switch invitation.CurrentStatus(time.Now()) {
+	case domain.InvitationStatusPending:
+		return invitation, nonceHash, nil
+	case domain.InvitationStatusExpired:
+		return nil, "", errors.BadRequest("Invitation has expired")
+	case domain.InvitationStatusRevoked:
+		return nil, "", errors.BadRequest("Invitation has been revoked")
+	default:
+		return nil, "", errors.Conflict("Invitation has already been used")
+	}
+}
+
+// getTenantInvitation loads an invitation and checks it belongs to the tenant
+func (s *InvitationService) getTenantInvitation(ctx context.Context, tenantID, invitationID string) (*domain.TenantInvitation, error) {
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
+	if err != nil {
+		s.logger.Error("Failed to find invitation", zap.String("invitation_id", invitationID), zap.Error(err))
+		return nil, errors.Internal("Failed to get invitation")
+	}
+	if invitation == nil || invitation.TenantID != tenantID {
+		return nil, errors.NotFound("Invitation not found")
+	}
+	return invitation, nil
+}
+
+// send delivers an invitation; delivery failures are logged and the invitation can be resent
+func (s *InvitationService) send(ctx context.Context, invitation *domain.TenantInvitation, token string) {
+	if err := s.sender.SendInvitation(ctx, invitation, token); err != nil {
+		s.logger.Error("Failed to send invitation",
+			zap.String("invitation_id", invitation.ID.Hex()),
+			zap.Error(err))
+	}
+}
+
+// exposedToken returns the token to give the inviter: only a LogInvitationSender leaves the
+// delivery to the caller. Otherwise the token only reaches the recipient, since whoever holds
+// it can join the tenant.
+func (s *InvitationService) exposedToken(token string) string {
+	if _, logOnly := s.sender.(*LogInvitationSender); logOnly {
+		return token
+	}
+	return ""
+}
+
+// issueToken rotates the nonce of an invitation and returns a token signed for it.
+// Tokens have the form base64(id.nonce.expiry).base64(hmac).
+func (s *InvitationService) issueToken(invitation *domain.TenantInvitation) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	nonce := hex.EncodeToString(buf)
+	invitation.NonceHash = hashNonce(nonce)
+
+	payload := strings.Join([]string{
+		invitation.ID.Hex(),
+		nonce,
+		strconv.FormatInt(invitation.ExpiresAt.Unix(), 10),
+	}, ".")
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(s.sign(payload)), nil
+}
+
+// parseToken verifies the signature and expiry of a token and returns its invitation ID and nonce
+func (s *InvitationService) parseToken(token string) (string, string, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", "", fmt.Errorf("malformed token")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return "", "", fmt.Errorf("malformed token payload: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return "", "", fmt.Errorf("malformed token signature: %w", err)
+	}
+	if !hmac.Equal(signature, s.sign(string(payload))) {
+		return "", "", fmt.Errorf("invalid token signature")
+	}
+
+	parts := strings.Split(string(payload), ".")
+	if len(parts) != 3 {
+		return "", "", fmt.Errorf("malformed token payload")
+	}
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return "", "", fmt.Errorf("malformed token expiry: %w", err)
+	}
+	if time.Now().Unix() > expiresAt {
+		return "", "", fmt.Errorf("token expired")
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// sign computes the HMAC of a token payload
+func (s *InvitationService) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, s.config.SigningKey)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// hashNonce returns the stored form of a token nonce
+func hashNonce(nonce string) string {
+	sum := sha256.Sum256([]byte(nonce))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestInvitationToken(t *testing.T) {
+	issuer := &InvitationService{config: InvitationConfig{SigningKey: []byte("invitation-key")}}
+
+	// tamper changes the payload of a token while keeping its signature
+	tamper := func(token string) string {
+		payload, signature, _ := strings.Cut(token, ".")
+		data, _ := base64.RawURLEncoding.DecodeString(payload)
+		data[0] ^= 1
+		return base64.RawURLEncoding.EncodeToString(data) + "." + signature
+	}
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		verifier  *InvitationService
+		token     func(token string) string
+		wantErr   string
+	}{
+		{name: "valid", expiresAt: time.Now().Add(time.Hour)},
+		{name: "expired", expiresAt: time.Now().Add(-time.Minute), wantErr: "token expired"},
+		{
+			name:      "other key",
+			expiresAt: time.Now().Add(time.Hour),
+			verifier:  &InvitationService{config: InvitationConfig{SigningKey: []byte("other-key")}},
+			wantErr:   "invalid token signature",
+		},
+		{name: "tampered payload", expiresAt: time.Now().Add(time.Hour), token: tamper, wantErr: "invalid token signature"},
+		{
+			name:      "missing signature",
+			expiresAt: time.Now().Add(time.Hour),
+			token:     func(token string) string { payload, _, _ := strings.Cut(token, "."); return payload },
+			wantErr:   "malformed token",
+		},
+		{
+			name:      "invalid encoding",
+			expiresAt: time.Now().Add(time.Hour),
+			token:     func(token string) string { return "!" + token },
+			wantErr:   "malformed token payload",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invitation := &domain.TenantInvitation{ID: primitive.NewObjectID(), ExpiresAt: tt.expiresAt}
+			token, err := issuer.issueToken(invitation)
+			if err != nil {
+				t.Fatalf("issueToken: %v", err)
+			}
+			if tt.token != nil {
+				token = tt.token(token)
+			}
+			verifier := issuer
+			if tt.verifier != nil {
+				verifier = tt.verifier
+			}
+
+			id, nonce, err := verifier.parseToken(token)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("parseToken() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseToken: %v", err)
+			}
+			if id != invitation.ID.Hex() {
+				t.Errorf("id = %s, want %s", id, invitation.ID.Hex())
+			}
+			if hashNonce(nonce) != invitation.NonceHash {
+				t.Error("nonce does not match the stored nonce hash")
+			}
+		})
+	}
+}
+
+func TestInvitationTokenRotation(t *testing.T) {
+	service := &InvitationService{config: InvitationConfig{SigningKey: []byte("invitation-key")}}
+	invitation := &domain.TenantInvitation{ID: primitive.NewObjectID(), ExpiresAt: time.Now().Add(time.Hour)}
+
+	first, err := service.issueToken(invitation)
+	if err != nil {
+		t.Fatalf("issueToken: %v", err)
+	}
+	if _, err := service.issueToken(invitation); err != nil {
+		t.Fatalf("issueToken: %v", err)
+	}
+
+	// The first token still carries a valid signature, but its nonce no longer matches
+	_, nonce, err := service.parseToken(first)
+	if err != nil {
+		t.Fatalf("parseToken: %v", err)
+	}
+	if hashNonce(nonce) == invitation.NonceHash {
+		t.Error("reissuing did not rotate the nonce")
+	}
+}
+
+// deliveringSender stands in for a sender that delivers invitations to their recipients
+type deliveringSender struct{}
+
+func (deliveringSender) SendInvitation(ctx context.Context, invitation *domain.TenantInvitation, token string) error {
+	return nil
+}
+
+func TestInvitationExposedToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		sender InvitationSender
+		want   string
+	}{
+		{name: "log sender leaves delivery to the caller", sender: NewLogInvitationSender(nil), want: "token"},
+		{name: "delivering sender keeps the token from the caller", sender: deliveringSender{}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &InvitationService{sender: tt.sender}
+			if got := s.exposedToken("token"); got != tt.want {
+				t.Errorf("exposedToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
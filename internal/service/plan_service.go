@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vhvplatform/go-shared/errors"
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+// PlanConfig controls how scheduled plan changes are applied
+type PlanConfig struct {
+	Interval  time.Duration // How often due plan changes are applied
+	BatchSize int           // Plan changes applied per run
+}
+
+// DefaultPlanConfig returns the default plan change settings
+func DefaultPlanConfig() PlanConfig {
+	return PlanConfig{
+		Interval:  5 * time.Minute,
+		BatchSize: 100,
+	}
+}
+
+// PlanService changes the subscription tier of tenants, immediately or at a scheduled time,
+// after checking that the tenant fits within the limits of the target tier
+type PlanService struct {
+	tenantService     *TenantService
+	tenantRepo        *repository.TenantRepository
+	tenantUserRepo    *repository.TenantUserRepository
+	serviceConfigRepo *repository.ServiceConfigRepository
+	domainRepo        *repository.TenantDomainRepository
+	config            PlanConfig
+	logger            *logger.Logger
+
+	job backgroundJob
+}
+
+// NewPlanService creates a new plan service
+func NewPlanService(
+	tenantService *TenantService,
+	tenantRepo *repository.TenantRepository,
+	tenantUserRepo *repository.TenantUserRepository,
+	serviceConfigRepo *repository.ServiceConfigRepository,
+	domainRepo *repository.TenantDomainRepository,
+	config PlanConfig,
+	log *logger.Logger,
+) *PlanService {
+	defaults := DefaultPlanConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+
+	return &PlanService{
+		tenantService:     tenantService,
+		tenantRepo:        tenantRepo,
+		tenantUserRepo:    tenantUserRepo,
+		serviceConfigRepo: serviceConfigRepo,
+		domainRepo:        domainRepo,
+		config:            config,
+		logger:            log,
+	}
+}
+
+// GetPlan returns the tier of a tenant with its scheduled change and plan history
+func (s *PlanService) GetPlan(ctx context.Context, tenantID string) (*domain.PlanResponse, error) {
+	tenant, err := s.tenantService.GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return toPlanResponse(tenant), nil
+}
+
+// ChangePlan moves a tenant to another subscription tier. Changes effective now are applied
+// immediately and rejected if the tenant does not fit within the target tier; later changes
+// are scheduled and checked again when they become effective. A running trial is converted
+// once the change is applied and keeps its expiry until then.
+func (s *PlanService) ChangePlan(ctx context.Context, tenantID string, req *domain.ChangePlanRequest) (*domain.PlanResponse, error) {
+	if !domain.IsValidSubscriptionTier(req.Tier) {
+		return nil, errors.BadRequest("Unknown subscription tier")
+	}
+
+	tenant, err := s.tenantService.GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	from := tierName(tenant)
+	if req.Tier == from {
+		return nil, errors.BadRequest(fmt.Sprintf("Tenant is already on the %s tier", from))
+	}
+	if tenant.ScheduledPlanChange != nil {
+		return nil, errors.Conflict("Tenant already has a scheduled plan change; cancel it first")
+	}
+
+	now := time.Now()
+	change := domain.PlanChange{
+		From:        from,
+		To:          req.Tier,
+		Reason:      req.Reason,
+		Actor:       domain.ActorFromContext(ctx).ID,
+		RequestedAt: now,
+		EffectiveAt: now,
+	}
+	if req.EffectiveAt != nil && req.EffectiveAt.After(now) {
+		change.EffectiveAt = *req.EffectiveAt
+	}
+
+	if change.EffectiveAt.After(now) {
+		ok, err := s.tenantRepo.SchedulePlanChange(ctx, tenantID, change)
+		if err != nil {
+			s.logger.Error("Failed to schedule plan change", zap.String("tenant_id", tenantID), zap.Error(err))
+			return nil, errors.Internal("Failed to change plan")
+		}
+		if !ok {
+			return nil, errors.Conflict("Tenant plan was changed concurrently; retry the request")
+		}
+
+		after := *tenant
+		after.ScheduledPlanChange = &change
+		s.tenantService.auditService.Record(ctx, planAuditEvent(domain.AuditActionTenantPlanScheduled, tenant, &after, change.Reason))
+
+		s.logger.Info("Plan change scheduled successfully",
+			zap.String("tenant_id", tenantID),
+			zap.String("to", change.To),
+			zap.Time("effective_at", change.EffectiveAt),
+		)
+		return s.GetPlan(ctx, tenantID)
+	}
+
+	violations, err := s.checkFits(ctx, tenant, change.To)
+	if err != nil {
+		return nil, err
+	}
+	if len(violations) > 0 {
+		return nil, errors.Conflict(fmt.Sprintf("Tenant does not fit the %s tier: %s", change.To, strings.Join(violations, "; ")))
+	}
+
+	if err := s.applyChange(ctx, tenant, change, false); err != nil {
+		return nil, err
+	}
+	s.convertTrial(ctx, tenant)
+	return s.GetPlan(ctx, tenantID)
+}
+
+// convertTrial ends the running trial of a tenant that chose a plan, so it is not expired later
+func (s *PlanService) convertTrial(ctx context.Context, tenant *domain.Tenant) {
+	if tenant.Trial == nil || !tenant.Trial.IsRunning() {
+		return
+	}
+
+	now := time.Now()
+	ended, err := s.tenantRepo.EndTrial(ctx, tenant.ID.Hex(), domain.TrialStatusConverted, now, nil, nil)
+	if err != nil {
+		s.logger.Error("Failed to convert trial", zap.String("tenant_id", tenant.ID.Hex()), zap.Error(err))
+		return
+	}
+	if !ended {
+		return
+	}
+	s.tenantService.auditService.Record(ctx, trialEndedAuditEvent(tenant, domain.TrialStatusConverted, now, nil))
+
+	s.logger.Info("Trial converted successfully",
+		zap.String("tenant_id", tenant.ID.Hex()),
+		zap.String("tier", tenant.Trial.Tier),
+	)
+}
+
+// CancelScheduledChange cancels the scheduled plan change of a tenant
+func (s *PlanService) CancelScheduledChange(ctx context.Context, tenantID string, req *domain.CancelPlanChangeRequest) (*domain.PlanResponse, error) {
+	tenant, err := s.tenantService.GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.ScheduledPlanChange == nil {
+		return nil, errors.NotFound("Tenant has no scheduled plan change")
+	}
+
+	change := *tenant.ScheduledPlanChange
+	change.Outcome = domain.PlanChangeCancelled
+	change.CompletedAt = time.Now()
+	if req.Reason != "" {
+		change.Reason = req.Reason
+	}
+	change.Actor = domain.ActorFromContext(ctx).ID
+
+	ok, err := s.tenantRepo.CloseScheduledPlanChange(ctx, tenantID, change)
+	if err != nil {
+		s.logger.Error("Failed to cancel plan change", zap.String("tenant_id", tenantID), zap.Error(err))
+		return nil, errors.Internal("Failed to cancel plan change")
+	}
+	if !ok {
+		return nil, errors.Conflict("Scheduled plan change was already applied or cancelled")
+	}
+
+	after := *tenant
+	after.ScheduledPlanChange = nil
+	s.tenantService.auditService.Record(ctx, planAuditEvent(domain.AuditActionTenantPlanCancelled, tenant, &after, change.Reason))
+
+	s.logger.Info("Plan change cancelled successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("to", change.To),
+	)
+
+	return s.GetPlan(ctx, tenantID)
+}
+
+// Start runs the scheduled plan change job in the background until Stop is called
+func (s *PlanService) Start(ctx context.Context) {
+	if !s.job.start(ctx, s.config.Interval, s.RunScheduledChanges) {
+		return
+	}
+
+	s.logger.Info("Scheduled plan changes started", zap.Duration("interval", s.config.Interval))
+}
+
+// Stop stops the background plan change job and waits for it to exit
+func (s *PlanService) Stop() {
+	if !s.job.stop() {
+		return
+	}
+
+	s.logger.Info("Scheduled plan changes stopped")
+}
+
+// RunScheduledChanges applies a batch of due plan changes. Changes the tenant no longer fits
+// are recorded as failed with the reasons and dropped.
+func (s *PlanService) RunScheduledChanges(ctx context.Context) {
+	tenants, err := s.tenantRepo.FindDuePlanChanges(ctx, time.Now(), s.config.BatchSize)
+	if err != nil {
+		s.logger.Error("Failed to load tenants with due plan changes", zap.Error(err))
+		return
+	}
+
+	for _, tenant := range tenants {
+		if ctx.Err() != nil {
+			return
+		}
+		tenantID := tenant.ID.Hex()
+		change := *tenant.ScheduledPlanChange
+
+		violations, err := s.checkFits(ctx, tenant, change.To)
+		if err != nil {
+			continue
+		}
+		if len(violations) > 0 {
+			change.Outcome = domain.PlanChangeFailed
+			change.Violations = violations
+			change.CompletedAt = time.Now()
+			closed, err := s.tenantRepo.CloseScheduledPlanChange(ctx, tenantID, change)
+			if err != nil {
+				s.logger.Error("Failed to record failed plan change", zap.String("tenant_id", tenantID), zap.Error(err))
+				continue
+			}
+			if !closed {
+				continue
+			}
+			after := *tenant
+			after.ScheduledPlanChange = nil
+			s.tenantService.auditService.Record(ctx, planAuditEvent(domain.AuditActionTenantPlanCancelled, tenant, &after,
+				fmt.Sprintf("Tenant does not fit the %s tier: %s", change.To, strings.Join(violations, "; "))))
+			s.logger.Warn("Scheduled plan change failed",
+				zap.String("tenant_id", tenantID),
+				zap.String("to", change.To),
+				zap.Strings("violations", violations),
+			)
+			continue
+		}
+
+		// Failures are logged by applyChange; the change stays scheduled and is retried on the next run
+		if err := s.applyChange(ctx, tenant, change, true); err == nil {
+			s.convertTrial(ctx, tenant)
+		}
+	}
+}
+
+// applyChange moves a tenant to the target tier of a change and records it in the plan history
+func (s *PlanService) applyChange(ctx context.Context, tenant *domain.Tenant, change domain.PlanChange, scheduled bool) error {
+	tenantID := tenant.ID.Hex()
+	change.Outcome = domain.PlanChangeApplied
+	change.CompletedAt = time.Now()
+
+	var ok bool
+	err := s.tenantService.outbox.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if ok, err = s.tenantRepo.ApplyPlanChange(ctx, tenantID, change, scheduled); err != nil || !ok {
+			return err
+		}
+		return s.tenantService.outbox.Add(ctx, planChangedEvent(tenantID, change))
+	})
+	if err != nil {
+		s.logger.Error("Failed to apply plan change", zap.String("tenant_id", tenantID), zap.Error(err))
+		return errors.Internal("Failed to change plan")
+	}
+	if !ok {
+		return errors.Conflict("Tenant plan was changed concurrently; retry the request")
+	}
+
+	after := *tenant
+	after.SubscriptionTier = change.To
+	after.ScheduledPlanChange = nil
+	s.tenantService.auditService.Record(ctx, planAuditEvent(domain.AuditActionTenantPlanChanged, tenant, &after, change.Reason))
+
+	s.logger.Info("Plan changed successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("from", change.From),
+		zap.String("to", change.To),
+	)
+	return nil
+}
+
+// planChangedEvent announces an applied plan change of a tenant
+func planChangedEvent(tenantID string, change domain.PlanChange) *domain.TenantEvent {
+	return domain.NewTenantEvent(domain.EventTenantPlanChanged, tenantID,
+		domain.TenantPlanChangedPayload{From: change.From, To: change.To, Reason: change.Reason})
+}
+
+// planAuditEvent builds the audit event of a change to the plan of a tenant from the tenant
+// as loaded and as left by the change
+func planAuditEvent(action string, before, after *domain.Tenant, reason string) *domain.AuditEvent {
+	event := domain.NewAuditEvent(action, domain.AuditTargetTenant, before.ID.Hex(), before.ID.Hex(),
+		domain.NewAuditSnapshot(before), domain.NewAuditSnapshot(after))
+	event.Reason = reason
+	return event
+}
+
+// checkFits lists the ways a tenant exceeds the entitlements it would have on a tier,
+// keeping its entitlement overrides
+func (s *PlanService) checkFits(ctx context.Context, tenant *domain.Tenant, tier string) ([]string, error) {
+	tenantID := tenant.ID.Hex()
+	entitlements := domain.ResolveEntitlements(tier, tenant.EntitlementOverrides)
+	var violations []string
+
+	members, err := s.tenantUserRepo.CountActive(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to count tenant members", zap.String("tenant_id", tenantID), zap.Error(err))
+		return nil, errors.Internal("Failed to check plan limits")
+	}
+	if !domain.FitsCount(entitlements.MaxMembers, members) {
+		violations = append(violations, fmt.Sprintf("%d members, the limit is %d", members, entitlements.MaxMembers))
+	}
+
+	configs, err := s.serviceConfigRepo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to list service configs", zap.String("tenant_id", tenantID), zap.Error(err))
+		return nil, errors.Internal("Failed to check plan limits")
+	}
+	if !domain.FitsCount(entitlements.MaxServiceConfigs, int64(len(configs))) {
+		violations = append(violations, fmt.Sprintf("%d service configs, the limit is %d", len(configs), entitlements.MaxServiceConfigs))
+	}
+	for _, config := range configs {
+		if !entitlements.AllowsLoadBalanceStrategy(config.LoadBalanceStrategy) {
+			violations = append(violations, fmt.Sprintf("service %s uses the unavailable %q load balance strategy", config.ServiceName, config.LoadBalanceStrategy))
+		}
+	}
+
+	if !entitlements.CustomDomains {
+		claims, err := s.domainRepo.ListByTenant(ctx, tenantID)
+		if err != nil {
+			s.logger.Error("Failed to list domain claims", zap.String("tenant_id", tenantID), zap.Error(err))
+			return nil, errors.Internal("Failed to check plan limits")
+		}
+		inUse := 0
+		now := time.Now()
+		for _, claim := range claims {
+			if claim.Status == domain.DomainStatusVerified || (claim.Status == domain.DomainStatusPending && !claim.IsExpired(now)) {
+				inUse++
+			}
+		}
+		if inUse > 0 {
+			violations = append(violations, fmt.Sprintf("%d custom domains, which the tier does not include", inUse))
+		}
+	}
+
+	return violations, nil
+}
+
+// toPlanResponse builds the plan view of a tenant
+func toPlanResponse(tenant *domain.Tenant) *domain.PlanResponse {
+	history := tenant.PlanHistory
+	if history == nil {
+		history = []domain.PlanChange{}
+	}
+	return &domain.PlanResponse{
+		TenantID:        tenant.ID.Hex(),
+		Tier:            tierName(tenant),
+		ScheduledChange: tenant.ScheduledPlanChange,
+		History:         history,
+	}
+}
@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vhvplatform/go-shared/errors"
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+// PurgeConfig controls how deleted tenants are purged
+type PurgeConfig struct {
+	GracePeriod time.Duration // How long a tenant stays pending deletion before its data is purged
+	Interval    time.Duration // How often tenants due for purge are processed
+	BatchSize   int           // Tenants purged per run
+}
+
+// DefaultPurgeConfig returns the default purge settings
+func DefaultPurgeConfig() PurgeConfig {
+	return PurgeConfig{
+		GracePeriod: 30 * 24 * time.Hour,
+		Interval:    time.Hour,
+		BatchSize:   50,
+	}
+}
+
+// TenantDataDeleter deletes the data a collection holds for a tenant and returns the number of deleted documents
+type TenantDataDeleter func(ctx context.Context, tenantID string) (int64, error)
+
+// ownedCollection is tenant-owned data removed when a tenant is purged
+type ownedCollection struct {
+	name   string
+	delete TenantDataDeleter
+}
+
+// PurgeService schedules tenant deletion and purges tenant data once the grace period has passed
+type PurgeService struct {
+	tenantService *TenantService
+	tenantRepo    *repository.TenantRepository
+	reportRepo    *repository.PurgeReportRepository
+	config        PurgeConfig
+	collections   []ownedCollection
+	logger        *logger.Logger
+
+	job backgroundJob
+}
+
+// NewPurgeService creates a new purge service
+func NewPurgeService(
+	tenantService *TenantService,
+	tenantRepo *repository.TenantRepository,
+	reportRepo *repository.PurgeReportRepository,
+	config PurgeConfig,
+	log *logger.Logger,
+) *PurgeService {
+	defaults := DefaultPurgeConfig()
+	if config.GracePeriod < 0 {
+		config.GracePeriod = defaults.GracePeriod
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+
+	return &PurgeService{
+		tenantService: tenantService,
+		tenantRepo:    tenantRepo,
+		reportRepo:    reportRepo,
+		config:        config,
+		logger:        log,
+	}
+}
+
+// RegisterCollection adds tenant-owned data to the purge. Collections are purged in
+// registration order, before the tenant itself is removed.
+func (s *PurgeService) RegisterCollection(name string, deleter TenantDataDeleter) {
+	s.collections = append(s.collections, ownedCollection{name: name, delete: deleter})
+}
+
+// ScheduleDeletion moves a tenant to pending deletion. Its data is purged once the grace period has passed.
+func (s *PurgeService) ScheduleDeletion(ctx context.Context, id string, req *domain.TenantStatusRequest) (*domain.Tenant, error) {
+	tenant, err := s.tenantService.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNoChildren(ctx, id); err != nil {
+		return nil, err
+	}
+
+	purgeAfter := time.Now().Add(s.config.GracePeriod)
+	change := domain.TenantStatusChange{
+		To:         domain.TenantStatusPendingDeletion,
+		Reason:     req.Reason,
+		Actor:      domain.ActorFromContext(ctx).ID,
+		PurgeAfter: &purgeAfter,
+	}
+	if change.Reason == "" {
+		change.Reason = "Deletion requested"
+	}
+
+	tenant, err = s.tenantService.changeStatus(ctx, tenant, change)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Tenant deletion scheduled successfully",
+		zap.String("tenant_id", id),
+		zap.Time("purge_after", purgeAfter),
+	)
+
+	return tenant, nil
+}
+
+// CancelDeletion returns a tenant pending deletion to the status it had before deletion was requested
+func (s *PurgeService) CancelDeletion(ctx context.Context, id string, req *domain.TenantStatusRequest) (*domain.Tenant, error) {
+	tenant, err := s.tenantService.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.CurrentStatus() != domain.TenantStatusPendingDeletion {
+		return nil, errors.Conflict("Tenant is not scheduled for deletion")
+	}
+
+	change := domain.TenantStatusChange{
+		To:     previousStatus(tenant),
+		Reason: req.Reason,
+		Actor:  domain.ActorFromContext(ctx).ID,
+	}
+	if change.Reason == "" {
+		change.Reason = "Deletion cancelled"
+	}
+
+	tenant, err = s.tenantService.changeStatus(ctx, tenant, change)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Tenant deletion cancelled successfully",
+		zap.String("tenant_id", id),
+		zap.String("status", change.To),
+	)
+
+	return tenant, nil
+}
+
+// previousStatus returns the status a tenant had before it was scheduled for deletion
+func previousStatus(tenant *domain.Tenant) string {
+	for i := len(tenant.StatusHistory) - 1; i >= 0; i-- {
+		change := tenant.StatusHistory[i]
+		if change.To == domain.TenantStatusPendingDeletion {
+			if change.From == domain.TenantStatusSuspended {
+				return domain.TenantStatusSuspended
+			}
+			break
+		}
+	}
+	return domain.TenantStatusActive
+}
+
+// PurgeTenant purges a tenant immediately, skipping the grace period, e.g. for data deletion requests
+func (s *PurgeService) PurgeTenant(ctx context.Context, id string, req *domain.TenantStatusRequest) (*domain.PurgeReport, error) {
+	if req.Reason == "" {
+		return nil, errors.BadRequest("Reason is required")
+	}
+
+	tenant, err := s.tenantService.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNoChildren(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.purge(ctx, tenant, req.Reason, domain.ActorFromContext(ctx).ID)
+}
+
+// checkNoChildren ensures a tenant has no sub-tenants left before it is deleted
+func (s *PurgeService) checkNoChildren(ctx context.Context, id string) error {
+	count, err := s.tenantRepo.CountChildren(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to count child tenants", zap.String("tenant_id", id), zap.Error(err))
+		return errors.Internal("Failed to check child tenants")
+	}
+	if count > 0 {
+		return errors.Conflict("Tenant has sub-tenants; move or delete them first")
+	}
+	return nil
+}
+
+// GetPurgeReport returns the latest purge report of a tenant
+func (s *PurgeService) GetPurgeReport(ctx context.Context, tenantID string) (*domain.PurgeReport, error) {
+	report, err := s.reportRepo.FindLatestByTenant(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to get purge report", zap.String("tenant_id", tenantID), zap.Error(err))
+		return nil, errors.Internal("Failed to get purge report")
+	}
+	if report == nil {
+		return nil, errors.NotFound("Purge report not found")
+	}
+	return report, nil
+}
+
+// Start runs the purge job in the background until Stop is called
+func (s *PurgeService) Start(ctx context.Context) {
+	if !s.job.start(ctx, s.config.Interval, s.RunPurge) {
+		return
+	}
+
+	s.logger.Info("Tenant purge started",
+		zap.Duration("interval", s.config.Interval),
+		zap.Duration("grace_period", s.config.GracePeriod))
+}
+
+// Stop stops the background purge job and waits for it to exit
+func (s *PurgeService) Stop() {
+	if !s.job.stop() {
+		return
+	}
+
+	s.logger.Info("Tenant purge stopped")
+}
+
+// RunPurge purges a batch of tenants whose grace period has passed, retrying incomplete purges
+func (s *PurgeService) RunPurge(ctx context.Context) {
+	tenants, err := s.tenantRepo.FindDuePurges(ctx, time.Now(), s.config.BatchSize)
+	if err != nil {
+		s.logger.Error("Failed to load tenants due for purge", zap.Error(err))
+		return
+	}
+
+	for _, tenant := range tenants {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := s.purge(ctx, tenant, "Deletion grace period elapsed", domain.SystemActor); err != nil {
+			s.logger.Error("Failed to purge tenant",
+				zap.String("tenant_id", tenant.ID.Hex()),
+				zap.Error(err))
+		}
+	}
+}
+
+// purge marks a tenant deleted, deletes its owned data and finally the tenant itself.
+// The tenant is kept if any collection fails so the purge is retried on the next run.
+func (s *PurgeService) purge(ctx context.Context, tenant *domain.Tenant, reason, actor string) (*domain.PurgeReport, error) {
+	tenantID := tenant.ID.Hex()
+	now := time.Now()
+
+	if tenant.CurrentStatus() != domain.TenantStatusDeleted {
+		purgeAfter := tenant.PurgeAfter
+		if purgeAfter == nil || purgeAfter.After(now) {
+			purgeAfter = &now
+		}
+		change := domain.TenantStatusChange{
+			To:         domain.TenantStatusDeleted,
+			Reason:     reason,
+			Actor:      actor,
+			PurgeAfter: purgeAfter,
+		}
+		if _, err := s.tenantService.changeStatus(ctx, tenant, change); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &domain.PurgeReport{
+		TenantID:      tenantID,
+		TenantName:    tenant.Name,
+		Status:        domain.PurgeStatusCompleted,
+		Reason:        reason,
+		Actor:         actor,
+		PurgeAfter:    tenant.PurgeAfter,
+		DeletedCounts: make(map[string]int64),
+		StartedAt:     now,
+	}
+
+	for _, collection := range s.collections {
+		deleted, err := collection.delete(ctx, tenantID)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", collection.name, err))
+			continue
+		}
+		report.DeletedCounts[collection.name] = deleted
+	}
+
+	if len(report.Errors) == 0 {
+		if err := s.tenantRepo.Delete(ctx, tenantID); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("tenants: %v", err))
+		} else {
+			report.DeletedCounts["tenants"] = 1
+		}
+	}
+
+	if len(report.Errors) > 0 {
+		report.Status = domain.PurgeStatusFailed
+	}
+	report.CompletedAt = time.Now()
+
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		s.logger.Error("Failed to save purge report", zap.String("tenant_id", tenantID), zap.Error(err))
+	}
+
+	if report.Status == domain.PurgeStatusFailed {
+		s.logger.Error("Tenant purge incomplete",
+			zap.String("tenant_id", tenantID),
+			zap.Strings("errors", report.Errors),
+		)
+		return nil, errors.Internal("Failed to purge tenant data, the purge will be retried")
+	}
+
+	s.logger.Info("Tenant purged successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("actor", actor),
+		zap.Any("deleted_counts", report.DeletedCounts),
+	)
+
+	return report, nil
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vhvplatform/go-shared/errors"
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// RoleService manages tenant roles and checks member permissions
+type RoleService struct {
+	roleRepo       *repository.TenantRoleRepository
+	tenantRepo     *repository.TenantRepository
+	tenantUserRepo *repository.TenantUserRepository
+	audit          *AuditService
+	logger         *logger.Logger
+}
+
+// NewRoleService creates a new role service
+func NewRoleService(
+	roleRepo *repository.TenantRoleRepository,
+	tenantRepo *repository.TenantRepository,
+	tenantUserRepo *repository.TenantUserRepository,
+	log *logger.Logger,
+) *RoleService {
+	return &RoleService{
+		roleRepo:       roleRepo,
+		tenantRepo:     tenantRepo,
+		tenantUserRepo: tenantUserRepo,
+		logger:         log,
+	}
+}
+
+// SetAuditService enables recording custom role changes in the audit log
+func (s *RoleService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
+// ListRoles lists the built-in roles followed by the custom roles of a tenant
+func (s *RoleService) ListRoles(ctx context.Context, tenantID string) ([]*domain.TenantRole, error) {
+	if err := s.checkTenantExists(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	custom, err := s.roleRepo.ListByTenant(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to list tenant roles", zap.String("tenant_id", tenantID), zap.Error(err))
+		return nil, errors.Internal("Failed to list roles")
+	}
+
+	return append(domain.BuiltInRoles(), custom...), nil
+}
+
+// GetRole returns a built-in role or a custom role of a tenant
+func (s *RoleService) GetRole(ctx context.Context, tenantID, name string) (*domain.TenantRole, error) {
+	if role := domain.BuiltInRole(name); role != nil {
+		return role, nil
+	}
+
+	role, err := s.roleRepo.FindByTenantAndName(ctx, tenantID, name)
+	if err != nil {
+		s.logger.Error("Failed to find tenant role", zap.String("tenant_id", tenantID), zap.Error(err))
+		return nil, errors.Internal("Failed to get role")
+	}
+	if role == nil {
+		return nil, errors.NotFound("Role not found")
+	}
+	return role, nil
+}
+
+// CreateRole defines a custom role for a tenant
+func (s *RoleService) CreateRole(ctx context.Context, tenantID string, req *domain.TenantRoleRequest) (*domain.TenantRole, error) {
+	role := &domain.TenantRole{
+		TenantID:    tenantID,
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	}
+	if err := role.Validate(); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	if err := s.checkTenantExists(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	if err := s.checkGrantable(ctx, tenantID, role.Permissions); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.roleRepo.FindByTenantAndName(ctx, tenantID, role.Name)
+	if err != nil {
+		s.logger.Error("Failed to check existing role", zap.Error(err))
+		return nil, errors.Internal("Failed to create role")
+	}
+	if existing != nil {
+		return nil, errors.Conflict("Role already exists")
+	}
+
+	if err := s.roleRepo.Create(ctx, role); err != nil {
+		s.logger.Error("Failed to create tenant role", zap.Error(err))
+		return nil, errors.Internal("Failed to create role")
+	}
+	s.recordAudit(ctx, domain.AuditActionRoleCreated, tenantID, role.Name, nil, domain.NewAuditSnapshot(role))
+
+	s.logger.Info("Tenant role created successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("role", role.Name),
+	)
+
+	return role, nil
+}
+
+// UpdateRole replaces the description and permissions of a custom role
+func (s *RoleService) UpdateRole(ctx context.Context, tenantID, name string, req *domain.TenantRoleRequest) (*domain.TenantRole, error) {
+	if domain.IsBuiltInRole(name) {
+		return nil, errors.BadRequest(domain.ErrBuiltInRole.Error())
+	}
+
+	role, err := s.GetRole(ctx, tenantID, name)
+	if err != nil {
+		return nil, err
+	}
+	before := domain.NewAuditSnapshot(role)
+
+	role.Description = req.Description
+	role.Permissions = req.Permissions
+	if err := role.Validate(); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+	if err := s.checkGrantable(ctx, tenantID, role.Permissions); err != nil {
+		return nil, err
+	}
+
+	if err := s.roleRepo.Update(ctx, role); err != nil {
+		s.logger.Error("Failed to update tenant role", zap.Error(err))
+		return nil, errors.Internal("Failed to update role")
+	}
+	s.recordAudit(ctx, domain.AuditActionRoleUpdated, tenantID, name, before, domain.NewAuditSnapshot(role))
+
+	s.logger.Info("Tenant role updated successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("role", name),
+	)
+
+	return role, nil
+}
+
+// DeleteRole deletes a custom role that is no longer assigned to any member
+func (s *RoleService) DeleteRole(ctx context.Context, tenantID, name string) error {
+	if domain.IsBuiltInRole(name) {
+		return errors.BadRequest(domain.ErrBuiltInRole.Error())
+	}
+
+	role, err := s.GetRole(ctx, tenantID, name)
+	if err != nil {
+		return err
+	}
+
+	members, err := s.tenantUserRepo.CountByRole(ctx, tenantID, name)
+	if err != nil {
+		s.logger.Error("Failed to count role members", zap.Error(err))
+		return errors.Internal("Failed to delete role")
+	}
+	if members > 0 {
+		return errors.Conflict("Role is still assigned to members")
+	}
+
+	if err := s.roleRepo.Delete(ctx, tenantID, name); err != nil {
+		s.logger.Error("Failed to delete tenant role", zap.Error(err))
+		return errors.Internal("Failed to delete role")
+	}
+	s.recordAudit(ctx, domain.AuditActionRoleDeleted, tenantID, name, domain.NewAuditSnapshot(role), nil)
+
+	s.logger.Info("Tenant role deleted successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("role", name),
+	)
+
+	return nil
+}
+
+// ValidateRole ensures a role can be assigned to members of a tenant
+func (s *RoleService) ValidateRole(ctx context.Context, tenantID, name string) error {
+	if _, err := s.GetRole(ctx, tenantID, name); err != nil {
+		if errors.FromError(err).StatusCode == http.StatusNotFound {
+			return errors.BadRequest("Unknown role: " + name)
+		}
+		return err
+	}
+	return nil
+}
+
+// CheckPermission checks if a user holds a permission in a tenant through their membership role.
+// Users that are not active members are denied, unless they own or administer a parent tenant.
+func (s *RoleService) CheckPermission(ctx context.Context, tenantID, userID, permission string) (*domain.PermissionCheck, error) {
+	if !domain.IsValidPermission(permission) {
+		return nil, errors.BadRequest("Invalid permission: " + permission)
+	}
+
+	membership, err := s.tenantUserRepo.FindByTenantAndUser(ctx, tenantID, userID)
+	if err != nil {
+		s.logger.Error("Failed to find tenant membership", zap.Error(err))
+		return nil, errors.Internal("Failed to check permission")
+	}
+	if membership == nil {
+		return s.checkInheritedPermission(ctx, tenantID, userID, permission)
+	}
+
+	check := &domain.PermissionCheck{Role: membership.Role}
+	role, err := s.GetRole(ctx, tenantID, membership.Role)
+	if err != nil {
+		if errors.FromError(err).StatusCode == http.StatusNotFound {
+			// Role definitions removed out of band grant nothing
+			return check, nil
+		}
+		return nil, err
+	}
+
+	check.Permissions = role.Permissions
+	check.Allowed = role.HasPermission(permission)
+	return check, nil
+}
+
+// checkInheritedPermission grants the owners and admins of parent tenants their built-in
+// role in sub-tenants, so a parent organization can manage its business units
+func (s *RoleService) checkInheritedPermission(ctx context.Context, tenantID, userID, permission string) (*domain.PermissionCheck, error) {
+	if !primitive.IsValidObjectID(tenantID) {
+		return &domain.PermissionCheck{Allowed: false}, nil
+	}
+	tenant, err := s.tenantRepo.FindByID(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to find tenant", zap.Error(err))
+		return nil, errors.Internal("Failed to check permission")
+	}
+	if tenant == nil || !tenant.IsSubTenant() {
+		return &domain.PermissionCheck{Allowed: false}, nil
+	}
+
+	ancestors, err := s.tenantRepo.FindAncestors(ctx, tenant, domain.MaxTenantDepth)
+	if err != nil {
+		s.logger.Error("Failed to find parent tenants", zap.String("tenant_id", tenantID), zap.Error(err))
+		return nil, errors.Internal("Failed to check permission")
+	}
+	for _, ancestor := range ancestors {
+		membership, err := s.tenantUserRepo.FindByTenantAndUser(ctx, ancestor.ID.Hex(), userID)
+		if err != nil {
+			s.logger.Error("Failed to find tenant membership", zap.Error(err))
+			return nil, errors.Internal("Failed to check permission")
+		}
+		if membership == nil || (membership.Role != domain.RoleOwner && membership.Role != domain.RoleAdmin) {
+			continue
+		}
+
+		role := domain.BuiltInRole(membership.Role)
+		return &domain.PermissionCheck{
+			Allowed:       role.HasPermission(permission),
+			Role:          role.Name,
+			Permissions:   role.Permissions,
+			InheritedFrom: ancestor.ID.Hex(),
+		}, nil
+	}
+
+	return &domain.PermissionCheck{Allowed: false}, nil
+}
+
+// checkGrantable ensures a signed-in user only puts permissions their own role holds into a
+// custom role, so members who manage roles cannot create one that outranks them
+func (s *RoleService) checkGrantable(ctx context.Context, tenantID string, permissions []string) error {
+	actor := domain.ActorFromContext(ctx)
+	if actor.Type != domain.ActorTypeUser {
+		return nil
+	}
+
+	check, err := s.CheckPermission(ctx, tenantID, actor.ID, domain.PermissionRolesManage)
+	if err != nil {
+		return err
+	}
+	for _, permission := range permissions {
+		if !domain.GrantsPermission(check.Permissions, permission) {
+			return errors.BadRequest("Cannot grant a permission your role does not hold: " + permission)
+		}
+	}
+	return nil
+}
+
+// checkTenantExists returns NotFound if the tenant does not exist
+func (s *RoleService) checkTenantExists(ctx context.Context, tenantID string) error {
+	tenant, err := s.tenantRepo.FindByID(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to find tenant", zap.Error(err))
+		return errors.Internal("Failed to find tenant")
+	}
+	if tenant == nil {
+		return errors.NotFound("Tenant not found")
+	}
+	return nil
+}
+
+// recordAudit records a change to a custom role in the audit log
+func (s *RoleService) recordAudit(ctx context.Context, action, tenantID, name string, before, after domain.AuditSnapshot) {
+	if s.audit == nil {
+		return
+	}
+	s.audit.Record(ctx, domain.NewAuditEvent(action, domain.AuditTargetRole, name, tenantID, before, after))
+}
@@ -1,386 +1,816 @@
-package service
-
-import (
-	"context"
-	"fmt"
-	"math/rand"
-	"sync"
-	"time"
-
-	"github.com/vhvplatform/go-shared/logger"
-	"github.com/vhvplatform/go-tenant-service/internal/domain"
-	"github.com/vhvplatform/go-tenant-service/internal/repository"
-)
-
-// ServiceRegistry manages service discovery and routing
-type ServiceRegistry struct {
-	repo           *repository.ServiceConfigRepository
-	healthStatus   map[string]*domain.ServiceStatus // key: tenantID:serviceName:url
-	statusMutex    sync.RWMutex
-	loadBalanceIdx map[string]int // key: tenantID:serviceName (for round-robin)
-	lbMutex        sync.Mutex
-	logger         logger.Logger
-}
-
-// NewServiceRegistry creates a new service registry
-func NewServiceRegistry(repo *repository.ServiceConfigRepository, log logger.Logger) *ServiceRegistry {
-	return &ServiceRegistry{
-		repo:           repo,
-		healthStatus:   make(map[string]*domain.ServiceStatus),
-		statusMutex:    sync.RWMutex{},
-		loadBalanceIdx: make(map[string]int),
-		lbMutex:        sync.Mutex{},
-		logger:         log,
-	}
-}
-
-// GetServiceURL resolves the best service URL for a tenant and service
-// It follows the fallback chain: tenant config -> default config -> error
-func (s *ServiceRegistry) GetServiceURL(ctx context.Context, tenantID, serviceName string) (*domain.FallbackChainResult, error) {
-	result := &domain.FallbackChainResult{
-		TenantID:    tenantID,
-		ServiceName: serviceName,
-		AttemptedAt: time.Now(),
-	}
-
-	// Try tenant-specific configuration
-	config, err := s.repo.FindByTenantAndService(ctx, tenantID, serviceName)
-	if err != nil {
-		s.logger.Error("Failed to find tenant service config",
-			"tenantId", tenantID,
-			"service", serviceName,
-			"error", err)
-	}
-
-	if config != nil && config.IsActive {
-		url, endpoint := s.selectEndpoint(config)
-		if url != "" {
-			result.ResolvedURL = url
-			result.UsedEndpoint = endpoint
-			result.IsDefault = false
-			result.Success = true
-			return result, nil
-		}
-	}
-
-	// Fallback to default configuration
-	defaultConfig, err := s.repo.GetDefaultConfig(ctx, serviceName)
-	if err != nil {
-		s.logger.Error("Failed to find default service config",
-			"service", serviceName,
-			"error", err)
-		result.Success = false
-		result.Error = fmt.Sprintf("failed to resolve service URL: %v", err)
-		return result, domain.ErrServiceNotFound
-	}
-
-	if defaultConfig == nil {
-		result.Success = false
-		result.Error = "no configuration found for service"
-		return result, domain.ErrServiceNotFound
-	}
-
-	result.ResolvedURL = defaultConfig.DefaultURL
-	result.IsDefault = true
-	result.Success = true
-	return result, nil
-}
-
-// selectEndpoint selects the best endpoint based on load balancing strategy
-func (s *ServiceRegistry) selectEndpoint(config *domain.ServiceConfig) (string, *domain.ServiceEndpoint) {
-	endpoints := config.GetActiveEndpoints()
-	if len(endpoints) == 0 {
-		// No active endpoints, try primary even if inactive
-		if config.PrimaryEndpoint.URL != "" {
-			return config.PrimaryEndpoint.URL, &config.PrimaryEndpoint
-		}
-		// Last resort: use default service URL
-		return config.DefaultServiceURL, nil
-	}
-
-	switch config.LoadBalanceStrategy {
-	case domain.LoadBalanceRoundRobin:
-		return s.roundRobinSelect(config.TenantID, config.ServiceName, endpoints)
-	case domain.LoadBalanceRandom:
-		return s.randomSelect(endpoints)
-	case domain.LoadBalanceWeighted:
-		return s.weightedSelect(endpoints)
-	case domain.LoadBalanceLeastConn:
-		// For now, fallback to round-robin (least-conn requires connection tracking)
-		return s.roundRobinSelect(config.TenantID, config.ServiceName, endpoints)
-	default:
-		// Default to round-robin
-		return s.roundRobinSelect(config.TenantID, config.ServiceName, endpoints)
-	}
-}
-
-// roundRobinSelect selects endpoint using round-robin algorithm
-func (s *ServiceRegistry) roundRobinSelect(tenantID, serviceName string, endpoints []*domain.ServiceEndpoint) (string, *domain.ServiceEndpoint) {
-	if len(endpoints) == 0 {
-		return "", nil
-	}
-
-	key := fmt.Sprintf("%s:%s", tenantID, serviceName)
-
-	s.lbMutex.Lock()
-	defer s.lbMutex.Unlock()
-
-	idx := s.loadBalanceIdx[key]
-	endpoint := endpoints[idx%len(endpoints)]
-
-	s.loadBalanceIdx[key] = (idx + 1) % len(endpoints)
-
-	return endpoint.URL, endpoint
-}
-
-// randomSelect selects a random active endpoint
-func (s *ServiceRegistry) randomSelect(endpoints []*domain.ServiceEndpoint) (string, *domain.ServiceEndpoint) {
-	if len(endpoints) == 0 {
-		return "", nil
-	}
-
-	idx := rand.Intn(len(endpoints))
-	endpoint := endpoints[idx]
-	return endpoint.URL, endpoint
-}
-
-// weightedSelect selects endpoint based on weight
-func (s *ServiceRegistry) weightedSelect(endpoints []*domain.ServiceEndpoint) (string, *domain.ServiceEndpoint) {
-	if len(endpoints) == 0 {
-		return "", nil
-	}
-
-	// Calculate total weight
-	totalWeight := 0
-	for _, ep := range endpoints {
-		totalWeight += ep.Weight
-	}
-
-	if totalWeight == 0 {
-		// If no weights, fallback to random
-		return s.randomSelect(endpoints)
-	}
-
-	// Select based on weight
-	r := rand.Intn(totalWeight)
-	cumulative := 0
-	for _, ep := range endpoints {
-		cumulative += ep.Weight
-		if r < cumulative {
-			return ep.URL, ep
-		}
-	}
-
-	// Fallback (should not reach here)
-	return endpoints[0].URL, endpoints[0]
-}
-
-// ResolveFallbackChain attempts to resolve a working endpoint through the fallback chain
-func (s *ServiceRegistry) ResolveFallbackChain(ctx context.Context, config *domain.ServiceConfig) (*domain.FallbackChainResult, error) {
-	result := &domain.FallbackChainResult{
-		TenantID:      config.TenantID,
-		ServiceName:   config.ServiceName,
-		AttemptedURLs: []string{},
-		AttemptedAt:   time.Now(),
-	}
-
-	// Try primary endpoint
-	if config.PrimaryEndpoint.IsActive && s.isEndpointHealthy(config.TenantID, config.ServiceName, config.PrimaryEndpoint.URL) {
-		result.ResolvedURL = config.PrimaryEndpoint.URL
-		result.UsedEndpoint = &config.PrimaryEndpoint
-		result.IsDefault = false
-		result.Success = true
-		return result, nil
-	}
-	result.AttemptedURLs = append(result.AttemptedURLs, config.PrimaryEndpoint.URL)
-
-	// Try fallback chain
-	for _, endpoint := range config.FallbackChain {
-		if endpoint.IsActive && s.isEndpointHealthy(config.TenantID, config.ServiceName, endpoint.URL) {
-			result.ResolvedURL = endpoint.URL
-			result.UsedEndpoint = &endpoint
-			result.IsDefault = false
-			result.Success = true
-			return result, nil
-		}
-		result.AttemptedURLs = append(result.AttemptedURLs, endpoint.URL)
-	}
-
-	// Last resort: use default service URL
-	if config.DefaultServiceURL != "" {
-		result.ResolvedURL = config.DefaultServiceURL
-		result.IsDefault = true
-		result.Success = true
-		return result, nil
-	}
-
-	result.Success = false
-	result.Error = "all endpoints in fallback chain are unhealthy"
-	return result, fmt.Errorf("no healthy endpoints available")
-}
-
-// UpdateHealthStatus updates the health status of an endpoint
-func (s *ServiceRegistry) UpdateHealthStatus(tenantID, serviceName, url string, healthy bool) {
-	key := fmt.Sprintf("%s:%s:%s", tenantID, serviceName, url)
-
-	s.statusMutex.Lock()
-	defer s.statusMutex.Unlock()
-
-	status, exists := s.healthStatus[key]
-	if !exists {
-		status = &domain.ServiceStatus{
-			TenantID:         tenantID,
-			ServiceName:      serviceName,
-			EndpointURL:      url,
-			IsHealthy:        healthy,
-			LastChecked:      time.Now(),
-			ConsecutiveFails: 0,
-		}
-		s.healthStatus[key] = status
-		return
-	}
-
-	status.LastChecked = time.Now()
-	if healthy {
-		status.IsHealthy = true
-		status.ConsecutiveFails = 0
-		status.LastSuccessful = time.Now()
-	} else {
-		status.ConsecutiveFails++
-		status.LastFailure = time.Now()
-		status.LastError = "health check failed"
-
-		// Mark as unhealthy after threshold
-		if status.ConsecutiveFails >= 3 {
-			status.IsHealthy = false
-		}
-	}
-}
-
-// isEndpointHealthy checks if an endpoint is currently healthy
-func (s *ServiceRegistry) isEndpointHealthy(tenantID, serviceName, url string) bool {
-	key := fmt.Sprintf("%s:%s:%s", tenantID, serviceName, url)
-
-	s.statusMutex.RLock()
-	defer s.statusMutex.RUnlock()
-
-	status, exists := s.healthStatus[key]
-	if !exists {
-		// If no status recorded, assume healthy
-		return true
-	}
-
-	return status.IsHealthy
-}
-
-// GetHealthStatus returns the current health status of an endpoint
-func (s *ServiceRegistry) GetHealthStatus(tenantID, serviceName, url string) *domain.ServiceStatus {
-	key := fmt.Sprintf("%s:%s:%s", tenantID, serviceName, url)
-
-	s.statusMutex.RLock()
-	defer s.statusMutex.RUnlock()
-
-	status, exists := s.healthStatus[key]
-	if !exists {
-		return &domain.ServiceStatus{
-			TenantID:    tenantID,
-			ServiceName: serviceName,
-			EndpointURL: url,
-			IsHealthy:   true, // Default to healthy
-			LastChecked: time.Now(),
-		}
-	}
-
-	// Return a copy to avoid race conditions
-	statusCopy := *status
-	return &statusCopy
-}
-
-// GetAllHealthStatus returns all health status records
-func (s *ServiceRegistry) GetAllHealthStatus() []*domain.ServiceStatus {
-	s.statusMutex.RLock()
-	defer s.statusMutex.RUnlock()
-
-	statuses := make([]*domain.ServiceStatus, 0, len(s.healthStatus))
-	for _, status := range s.healthStatus {
-		statusCopy := *status
-		statuses = append(statuses, &statusCopy)
-	}
-
-	return statuses
-}
-
-// InvalidateCache clears the health status cache for a specific endpoint
-func (s *ServiceRegistry) InvalidateCache(tenantID, serviceName, url string) {
-	key := fmt.Sprintf("%s:%s:%s", tenantID, serviceName, url)
-
-	s.statusMutex.Lock()
-	defer s.statusMutex.Unlock()
-
-	delete(s.healthStatus, key)
-}
-
-// InvalidateAllCache clears all health status cache
-func (s *ServiceRegistry) InvalidateAllCache() {
-	s.statusMutex.Lock()
-	defer s.statusMutex.Unlock()
-
-	s.healthStatus = make(map[string]*domain.ServiceStatus)
-}
-
-// CreateOrUpdateServiceConfig creates or updates a service configuration
-func (s *ServiceRegistry) CreateOrUpdateServiceConfig(ctx context.Context, config *domain.ServiceConfig) error {
-	if err := config.Validate(); err != nil {
-		return err
-	}
-
-	return s.repo.Upsert(ctx, config)
-}
-
-// GetServiceConfig gets a service configuration for a tenant
-func (s *ServiceRegistry) GetServiceConfig(ctx context.Context, tenantID, serviceName string) (*domain.ServiceConfig, error) {
-	config, err := s.repo.FindByTenantAndService(ctx, tenantID, serviceName)
-	if err != nil {
-		return nil, err
-	}
-
-	if config == nil {
-		return nil, domain.ErrServiceNotFound
-	}
-
-	return config, nil
-}
-
-// GetTenantServices gets all service configurations for a tenant
-func (s *ServiceRegistry) GetTenantServices(ctx context.Context, tenantID string) ([]*domain.ServiceConfig, error) {
-	return s.repo.FindByTenant(ctx, tenantID)
-}
-
-// DeleteServiceConfig deletes a service configuration
-func (s *ServiceRegistry) DeleteServiceConfig(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
-}
-
-// CreateDefaultConfig creates or updates a default service configuration
-func (s *ServiceRegistry) CreateDefaultConfig(ctx context.Context, config *domain.DefaultServiceConfig) error {
-	return s.repo.UpsertDefaultConfig(ctx, config)
-}
-
-// GetDefaultConfig gets the default configuration for a service
-func (s *ServiceRegistry) GetDefaultConfig(ctx context.Context, serviceName string) (*domain.DefaultServiceConfig, error) {
-	config, err := s.repo.GetDefaultConfig(ctx, serviceName)
-	if err != nil {
-		return nil, err
-	}
-
-	if config == nil {
-		return nil, domain.ErrServiceNotFound
-	}
-
-	return config, nil
-}
-
-// GetAllDefaultConfigs gets all default service configurations
-func (s *ServiceRegistry) GetAllDefaultConfigs(ctx context.Context) ([]*domain.DefaultServiceConfig, error) {
-	return s.repo.GetAllDefaultConfigs(ctx)
-}
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// DefaultConfigCacheTTL bounds how long a resolved service configuration is reused
+// when no change notification is received
+const DefaultConfigCacheTTL = 30 * time.Second
+
+// ServiceRegistry manages service discovery and routing
+type ServiceRegistry struct {
+	repo           *repository.ServiceConfigRepository
+	healthStatus   map[string]*domain.ServiceStatus // key: tenantID:serviceName:url
+	statusMutex    sync.RWMutex
+	loadBalanceIdx map[string]int // key: tenantID:serviceName (for round-robin)
+	lbMutex        sync.Mutex
+	configCache    map[string]*cachedServiceConfig // key: tenantID:serviceName
+	defaultCache   map[string]*cachedDefaultConfig // key: serviceName
+	ancestorCache  map[string]*cachedAncestors     // key: tenantID
+	cacheMutex     sync.RWMutex
+	cacheTTL       time.Duration
+	breakers       map[string]*circuitBreaker // key: tenantID:serviceName:url
+	breakerMutex   sync.Mutex
+	inFlight       map[string]int // key: tenantID:serviceName:url
+	inFlightMutex  sync.Mutex
+	entitlements   *EntitlementService          // Optional; enforces tier limits on config changes
+	tenants        *repository.TenantRepository // Optional; resolves configs inherited from parent tenants
+	audit          *AuditService                // Optional; records configuration changes in the audit log
+	outbox         *EventOutbox                 // Optional; announces configuration changes as tenant events
+	logger         *logger.Logger
+}
+
+// cachedServiceConfig is a tenant config lookup result; config is nil if the tenant has none
+type cachedServiceConfig struct {
+	config    *domain.ServiceConfig
+	expiresAt time.Time
+}
+
+// cachedDefaultConfig is a default config lookup result; config is nil if none exists
+type cachedDefaultConfig struct {
+	config    *domain.DefaultServiceConfig
+	expiresAt time.Time
+}
+
+// cachedAncestors is the list of parent tenant IDs of a tenant, nearest first
+type cachedAncestors struct {
+	ids       []string
+	expiresAt time.Time
+}
+
+// NewServiceRegistry creates a new service registry
+func NewServiceRegistry(repo *repository.ServiceConfigRepository, log *logger.Logger) *ServiceRegistry {
+	return &ServiceRegistry{
+		repo:           repo,
+		healthStatus:   make(map[string]*domain.ServiceStatus),
+		statusMutex:    sync.RWMutex{},
+		loadBalanceIdx: make(map[string]int),
+		lbMutex:        sync.Mutex{},
+		configCache:    make(map[string]*cachedServiceConfig),
+		defaultCache:   make(map[string]*cachedDefaultConfig),
+		ancestorCache:  make(map[string]*cachedAncestors),
+		cacheTTL:       DefaultConfigCacheTTL,
+		breakers:       make(map[string]*circuitBreaker),
+		inFlight:       make(map[string]int),
+		logger:         log,
+	}
+}
+
+// SetConfigCacheTTL sets how long resolved configurations are cached; zero disables caching
+func (s *ServiceRegistry) SetConfigCacheTTL(ttl time.Duration) {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	s.cacheTTL = ttl
+	s.configCache = make(map[string]*cachedServiceConfig)
+	s.defaultCache = make(map[string]*cachedDefaultConfig)
+	s.ancestorCache = make(map[string]*cachedAncestors)
+}
+
+// SetEntitlementService enables enforcement of tenant entitlements when configurations change
+func (s *ServiceRegistry) SetEntitlementService(entitlements *EntitlementService) {
+	s.entitlements = entitlements
+}
+
+// SetTenantRepository enables resolving service configurations inherited from parent tenants
+func (s *ServiceRegistry) SetTenantRepository(tenants *repository.TenantRepository) {
+	s.tenants = tenants
+}
+
+// SetAuditService enables recording configuration changes in the audit log
+func (s *ServiceRegistry) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
+// SetEventOutbox enables announcing configuration changes as tenant events, committed
+// together with the changes
+func (s *ServiceRegistry) SetEventOutbox(outbox *EventOutbox) {
+	s.outbox = outbox
+}
+
+// GetServiceURL resolves the best service URL for a tenant and service
+// It follows the fallback chain: tenant config -> parent tenant configs -> default config -> error
+func (s *ServiceRegistry) GetServiceURL(ctx context.Context, tenantID, serviceName string) (*domain.FallbackChainResult, error) {
+	return s.resolveServiceURL(ctx, tenantID, serviceName, false)
+}
+
+// AcquireServiceURL resolves the service URL like GetServiceURL and, for a selected endpoint,
+// records a request to it as in flight in the same step. The caller must pass the result to
+// ReleaseServiceURL once the request completes.
+func (s *ServiceRegistry) AcquireServiceURL(ctx context.Context, tenantID, serviceName string) (*domain.FallbackChainResult, error) {
+	return s.resolveServiceURL(ctx, tenantID, serviceName, true)
+}
+
+// ReleaseServiceURL completes a request to an endpoint returned by AcquireServiceURL
+func (s *ServiceRegistry) ReleaseServiceURL(result *domain.FallbackChainResult) {
+	if result == nil || result.IsDefault || result.ResolvedURL == "" {
+		return
+	}
+	s.ReleaseConnection(result.ConfigTenantID(), result.ServiceName, result.ResolvedURL)
+}
+
+// resolveServiceURL walks the fallback chain, acquiring a connection to the selected endpoint if requested
+func (s *ServiceRegistry) resolveServiceURL(ctx context.Context, tenantID, serviceName string, acquire bool) (*domain.FallbackChainResult, error) {
+	result := &domain.FallbackChainResult{
+		TenantID:    tenantID,
+		ServiceName: serviceName,
+		AttemptedAt: time.Now(),
+	}
+
+	// Try tenant-specific configuration
+	config, err := s.findTenantConfig(ctx, tenantID, serviceName)
+	if err != nil {
+		s.logger.Error("Failed to find tenant service config",
+			zap.String("tenant_id", tenantID),
+			zap.String("service", serviceName),
+			zap.Error(err))
+	}
+
+	if config != nil && config.IsActive {
+		url, endpoint := s.selectEndpoint(config, acquire)
+		if url != "" {
+			result.ResolvedURL = url
+			result.UsedEndpoint = endpoint
+			result.IsDefault = false
+			result.Success = true
+			return result, nil
+		}
+	}
+
+	// Fallback to the configurations of parent tenants, nearest first
+	for _, ancestorID := range s.findAncestors(ctx, tenantID) {
+		config, err := s.findTenantConfig(ctx, ancestorID, serviceName)
+		if err != nil {
+			s.logger.Error("Failed to find parent tenant service config",
+				zap.String("tenant_id", ancestorID),
+				zap.String("service", serviceName),
+				zap.Error(err))
+			continue
+		}
+		if config == nil || !config.IsActive {
+			continue
+		}
+
+		url, endpoint := s.selectEndpoint(config, acquire)
+		if url != "" {
+			result.ResolvedURL = url
+			result.UsedEndpoint = endpoint
+			result.IsDefault = false
+			result.InheritedFrom = ancestorID
+			result.Success = true
+			return result, nil
+		}
+	}
+
+	// Fallback to default configuration
+	defaultConfig, err := s.findDefaultConfig(ctx, serviceName)
+	if err != nil {
+		s.logger.Error("Failed to find default service config",
+			zap.String("service", serviceName),
+			zap.Error(err))
+		result.Success = false
+		result.Error = fmt.Sprintf("failed to resolve service URL: %v", err)
+		return result, domain.ErrServiceNotFound
+	}
+
+	if defaultConfig == nil {
+		result.Success = false
+		result.Error = "no configuration found for service"
+		return result, domain.ErrServiceNotFound
+	}
+
+	result.ResolvedURL = defaultConfig.DefaultURL
+	result.IsDefault = true
+	result.Success = true
+	return result, nil
+}
+
+// findTenantConfig looks up a tenant service configuration through the config cache
+func (s *ServiceRegistry) findTenantConfig(ctx context.Context, tenantID, serviceName string) (*domain.ServiceConfig, error) {
+	key := fmt.Sprintf("%s:%s", tenantID, serviceName)
+
+	s.cacheMutex.RLock()
+	entry, ok := s.configCache[key]
+	ttl := s.cacheTTL
+	s.cacheMutex.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.config, nil
+	}
+
+	config, err := s.repo.FindByTenantAndService(ctx, tenantID, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if ttl > 0 {
+		s.cacheMutex.Lock()
+		s.configCache[key] = &cachedServiceConfig{config: config, expiresAt: time.Now().Add(ttl)}
+		s.cacheMutex.Unlock()
+	}
+
+	return config, nil
+}
+
+// findAncestors returns the parent tenant IDs of a tenant through the ancestor cache.
+// Lookup failures are logged and skip inheritance rather than failing resolution.
+func (s *ServiceRegistry) findAncestors(ctx context.Context, tenantID string) []string {
+	if s.tenants == nil || !primitive.IsValidObjectID(tenantID) {
+		return nil
+	}
+
+	s.cacheMutex.RLock()
+	entry, ok := s.ancestorCache[tenantID]
+	ttl := s.cacheTTL
+	s.cacheMutex.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.ids
+	}
+
+	tenant, err := s.tenants.FindByID(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to find tenant", zap.String("tenant_id", tenantID), zap.Error(err))
+		return nil
+	}
+
+	var ids []string
+	if tenant != nil && tenant.IsSubTenant() {
+		ancestors, err := s.tenants.FindAncestors(ctx, tenant, domain.MaxTenantDepth)
+		if err != nil {
+			s.logger.Error("Failed to find parent tenants", zap.String("tenant_id", tenantID), zap.Error(err))
+			return nil
+		}
+		for _, ancestor := range ancestors {
+			ids = append(ids, ancestor.ID.Hex())
+		}
+	}
+
+	if ttl > 0 {
+		s.cacheMutex.Lock()
+		s.ancestorCache[tenantID] = &cachedAncestors{ids: ids, expiresAt: time.Now().Add(ttl)}
+		s.cacheMutex.Unlock()
+	}
+	return ids
+}
+
+// findDefaultConfig looks up a default service configuration through the config cache
+func (s *ServiceRegistry) findDefaultConfig(ctx context.Context, serviceName string) (*domain.DefaultServiceConfig, error) {
+	s.cacheMutex.RLock()
+	entry, ok := s.defaultCache[serviceName]
+	ttl := s.cacheTTL
+	s.cacheMutex.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.config, nil
+	}
+
+	config, err := s.repo.GetDefaultConfig(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if ttl > 0 {
+		s.cacheMutex.Lock()
+		s.defaultCache[serviceName] = &cachedDefaultConfig{config: config, expiresAt: time.Now().Add(ttl)}
+		s.cacheMutex.Unlock()
+	}
+
+	return config, nil
+}
+
+// InvalidateConfigCache drops the cached configuration for a tenant and service
+func (s *ServiceRegistry) InvalidateConfigCache(tenantID, serviceName string) {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	delete(s.configCache, fmt.Sprintf("%s:%s", tenantID, serviceName))
+}
+
+// InvalidateDefaultConfigCache drops the cached default configuration for a service
+func (s *ServiceRegistry) InvalidateDefaultConfigCache(serviceName string) {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	delete(s.defaultCache, serviceName)
+}
+
+// InvalidateAllConfigCache drops every cached configuration
+func (s *ServiceRegistry) InvalidateAllConfigCache() {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	s.configCache = make(map[string]*cachedServiceConfig)
+	s.defaultCache = make(map[string]*cachedDefaultConfig)
+	s.ancestorCache = make(map[string]*cachedAncestors)
+}
+
+// WatchConfigChanges invalidates cached configurations whenever they change in the
+// database, so that processes other than the writer (e.g. the gateway) see updates
+// without waiting for the cache TTL. It blocks until ctx is cancelled, reconnecting
+// the change stream after errors.
+func (s *ServiceRegistry) WatchConfigChanges(ctx context.Context) {
+	backoff := time.Second
+	for {
+		err := s.repo.WatchChanges(ctx, func(change domain.ServiceConfigChange) {
+			switch {
+			case change.IsDefault && change.ServiceName != "":
+				s.InvalidateDefaultConfigCache(change.ServiceName)
+			case !change.IsDefault && change.TenantID != "" && change.ServiceName != "":
+				s.InvalidateConfigCache(change.TenantID, change.ServiceName)
+			default:
+				s.InvalidateAllConfigCache()
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Changes may have been missed while disconnected
+		s.InvalidateAllConfigCache()
+		s.logger.Warn("Service config change stream interrupted, retrying",
+			zap.Duration("backoff", backoff),
+			zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < time.Minute {
+			backoff *= 2
+		}
+	}
+}
+
+// selectEndpoint selects the best endpoint based on load balancing strategy.
+// With acquire set, a request to a selected active endpoint is recorded as in flight.
+func (s *ServiceRegistry) selectEndpoint(config *domain.ServiceConfig, acquire bool) (string, *domain.ServiceEndpoint) {
+	endpoints := config.GetActiveEndpoints()
+	if len(endpoints) == 0 {
+		// No active endpoints, try primary even if inactive
+		url, endpoint := config.PrimaryEndpoint.URL, &config.PrimaryEndpoint
+		if url == "" {
+			// Last resort: use default service URL
+			url, endpoint = config.DefaultServiceURL, nil
+		}
+		if acquire && url != "" {
+			// ReleaseServiceURL releases every non-default result, so count this one too
+			s.AcquireConnection(config.TenantID, config.ServiceName, url)
+		}
+		return url, endpoint
+	}
+
+	// Skip endpoints that fail their health probes or whose circuit is open
+	available := make([]*domain.ServiceEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if s.isEndpointHealthy(config.TenantID, config.ServiceName, endpoint.URL) && s.isCircuitAvailable(config, endpoint.URL) {
+			available = append(available, endpoint)
+		}
+	}
+	if len(available) == 0 {
+		// Every endpoint is unhealthy or tripped; let the caller fall back to the default config
+		return "", nil
+	}
+
+	// A half-open endpoint whose probe slots are taken is skipped in favour of the others
+	for len(available) > 0 {
+		url, selected := s.pickEndpoint(config, available, acquire)
+		if !acquire {
+			// Lookups send no request, so they take no half-open trial slot
+			return url, selected
+		}
+		// Least-connections acquires while selecting so concurrent requests see each other
+		acquired := config.LoadBalanceStrategy == domain.LoadBalanceLeastConn
+		if s.acquireCircuit(config, url) {
+			if !acquired {
+				s.AcquireConnection(config.TenantID, config.ServiceName, url)
+			}
+			return url, selected
+		}
+		if acquired {
+			s.ReleaseConnection(config.TenantID, config.ServiceName, url)
+		}
+		for i, endpoint := range available {
+			if endpoint == selected {
+				available = append(available[:i:i], available[i+1:]...)
+				break
+			}
+		}
+	}
+	return "", nil
+}
+
+// pickEndpoint applies the load balancing strategy of a config to the available endpoints
+func (s *ServiceRegistry) pickEndpoint(config *domain.ServiceConfig, available []*domain.ServiceEndpoint, acquire bool) (string, *domain.ServiceEndpoint) {
+	switch config.LoadBalanceStrategy {
+	case domain.LoadBalanceRoundRobin:
+		return s.roundRobinSelect(config.TenantID, config.ServiceName, available)
+	case domain.LoadBalanceRandom:
+		return s.randomSelect(available)
+	case domain.LoadBalanceWeighted:
+		return s.weightedSelect(available)
+	case domain.LoadBalanceLeastConn:
+		return s.leastConnSelect(config.TenantID, config.ServiceName, available, acquire)
+	default:
+		// Default to round-robin
+		return s.roundRobinSelect(config.TenantID, config.ServiceName, available)
+	}
+}
+
+// roundRobinSelect selects endpoint using round-robin algorithm
+func (s *ServiceRegistry) roundRobinSelect(tenantID, serviceName string, endpoints []*domain.ServiceEndpoint) (string, *domain.ServiceEndpoint) {
+	if len(endpoints) == 0 {
+		return "", nil
+	}
+
+	key := fmt.Sprintf("%s:%s", tenantID, serviceName)
+
+	s.lbMutex.Lock()
+	defer s.lbMutex.Unlock()
+
+	idx := s.loadBalanceIdx[key]
+	endpoint := endpoints[idx%len(endpoints)]
+
+	s.loadBalanceIdx[key] = (idx + 1) % len(endpoints)
+
+	return endpoint.URL, endpoint
+}
+
+// randomSelect selects a random active endpoint
+func (s *ServiceRegistry) randomSelect(endpoints []*domain.ServiceEndpoint) (string, *domain.ServiceEndpoint) {
+	if len(endpoints) == 0 {
+		return "", nil
+	}
+
+	idx := rand.Intn(len(endpoints))
+	endpoint := endpoints[idx]
+	return endpoint.URL, endpoint
+}
+
+// weightedSelect selects endpoint based on weight
+func (s *ServiceRegistry) weightedSelect(endpoints []*domain.ServiceEndpoint) (string, *domain.ServiceEndpoint) {
+	if len(endpoints) == 0 {
+		return "", nil
+	}
+
+	// Calculate total weight
+	totalWeight := 0
+	for _, ep := range endpoints {
+		totalWeight += ep.Weight
+	}
+
+	if totalWeight == 0 {
+		// If no weights, fallback to random
+		return s.randomSelect(endpoints)
+	}
+
+	// Select based on weight
+	r := rand.Intn(totalWeight)
+	cumulative := 0
+	for _, ep := range endpoints {
+		cumulative += ep.Weight
+		if r < cumulative {
+			return ep.URL, ep
+		}
+	}
+
+	// Fallback (should not reach here)
+	return endpoints[0].URL, endpoints[0]
+}
+
+// UpdateHealthStatus updates the health status of an endpoint using the default fail threshold
+func (s *ServiceRegistry) UpdateHealthStatus(tenantID, serviceName, url string, healthy bool) {
+	var probeErr error
+	if !healthy {
+		probeErr = fmt.Errorf("health check failed")
+	}
+	s.RecordHealthCheck(tenantID, serviceName, url, domain.DefaultFailThreshold, 0, probeErr)
+}
+
+// RecordHealthCheck records the outcome of a health probe. The endpoint is marked
+// unhealthy once failThreshold consecutive probes have failed, and healthy again
+// on the first successful probe.
+func (s *ServiceRegistry) RecordHealthCheck(tenantID, serviceName, url string, failThreshold int, responseTime time.Duration, probeErr error) {
+	key := fmt.Sprintf("%s:%s:%s", tenantID, serviceName, url)
+	if failThreshold <= 0 {
+		failThreshold = domain.DefaultFailThreshold
+	}
+
+	s.statusMutex.Lock()
+	defer s.statusMutex.Unlock()
+
+	status, exists := s.healthStatus[key]
+	if !exists {
+		status = &domain.ServiceStatus{
+			TenantID:    tenantID,
+			ServiceName: serviceName,
+			EndpointURL: url,
+			IsHealthy:   true,
+		}
+		s.healthStatus[key] = status
+	}
+
+	now := time.Now()
+	status.LastChecked = now
+	status.ResponseTime = responseTime.Milliseconds()
+	if probeErr == nil {
+		status.IsHealthy = true
+		status.ConsecutiveFails = 0
+		status.LastSuccessful = now
+		status.LastError = ""
+		return
+	}
+
+	status.ConsecutiveFails++
+	status.LastFailure = now
+	status.LastError = probeErr.Error()
+
+	// Mark as unhealthy after threshold
+	if status.ConsecutiveFails >= failThreshold && status.IsHealthy {
+		status.IsHealthy = false
+		s.logger.Warn("Endpoint marked unhealthy",
+			zap.String("tenant_id", tenantID),
+			zap.String("service", serviceName),
+			zap.String("url", url),
+			zap.Int("consecutive_fails", status.ConsecutiveFails),
+			zap.String("error", status.LastError))
+	}
+}
+
+// isEndpointHealthy checks if an endpoint is currently healthy
+func (s *ServiceRegistry) isEndpointHealthy(tenantID, serviceName, url string) bool {
+	key := fmt.Sprintf("%s:%s:%s", tenantID, serviceName, url)
+
+	s.statusMutex.RLock()
+	defer s.statusMutex.RUnlock()
+
+	status, exists := s.healthStatus[key]
+	if !exists {
+		// If no status recorded, assume healthy
+		return true
+	}
+
+	return status.IsHealthy
+}
+
+// GetHealthStatus returns the current health status of an endpoint
+func (s *ServiceRegistry) GetHealthStatus(tenantID, serviceName, url string) *domain.ServiceStatus {
+	key := fmt.Sprintf("%s:%s:%s", tenantID, serviceName, url)
+
+	s.statusMutex.RLock()
+	defer s.statusMutex.RUnlock()
+
+	status, exists := s.healthStatus[key]
+	if !exists {
+		return &domain.ServiceStatus{
+			TenantID:          tenantID,
+			ServiceName:       serviceName,
+			EndpointURL:       url,
+			IsHealthy:         true, // Default to healthy
+			LastChecked:       time.Now(),
+			CircuitState:      s.GetCircuitState(tenantID, serviceName, url),
+			ActiveConnections: s.GetActiveConnections(tenantID, serviceName, url),
+		}
+	}
+
+	// Return a copy to avoid race conditions
+	statusCopy := *status
+	statusCopy.CircuitState = s.GetCircuitState(tenantID, serviceName, url)
+	statusCopy.ActiveConnections = s.GetActiveConnections(tenantID, serviceName, url)
+	return &statusCopy
+}
+
+// GetAllHealthStatus returns all health status records
+func (s *ServiceRegistry) GetAllHealthStatus() []*domain.ServiceStatus {
+	s.statusMutex.RLock()
+	defer s.statusMutex.RUnlock()
+
+	statuses := make([]*domain.ServiceStatus, 0, len(s.healthStatus))
+	for _, status := range s.healthStatus {
+		statusCopy := *status
+		statusCopy.CircuitState = s.GetCircuitState(status.TenantID, status.ServiceName, status.EndpointURL)
+		statusCopy.ActiveConnections = s.GetActiveConnections(status.TenantID, status.ServiceName, status.EndpointURL)
+		statuses = append(statuses, &statusCopy)
+	}
+
+	return statuses
+}
+
+// InvalidateCache clears the health status cache for a specific endpoint
+func (s *ServiceRegistry) InvalidateCache(tenantID, serviceName, url string) {
+	key := fmt.Sprintf("%s:%s:%s", tenantID, serviceName, url)
+
+	s.statusMutex.Lock()
+	defer s.statusMutex.Unlock()
+
+	delete(s.healthStatus, key)
+}
+
+// InvalidateAllCache clears all health status cache
+func (s *ServiceRegistry) InvalidateAllCache() {
+	s.statusMutex.Lock()
+	defer s.statusMutex.Unlock()
+
+	s.healthStatus = make(map[string]*domain.ServiceStatus)
+}
+
+// CreateOrUpdateServiceConfig creates or updates a service configuration. A non-zero
+// expected version only updates an existing configuration still at that version.
+func (s *ServiceRegistry) CreateOrUpdateServiceConfig(ctx context.Context, config *domain.ServiceConfig, expectedVersion int64) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if s.entitlements != nil {
+		if err := s.entitlements.CheckServiceConfig(ctx, config); err != nil {
+			return err
+		}
+	}
+
+	var before domain.AuditSnapshot
+	if s.audit != nil {
+		existing, err := s.repo.FindByTenantAndService(ctx, config.TenantID, config.ServiceName)
+		if err != nil {
+			return err
+		}
+		before = domain.NewAuditSnapshot(existing)
+	}
+
+	err := s.outbox.Transaction(ctx, func(ctx context.Context) error {
+		if expectedVersion > 0 {
+			if err := s.repo.UpdateIfVersion(ctx, config, expectedVersion); err != nil {
+				return err
+			}
+		} else if err := s.repo.Upsert(ctx, config); err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, domain.NewTenantEvent(domain.EventServiceConfigChanged, config.TenantID,
+			domain.ServiceConfigPayload{ServiceName: config.ServiceName, IsActive: config.IsActive, Version: config.Version}))
+	})
+	if err != nil {
+		return err
+	}
+
+	s.InvalidateConfigCache(config.TenantID, config.ServiceName)
+
+	if s.audit != nil {
+		action := domain.AuditActionServiceConfigUpdated
+		if before == nil {
+			action = domain.AuditActionServiceConfigCreated
+		}
+		s.audit.Record(ctx, domain.NewAuditEvent(action, domain.AuditTargetServiceConfig,
+			config.ServiceName, config.TenantID, before, domain.NewAuditSnapshot(config)))
+	}
+	return nil
+}
+
+// GetServiceConfig gets a service configuration for a tenant
+func (s *ServiceRegistry) GetServiceConfig(ctx context.Context, tenantID, serviceName string) (*domain.ServiceConfig, error) {
+	config, err := s.repo.FindByTenantAndService(ctx, tenantID, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if config == nil {
+		return nil, domain.ErrServiceNotFound
+	}
+
+	return config, nil
+}
+
+// GetTenantServices gets a page of the service configurations of a tenant, ordered by service name
+func (s *ServiceRegistry) GetTenantServices(ctx context.Context, tenantID string, page domain.PageRequest) ([]*domain.ServiceConfig, *domain.PageInfo, error) {
+	if page.PageSize > domain.MaxPageSize {
+		return nil, nil, domain.NewValidationError(fmt.Sprintf("page_size must be at most %d", domain.MaxPageSize))
+	}
+	return s.repo.ListByTenant(ctx, tenantID, page)
+}
+
+// DeleteServiceConfig deletes a service configuration
+func (s *ServiceRegistry) DeleteServiceConfig(ctx context.Context, id string) error {
+	config, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.outbox.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, configDeletedEvent(config))
+	})
+	if err != nil {
+		return err
+	}
+
+	s.InvalidateConfigCache(config.TenantID, config.ServiceName)
+	s.recordConfigDeleted(ctx, config)
+	return nil
+}
+
+// DeleteTenantServiceConfigs deletes every service configuration of a tenant
+func (s *ServiceRegistry) DeleteTenantServiceConfigs(ctx context.Context, tenantID string) (int64, error) {
+	configs, err := s.repo.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	err = s.outbox.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if deleted, err = s.repo.DeleteByTenant(ctx, tenantID); err != nil {
+			return err
+		}
+		for _, config := range configs {
+			if err := s.outbox.Add(ctx, configDeletedEvent(config)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, config := range configs {
+		s.InvalidateConfigCache(config.TenantID, config.ServiceName)
+		s.recordConfigDeleted(ctx, config)
+	}
+	return deleted, nil
+}
+
+// recordConfigDeleted records the deletion of a tenant service configuration in the audit log
+func (s *ServiceRegistry) recordConfigDeleted(ctx context.Context, config *domain.ServiceConfig) {
+	if s.audit == nil {
+		return
+	}
+	s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionServiceConfigDeleted, domain.AuditTargetServiceConfig,
+		config.ServiceName, config.TenantID, domain.NewAuditSnapshot(config), nil))
+}
+
+// configDeletedEvent announces the deletion of a tenant service configuration
+func configDeletedEvent(config *domain.ServiceConfig) *domain.TenantEvent {
+	return domain.NewTenantEvent(domain.EventServiceConfigDeleted, config.TenantID,
+		domain.ServiceConfigPayload{ServiceName: config.ServiceName, Version: config.Version})
+}
+
+// CreateDefaultConfig creates or updates a default service configuration
+func (s *ServiceRegistry) CreateDefaultConfig(ctx context.Context, config *domain.DefaultServiceConfig) error {
+	var before domain.AuditSnapshot
+	if s.audit != nil {
+		existing, err := s.repo.GetDefaultConfig(ctx, config.ServiceName)
+		if err != nil {
+			return err
+		}
+		before = domain.NewAuditSnapshot(existing)
+	}
+
+	if err := s.repo.UpsertDefaultConfig(ctx, config); err != nil {
+		return err
+	}
+
+	s.InvalidateDefaultConfigCache(config.ServiceName)
+
+	if s.audit != nil {
+		s.audit.Record(ctx, domain.NewAuditEvent(domain.AuditActionDefaultServiceConfigSet, domain.AuditTargetDefaultServiceConfig,
+			config.ServiceName, "", before, domain.NewAuditSnapshot(config)))
+	}
+	return nil
+}
+
+// GetDefaultConfig gets the default configuration for a service
+func (s *ServiceRegistry) GetDefaultConfig(ctx context.Context, serviceName string) (*domain.DefaultServiceConfig, error) {
+	config, err := s.repo.GetDefaultConfig(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if config == nil {
+		return nil, domain.ErrServiceNotFound
+	}
+
+	return config, nil
+}
+
+// GetAllDefaultConfigs gets all default service configurations
+func (s *ServiceRegistry) GetAllDefaultConfigs(ctx context.Context) ([]*domain.DefaultServiceConfig, error) {
+	return s.repo.GetAllDefaultConfigs(ctx)
+}
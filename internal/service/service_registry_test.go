@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+)
+
+func TestSelectEndpointHealth(t *testing.T) {
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatalf("logger: %v", err)
+	}
+
+	const (
+		primary  = "http://primary:8080"
+		fallback = "http://fallback:8080"
+	)
+
+	tests := []struct {
+		name   string
+		probes map[string]int // Failed probes per endpoint
+		want   map[string]bool
+	}{
+		{
+			name: "healthy endpoints share the traffic",
+			want: map[string]bool{primary: true, fallback: true},
+		},
+		{
+			name:   "failures below the threshold keep the endpoint",
+			probes: map[string]int{primary: domain.DefaultFailThreshold - 1},
+			want:   map[string]bool{primary: true, fallback: true},
+		},
+		{
+			name:   "endpoint failing its probes is skipped",
+			probes: map[string]int{primary: domain.DefaultFailThreshold},
+			want:   map[string]bool{fallback: true},
+		},
+		{
+			name:   "all endpoints unhealthy falls through",
+			probes: map[string]int{primary: domain.DefaultFailThreshold, fallback: domain.DefaultFailThreshold},
+			want:   map[string]bool{"": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewServiceRegistry(nil, log)
+			config := &domain.ServiceConfig{
+				TenantID:            "t1",
+				ServiceName:         "user-service",
+				PrimaryEndpoint:     domain.ServiceEndpoint{URL: primary, IsActive: true},
+				FallbackChain:       []domain.ServiceEndpoint{{URL: fallback, Priority: 1, IsActive: true}},
+				LoadBalanceStrategy: domain.LoadBalanceRoundRobin,
+			}
+			for url, fails := range tt.probes {
+				for i := 0; i < fails; i++ {
+					registry.RecordHealthCheck(config.TenantID, config.ServiceName, url, 0, 0, errors.New("connection refused"))
+				}
+			}
+
+			got := make(map[string]bool)
+			for i := 0; i < 4; i++ {
+				url, _ := registry.selectEndpoint(config, false)
+				got[url] = true
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("selected %v, want %v", got, tt.want)
+			}
+			for url := range tt.want {
+				if !got[url] {
+					t.Errorf("selected %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSelectEndpointWithoutActiveEndpoints(t *testing.T) {
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatalf("logger: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		config *domain.ServiceConfig
+		want   string
+	}{
+		{
+			name: "inactive primary",
+			config: &domain.ServiceConfig{
+				PrimaryEndpoint:   domain.ServiceEndpoint{URL: "http://primary:8080"},
+				DefaultServiceURL: "http://default:8080",
+			},
+			want: "http://primary:8080",
+		},
+		{
+			name:   "default service URL",
+			config: &domain.ServiceConfig{DefaultServiceURL: "http://default:8080"},
+			want:   "http://default:8080",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewServiceRegistry(nil, log)
+			tt.config.TenantID = "t1"
+			tt.config.ServiceName = "user-service"
+			// Another request to the same URL is in flight and must stay counted
+			registry.AcquireConnection("t1", "user-service", tt.want)
+
+			url, _ := registry.selectEndpoint(tt.config, true)
+			if url != tt.want {
+				t.Fatalf("selected %q, want %q", url, tt.want)
+			}
+			if got := registry.GetActiveConnections("t1", "user-service", url); got != 2 {
+				t.Errorf("active connections after acquire = %d, want 2", got)
+			}
+			registry.ReleaseServiceURL(&domain.FallbackChainResult{TenantID: "t1", ServiceName: "user-service", ResolvedURL: url})
+			if got := registry.GetActiveConnections("t1", "user-service", url); got != 1 {
+				t.Errorf("active connections after release = %d, want 1", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vhvplatform/go-shared/errors"
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+// DefaultSettingsCacheTTL bounds how long the setting definitions are reused before they
+// are reloaded, so definitions registered through other instances are picked up
+const DefaultSettingsCacheTTL = 30 * time.Second
+
+// SettingsService manages the registry of tenant settings. Platform services register the
+// setting keys they read with a JSON Schema, a default and a visibility; tenant settings
+// are validated against the registry on write and resolved with the defaults on read.
+type SettingsService struct {
+	definitionRepo *repository.SettingDefinitionRepository
+	tenantRepo     *repository.TenantRepository
+	registry       *domain.SettingsRegistry
+	expiresAt      time.Time
+	cacheTTL       time.Duration
+	cacheMutex     sync.Mutex
+	logger         *logger.Logger
+}
+
+// NewSettingsService creates a new settings service
+func NewSettingsService(
+	definitionRepo *repository.SettingDefinitionRepository,
+	tenantRepo *repository.TenantRepository,
+	log *logger.Logger,
+) *SettingsService {
+	return &SettingsService{
+		definitionRepo: definitionRepo,
+		tenantRepo:     tenantRepo,
+		cacheTTL:       DefaultSettingsCacheTTL,
+		logger:         log,
+	}
+}
+
+// RegisterSetting registers or replaces the definition of a setting key. Keys may not
+// address a value inside another registered setting.
+func (s *SettingsService) RegisterSetting(ctx context.Context, key string, req *domain.RegisterSettingRequest) (*domain.SettingDefinition, error) {
+	definition := req.Definition(key)
+	if err := definition.Validate(); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	definitions, err := s.definitionRepo.List(ctx, "")
+	if err != nil {
+		s.logger.Error("Failed to list setting definitions", zap.Error(err))
+		return nil, errors.Internal("Failed to register setting")
+	}
+	for _, existing := range definitions {
+		if existing.Key != key && existing.Overlaps(key) {
+			return nil, errors.Conflict(fmt.Sprintf("Setting %s overlaps the registered setting %s", key, existing.Key))
+		}
+	}
+
+	if err := s.definitionRepo.Upsert(ctx, definition); err != nil {
+		s.logger.Error("Failed to save setting definition", zap.String("key", key), zap.Error(err))
+		return nil, errors.Internal("Failed to register setting")
+	}
+	s.invalidate()
+
+	s.logger.Info("Setting registered successfully",
+		zap.String("key", key),
+		zap.String("service", definition.Service),
+		zap.String("visibility", definition.Visibility),
+	)
+
+	return definition, nil
+}
+
+// GetSettingDefinition gets the definition of a setting key
+func (s *SettingsService) GetSettingDefinition(ctx context.Context, key string) (*domain.SettingDefinition, error) {
+	definition, err := s.definitionRepo.FindByKey(ctx, key)
+	if err != nil {
+		s.logger.Error("Failed to find setting definition", zap.String("key", key), zap.Error(err))
+		return nil, errors.Internal("Failed to get setting")
+	}
+	if definition == nil {
+		return nil, errors.NotFound("Setting not found")
+	}
+	return definition, nil
+}
+
+// ListSettingDefinitions lists the registered settings, optionally of a single service
+func (s *SettingsService) ListSettingDefinitions(ctx context.Context, service string) ([]*domain.SettingDefinition, error) {
+	definitions, err := s.definitionRepo.List(ctx, service)
+	if err != nil {
+		s.logger.Error("Failed to list setting definitions", zap.Error(err))
+		return nil, errors.Internal("Failed to list settings")
+	}
+	return definitions, nil
+}
+
+// DeleteSettingDefinition unregisters a setting key. Values tenants stored for it are kept
+// but can no longer be written.
+func (s *SettingsService) DeleteSettingDefinition(ctx context.Context, key string) error {
+	deleted, err := s.definitionRepo.Delete(ctx, key)
+	if err != nil {
+		s.logger.Error("Failed to delete setting definition", zap.String("key", key), zap.Error(err))
+		return errors.Internal("Failed to delete setting")
+	}
+	if !deleted {
+		return errors.NotFound("Setting not found")
+	}
+	s.invalidate()
+
+	s.logger.Info("Setting unregistered successfully", zap.String("key", key))
+	return nil
+}
+
+// ValidateSettings checks the settings changed by a write against the registry. Each
+// changed key is a dotted settings key, or empty when the settings were replaced as a whole.
+func (s *SettingsService) ValidateSettings(ctx context.Context, settings map[string]interface{}, changed []string) error {
+	if len(changed) == 0 {
+		return nil
+	}
+
+	registry, err := s.loadRegistry(ctx)
+	if err != nil {
+		return err
+	}
+	if err := registry.ValidateChanges(settings, changed); err != nil {
+		return errors.BadRequest(err.Error())
+	}
+	return nil
+}
+
+// GetEffectiveSettings resolves the settings of a tenant with the registered defaults,
+// keeping the settings the audience of the visibility may read
+func (s *SettingsService) GetEffectiveSettings(ctx context.Context, tenantID, visibility string) (*domain.EffectiveSettings, error) {
+	if !domain.IsValidSettingVisibility(visibility) {
+		return nil, errors.BadRequest("visibility must be public, admin or internal")
+	}
+
+	tenant, err := s.tenantRepo.FindByID(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to find tenant", zap.Error(err))
+		return nil, errors.Internal("Failed to get tenant settings")
+	}
+	if tenant == nil {
+		return nil, errors.NotFound("Tenant not found")
+	}
+
+	registry, err := s.loadRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return registry.Resolve(tenant.Settings, visibility), nil
+}
+
+// loadRegistry returns the cached registry, reloading it once it has expired
+func (s *SettingsService) loadRegistry(ctx context.Context) (*domain.SettingsRegistry, error) {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	if s.registry != nil && time.Now().Before(s.expiresAt) {
+		return s.registry, nil
+	}
+
+	definitions, err := s.definitionRepo.List(ctx, "")
+	if err != nil {
+		s.logger.Error("Failed to load setting definitions", zap.Error(err))
+		return nil, errors.Internal("Failed to load settings registry")
+	}
+	s.registry = domain.NewSettingsRegistry(definitions)
+	s.expiresAt = time.Now().Add(s.cacheTTL)
+	return s.registry, nil
+}
+
+// invalidate drops the cached registry after a definition changed
+func (s *SettingsService) invalidate() {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	s.registry = nil
+}
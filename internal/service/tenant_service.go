@@ -1,231 +1,1091 @@
-package service
-
-import (
-	"context"
-
-	"github.com/vhvplatform/go-shared/errors"
-	"github.com/vhvplatform/go-shared/logger"
-	"github.com/vhvplatform/go-tenant-service/internal/domain"
-	"github.com/vhvplatform/go-tenant-service/internal/repository"
-	"go.uber.org/zap"
-)
-
-// TenantService handles tenant business logic
-type TenantService struct {
-	tenantRepo     *repository.TenantRepository
-	tenantUserRepo *repository.TenantUserRepository
-	logger         *logger.Logger
-}
-
-// NewTenantService creates a new tenant service
-func NewTenantService(
-	tenantRepo *repository.TenantRepository,
-	tenantUserRepo *repository.TenantUserRepository,
-	log *logger.Logger,
-) *TenantService {
-	return &TenantService{
-		tenantRepo:     tenantRepo,
-		tenantUserRepo: tenantUserRepo,
-		logger:         log,
-	}
-}
-
-// CreateTenant creates a new tenant
-func (s *TenantService) CreateTenant(ctx context.Context, req *domain.CreateTenantRequest) (*domain.Tenant, error) {
-	// Check if tenant already exists
-	existingTenant, err := s.tenantRepo.FindByName(ctx, req.Name)
-	if err != nil {
-		s.logger.Error("Failed to check existing tenant", zap.Error(err))
-		return nil, errors.Internal("Failed to create tenant")
-	}
-	if existingTenant != nil {
-		return nil, errors.Conflict("Tenant already exists with this name")
-	}
-
-	// Check domain if provided
-	if req.Domain != "" {
-		existingDomain, err := s.tenantRepo.FindByDomain(ctx, req.Domain)
-		if err != nil {
-			s.logger.Error("Failed to check existing domain", zap.Error(err))
-			return nil, errors.Internal("Failed to create tenant")
-		}
-		if existingDomain != nil {
-			return nil, errors.Conflict("Tenant already exists with this domain")
-		}
-	}
-
-	// Create tenant
-	tenant := &domain.Tenant{
-		Name:             req.Name,
-		Domain:           req.Domain,
-		SubscriptionTier: req.SubscriptionTier,
-		AuthSettings:     domain.AuthSettings{AllowedLoginMethods: []string{"email"}}, // Default
-	}
-
-	if err := s.tenantRepo.Create(ctx, tenant); err != nil {
-		s.logger.Error("Failed to create tenant", zap.Error(err))
-		return nil, errors.Internal("Failed to create tenant")
-	}
-
-	s.logger.Info("Tenant created successfully",
-		zap.String("tenant_id", tenant.ID.Hex()),
-		zap.String("name", tenant.Name),
-	)
-
-	return tenant, nil
-}
-
-// GetTenant retrieves a tenant by ID
-func (s *TenantService) GetTenant(ctx context.Context, id string) (*domain.Tenant, error) {
-	tenant, err := s.tenantRepo.FindByID(ctx, id)
-	if err != nil {
-		s.logger.Error("Failed to get tenant", zap.String("tenant_id", id), zap.Error(err))
-		return nil, errors.Internal("Failed to get tenant")
-	}
-	if tenant == nil {
-		return nil, errors.NotFound("Tenant not found")
-	}
-	return tenant, nil
-}
-
-// ListTenants lists all tenants with pagination
-func (s *TenantService) ListTenants(ctx context.Context, page, pageSize int) ([]*domain.Tenant, int64, error) {
-	tenants, total, err := s.tenantRepo.List(ctx, page, pageSize)
-	if err != nil {
-		s.logger.Error("Failed to list tenants", zap.Error(err))
-		return nil, 0, errors.Internal("Failed to list tenants")
-	}
-	return tenants, total, nil
-}
-
-// UpdateTenant updates a tenant
-func (s *TenantService) UpdateTenant(ctx context.Context, id string, req *domain.UpdateTenantRequest) (*domain.Tenant, error) {
-	// Get existing tenant
-	tenant, err := s.tenantRepo.FindByID(ctx, id)
-	if err != nil {
-		s.logger.Error("Failed to find tenant", zap.Error(err))
-		return nil, errors.Internal("Failed to update tenant")
-	}
-	if tenant == nil {
-		return nil, errors.NotFound("Tenant not found")
-	}
-
-	// Update fields
-	if req.Name != "" {
-		tenant.Name = req.Name
-	}
-	if req.Domain != "" {
-		tenant.Domain = req.Domain
-	}
-	if req.SubscriptionTier != "" {
-		tenant.SubscriptionTier = req.SubscriptionTier
-	}
-
-	// Update new fields if provided in meta or settings
-	// For this task, we'll assume they can be passed in settings or a specialized request
-	if ds, ok := tenant.Settings["default_service"].(string); ok {
-		tenant.DefaultService = ds
-	}
-
-	if err := s.tenantRepo.Update(ctx, tenant); err != nil {
-		s.logger.Error("Failed to update tenant", zap.Error(err))
-		return nil, errors.Internal("Failed to update tenant")
-	}
-
-	s.logger.Info("Tenant updated successfully",
-		zap.String("tenant_id", tenant.ID.Hex()),
-	)
-
-	return tenant, nil
-}
-
-// DeleteTenant deletes a tenant
-func (s *TenantService) DeleteTenant(ctx context.Context, id string) error {
-	// Check if tenant exists
-	tenant, err := s.tenantRepo.FindByID(ctx, id)
-	if err != nil {
-		s.logger.Error("Failed to find tenant", zap.Error(err))
-		return errors.Internal("Failed to delete tenant")
-	}
-	if tenant == nil {
-		return errors.NotFound("Tenant not found")
-	}
-
-	if err := s.tenantRepo.Delete(ctx, id); err != nil {
-		s.logger.Error("Failed to delete tenant", zap.Error(err))
-		return errors.Internal("Failed to delete tenant")
-	}
-
-	s.logger.Info("Tenant deleted successfully",
-		zap.String("tenant_id", id),
-	)
-
-	return nil
-}
-
-// AddUserToTenant adds a user to a tenant
-func (s *TenantService) AddUserToTenant(ctx context.Context, tenantID, userID, role string) error {
-	// Check if tenant exists
-	tenant, err := s.tenantRepo.FindByID(ctx, tenantID)
-	if err != nil {
-		s.logger.Error("Failed to find tenant", zap.Error(err))
-		return errors.Internal("Failed to add user to tenant")
-	}
-	if tenant == nil {
-		return errors.NotFound("Tenant not found")
-	}
-
-	// Check if relationship already exists
-	existing, err := s.tenantUserRepo.FindByTenantAndUser(ctx, tenantID, userID)
-	if err != nil {
-		s.logger.Error("Failed to check existing relationship", zap.Error(err))
-		return errors.Internal("Failed to add user to tenant")
-	}
-	if existing != nil {
-		return errors.Conflict("User already belongs to this tenant")
-	}
-
-	// Add user to tenant
-	tenantUser := &domain.TenantUser{
-		TenantID: tenantID,
-		UserID:   userID,
-		Role:     role,
-	}
-
-	if err := s.tenantUserRepo.AddUser(ctx, tenantUser); err != nil {
-		s.logger.Error("Failed to add user to tenant", zap.Error(err))
-		return errors.Internal("Failed to add user to tenant")
-	}
-
-	s.logger.Info("User added to tenant successfully",
-		zap.String("tenant_id", tenantID),
-		zap.String("user_id", userID),
-	)
-
-	return nil
-}
-
-// RemoveUserFromTenant removes a user from a tenant
-func (s *TenantService) RemoveUserFromTenant(ctx context.Context, tenantID, userID string) error {
-	// Check if relationship exists
-	existing, err := s.tenantUserRepo.FindByTenantAndUser(ctx, tenantID, userID)
-	if err != nil {
-		s.logger.Error("Failed to check existing relationship", zap.Error(err))
-		return errors.Internal("Failed to remove user from tenant")
-	}
-	if existing == nil {
-		return errors.NotFound("User not found in tenant")
-	}
-
-	if err := s.tenantUserRepo.RemoveUser(ctx, tenantID, userID); err != nil {
-		s.logger.Error("Failed to remove user from tenant", zap.Error(err))
-		return errors.Internal("Failed to remove user from tenant")
-	}
-
-	s.logger.Info("User removed from tenant successfully",
-		zap.String("tenant_id", tenantID),
-		zap.String("user_id", userID),
-	)
-
-	return nil
-}
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vhvplatform/go-shared/errors"
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// TenantService handles tenant business logic
+type TenantService struct {
+	tenantRepo         *repository.TenantRepository
+	tenantUserRepo     *repository.TenantUserRepository
+	domainService      *DomainService
+	roleService        *RoleService
+	entitlementService *EntitlementService
+	settingsService    *SettingsService
+	auditService       *AuditService
+	outbox             *EventOutbox
+	logger             *logger.Logger
+}
+
+// NewTenantService creates a new tenant service
+func NewTenantService(
+	tenantRepo *repository.TenantRepository,
+	tenantUserRepo *repository.TenantUserRepository,
+	domainService *DomainService,
+	roleService *RoleService,
+	entitlementService *EntitlementService,
+	settingsService *SettingsService,
+	auditService *AuditService,
+	outbox *EventOutbox,
+	log *logger.Logger,
+) *TenantService {
+	return &TenantService{
+		tenantRepo:         tenantRepo,
+		tenantUserRepo:     tenantUserRepo,
+		domainService:      domainService,
+		roleService:        roleService,
+		entitlementService: entitlementService,
+		settingsService:    settingsService,
+		auditService:       auditService,
+		outbox:             outbox,
+		logger:             log,
+	}
+}
+
+// CreateTenant creates a new tenant. A requested domain is claimed as the primary
+// domain and only set on the tenant once it has been verified.
+func (s *TenantService) CreateTenant(ctx context.Context, req *domain.CreateTenantRequest) (*domain.Tenant, error) {
+	// Check if tenant already exists
+	existingTenant, err := s.tenantRepo.FindByName(ctx, req.Name)
+	if err != nil {
+		s.logger.Error("Failed to check existing tenant", zap.Error(err))
+		return nil, errors.Internal("Failed to create tenant")
+	}
+	if existingTenant != nil {
+		return nil, errors.Conflict("Tenant already exists with this name")
+	}
+
+	// Check domain if provided
+	if req.Domain != "" {
+		if err := s.domainService.CheckDomainAvailable(ctx, req.Domain, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	// Check subdomain if provided
+	if req.Subdomain != "" {
+		if err := s.checkSubdomainAvailable(ctx, req.Subdomain, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	tier := req.SubscriptionTier
+	if tier == "" {
+		tier = domain.SubscriptionFree
+	}
+	if !domain.IsValidSubscriptionTier(tier) {
+		return nil, errors.BadRequest("Unknown subscription tier")
+	}
+	// Rejected here rather than by the claim, which is only made once the tenant exists
+	if req.Domain != "" && !domain.TierEntitlements(tier).CustomDomains {
+		return nil, errors.BadRequest(fmt.Sprintf("Custom domains are not available on the %s tier", tier))
+	}
+
+	config := domain.DefaultTenantConfig()
+	if req.ParentID != "" {
+		if err := s.checkParent(ctx, nil, req.ParentID); err != nil {
+			return nil, err
+		}
+		// Sub-tenants inherit login identifiers from their parent until they set their own
+		config.AllowedLoginIdentifiers = nil
+	}
+
+	// Create tenant
+	tenant := &domain.Tenant{
+		Name:             req.Name,
+		Subdomain:        req.Subdomain,
+		ParentID:         req.ParentID,
+		SubscriptionTier: tier,
+		AuthSettings:     domain.DefaultAuthSettings(),
+		Config:           config,
+	}
+
+	err = s.outbox.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.tenantRepo.Create(ctx, tenant); err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, domain.NewTenantEvent(domain.EventTenantCreated, tenant.ID.Hex(),
+			domain.TenantChangedPayload{Tenant: domain.NewEventTenant(tenant)}))
+	})
+	if err != nil {
+		s.logger.Error("Failed to create tenant", zap.Error(err))
+		return nil, errors.Internal("Failed to create tenant")
+	}
+
+	s.auditService.Record(ctx, domain.NewAuditEvent(domain.AuditActionTenantCreated, domain.AuditTargetTenant,
+		tenant.ID.Hex(), tenant.ID.Hex(), nil, domain.NewAuditSnapshot(tenant)))
+
+	s.logger.Info("Tenant created successfully",
+		zap.String("tenant_id", tenant.ID.Hex()),
+		zap.String("name", tenant.Name),
+	)
+
+	if req.Domain != "" {
+		claimReq := &domain.ClaimDomainRequest{Domain: req.Domain, IsPrimary: true}
+		if _, err := s.domainService.ClaimDomain(ctx, tenant.ID.Hex(), claimReq); err != nil {
+			// The domain and tier were checked above, so only storage errors remain; the tenant
+			// exists and the domain can be claimed again later
+			s.logger.Error("Failed to claim tenant domain",
+				zap.String("tenant_id", tenant.ID.Hex()),
+				zap.String("domain", req.Domain),
+				zap.Error(err))
+		}
+	}
+
+	// Provisioning is complete once the tenant and its defaults are stored
+	provisioned := domain.TenantStatusChange{To: domain.TenantStatusActive, Reason: "Tenant provisioned", Actor: domain.SystemActor}
+	if _, err := s.changeStatus(ctx, tenant, provisioned); err != nil {
+		return nil, err
+	}
+
+	return tenant, nil
+}
+
+// GetTenant retrieves a tenant by ID
+func (s *TenantService) GetTenant(ctx context.Context, id string) (*domain.Tenant, error) {
+	tenant, err := s.tenantRepo.FindByID(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to get tenant", zap.String("tenant_id", id), zap.Error(err))
+		return nil, errors.Internal("Failed to get tenant")
+	}
+	if tenant == nil {
+		return nil, errors.NotFound("Tenant not found")
+	}
+	return tenant, nil
+}
+
+// ListTenants lists a page of the tenants matching a filter
+func (s *TenantService) ListTenants(ctx context.Context, filter domain.TenantFilter, page domain.PageRequest) ([]*domain.Tenant, *domain.PageInfo, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, nil, errors.BadRequest(err.Error())
+	}
+	if err := checkPageRequest(page); err != nil {
+		return nil, nil, err
+	}
+
+	tenants, info, err := s.tenantRepo.List(ctx, filter, page)
+	if err != nil {
+		if domain.IsInvalidPageToken(err) {
+			return nil, nil, errors.BadRequest("Invalid page token")
+		}
+		s.logger.Error("Failed to list tenants", zap.Error(err))
+		return nil, nil, errors.Internal("Failed to list tenants")
+	}
+	return tenants, info, nil
+}
+
+// checkPageRequest rejects page sizes above the maximum instead of silently shrinking them
+func checkPageRequest(page domain.PageRequest) error {
+	if page.PageSize > domain.MaxPageSize {
+		return errors.BadRequest(fmt.Sprintf("Page size must be at most %d", domain.MaxPageSize))
+	}
+	return nil
+}
+
+// UpdateTenant updates a tenant. Empty fields are left unchanged; use PatchTenant to clear
+// fields. A new domain is claimed as the primary domain and replaces the current one once
+// it has been verified.
+func (s *TenantService) UpdateTenant(ctx context.Context, id string, req *domain.UpdateTenantRequest) (*domain.Tenant, error) {
+	tenant, err := s.findTenantForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.SubscriptionTier != "" && req.SubscriptionTier != tenant.SubscriptionTier {
+		// Tier changes are checked against the target tier limits by PlanService.ChangePlan
+		return nil, errors.BadRequest("Subscription tier can only be changed through a plan change")
+	}
+
+	patch := &domain.TenantPatch{
+		Name:            req.Name,
+		Domain:          req.Domain,
+		Subdomain:       req.Subdomain,
+		ExpectedVersion: req.ExpectedVersion,
+	}
+	if req.Name != "" {
+		patch.Paths = append(patch.Paths, domain.TenantFieldName)
+	}
+	if req.Domain != "" {
+		patch.Paths = append(patch.Paths, domain.TenantFieldDomain)
+	}
+	if req.Subdomain != "" {
+		patch.Paths = append(patch.Paths, domain.TenantFieldSubdomain)
+	}
+	if err := patch.Validate(); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	return s.applyPatch(ctx, tenant, patch)
+}
+
+// PatchTenant applies a partial update to a tenant. Only the fields named by the patch
+// change, and named fields without a value are cleared.
+func (s *TenantService) PatchTenant(ctx context.Context, id string, patch *domain.TenantPatch) (*domain.Tenant, error) {
+	if err := patch.Validate(); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	tenant, err := s.findTenantForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.applyPatch(ctx, tenant, patch)
+}
+
+// findTenantForUpdate loads the tenant an update applies to
+func (s *TenantService) findTenantForUpdate(ctx context.Context, id string) (*domain.Tenant, error) {
+	tenant, err := s.tenantRepo.FindByID(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to find tenant", zap.Error(err))
+		return nil, errors.Internal("Failed to update tenant")
+	}
+	if tenant == nil {
+		return nil, errors.NotFound("Tenant not found")
+	}
+	return tenant, nil
+}
+
+// applyPatch checks a validated patch against other tenants and saves it. The tenant is
+// only saved at the version it was read at, so concurrent edits are not lost. A new domain
+// is claimed in the same transaction, after everything else has been checked.
+func (s *TenantService) applyPatch(ctx context.Context, tenant *domain.Tenant, patch *domain.TenantPatch) (*domain.Tenant, error) {
+	id := tenant.ID.Hex()
+	if patch.ExpectedVersion > 0 && patch.ExpectedVersion != tenant.Version {
+		return nil, errors.Conflict(domain.TenantModifiedMessage)
+	}
+	if len(patch.Paths) == 0 {
+		return tenant, nil
+	}
+	before := domain.NewAuditSnapshot(tenant)
+
+	if patch.Has(domain.TenantFieldName) && patch.Name != tenant.Name {
+		existing, err := s.tenantRepo.FindByName(ctx, patch.Name)
+		if err != nil {
+			s.logger.Error("Failed to check existing tenant", zap.Error(err))
+			return nil, errors.Internal("Failed to update tenant")
+		}
+		if existing != nil && existing.ID != tenant.ID {
+			return nil, errors.Conflict("Tenant already exists with this name")
+		}
+	}
+	if patch.Has(domain.TenantFieldSubdomain) && patch.Subdomain != "" && patch.Subdomain != tenant.Subdomain {
+		if err := s.checkSubdomainAvailable(ctx, patch.Subdomain, id); err != nil {
+			return nil, err
+		}
+	}
+
+	clearDomain := patch.Has(domain.TenantFieldDomain) && patch.Domain == ""
+	claimDomain := patch.Has(domain.TenantFieldDomain) && !clearDomain && domain.NormalizeHost(patch.Domain) != tenant.Domain
+	if claimDomain {
+		if err := s.domainService.CheckDomainClaimable(ctx, tenant, patch.Domain); err != nil {
+			return nil, err
+		}
+	}
+
+	patch.Apply(tenant)
+	if err := s.settingsService.ValidateSettings(ctx, tenant.Settings, patch.SettingsKeys()); err != nil {
+		return nil, err
+	}
+
+	audit := domain.NewAuditEvent(domain.AuditActionTenantUpdated, domain.AuditTargetTenant,
+		id, id, before, domain.NewAuditSnapshot(tenant))
+
+	version := tenant.Version
+	var updated bool
+	var claim *domain.TenantDomain
+	var domainErr error
+	err := s.outbox.Transaction(ctx, func(ctx context.Context) error {
+		// A retried transaction saves the tenant at the version it was read at again
+		tenant.Version = version
+		claim, domainErr = nil, nil
+		var err error
+		if updated, err = s.tenantRepo.Update(ctx, tenant); err != nil || !updated {
+			return err
+		}
+
+		saved := *tenant
+		switch {
+		case clearDomain:
+			if domainErr = s.domainService.ClearPrimaryDomain(ctx, id); domainErr != nil {
+				return domainErr
+			}
+		case claimDomain:
+			claim, domainErr = s.domainService.ClaimDomain(ctx, id, &domain.ClaimDomainRequest{Domain: patch.Domain, IsPrimary: true})
+			if domainErr != nil {
+				return domainErr
+			}
+			if claim.Status == domain.DomainStatusVerified {
+				// Promoting a verified domain saves it on the tenant
+				saved.Domain = claim.Domain
+			}
+		}
+
+		return s.outbox.Add(ctx, domain.NewTenantEvent(domain.EventTenantUpdated, id,
+			domain.TenantChangedPayload{Tenant: domain.NewEventTenant(&saved), Changed: audit.ChangedFields()}))
+	})
+	if domainErr != nil {
+		return nil, domainErr
+	}
+	if err != nil {
+		s.logger.Error("Failed to update tenant", zap.Error(err))
+		return nil, errors.Internal("Failed to update tenant")
+	}
+	if !updated {
+		return nil, errors.Conflict(domain.TenantModifiedMessage)
+	}
+
+	if claim != nil && claim.Status == domain.DomainStatusVerified {
+		// Reload the domain and version saved by promoting the claim
+		if tenant, err = s.findTenantForUpdate(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	s.auditService.Record(ctx, audit)
+
+	s.logger.Info("Tenant updated successfully",
+		zap.String("tenant_id", id),
+		zap.Strings("fields", patch.Paths),
+	)
+
+	return tenant, nil
+}
+
+// checkSubdomainAvailable validates a subdomain and ensures no other tenant holds it
+func (s *TenantService) checkSubdomainAvailable(ctx context.Context, subdomain, tenantID string) error {
+	if !domain.IsValidSubdomain(subdomain) {
+		return errors.BadRequest(domain.ErrInvalidSubdomain.Error())
+	}
+
+	existing, err := s.tenantRepo.FindBySubdomain(ctx, subdomain)
+	if err != nil {
+		s.logger.Error("Failed to check existing subdomain", zap.Error(err))
+		return errors.Internal("Failed to check subdomain")
+	}
+	if existing != nil && existing.ID.Hex() != tenantID {
+		return errors.Conflict("Tenant already exists with this subdomain")
+	}
+	return nil
+}
+
+// SuspendTenant suspends an active tenant, e.g. for non-payment
+func (s *TenantService) SuspendTenant(ctx context.Context, id string, req *domain.TenantStatusRequest) (*domain.Tenant, error) {
+	return s.transitionTenant(ctx, id, domain.TenantStatusSuspended, req)
+}
+
+// ReactivateTenant returns a suspended tenant to active
+func (s *TenantService) ReactivateTenant(ctx context.Context, id string, req *domain.TenantStatusRequest) (*domain.Tenant, error) {
+	return s.transitionTenant(ctx, id, domain.TenantStatusActive, req)
+}
+
+// transitionTenant loads a tenant and moves it to a new lifecycle status
+func (s *TenantService) transitionTenant(ctx context.Context, id, status string, req *domain.TenantStatusRequest) (*domain.Tenant, error) {
+	if req.Reason == "" {
+		return nil, errors.BadRequest("Reason is required")
+	}
+
+	tenant, err := s.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	change := domain.TenantStatusChange{To: status, Reason: req.Reason, Actor: domain.ActorFromContext(ctx).ID}
+	return s.changeStatus(ctx, tenant, change)
+}
+
+// changeStatus applies a lifecycle transition from the current status of a tenant,
+// rejecting transitions the state machine does not allow
+func (s *TenantService) changeStatus(ctx context.Context, tenant *domain.Tenant, change domain.TenantStatusChange) (*domain.Tenant, error) {
+	change.From = tenant.CurrentStatus()
+	if !domain.CanTransitionTenantStatus(change.From, change.To) {
+		return nil, errors.Conflict(fmt.Sprintf("Cannot change tenant status from %s to %s", change.From, change.To))
+	}
+	change.ChangedAt = time.Now()
+	before := domain.NewAuditSnapshot(tenant)
+
+	var updated bool
+	err := s.outbox.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if updated, err = s.tenantRepo.UpdateStatus(ctx, tenant.ID.Hex(), change); err != nil || !updated {
+			return err
+		}
+		return s.outbox.Add(ctx, domain.NewTenantEvent(domain.EventTenantStatusChanged, tenant.ID.Hex(),
+			domain.TenantStatusChangedPayload{From: change.From, To: change.To, Reason: change.Reason}))
+	})
+	if err != nil {
+		s.logger.Error("Failed to update tenant status", zap.Error(err))
+		return nil, errors.Internal("Failed to update tenant status")
+	}
+	if !updated {
+		return nil, errors.Conflict("Tenant status was changed concurrently")
+	}
+
+	tenant.Status = change.To
+	tenant.StatusReason = change.Reason
+	tenant.StatusChangedAt = change.ChangedAt
+	tenant.StatusHistory = append(tenant.StatusHistory, change)
+	tenant.IsActive = change.To == domain.TenantStatusActive
+	tenant.PurgeAfter = change.PurgeAfter
+	tenant.UpdatedAt = change.ChangedAt
+
+	event := domain.NewAuditEvent(domain.AuditActionTenantStatusChanged, domain.AuditTargetTenant,
+		tenant.ID.Hex(), tenant.ID.Hex(), before, domain.NewAuditSnapshot(tenant))
+	event.Reason = change.Reason
+	s.auditService.Record(ctx, event)
+
+	s.logger.Info("Tenant status changed successfully",
+		zap.String("tenant_id", tenant.ID.Hex()),
+		zap.String("from", change.From),
+		zap.String("to", change.To),
+		zap.String("actor", change.Actor),
+		zap.String("reason", change.Reason),
+	)
+
+	return tenant, nil
+}
+
+// GetTenantConfig retrieves the configuration of a tenant as stored, for editing.
+// Settings a sub-tenant leaves unset are empty; see GetEffectiveTenantConfig.
+func (s *TenantService) GetTenantConfig(ctx context.Context, id string) (*domain.TenantConfig, error) {
+	tenant, err := s.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	config := tenant.Config
+	return &config, nil
+}
+
+// GetEffectiveTenantConfig retrieves the configuration that applies to a tenant. Sub-tenants
+// inherit the settings they leave unset from their parent tenants.
+func (s *TenantService) GetEffectiveTenantConfig(ctx context.Context, id string) (*domain.TenantConfig, error) {
+	tenant, err := s.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.effectiveConfig(ctx, tenant)
+}
+
+// effectiveConfig applies the configuration inherited from the parent tenants of a tenant
+func (s *TenantService) effectiveConfig(ctx context.Context, tenant *domain.Tenant) (*domain.TenantConfig, error) {
+	config := tenant.Config
+	if !tenant.IsSubTenant() {
+		return &config, nil
+	}
+
+	ancestors, err := s.tenantRepo.FindAncestors(ctx, tenant, domain.MaxTenantDepth)
+	if err != nil {
+		s.logger.Error("Failed to find parent tenants", zap.String("tenant_id", tenant.ID.Hex()), zap.Error(err))
+		return nil, errors.Internal("Failed to get tenant config")
+	}
+	for _, ancestor := range ancestors {
+		config = config.Inherit(ancestor.Config)
+	}
+	return &config, nil
+}
+
+// UpdateTenantConfig validates and replaces the configuration of a tenant, returning it as stored.
+// Sub-tenants may leave settings unset to inherit them, so their effective configuration is validated.
+// A non-zero expected version rejects the update unless the tenant is at that version.
+func (s *TenantService) UpdateTenantConfig(ctx context.Context, id string, config *domain.TenantConfig, expectedVersion int64) (*domain.TenantConfig, error) {
+	tenant, err := s.tenantRepo.FindByID(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to find tenant", zap.Error(err))
+		return nil, errors.Internal("Failed to update tenant config")
+	}
+	if tenant == nil {
+		return nil, errors.NotFound("Tenant not found")
+	}
+	if expectedVersion > 0 && expectedVersion != tenant.Version {
+		return nil, errors.Conflict(domain.TenantModifiedMessage)
+	}
+
+	before := domain.NewAuditSnapshot(tenant)
+	tenant.Config = *config
+	effective, err := s.effectiveConfig(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	if err := effective.Validate(); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	audit := domain.NewAuditEvent(domain.AuditActionTenantConfigUpdated, domain.AuditTargetTenant,
+		id, id, before, domain.NewAuditSnapshot(tenant))
+
+	// The config was validated against the tenant as read, so only apply it at that version
+	var updated bool
+	err = s.outbox.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if updated, err = s.tenantRepo.UpdateConfig(ctx, id, config, tenant.Version); err != nil || !updated {
+			return err
+		}
+		eventTenant := domain.NewEventTenant(tenant)
+		eventTenant.Version++
+		return s.outbox.Add(ctx, domain.NewTenantEvent(domain.EventTenantConfigUpdated, id,
+			domain.TenantChangedPayload{Tenant: eventTenant, Changed: audit.ChangedFields()}))
+	})
+	if err != nil {
+		s.logger.Error("Failed to update tenant config", zap.Error(err))
+		return nil, errors.Internal("Failed to update tenant config")
+	}
+	if !updated {
+		return nil, errors.Conflict(domain.TenantModifiedMessage)
+	}
+
+	s.auditService.Record(ctx, audit)
+
+	s.logger.Info("Tenant config updated successfully",
+		zap.String("tenant_id", id),
+	)
+
+	return config, nil
+}
+
+// GetDefaultService resolves the default service of a tenant and its fallback targets
+func (s *TenantService) GetDefaultService(ctx context.Context, id string) (*domain.DefaultServiceInfo, error) {
+	tenant, err := s.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := s.effectiveConfig(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &domain.DefaultServiceInfo{
+		DefaultServiceURL: config.DefaultServiceURL,
+		FallbackURLs:      make([]string, 0, len(config.FallbackChain)),
+	}
+	if info.DefaultServiceURL == "" && tenant.DefaultService != "" {
+		info.DefaultServiceURL = config.ResolveService(tenant.DefaultService)
+	}
+
+	for _, serviceName := range config.FallbackChain {
+		info.FallbackURLs = append(info.FallbackURLs, config.ResolveService(serviceName))
+	}
+
+	return info, nil
+}
+
+// ListChildren lists the direct sub-tenants of a tenant
+func (s *TenantService) ListChildren(ctx context.Context, id string, page, pageSize int) ([]*domain.Tenant, int64, error) {
+	if err := checkPageRequest(domain.PageRequest{Page: page, PageSize: pageSize}); err != nil {
+		return nil, 0, err
+	}
+	if _, err := s.GetTenant(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	tenants, total, err := s.tenantRepo.ListChildren(ctx, id, page, pageSize)
+	if err != nil {
+		s.logger.Error("Failed to list child tenants", zap.String("tenant_id", id), zap.Error(err))
+		return nil, 0, errors.Internal("Failed to list child tenants")
+	}
+	return tenants, total, nil
+}
+
+// GetAncestors returns the parent tenants of a tenant, nearest first
+func (s *TenantService) GetAncestors(ctx context.Context, id string) ([]*domain.Tenant, error) {
+	tenant, err := s.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors, err := s.tenantRepo.FindAncestors(ctx, tenant, domain.MaxTenantDepth)
+	if err != nil {
+		s.logger.Error("Failed to find parent tenants", zap.String("tenant_id", id), zap.Error(err))
+		return nil, errors.Internal("Failed to get parent tenants")
+	}
+	return ancestors, nil
+}
+
+// SetParent moves a tenant under a parent tenant, or to the top level when the parent is empty.
+// Tenants being deleted cannot be moved, and a non-zero expected version rejects the move
+// unless the tenant is at that version.
+func (s *TenantService) SetParent(ctx context.Context, id string, req *domain.SetParentRequest) (*domain.Tenant, error) {
+	tenant, err := s.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpectedVersion > 0 && req.ExpectedVersion != tenant.Version {
+		return nil, errors.Conflict(domain.TenantModifiedMessage)
+	}
+	if status := tenant.CurrentStatus(); status == domain.TenantStatusPendingDeletion || status == domain.TenantStatusDeleted {
+		return nil, errors.Conflict("Tenant is being deleted")
+	}
+	if req.ParentID == tenant.ParentID {
+		return tenant, nil
+	}
+	if req.ParentID != "" {
+		if err := s.checkParent(ctx, tenant, req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	before := domain.NewAuditSnapshot(tenant)
+	tenant.ParentID = req.ParentID
+	audit := domain.NewAuditEvent(domain.AuditActionTenantParentChanged, domain.AuditTargetTenant,
+		id, id, before, domain.NewAuditSnapshot(tenant))
+
+	// The parent was checked against the tenant as read, so only move it at that version
+	var updated bool
+	err = s.outbox.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if updated, err = s.tenantRepo.UpdateParent(ctx, id, req.ParentID, tenant.Version); err != nil || !updated {
+			return err
+		}
+		eventTenant := domain.NewEventTenant(tenant)
+		eventTenant.Version++
+		return s.outbox.Add(ctx, domain.NewTenantEvent(domain.EventTenantParentChanged, id,
+			domain.TenantChangedPayload{Tenant: eventTenant, Changed: audit.ChangedFields()}))
+	})
+	if err != nil {
+		s.logger.Error("Failed to update tenant parent", zap.Error(err))
+		return nil, errors.Internal("Failed to update tenant parent")
+	}
+	if !updated {
+		return nil, errors.Conflict(domain.TenantModifiedMessage)
+	}
+	tenant.Version++
+
+	s.auditService.Record(ctx, audit)
+
+	s.logger.Info("Tenant parent updated successfully",
+		zap.String("tenant_id", id),
+		zap.String("parent_id", req.ParentID),
+	)
+
+	return tenant, nil
+}
+
+// checkParent ensures a tenant may be placed under a parent: the parent must exist, not be
+// deleted, and include sub-tenants in its entitlements, and the move must neither create a
+// cycle nor nest the sub-tree of the tenant deeper than MaxTenantDepth. The tenant is nil
+// when it is being created.
+func (s *TenantService) checkParent(ctx context.Context, tenant *domain.Tenant, parentID string) error {
+	if !primitive.IsValidObjectID(parentID) {
+		return errors.BadRequest("Invalid parent tenant ID")
+	}
+	parent, err := s.tenantRepo.FindByID(ctx, parentID)
+	if err != nil {
+		s.logger.Error("Failed to find parent tenant", zap.String("parent_id", parentID), zap.Error(err))
+		return errors.Internal("Failed to check parent tenant")
+	}
+	if parent == nil {
+		return errors.BadRequest("Parent tenant not found")
+	}
+	if status := parent.CurrentStatus(); status == domain.TenantStatusPendingDeletion || status == domain.TenantStatusDeleted {
+		return errors.Conflict("Parent tenant is being deleted")
+	}
+	if !parent.Entitlements().HasFeature(domain.FeatureSubTenants) {
+		return errors.Conflict(fmt.Sprintf("Sub-tenants are not available on the %s tier", tierName(parent)))
+	}
+
+	ancestors, err := s.tenantRepo.FindAncestors(ctx, parent, domain.MaxTenantDepth)
+	if err != nil {
+		s.logger.Error("Failed to find parent tenants", zap.String("parent_id", parentID), zap.Error(err))
+		return errors.Internal("Failed to check parent tenant")
+	}
+	depth := len(ancestors) + 1
+
+	if tenant != nil {
+		id := tenant.ID.Hex()
+		if parentID == id {
+			return errors.BadRequest("A tenant cannot be its own parent")
+		}
+		for _, ancestor := range ancestors {
+			if ancestor.ID.Hex() == id {
+				return errors.BadRequest("Parent tenant is a sub-tenant of this tenant")
+			}
+		}
+
+		height, err := s.subtreeHeight(ctx, id)
+		if err != nil {
+			s.logger.Error("Failed to find child tenants", zap.String("tenant_id", id), zap.Error(err))
+			return errors.Internal("Failed to check parent tenant")
+		}
+		depth += height
+	}
+
+	if depth > domain.MaxTenantDepth {
+		return errors.BadRequest(fmt.Sprintf("Tenants can be nested at most %d levels deep", domain.MaxTenantDepth))
+	}
+	return nil
+}
+
+// subtreeHeight returns how many levels of sub-tenants are below a tenant, up to one past MaxTenantDepth
+func (s *TenantService) subtreeHeight(ctx context.Context, id string) (int, error) {
+	height := 0
+	level := []string{id}
+	for height <= domain.MaxTenantDepth {
+		children, err := s.tenantRepo.FindChildIDs(ctx, level)
+		if err != nil {
+			return 0, err
+		}
+		if len(children) == 0 {
+			break
+		}
+		height++
+		level = children
+	}
+	return height, nil
+}
+
+// AddUserToTenant adds a user to a tenant with a built-in or custom role of the tenant.
+// Users added without a role become members. Only active tenants accept members.
+func (s *TenantService) AddUserToTenant(ctx context.Context, tenantID, userID, role string) error {
+	// Check if tenant exists
+	tenant, err := s.tenantRepo.FindByID(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to find tenant", zap.Error(err))
+		return errors.Internal("Failed to add user to tenant")
+	}
+	if tenant == nil {
+		return errors.NotFound("Tenant not found")
+	}
+	if tenant.CurrentStatus() != domain.TenantStatusActive {
+		return errors.Conflict("Only active tenants can accept members")
+	}
+
+	if role == "" {
+		role = domain.RoleMember
+	}
+	if err := s.roleService.ValidateRole(ctx, tenantID, role); err != nil {
+		return err
+	}
+
+	// Check if relationship already exists
+	existing, err := s.tenantUserRepo.FindByTenantAndUser(ctx, tenantID, userID)
+	if err != nil {
+		s.logger.Error("Failed to check existing relationship", zap.Error(err))
+		return errors.Internal("Failed to add user to tenant")
+	}
+	if existing != nil {
+		return errors.Conflict("User already belongs to this tenant")
+	}
+
+	if err := s.entitlementService.CheckMemberLimit(ctx, tenant); err != nil {
+		return err
+	}
+
+	// Add user to tenant
+	tenantUser := &domain.TenantUser{
+		TenantID: tenantID,
+		UserID:   userID,
+		Role:     role,
+	}
+
+	err = s.outbox.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.tenantUserRepo.AddUser(ctx, tenantUser); err != nil {
+			return err
+		}
+		if role == domain.RoleOwner {
+			if err := s.tenantRepo.AddOwners(ctx, tenantID, 1); err != nil {
+				return err
+			}
+		}
+		return s.outbox.Add(ctx, domain.NewTenantEvent(domain.EventMemberAdded, tenantID,
+			domain.MemberPayload{UserID: userID, Role: role}))
+	})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.Conflict("User already belongs to this tenant")
+		}
+		s.logger.Error("Failed to add user to tenant", zap.Error(err))
+		return errors.Internal("Failed to add user to tenant")
+	}
+
+	s.auditService.Record(ctx, domain.NewAuditEvent(domain.AuditActionMemberAdded, domain.AuditTargetMember,
+		userID, tenantID, nil, domain.NewAuditSnapshot(tenantUser)))
+
+	s.logger.Info("User added to tenant successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("user_id", userID),
+	)
+
+	return nil
+}
+
+// RemoveUserFromTenant removes a user from a tenant
+func (s *TenantService) RemoveUserFromTenant(ctx context.Context, tenantID, userID string) error {
+	// Check if relationship exists
+	existing, err := s.tenantUserRepo.FindByTenantAndUser(ctx, tenantID, userID)
+	if err != nil {
+		s.logger.Error("Failed to check existing relationship", zap.Error(err))
+		return errors.Internal("Failed to remove user from tenant")
+	}
+	if existing == nil {
+		return errors.NotFound("User not found in tenant")
+	}
+	removesOwner := existing.Role == domain.RoleOwner
+
+	var removed, released, lastOwner bool
+	err = s.outbox.Transaction(ctx, func(ctx context.Context) error {
+		removed, released, lastOwner = false, false, false
+		var err error
+		if removed, err = s.tenantUserRepo.RemoveUser(ctx, tenantID, userID, existing.Role); err != nil || !removed {
+			return err
+		}
+		if removesOwner {
+			if released, err = s.tenantRepo.ReleaseOwner(ctx, tenantID); err != nil {
+				return err
+			}
+			if lastOwner = !released; lastOwner {
+				return errors.Conflict("A tenant must keep at least one owner")
+			}
+		}
+		return s.outbox.Add(ctx, domain.NewTenantEvent(domain.EventMemberRemoved, tenantID,
+			domain.MemberPayload{UserID: userID, Role: existing.Role}))
+	})
+	if err != nil && removed && removesOwner && !released {
+		// Without transactions the removal has been saved and must be undone
+		if restoreErr := s.tenantUserRepo.RestoreUser(ctx, tenantID, userID, domain.RoleOwner); restoreErr != nil {
+			s.logger.Error("Failed to restore owner", zap.Error(restoreErr))
+		}
+	}
+	if lastOwner {
+		return errors.Conflict("A tenant must keep at least one owner; transfer ownership first")
+	}
+	if err != nil {
+		s.logger.Error("Failed to remove user from tenant", zap.Error(err))
+		return errors.Internal("Failed to remove user from tenant")
+	}
+	if !removed {
+		return errors.Conflict("Membership was changed concurrently; retry the request")
+	}
+
+	s.auditService.Record(ctx, domain.NewAuditEvent(domain.AuditActionMemberRemoved, domain.AuditTargetMember,
+		userID, tenantID, domain.NewAuditSnapshot(existing), nil))
+
+	s.logger.Info("User removed from tenant successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("user_id", userID),
+	)
+
+	return nil
+}
+
+// ListMembers lists a page of the members of a tenant
+func (s *TenantService) ListMembers(ctx context.Context, tenantID string, filter domain.MemberFilter, page domain.PageRequest) ([]*domain.TenantUser, *domain.PageInfo, error) {
+	if err := checkPageRequest(page); err != nil {
+		return nil, nil, err
+	}
+	if _, err := s.GetTenant(ctx, tenantID); err != nil {
+		return nil, nil, err
+	}
+
+	members, info, err := s.tenantUserRepo.ListUsersByTenant(ctx, tenantID, filter, page)
+	if err != nil {
+		if domain.IsInvalidPageToken(err) {
+			return nil, nil, errors.BadRequest("Invalid page token")
+		}
+		s.logger.Error("Failed to list tenant members", zap.String("tenant_id", tenantID), zap.Error(err))
+		return nil, nil, errors.Internal("Failed to list tenant members")
+	}
+	return members, info, nil
+}
+
+// ListUserTenants lists a page of the tenants a user belongs to
+func (s *TenantService) ListUserTenants(ctx context.Context, userID string, filter domain.MemberFilter, page domain.PageRequest) ([]*domain.UserTenant, *domain.PageInfo, error) {
+	if err := checkPageRequest(page); err != nil {
+		return nil, nil, err
+	}
+
+	memberships, info, err := s.tenantUserRepo.ListTenantsByUser(ctx, userID, filter, page)
+	if err != nil {
+		if domain.IsInvalidPageToken(err) {
+			return nil, nil, errors.BadRequest("Invalid page token")
+		}
+		s.logger.Error("Failed to list user tenants", zap.String("user_id", userID), zap.Error(err))
+		return nil, nil, errors.Internal("Failed to list user tenants")
+	}
+
+	tenantIDs := make([]string, len(memberships))
+	for i, membership := range memberships {
+		tenantIDs[i] = membership.TenantID
+	}
+	tenants, err := s.tenantRepo.FindByIDs(ctx, tenantIDs)
+	if err != nil {
+		s.logger.Error("Failed to find user tenants", zap.String("user_id", userID), zap.Error(err))
+		return nil, nil, errors.Internal("Failed to list user tenants")
+	}
+	tenantsByID := make(map[string]*domain.Tenant, len(tenants))
+	for _, tenant := range tenants {
+		tenantsByID[tenant.ID.Hex()] = tenant
+	}
+
+	userTenants := make([]*domain.UserTenant, len(memberships))
+	for i, membership := range memberships {
+		userTenants[i] = &domain.UserTenant{
+			TenantID: membership.TenantID,
+			Role:     membership.Role,
+			JoinedAt: membership.CreatedAt,
+			Tenant:   tenantsByID[membership.TenantID],
+		}
+	}
+	return userTenants, info, nil
+}
+
+// GetMemberHistory returns the membership record of a user in a tenant with its
+// history of joins, departures and role changes, including after removal
+func (s *TenantService) GetMemberHistory(ctx context.Context, tenantID, userID string) (*domain.TenantUser, error) {
+	membership, err := s.tenantUserRepo.FindMembership(ctx, tenantID, userID)
+	if err != nil {
+		s.logger.Error("Failed to find tenant membership", zap.Error(err))
+		return nil, errors.Internal("Failed to get member history")
+	}
+	if membership == nil {
+		return nil, errors.NotFound("User has never been a member of this tenant")
+	}
+	return membership, nil
+}
+
+// UpdateMemberRole changes the role of a tenant member. The last owner of a tenant cannot be demoted.
+func (s *TenantService) UpdateMemberRole(ctx context.Context, tenantID, userID, role string) (*domain.TenantUser, error) {
+	if err := s.roleService.ValidateRole(ctx, tenantID, role); err != nil {
+		return nil, err
+	}
+
+	member, err := s.tenantUserRepo.FindByTenantAndUser(ctx, tenantID, userID)
+	if err != nil {
+		s.logger.Error("Failed to find tenant member", zap.Error(err))
+		return nil, errors.Internal("Failed to update member role")
+	}
+	if member == nil {
+		return nil, errors.NotFound("User not found in tenant")
+	}
+	if member.Role == role {
+		return member, nil
+	}
+
+	if err := s.setMemberRole(ctx, member, role); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Member role updated successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("user_id", userID),
+		zap.String("role", role),
+	)
+
+	return member, nil
+}
+
+// TransferOwnership makes a member an owner and then demotes the previous owner,
+// so the tenant has an owner at every step
+func (s *TenantService) TransferOwnership(ctx context.Context, tenantID string, req *domain.TransferOwnershipRequest) error {
+	if req.FromUserID == req.ToUserID {
+		return errors.BadRequest("Ownership must be transferred to another member")
+	}
+	fromRole := req.FromRole
+	if fromRole == "" {
+		fromRole = domain.RoleAdmin
+	}
+	if fromRole == domain.RoleOwner {
+		return errors.BadRequest("from_role must not be owner")
+	}
+	if err := s.roleService.ValidateRole(ctx, tenantID, fromRole); err != nil {
+		return err
+	}
+
+	from, err := s.tenantUserRepo.FindByTenantAndUser(ctx, tenantID, req.FromUserID)
+	if err != nil {
+		s.logger.Error("Failed to find tenant member", zap.Error(err))
+		return errors.Internal("Failed to transfer ownership")
+	}
+	if from == nil || from.Role != domain.RoleOwner {
+		return errors.BadRequest("from_user_id must be an owner of the tenant")
+	}
+
+	to, err := s.tenantUserRepo.FindByTenantAndUser(ctx, tenantID, req.ToUserID)
+	if err != nil {
+		s.logger.Error("Failed to find tenant member", zap.Error(err))
+		return errors.Internal("Failed to transfer ownership")
+	}
+	if to == nil {
+		return errors.BadRequest("to_user_id must be a member of the tenant")
+	}
+
+	if to.Role != domain.RoleOwner {
+		if err := s.setMemberRole(ctx, to, domain.RoleOwner); err != nil {
+			return err
+		}
+	}
+	if err := s.setMemberRole(ctx, from, fromRole); err != nil {
+		return err
+	}
+
+	s.logger.Info("Tenant ownership transferred successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("from_user_id", req.FromUserID),
+		zap.String("to_user_id", req.ToUserID),
+	)
+
+	return nil
+}
+
+// setMemberRole stores a new role for a member. Demoting an owner is refused when no
+// other owner remains, checked against the owner count of the tenant in the same write
+// that takes the owner off it.
+func (s *TenantService) setMemberRole(ctx context.Context, member *domain.TenantUser, role string) error {
+	demotesOwner := member.Role == domain.RoleOwner && role != domain.RoleOwner
+	promotesOwner := member.Role != domain.RoleOwner && role == domain.RoleOwner
+
+	var updated, released, lastOwner bool
+	err := s.outbox.Transaction(ctx, func(ctx context.Context) error {
+		updated, released, lastOwner = false, false, false
+		var err error
+		if updated, err = s.tenantUserRepo.UpdateRole(ctx, member.TenantID, member.UserID, member.Role, role); err != nil || !updated {
+			return err
+		}
+		switch {
+		case demotesOwner:
+			if released, err = s.tenantRepo.ReleaseOwner(ctx, member.TenantID); err != nil {
+				return err
+			}
+			if lastOwner = !released; lastOwner {
+				return errors.Conflict("A tenant must keep at least one owner")
+			}
+		case promotesOwner:
+			if err := s.tenantRepo.AddOwners(ctx, member.TenantID, 1); err != nil {
+				return err
+			}
+		}
+		return s.outbox.Add(ctx, domain.NewTenantEvent(domain.EventMemberRoleChanged, member.TenantID,
+			domain.MemberPayload{UserID: member.UserID, Role: role, PreviousRole: member.Role}))
+	})
+	if err != nil && updated && demotesOwner && !released {
+		// Without transactions the demotion has been saved and must be undone
+		if restoreErr := s.tenantUserRepo.RestoreRole(ctx, member.TenantID, member.UserID, role, domain.RoleOwner); restoreErr != nil {
+			s.logger.Error("Failed to restore owner role", zap.Error(restoreErr))
+		}
+	}
+	if lastOwner {
+		return errors.Conflict("A tenant must keep at least one owner")
+	}
+	if err != nil {
+		s.logger.Error("Failed to update member role", zap.Error(err))
+		return errors.Internal("Failed to update member role")
+	}
+	if !updated {
+		return errors.Conflict("Membership was changed concurrently; retry the request")
+	}
+
+	before := domain.NewAuditSnapshot(member)
+	member.Role = role
+	member.UpdatedAt = time.Now()
+
+	s.auditService.Record(ctx, domain.NewAuditEvent(domain.AuditActionMemberRoleChanged, domain.AuditTargetMember,
+		member.UserID, member.TenantID, before, domain.NewAuditSnapshot(member)))
+	return nil
+}
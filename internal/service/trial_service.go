@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vhvplatform/go-shared/errors"
+	"github.com/vhvplatform/go-shared/logger"
+	"github.com/vhvplatform/go-tenant-service/internal/domain"
+	"github.com/vhvplatform/go-tenant-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+// TrialConfig controls trial lengths and what happens when trials expire
+type TrialConfig struct {
+	DefaultDuration time.Duration // Trial length when none is requested
+	MaxDuration     time.Duration // Longest trial that may be requested
+	GracePeriod     time.Duration // How long a tenant keeps the trial tier after the trial ends
+	ExpiryAction    string        // downgrade or suspend
+	ExpiredTier     string        // Tier expired trials are downgraded to
+	Interval        time.Duration // How often expired trials are processed
+	BatchSize       int           // Trials expired per run
+}
+
+// DefaultTrialConfig returns the default trial settings
+func DefaultTrialConfig() TrialConfig {
+	return TrialConfig{
+		DefaultDuration: 14 * 24 * time.Hour,
+		MaxDuration:     90 * 24 * time.Hour,
+		GracePeriod:     3 * 24 * time.Hour,
+		ExpiryAction:    domain.TrialExpiryDowngrade,
+		ExpiredTier:     domain.SubscriptionFree,
+		Interval:        time.Hour,
+		BatchSize:       100,
+	}
+}
+
+// TrialService starts trials of paid tiers and ends them once they expire
+type TrialService struct {
+	tenantService *TenantService
+	tenantRepo    *repository.TenantRepository
+	config        TrialConfig
+	logger        *logger.Logger
+
+	job backgroundJob
+}
+
+// NewTrialService creates a new trial service
+func NewTrialService(
+	tenantService *TenantService,
+	tenantRepo *repository.TenantRepository,
+	config TrialConfig,
+	log *logger.Logger,
+) *TrialService {
+	defaults := DefaultTrialConfig()
+	if config.DefaultDuration <= 0 {
+		config.DefaultDuration = defaults.DefaultDuration
+	}
+	if config.MaxDuration < config.DefaultDuration {
+		config.MaxDuration = config.DefaultDuration
+	}
+	if config.GracePeriod < 0 {
+		config.GracePeriod = defaults.GracePeriod
+	}
+	if config.ExpiryAction == "" {
+		config.ExpiryAction = defaults.ExpiryAction
+	} else if config.ExpiryAction != domain.TrialExpiryDowngrade && config.ExpiryAction != domain.TrialExpirySuspend {
+		log.Warn("Unknown trial expiry action, using the default", zap.String("action", config.ExpiryAction))
+		config.ExpiryAction = defaults.ExpiryAction
+	}
+	if config.ExpiredTier == "" {
+		config.ExpiredTier = defaults.ExpiredTier
+	} else if !domain.IsValidSubscriptionTier(config.ExpiredTier) {
+		log.Warn("Unknown tier for expired trials, using the default", zap.String("tier", config.ExpiredTier))
+		config.ExpiredTier = defaults.ExpiredTier
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+
+	return &TrialService{
+		tenantService: tenantService,
+		tenantRepo:    tenantRepo,
+		config:        config,
+		logger:        log,
+	}
+}
+
+// StartTrial moves a tenant to a paid tier for a limited time. Each tenant gets one trial.
+func (s *TrialService) StartTrial(ctx context.Context, tenantID string, req *domain.StartTrialRequest) (*domain.Tenant, error) {
+	if !domain.IsValidSubscriptionTier(req.Tier) || req.Tier == domain.SubscriptionFree {
+		return nil, errors.BadRequest("Trials are available for the basic, professional and enterprise tiers")
+	}
+	duration := s.config.DefaultDuration
+	if req.DurationDays < 0 {
+		return nil, errors.BadRequest("Trial duration must be positive")
+	}
+	if req.DurationDays > 0 {
+		duration = time.Duration(req.DurationDays) * 24 * time.Hour
+	}
+	if duration > s.config.MaxDuration {
+		return nil, errors.BadRequest(fmt.Sprintf("Trials can last at most %d days", int(s.config.MaxDuration/(24*time.Hour))))
+	}
+
+	tenant, err := s.tenantService.GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.Trial != nil {
+		return nil, errors.Conflict("Tenant has already had a trial")
+	}
+	if tenant.ScheduledPlanChange != nil {
+		return nil, errors.Conflict("Tenant has a scheduled plan change; cancel it first")
+	}
+	from := tierName(tenant)
+	if req.Tier == from {
+		return nil, errors.BadRequest(fmt.Sprintf("Tenant is already on the %s tier", from))
+	}
+
+	actor := domain.ActorFromContext(ctx).ID
+	now := time.Now()
+	trial := &domain.TenantTrial{
+		Tier:        req.Tier,
+		Status:      domain.TrialStatusActive,
+		Actor:       actor,
+		StartedAt:   now,
+		EndsAt:      now.Add(duration),
+		GraceEndsAt: now.Add(duration + s.config.GracePeriod),
+	}
+	change := domain.PlanChange{
+		From:        from,
+		To:          req.Tier,
+		Reason:      "Trial started",
+		Actor:       actor,
+		Outcome:     domain.PlanChangeApplied,
+		RequestedAt: now,
+		EffectiveAt: now,
+		CompletedAt: now,
+	}
+
+	var ok bool
+	err = s.tenantService.outbox.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if ok, err = s.tenantRepo.StartTrial(ctx, tenantID, trial, change); err != nil || !ok {
+			return err
+		}
+		return s.tenantService.outbox.Add(ctx, planChangedEvent(tenantID, change))
+	})
+	if err != nil {
+		s.logger.Error("Failed to start trial", zap.String("tenant_id", tenantID), zap.Error(err))
+		return nil, errors.Internal("Failed to start trial")
+	}
+	if !ok {
+		return nil, errors.Conflict("Tenant plan was changed concurrently; retry the request")
+	}
+
+	after := *tenant
+	after.SubscriptionTier = req.Tier
+	after.Trial = trial
+	s.tenantService.auditService.Record(ctx, planAuditEvent(domain.AuditActionTenantTrialStarted, tenant, &after, change.Reason))
+
+	s.logger.Info("Trial started successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("tier", trial.Tier),
+		zap.Time("ends_at", trial.EndsAt),
+	)
+
+	return s.tenantService.GetTenant(ctx, tenantID)
+}
+
+// ListExpiringTrials lists tenants whose trial ends within the given time, soonest first,
+// including trials already in their grace period
+func (s *TrialService) ListExpiringTrials(ctx context.Context, within time.Duration, page, pageSize int) ([]*domain.Tenant, int64, error) {
+	if within < 0 {
+		return nil, 0, errors.BadRequest("Expiry window must not be negative")
+	}
+	if err := checkPageRequest(domain.PageRequest{Page: page, PageSize: pageSize}); err != nil {
+		return nil, 0, err
+	}
+
+	tenants, total, err := s.tenantRepo.ListExpiringTrials(ctx, time.Now().Add(within), page, pageSize)
+	if err != nil {
+		s.logger.Error("Failed to list expiring trials", zap.Error(err))
+		return nil, 0, errors.Internal("Failed to list expiring trials")
+	}
+	return tenants, total, nil
+}
+
+// Start runs the trial expiry job in the background until Stop is called
+func (s *TrialService) Start(ctx context.Context) {
+	if !s.job.start(ctx, s.config.Interval, s.RunExpiry) {
+		return
+	}
+
+	s.logger.Info("Trial expiry started",
+		zap.Duration("interval", s.config.Interval),
+		zap.String("expiry_action", s.config.ExpiryAction))
+}
+
+// Stop stops the background trial expiry job and waits for it to exit
+func (s *TrialService) Stop() {
+	if !s.job.stop() {
+		return
+	}
+
+	s.logger.Info("Trial expiry stopped")
+}
+
+// RunExpiry ends a batch of trials whose grace period has passed
+func (s *TrialService) RunExpiry(ctx context.Context) {
+	tenants, err := s.tenantRepo.FindExpiredTrials(ctx, time.Now(), s.config.BatchSize)
+	if err != nil {
+		s.logger.Error("Failed to load tenants with expired trials", zap.Error(err))
+		return
+	}
+
+	for _, tenant := range tenants {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.expire(ctx, tenant); err != nil {
+			s.logger.Error("Failed to expire trial",
+				zap.String("tenant_id", tenant.ID.Hex()),
+				zap.Error(err))
+		}
+	}
+}
+
+// expire ends the trial of a tenant, downgrading it to the expired tier or suspending it
+func (s *TrialService) expire(ctx context.Context, tenant *domain.Tenant) error {
+	tenantID := tenant.ID.Hex()
+	now := time.Now()
+
+	if tierName(tenant) != tenant.Trial.Tier {
+		// The tenant already moved off the trial tier by changing plan
+		ended, err := s.tenantRepo.EndTrial(ctx, tenantID, domain.TrialStatusConverted, now, nil, nil)
+		if err != nil || !ended {
+			return err
+		}
+		s.tenantService.auditService.Record(ctx, trialEndedAuditEvent(tenant, domain.TrialStatusConverted, now, nil))
+		return nil
+	}
+
+	var change *domain.PlanChange
+	if s.config.ExpiryAction == domain.TrialExpirySuspend {
+		if tenant.CurrentStatus() == domain.TenantStatusActive {
+			statusChange := domain.TenantStatusChange{
+				To:     domain.TenantStatusSuspended,
+				Reason: "Trial expired",
+				Actor:  domain.SystemActor,
+			}
+			if _, err := s.tenantService.changeStatus(ctx, tenant, statusChange); err != nil {
+				return err
+			}
+		}
+	} else if from := tierName(tenant); from != s.config.ExpiredTier {
+		change = &domain.PlanChange{
+			From:        from,
+			To:          s.config.ExpiredTier,
+			Reason:      "Trial expired",
+			Actor:       domain.SystemActor,
+			Outcome:     domain.PlanChangeApplied,
+			RequestedAt: now,
+			EffectiveAt: tenant.Trial.GraceEndsAt,
+			CompletedAt: now,
+		}
+	}
+
+	var ok bool
+	err := s.tenantService.outbox.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if ok, err = s.tenantRepo.EndTrial(ctx, tenantID, domain.TrialStatusExpired, now, change, tenant.ScheduledPlanChange); err != nil || !ok || change == nil {
+			return err
+		}
+		return s.tenantService.outbox.Add(ctx, planChangedEvent(tenantID, *change))
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// The tenant converted or changed plan since it was loaded; the next run reloads it
+		return nil
+	}
+
+	s.tenantService.auditService.Record(ctx, trialEndedAuditEvent(tenant, domain.TrialStatusExpired, now, change))
+
+	s.logger.Info("Trial expired successfully",
+		zap.String("tenant_id", tenantID),
+		zap.String("tier", tenant.Trial.Tier),
+		zap.String("expiry_action", s.config.ExpiryAction),
+	)
+	return nil
+}
+
+// trialEndedAuditEvent builds the audit event of the trial of a tenant ending, with the plan
+// change that moved it off the trial tier if any
+func trialEndedAuditEvent(tenant *domain.Tenant, status string, endedAt time.Time, change *domain.PlanChange) *domain.AuditEvent {
+	trial := *tenant.Trial
+	trial.Status = status
+	trial.EndedAt = endedAt
+
+	after := *tenant
+	after.Trial = &trial
+	reason := "Trial converted"
+	if status == domain.TrialStatusExpired {
+		reason = "Trial expired"
+	}
+	if change != nil {
+		after.SubscriptionTier = change.To
+	}
+	return planAuditEvent(domain.AuditActionTenantTrialEnded, tenant, &after, reason)
+}
@@ -0,0 +1,32 @@
+// Migration: 003_tenant_config_defaults
+// Description: Give tenants created before tenant configuration the default configuration
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+// Tenants created before tenant configuration have no config, so any config update would
+// be rejected for lacking login identifiers. They get the config new tenants start with.
+db.tenants.updateMany(
+  { $or: [{ config: { $exists: false } }, { config: null }] },
+  {
+    $set: {
+      config: {
+        allowedLoginIdentifiers: ['email'],
+        require2fa: false,
+        allowRegistration: true
+      }
+    }
+  }
+);
+
+db.tenants.updateMany(
+  {
+    $or: [
+      { 'config.allowedLoginIdentifiers': { $exists: false } },
+      { 'config.allowedLoginIdentifiers': { $size: 0 } }
+    ]
+  },
+  { $set: { 'config.allowedLoginIdentifiers': ['email'] } }
+);
+
+print('Migration 003_tenant_config_defaults completed successfully!');
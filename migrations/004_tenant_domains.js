@@ -0,0 +1,51 @@
+// Migration: 004_tenant_domains
+// Description: Setup tenant_domains collection and carry over existing tenant domains as verified
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+// Create tenant_domains collection
+db.createCollection('tenant_domains');
+
+// Create indexes for tenant_domains
+db.tenant_domains.createIndex(
+    { tenantId: 1, domain: 1 },
+    { unique: true, name: 'idx_tenant_domain_unique' }
+);
+
+db.tenant_domains.createIndex(
+    { domain: 1 },
+    { unique: true, partialFilterExpression: { status: 'verified' }, name: 'idx_verified_domain_unique' }
+);
+
+db.tenant_domains.createIndex(
+    { status: 1, lastCheckedAt: 1 },
+    { name: 'idx_status_last_checked' }
+);
+
+// Domains set before verification existed are trusted as verified primary domains
+db.tenants.find({ domain: { $exists: true, $ne: '' } }).forEach(function (tenant) {
+    var domain = tenant.domain.toLowerCase();
+    db.tenant_domains.updateOne(
+        { tenantId: tenant._id.str, domain: domain },
+        {
+            $setOnInsert: {
+                tenantId: tenant._id.str,
+                domain: domain,
+                status: 'verified',
+                verificationMethod: 'dns-txt',
+                token: '',
+                isPrimary: true,
+                attempts: 0,
+                verifiedAt: new Date(),
+                expiresAt: new Date(),
+                createdAt: new Date(),
+                updatedAt: new Date()
+            }
+        },
+        { upsert: true }
+    );
+});
+
+print('Migration 004_tenant_domains completed successfully!');
+print('Created collection: tenant_domains');
@@ -0,0 +1,26 @@
+// Migration: 005_tenant_status
+// Description: Backfill the tenant lifecycle status from the legacy isActive flag
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+var now = new Date();
+
+// Active tenants become "active", deactivated tenants were deleted through DeleteTenant
+db.tenants.updateMany(
+    { status: { $exists: false }, isActive: true },
+    { $set: { status: 'active', statusChangedAt: now } }
+);
+
+db.tenants.updateMany(
+    { status: { $exists: false }, isActive: { $ne: true } },
+    { $set: { status: 'deleted', statusReason: 'Deactivated before lifecycle statuses', statusChangedAt: now } }
+);
+
+db.tenants.createIndex(
+    { status: 1 },
+    { name: 'idx_status' }
+);
+
+print('Migration 005_tenant_status completed successfully!');
+print('Backfilled tenant lifecycle status');
@@ -0,0 +1,27 @@
+// Migration: 006_tenant_purge
+// Description: Setup tenant_purge_reports collection and schedule the purge of tenants deleted before the purge pipeline
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+// Create tenant_purge_reports collection
+db.createCollection('tenant_purge_reports');
+
+db.tenant_purge_reports.createIndex(
+    { tenantId: 1, completedAt: -1 },
+    { name: 'idx_tenant_completed_at' }
+);
+
+db.tenants.createIndex(
+    { status: 1, purgeAfter: 1 },
+    { name: 'idx_status_purge_after' }
+);
+
+// Soft-deleted tenants left their memberships and service configs behind; purge them on the next run
+db.tenants.updateMany(
+    { status: 'deleted', purgeAfter: { $exists: false } },
+    { $set: { purgeAfter: new Date() } }
+);
+
+print('Migration 006_tenant_purge completed successfully!');
+print('Scheduled purge of previously deleted tenants');
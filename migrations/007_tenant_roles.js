@@ -0,0 +1,28 @@
+// Migration: 007_tenant_roles
+// Description: Setup tenant_roles collection for custom tenant roles
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+// Create tenant_roles collection
+db.createCollection('tenant_roles');
+
+db.tenant_roles.createIndex(
+    { tenantId: 1, name: 1 },
+    { unique: true, name: 'idx_tenant_role_unique' }
+);
+
+db.tenant_users.createIndex(
+    { tenantId: 1, role: 1 },
+    { name: 'idx_tenant_role' }
+);
+
+// Memberships with roles outside the built-in set and without a custom definition grant no permissions
+var builtInRoles = ['owner', 'admin', 'member', 'viewer'];
+var unknownRoles = db.tenant_users.distinct('role', { role: { $nin: builtInRoles } });
+if (unknownRoles.length > 0) {
+    print('Memberships with roles that need a custom role definition: ' + unknownRoles.join(', '));
+}
+
+print('Migration 007_tenant_roles completed successfully!');
+print('Created tenant_roles collection');
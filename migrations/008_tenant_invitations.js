@@ -0,0 +1,27 @@
+// Migration: 008_tenant_invitations
+// Description: Setup tenant_invitations collection for membership invitations
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+// Create tenant_invitations collection
+db.createCollection('tenant_invitations');
+
+db.tenant_invitations.createIndex(
+    { tenantId: 1, status: 1, createdAt: -1 },
+    { name: 'idx_tenant_invitation_status' }
+);
+
+// Lookup of pending invitations by recipient
+db.tenant_invitations.createIndex(
+    { tenantId: 1, email: 1 },
+    { name: 'idx_tenant_invitation_email', partialFilterExpression: { status: 'pending' } }
+);
+
+db.tenant_invitations.createIndex(
+    { tenantId: 1, phone: 1 },
+    { name: 'idx_tenant_invitation_phone', partialFilterExpression: { status: 'pending' } }
+);
+
+print('Migration 008_tenant_invitations completed successfully!');
+print('Created tenant_invitations collection');
@@ -0,0 +1,19 @@
+// Migration: 009_membership_history
+// Description: Backfill membership history on tenant_users
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+// Every existing membership joined when it was created; removed ones left at their last update
+var backfilled = 0;
+db.tenant_users.find({ history: { $exists: false } }).forEach(function (membership) {
+    var history = [{ type: 'joined', role: membership.role, at: membership.createdAt }];
+    if (!membership.isActive) {
+        history.push({ type: 'left', role: membership.role, at: membership.updatedAt });
+    }
+    db.tenant_users.updateOne({ _id: membership._id }, { $set: { history: history } });
+    backfilled++;
+});
+
+print('Migration 009_membership_history completed successfully!');
+print('Backfilled history for ' + backfilled + ' memberships');
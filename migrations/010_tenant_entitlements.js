@@ -0,0 +1,15 @@
+// Migration: 010_tenant_entitlements
+// Description: Default the subscription tier of existing tenants for entitlement enforcement
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+// Tenants without a known tier get the free tier entitlements
+var knownTiers = ['free', 'basic', 'professional', 'enterprise'];
+var result = db.tenants.updateMany(
+    { subscriptionTier: { $nin: knownTiers } },
+    { $set: { subscriptionTier: 'free', updatedAt: new Date() } }
+);
+
+print('Migration 010_tenant_entitlements completed successfully!');
+print('Set the free tier on ' + result.modifiedCount + ' tenants');
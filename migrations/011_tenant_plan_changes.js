@@ -0,0 +1,13 @@
+// Migration: 011_tenant_plan_changes
+// Description: Index scheduled plan changes of tenants for the plan change job
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+// Due scheduled plan changes are looked up by their effective time
+db.tenants.createIndex(
+    { 'scheduledPlanChange.effectiveAt': 1 },
+    { sparse: true }
+);
+
+print('Migration 011_tenant_plan_changes completed successfully!');
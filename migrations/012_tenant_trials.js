@@ -0,0 +1,17 @@
+// Migration: 012_tenant_trials
+// Description: Index tenant trials for the expiry job and the expiring trials listing
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+// Running trials are looked up by their end and grace period end
+db.tenants.createIndex(
+    { 'trial.status': 1, 'trial.endsAt': 1 },
+    { sparse: true }
+);
+db.tenants.createIndex(
+    { 'trial.status': 1, 'trial.graceEndsAt': 1 },
+    { sparse: true }
+);
+
+print('Migration 012_tenant_trials completed successfully!');
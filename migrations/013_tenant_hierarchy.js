@@ -0,0 +1,13 @@
+// Migration: 013_tenant_hierarchy
+// Description: Index parent tenants for listing sub-tenants and walking the hierarchy
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+// Sub-tenants are listed by parent; top-level tenants have no parentId
+db.tenants.createIndex(
+    { parentId: 1 },
+    { sparse: true }
+);
+
+print('Migration 013_tenant_hierarchy completed successfully!');
@@ -0,0 +1,13 @@
+// Migration: 014_tenant_listing
+// Description: Index tenants for filtered and sorted listings
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+// Default listing order, newest first
+db.tenants.createIndex({ createdAt: -1 });
+
+// Listings filtered by subscription tier
+db.tenants.createIndex({ subscriptionTier: 1, createdAt: -1 });
+
+print('Migration 014_tenant_listing completed successfully!');
@@ -0,0 +1,11 @@
+// Migration: 015_cursor_pagination
+// Description: Index membership listings for page token (keyset) pagination
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+// Members of a tenant and tenants of a user, newest first with the ID breaking ties
+db.tenant_users.createIndex({ tenantId: 1, isActive: 1, createdAt: -1, _id: -1 });
+db.tenant_users.createIndex({ userId: 1, isActive: 1, createdAt: -1, _id: -1 });
+
+print('Migration 015_cursor_pagination completed successfully!');
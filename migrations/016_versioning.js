@@ -0,0 +1,18 @@
+// Migration: 016_versioning
+// Description: Start version counters for optimistic concurrency on tenants and service configs
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+// Documents written before versioning start at version 1
+db.tenants.updateMany(
+  { version: { $exists: false } },
+  { $set: { version: NumberLong(1) } }
+);
+
+db.service_configs.updateMany(
+  { version: { $exists: false } },
+  { $set: { version: NumberLong(1) } }
+);
+
+print('Migration 016_versioning completed successfully!');
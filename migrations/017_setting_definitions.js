@@ -0,0 +1,15 @@
+// Migration: 017_setting_definitions
+// Description: Registry of tenant setting keys with their JSON Schema, default and visibility
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+db.createCollection('setting_definitions');
+
+// One definition per setting key
+db.setting_definitions.createIndex({ key: 1 }, { unique: true });
+
+// Settings registered by a service
+db.setting_definitions.createIndex({ service: 1 });
+
+print('Migration 017_setting_definitions completed successfully!');
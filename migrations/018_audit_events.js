@@ -0,0 +1,28 @@
+// Migration: 018_audit_events
+// Description: Append-only audit log of changes to tenants, members and service configurations
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+db.createCollection('audit_events');
+
+// Audit log of a tenant, newest first
+db.audit_events.createIndex({ tenantId: 1, createdAt: -1 });
+
+// Changes made by an actor
+db.audit_events.createIndex({ 'actor.id': 1, createdAt: -1 });
+
+// Changes of a kind
+db.audit_events.createIndex({ action: 1, createdAt: -1 });
+
+// History of a single target
+db.audit_events.createIndex({ targetType: 1, targetId: 1, createdAt: -1 });
+
+// Retention: events expire after AUDIT_RETENTION (365 days by default). The service keeps
+// this index in line with its configuration on startup.
+db.audit_events.createIndex(
+  { createdAt: 1 },
+  { name: 'createdAt_retention', expireAfterSeconds: 365 * 24 * 60 * 60 }
+);
+
+print('Migration 018_audit_events completed successfully!');
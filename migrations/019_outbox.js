@@ -0,0 +1,26 @@
+// Migration: 019_outbox
+// Description: Outbox of tenant events written in the same transaction as tenant changes
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+// Collections are created up front; MongoDB before 4.4 cannot create them inside a transaction
+db.createCollection('outbox_events');
+db.createCollection('outbox_sequences');
+db.createCollection('outbox_leases');
+
+// Events of a tenant are numbered without duplicates
+db.outbox_events.createIndex({ tenantId: 1, sequence: 1 }, { unique: true });
+
+// Pending events of a tenant in publishing order
+db.outbox_events.createIndex({ status: 1, tenantId: 1, sequence: 1 });
+
+// Retention: published events expire after OUTBOX_RETENTION (7 days by default). Pending
+// events have no publishedAt and are never expired. The service keeps this index in line
+// with its configuration on startup.
+db.outbox_events.createIndex(
+  { publishedAt: 1 },
+  { name: 'publishedAt_retention', expireAfterSeconds: 7 * 24 * 60 * 60 }
+);
+
+print('Migration 019_outbox completed successfully!');
@@ -0,0 +1,20 @@
+// Migration: 020_owner_count
+// Description: Count the owners of each tenant, which guards against removing the last owner
+// Date: 2026-10-16
+
+db = db.getSiblingDB('tenant_service');
+
+// Owners are counted from the active memberships holding the owner role
+var counted = 0;
+db.tenants.find({ ownerCount: { $exists: false } }).forEach(function (tenant) {
+    var owners = db.tenant_users.countDocuments({
+        tenantId: tenant._id.str,
+        role: 'owner',
+        isActive: true
+    });
+    db.tenants.updateOne({ _id: tenant._id }, { $set: { ownerCount: NumberLong(owners) } });
+    counted++;
+});
+
+print('Migration 020_owner_count completed successfully!');
+print('Counted owners of ' + counted + ' tenants');
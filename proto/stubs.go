@@ -1,224 +1,1829 @@
-package proto
-
-import (
-	"context"
-
-	"google.golang.org/grpc"
-)
-
-// This file is a TEMPORARY STUB to allow compilation without running protoc.
-// It matches the expected output of proper protobuf generation.
-
-type Tenant struct {
-	Id               string `json:"id,omitempty"`
-	Name             string `json:"name,omitempty"`
-	Domain           string `json:"domain,omitempty"`
-	SubscriptionTier string `json:"subscription_tier,omitempty"`
-	IsActive         bool   `json:"is_active,omitempty"`
-	CreatedAt        string `json:"created_at,omitempty"`
-	UpdatedAt        string `json:"updated_at,omitempty"`
-}
-
-type GetTenantRequest struct {
-	TenantId string `json:"tenant_id,omitempty"`
-}
-
-type GetTenantResponse struct {
-	Tenant *Tenant `json:"tenant,omitempty"`
-}
-
-type ListTenantsRequest struct {
-	Page     int32 `json:"page,omitempty"`
-	PageSize int32 `json:"page_size,omitempty"`
-}
-
-type ListTenantsResponse struct {
-	Tenants []*Tenant `json:"tenants,omitempty"`
-	Total   int32     `json:"total,omitempty"`
-}
-
-type CreateTenantRequest struct {
-	Name             string `json:"name,omitempty"`
-	Domain           string `json:"domain,omitempty"`
-	SubscriptionTier string `json:"subscription_tier,omitempty"`
-}
-
-type CreateTenantResponse struct {
-	Tenant *Tenant `json:"tenant,omitempty"`
-}
-
-type UpdateTenantRequest struct {
-	TenantId         string `json:"tenant_id,omitempty"`
-	Name             string `json:"name,omitempty"`
-	Domain           string `json:"domain,omitempty"`
-	SubscriptionTier string `json:"subscription_tier,omitempty"`
-}
-
-type UpdateTenantResponse struct {
-	Tenant *Tenant `json:"tenant,omitempty"`
-}
-
-type DeleteTenantRequest struct {
-	TenantId string `json:"tenant_id,omitempty"`
-}
-
-type DeleteTenantResponse struct {
-	Success bool `json:"success,omitempty"`
-}
-
-type AddUserToTenantRequest struct {
-	TenantId string `json:"tenant_id,omitempty"`
-	UserId   string `json:"user_id,omitempty"`
-	Role     string `json:"role,omitempty"`
-}
-
-type AddUserToTenantResponse struct {
-	Success bool `json:"success,omitempty"`
-}
-
-type RemoveUserFromTenantRequest struct {
-	TenantId string `json:"tenant_id,omitempty"`
-	UserId   string `json:"user_id,omitempty"`
-}
-
-type RemoveUserFromTenantResponse struct {
-	Success bool `json:"success,omitempty"`
-}
-
-// TenantServiceClient is the client API for TenantService.
-type TenantServiceClient interface {
-	GetTenant(ctx context.Context, in *GetTenantRequest, opts ...grpc.CallOption) (*GetTenantResponse, error)
-	ListTenants(ctx context.Context, in *ListTenantsRequest, opts ...grpc.CallOption) (*ListTenantsResponse, error)
-	CreateTenant(ctx context.Context, in *CreateTenantRequest, opts ...grpc.CallOption) (*CreateTenantResponse, error)
-	UpdateTenant(ctx context.Context, in *UpdateTenantRequest, opts ...grpc.CallOption) (*UpdateTenantResponse, error)
-	DeleteTenant(ctx context.Context, in *DeleteTenantRequest, opts ...grpc.CallOption) (*DeleteTenantResponse, error)
-	AddUserToTenant(ctx context.Context, in *AddUserToTenantRequest, opts ...grpc.CallOption) (*AddUserToTenantResponse, error)
-	RemoveUserFromTenant(ctx context.Context, in *RemoveUserFromTenantRequest, opts ...grpc.CallOption) (*RemoveUserFromTenantResponse, error)
-}
-
-type tenantServiceClient struct {
-	cc grpc.ClientConnInterface
-}
-
-func NewTenantServiceClient(cc grpc.ClientConnInterface) TenantServiceClient {
-	return &tenantServiceClient{cc}
-}
-
-func (c *tenantServiceClient) GetTenant(ctx context.Context, in *GetTenantRequest, opts ...grpc.CallOption) (*GetTenantResponse, error) {
-	out := new(GetTenantResponse)
-	err := c.cc.Invoke(ctx, "/tenant.TenantService/GetTenant", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *tenantServiceClient) ListTenants(ctx context.Context, in *ListTenantsRequest, opts ...grpc.CallOption) (*ListTenantsResponse, error) {
-	out := new(ListTenantsResponse)
-	err := c.cc.Invoke(ctx, "/tenant.TenantService/ListTenants", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *tenantServiceClient) CreateTenant(ctx context.Context, in *CreateTenantRequest, opts ...grpc.CallOption) (*CreateTenantResponse, error) {
-	out := new(CreateTenantResponse)
-	err := c.cc.Invoke(ctx, "/tenant.TenantService/CreateTenant", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *tenantServiceClient) UpdateTenant(ctx context.Context, in *UpdateTenantRequest, opts ...grpc.CallOption) (*UpdateTenantResponse, error) {
-	out := new(UpdateTenantResponse)
-	err := c.cc.Invoke(ctx, "/tenant.TenantService/UpdateTenant", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *tenantServiceClient) DeleteTenant(ctx context.Context, in *DeleteTenantRequest, opts ...grpc.CallOption) (*DeleteTenantResponse, error) {
-	out := new(DeleteTenantResponse)
-	err := c.cc.Invoke(ctx, "/tenant.TenantService/DeleteTenant", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *tenantServiceClient) AddUserToTenant(ctx context.Context, in *AddUserToTenantRequest, opts ...grpc.CallOption) (*AddUserToTenantResponse, error) {
-	out := new(AddUserToTenantResponse)
-	err := c.cc.Invoke(ctx, "/tenant.TenantService/AddUserToTenant", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *tenantServiceClient) RemoveUserFromTenant(ctx context.Context, in *RemoveUserFromTenantRequest, opts ...grpc.CallOption) (*RemoveUserFromTenantResponse, error) {
-	out := new(RemoveUserFromTenantResponse)
-	err := c.cc.Invoke(ctx, "/tenant.TenantService/RemoveUserFromTenant", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-// TenantServiceServer is the server API for TenantService.
-type TenantServiceServer interface {
-	GetTenant(context.Context, *GetTenantRequest) (*GetTenantResponse, error)
-	ListTenants(context.Context, *ListTenantsRequest) (*ListTenantsResponse, error)
-	CreateTenant(context.Context, *CreateTenantRequest) (*CreateTenantResponse, error)
-	UpdateTenant(context.Context, *UpdateTenantRequest) (*UpdateTenantResponse, error)
-	DeleteTenant(context.Context, *DeleteTenantRequest) (*DeleteTenantResponse, error)
-	AddUserToTenant(context.Context, *AddUserToTenantRequest) (*AddUserToTenantResponse, error)
-	RemoveUserFromTenant(context.Context, *RemoveUserFromTenantRequest) (*RemoveUserFromTenantResponse, error)
-	mustEmbedUnimplementedTenantServiceServer()
-}
-
-// UnimplementedTenantServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedTenantServiceServer struct{}
-
-func (UnimplementedTenantServiceServer) GetTenant(context.Context, *GetTenantRequest) (*GetTenantResponse, error) {
-	return nil, nil
-}
-func (UnimplementedTenantServiceServer) ListTenants(context.Context, *ListTenantsRequest) (*ListTenantsResponse, error) {
-	return nil, nil
-}
-func (UnimplementedTenantServiceServer) CreateTenant(context.Context, *CreateTenantRequest) (*CreateTenantResponse, error) {
-	return nil, nil
-}
-func (UnimplementedTenantServiceServer) UpdateTenant(context.Context, *UpdateTenantRequest) (*UpdateTenantResponse, error) {
-	return nil, nil
-}
-func (UnimplementedTenantServiceServer) DeleteTenant(context.Context, *DeleteTenantRequest) (*DeleteTenantResponse, error) {
-	return nil, nil
-}
-func (UnimplementedTenantServiceServer) AddUserToTenant(context.Context, *AddUserToTenantRequest) (*AddUserToTenantResponse, error) {
-	return nil, nil
-}
-func (UnimplementedTenantServiceServer) RemoveUserFromTenant(context.Context, *RemoveUserFromTenantRequest) (*RemoveUserFromTenantResponse, error) {
-	return nil, nil
-}
-func (UnimplementedTenantServiceServer) mustEmbedUnimplementedTenantServiceServer() {}
-
-func RegisterTenantServiceServer(s grpc.ServiceRegistrar, srv TenantServiceServer) {
-	s.RegisterService(&grpc.ServiceDesc{
-		ServiceName: "tenant.TenantService",
-		HandlerType: (*TenantServiceServer)(nil),
-		Methods: []grpc.MethodDesc{
-			{MethodName: "GetTenant", Handler: nil},
-			{MethodName: "ListTenants", Handler: nil},
-			{MethodName: "CreateTenant", Handler: nil},
-			{MethodName: "UpdateTenant", Handler: nil},
-			{MethodName: "DeleteTenant", Handler: nil},
-			{MethodName: "AddUserToTenant", Handler: nil},
-			{MethodName: "RemoveUserFromTenant", Handler: nil},
-		},
-		Streams:  []grpc.StreamDesc{},
-		Metadata: "tenant.proto",
-	}, srv)
-}
+package proto
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// This file is a TEMPORARY STUB to allow compilation without running protoc.
+// It matches the expected output of proper protobuf generation.
+
+type Tenant struct {
+	Id               string                `json:"id,omitempty"`
+	Name             string                `json:"name,omitempty"`
+	Domain           string                `json:"domain,omitempty"`
+	Subdomain        string                `json:"subdomain,omitempty"`
+	SubscriptionTier string                `json:"subscription_tier,omitempty"`
+	IsActive         bool                  `json:"is_active,omitempty"`
+	CreatedAt        string                `json:"created_at,omitempty"`
+	UpdatedAt        string                `json:"updated_at,omitempty"`
+	Config           *TenantConfig         `json:"config,omitempty"`
+	Status           string                `json:"status,omitempty"`
+	StatusReason     string                `json:"status_reason,omitempty"`
+	StatusHistory    []*TenantStatusChange `json:"status_history,omitempty"`
+	PurgeAfter       string                `json:"purge_after,omitempty"`
+	Trial            *TenantTrial          `json:"trial,omitempty"`
+	ParentId         string                `json:"parent_id,omitempty"`
+	Version          int64                 `json:"version,omitempty"`
+	DefaultService   string                `json:"default_service,omitempty"`
+	AuthSettings     *AuthSettings         `json:"auth_settings,omitempty"`
+	Settings         *structpb.Struct      `json:"settings,omitempty"`
+}
+
+type AuthSettings struct {
+	AllowedLoginMethods []string `json:"allowed_login_methods,omitempty"`
+}
+
+type TenantStatusChange struct {
+	From       string `json:"from,omitempty"`
+	To         string `json:"to,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	Actor      string `json:"actor,omitempty"`
+	ChangedAt  string `json:"changed_at,omitempty"`
+	PurgeAfter string `json:"purge_after,omitempty"`
+}
+
+type TenantConfig struct {
+	DefaultServiceUrl       string            `json:"default_service_url,omitempty"`
+	ServiceMappings         map[string]string `json:"service_mappings,omitempty"`
+	FallbackChain           []string          `json:"fallback_chain,omitempty"`
+	AllowedLoginIdentifiers []string          `json:"allowed_login_identifiers,omitempty"`
+	Require_2Fa             bool              `json:"require_2fa,omitempty"`
+	AllowRegistration       bool              `json:"allow_registration,omitempty"`
+	CustomLogoUrl           string            `json:"custom_logo_url,omitempty"`
+	CustomBackgroundUrl     string            `json:"custom_background_url,omitempty"`
+	CustomSettings          map[string]string `json:"custom_settings,omitempty"`
+}
+
+type GetTenantRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+}
+
+type GetTenantResponse struct {
+	Tenant *Tenant `json:"tenant,omitempty"`
+}
+
+type ListTenantsRequest struct {
+	Page             int32  `json:"page,omitempty"`
+	PageSize         int32  `json:"page_size,omitempty"`
+	Name             string `json:"name,omitempty"`
+	NamePrefix       string `json:"name_prefix,omitempty"`
+	Domain           string `json:"domain,omitempty"`
+	SubscriptionTier string `json:"subscription_tier,omitempty"`
+	Status           string `json:"status,omitempty"`
+	IsActive         *bool  `json:"is_active,omitempty"`
+	CreatedAfter     string `json:"created_after,omitempty"`
+	CreatedBefore    string `json:"created_before,omitempty"`
+	SettingsKey      string `json:"settings_key,omitempty"`
+	Search           string `json:"search,omitempty"`
+	SortBy           string `json:"sort_by,omitempty"`
+	SortOrder        string `json:"sort_order,omitempty"`
+	PageToken        string `json:"page_token,omitempty"`
+	IncludeTotal     bool   `json:"include_total,omitempty"`
+}
+
+type ListTenantsResponse struct {
+	Tenants       []*Tenant `json:"tenants,omitempty"`
+	Total         int32     `json:"total,omitempty"`
+	NextPageToken string    `json:"next_page_token,omitempty"`
+	PrevPageToken string    `json:"prev_page_token,omitempty"`
+}
+
+type CreateTenantRequest struct {
+	Name             string `json:"name,omitempty"`
+	Domain           string `json:"domain,omitempty"`
+	SubscriptionTier string `json:"subscription_tier,omitempty"`
+	Subdomain        string `json:"subdomain,omitempty"`
+	ParentId         string `json:"parent_id,omitempty"`
+}
+
+type CreateTenantResponse struct {
+	Tenant *Tenant `json:"tenant,omitempty"`
+}
+
+type UpdateTenantRequest struct {
+	TenantId         string                 `json:"tenant_id,omitempty"`
+	Name             string                 `json:"name,omitempty"`
+	Domain           string                 `json:"domain,omitempty"`
+	SubscriptionTier string                 `json:"subscription_tier,omitempty"`
+	Subdomain        string                 `json:"subdomain,omitempty"`
+	ExpectedVersion  int64                  `json:"expected_version,omitempty"`
+	DefaultService   string                 `json:"default_service,omitempty"`
+	AuthSettings     *AuthSettings          `json:"auth_settings,omitempty"`
+	Settings         *structpb.Struct       `json:"settings,omitempty"`
+	UpdateMask       *fieldmaskpb.FieldMask `json:"update_mask,omitempty"`
+}
+
+type UpdateTenantResponse struct {
+	Tenant *Tenant `json:"tenant,omitempty"`
+}
+
+type DeleteTenantRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type DeleteTenantResponse struct {
+	Success    bool   `json:"success,omitempty"`
+	PurgeAfter string `json:"purge_after,omitempty"`
+}
+
+type AddUserToTenantRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	UserId   string `json:"user_id,omitempty"`
+	Role     string `json:"role,omitempty"`
+}
+
+type AddUserToTenantResponse struct {
+	Success bool `json:"success,omitempty"`
+}
+
+type RemoveUserFromTenantRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	UserId   string `json:"user_id,omitempty"`
+}
+
+type RemoveUserFromTenantResponse struct {
+	Success bool `json:"success,omitempty"`
+}
+
+type GetTenantConfigRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+}
+
+type GetTenantConfigResponse struct {
+	Config *TenantConfig `json:"config,omitempty"`
+}
+
+type UpdateTenantConfigRequest struct {
+	TenantId        string        `json:"tenant_id,omitempty"`
+	Config          *TenantConfig `json:"config,omitempty"`
+	ExpectedVersion int64         `json:"expected_version,omitempty"`
+}
+
+type UpdateTenantConfigResponse struct {
+	Config *TenantConfig `json:"config,omitempty"`
+}
+
+type GetDefaultServiceRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+}
+
+type GetDefaultServiceResponse struct {
+	DefaultServiceUrl string   `json:"default_service_url,omitempty"`
+	FallbackUrls      []string `json:"fallback_urls,omitempty"`
+}
+
+// Service Registry Messages
+
+type GetServiceConfigRequest struct {
+	TenantId    string `json:"tenant_id,omitempty"`
+	ServiceName string `json:"service_name,omitempty"`
+}
+
+type GetServiceConfigResponse struct {
+	Config *ServiceConfig `json:"config,omitempty"`
+}
+
+type UpdateServiceConfigRequest struct {
+	TenantId        string         `json:"tenant_id,omitempty"`
+	ServiceName     string         `json:"service_name,omitempty"`
+	Config          *ServiceConfig `json:"config,omitempty"`
+	ExpectedVersion int64          `json:"expected_version,omitempty"`
+}
+
+type UpdateServiceConfigResponse struct {
+	Config *ServiceConfig `json:"config,omitempty"`
+}
+
+type GetServiceURLRequest struct {
+	TenantId          string `json:"tenant_id,omitempty"`
+	ServiceName       string `json:"service_name,omitempty"`
+	AcquireConnection bool   `json:"acquire_connection,omitempty"`
+}
+
+type GetServiceURLResponse struct {
+	Url           string           `json:"url,omitempty"`
+	IsDefault     bool             `json:"is_default,omitempty"`
+	Success       bool             `json:"success,omitempty"`
+	Error         string           `json:"error,omitempty"`
+	AttemptedUrls []string         `json:"attempted_urls,omitempty"`
+	Endpoint      *ServiceEndpoint `json:"endpoint,omitempty"`
+	InheritedFrom string           `json:"inherited_from,omitempty"`
+}
+
+type ListTenantServicesRequest struct {
+	TenantId     string `json:"tenant_id,omitempty"`
+	PageSize     int32  `json:"page_size,omitempty"`
+	PageToken    string `json:"page_token,omitempty"`
+	IncludeTotal bool   `json:"include_total,omitempty"`
+}
+
+type ListTenantServicesResponse struct {
+	Services      []*ServiceConfig `json:"services,omitempty"`
+	Total         int32            `json:"total,omitempty"`
+	NextPageToken string           `json:"next_page_token,omitempty"`
+	PrevPageToken string           `json:"prev_page_token,omitempty"`
+}
+
+type GetServiceHealthRequest struct {
+	TenantId    string `json:"tenant_id,omitempty"`
+	ServiceName string `json:"service_name,omitempty"`
+}
+
+type GetServiceHealthResponse struct {
+	Healths []*ServiceHealth `json:"healths,omitempty"`
+}
+
+type ServiceConfig struct {
+	Id                  string                `json:"id,omitempty"`
+	TenantId            string                `json:"tenant_id,omitempty"`
+	ServiceName         string                `json:"service_name,omitempty"`
+	PrimaryEndpoint     *ServiceEndpoint      `json:"primary_endpoint,omitempty"`
+	FallbackChain       []*ServiceEndpoint    `json:"fallback_chain,omitempty"`
+	DefaultServiceUrl   string                `json:"default_service_url,omitempty"`
+	HealthCheck         *HealthCheckConfig    `json:"health_check,omitempty"`
+	LoadBalanceStrategy string                `json:"load_balance_strategy,omitempty"`
+	IsActive            bool                  `json:"is_active,omitempty"`
+	Metadata            map[string]string     `json:"metadata,omitempty"`
+	CreatedAt           string                `json:"created_at,omitempty"`
+	UpdatedAt           string                `json:"updated_at,omitempty"`
+	CircuitBreaker      *CircuitBreakerConfig `json:"circuit_breaker,omitempty"`
+	Version             int64                 `json:"version,omitempty"`
+}
+
+type ServiceEndpoint struct {
+	Url      string            `json:"url,omitempty"`
+	Priority int32             `json:"priority,omitempty"`
+	Weight   int32             `json:"weight,omitempty"`
+	Timeout  int32             `json:"timeout,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	IsActive bool              `json:"is_active,omitempty"`
+}
+
+type HealthCheckConfig struct {
+	Enabled       bool   `json:"enabled,omitempty"`
+	Path          string `json:"path,omitempty"`
+	Method        string `json:"method,omitempty"`
+	Interval      int32  `json:"interval,omitempty"`
+	Timeout       int32  `json:"timeout,omitempty"`
+	FailThreshold int32  `json:"fail_threshold,omitempty"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
+type CircuitBreakerConfig struct {
+	Enabled            bool    `json:"enabled,omitempty"`
+	ErrorRateThreshold float64 `json:"error_rate_threshold,omitempty"`
+	MinRequests        int32   `json:"min_requests,omitempty"`
+	Window             int32   `json:"window,omitempty"`
+	OpenDuration       int32   `json:"open_duration,omitempty"`
+	HalfOpenRequests   int32   `json:"half_open_requests,omitempty"`
+}
+
+type ServiceHealth struct {
+	EndpointUrl       string `json:"endpoint_url,omitempty"`
+	IsHealthy         bool   `json:"is_healthy,omitempty"`
+	LastChecked       string `json:"last_checked,omitempty"`
+	LastSuccessful    string `json:"last_successful,omitempty"`
+	LastFailure       string `json:"last_failure,omitempty"`
+	ConsecutiveFails  int32  `json:"consecutive_fails,omitempty"`
+	LastError         string `json:"last_error,omitempty"`
+	CircuitState      string `json:"circuit_state,omitempty"`
+	ActiveConnections int32  `json:"active_connections,omitempty"`
+}
+
+type ResolveTenantByHostRequest struct {
+	Host       string `json:"host,omitempty"`
+	BaseDomain string `json:"base_domain,omitempty"`
+}
+
+type ResolveTenantByHostResponse struct {
+	Tenant *Tenant `json:"tenant,omitempty"`
+}
+
+type ReportServiceOutcomeRequest struct {
+	TenantId    string `json:"tenant_id,omitempty"`
+	ServiceName string `json:"service_name,omitempty"`
+	Url         string `json:"url,omitempty"`
+	Success     bool   `json:"success,omitempty"`
+}
+
+type ReportServiceOutcomeResponse struct{}
+
+type ServiceConnectionRequest struct {
+	TenantId    string `json:"tenant_id,omitempty"`
+	ServiceName string `json:"service_name,omitempty"`
+	Url         string `json:"url,omitempty"`
+}
+
+type ServiceConnectionResponse struct{}
+
+// Custom Domain Messages
+
+type TenantDomain struct {
+	Id                 string `json:"id,omitempty"`
+	TenantId           string `json:"tenant_id,omitempty"`
+	Domain             string `json:"domain,omitempty"`
+	Status             string `json:"status,omitempty"`
+	VerificationMethod string `json:"verification_method,omitempty"`
+	Token              string `json:"token,omitempty"`
+	IsPrimary          bool   `json:"is_primary,omitempty"`
+	Attempts           int32  `json:"attempts,omitempty"`
+	LastError          string `json:"last_error,omitempty"`
+	TxtRecordName      string `json:"txt_record_name,omitempty"`
+	TxtRecordValue     string `json:"txt_record_value,omitempty"`
+	WellKnownUrl       string `json:"well_known_url,omitempty"`
+	VerifiedAt         string `json:"verified_at,omitempty"`
+	ExpiresAt          string `json:"expires_at,omitempty"`
+	CreatedAt          string `json:"created_at,omitempty"`
+}
+
+type ClaimDomainRequest struct {
+	TenantId           string `json:"tenant_id,omitempty"`
+	Domain             string `json:"domain,omitempty"`
+	VerificationMethod string `json:"verification_method,omitempty"`
+	IsPrimary          bool   `json:"is_primary,omitempty"`
+}
+
+type ClaimDomainResponse struct {
+	Domain *TenantDomain `json:"domain,omitempty"`
+}
+
+type ListDomainsRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+}
+
+type ListDomainsResponse struct {
+	Domains []*TenantDomain `json:"domains,omitempty"`
+}
+
+type VerifyDomainRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	DomainId string `json:"domain_id,omitempty"`
+}
+
+type VerifyDomainResponse struct {
+	Domain *TenantDomain `json:"domain,omitempty"`
+}
+
+type SetPrimaryDomainRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	DomainId string `json:"domain_id,omitempty"`
+}
+
+type SetPrimaryDomainResponse struct {
+	Domain *TenantDomain `json:"domain,omitempty"`
+}
+
+type RemoveDomainRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	DomainId string `json:"domain_id,omitempty"`
+}
+
+type RemoveDomainResponse struct {
+	Success bool `json:"success,omitempty"`
+}
+
+type SuspendTenantRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type SuspendTenantResponse struct {
+	Tenant *Tenant `json:"tenant,omitempty"`
+}
+
+type ReactivateTenantRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type ReactivateTenantResponse struct {
+	Tenant *Tenant `json:"tenant,omitempty"`
+}
+
+type CancelTenantDeletionRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type CancelTenantDeletionResponse struct {
+	Tenant *Tenant `json:"tenant,omitempty"`
+}
+
+type PurgeTenantRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type PurgeTenantResponse struct {
+	Report *PurgeReport `json:"report,omitempty"`
+}
+
+type GetPurgeReportRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+}
+
+type GetPurgeReportResponse struct {
+	Report *PurgeReport `json:"report,omitempty"`
+}
+
+type PurgeReport struct {
+	Id            string           `json:"id,omitempty"`
+	TenantId      string           `json:"tenant_id,omitempty"`
+	TenantName    string           `json:"tenant_name,omitempty"`
+	Status        string           `json:"status,omitempty"`
+	Reason        string           `json:"reason,omitempty"`
+	Actor         string           `json:"actor,omitempty"`
+	PurgeAfter    string           `json:"purge_after,omitempty"`
+	DeletedCounts map[string]int64 `json:"deleted_counts,omitempty"`
+	Errors        []string         `json:"errors,omitempty"`
+	StartedAt     string           `json:"started_at,omitempty"`
+	CompletedAt   string           `json:"completed_at,omitempty"`
+}
+
+// Role Messages
+
+type TenantRole struct {
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	IsBuiltIn   bool     `json:"is_built_in,omitempty"`
+	CreatedAt   string   `json:"created_at,omitempty"`
+	UpdatedAt   string   `json:"updated_at,omitempty"`
+}
+
+type ListTenantRolesRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+}
+
+type ListTenantRolesResponse struct {
+	Roles []*TenantRole `json:"roles,omitempty"`
+}
+
+type CreateTenantRoleRequest struct {
+	TenantId    string   `json:"tenant_id,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+type CreateTenantRoleResponse struct {
+	Role *TenantRole `json:"role,omitempty"`
+}
+
+type UpdateTenantRoleRequest struct {
+	TenantId    string   `json:"tenant_id,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+type UpdateTenantRoleResponse struct {
+	Role *TenantRole `json:"role,omitempty"`
+}
+
+type DeleteTenantRoleRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+type DeleteTenantRoleResponse struct {
+	Success bool `json:"success,omitempty"`
+}
+
+type CheckPermissionRequest struct {
+	TenantId   string `json:"tenant_id,omitempty"`
+	UserId     string `json:"user_id,omitempty"`
+	Permission string `json:"permission,omitempty"`
+}
+
+type CheckPermissionResponse struct {
+	Allowed       bool     `json:"allowed,omitempty"`
+	Role          string   `json:"role,omitempty"`
+	Permissions   []string `json:"permissions,omitempty"`
+	InheritedFrom string   `json:"inherited_from,omitempty"`
+}
+
+type TenantInvitation struct {
+	Id         string `json:"id,omitempty"`
+	TenantId   string `json:"tenant_id,omitempty"`
+	Email      string `json:"email,omitempty"`
+	Phone      string `json:"phone,omitempty"`
+	Role       string `json:"role,omitempty"`
+	Status     string `json:"status,omitempty"`
+	InvitedBy  string `json:"invited_by,omitempty"`
+	AcceptedBy string `json:"accepted_by,omitempty"`
+	SendCount  int32  `json:"send_count,omitempty"`
+	ExpiresAt  string `json:"expires_at,omitempty"`
+	CreatedAt  string `json:"created_at,omitempty"`
+}
+
+type CreateInvitationRequest struct {
+	TenantId  string `json:"tenant_id,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Phone     string `json:"phone,omitempty"`
+	Role      string `json:"role,omitempty"`
+	InvitedBy string `json:"invited_by,omitempty"`
+}
+
+type CreateInvitationResponse struct {
+	Invitation *TenantInvitation `json:"invitation,omitempty"`
+	Token      string            `json:"token,omitempty"`
+}
+
+type ListInvitationsRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	Status   string `json:"status,omitempty"`
+}
+
+type ListInvitationsResponse struct {
+	Invitations []*TenantInvitation `json:"invitations,omitempty"`
+}
+
+type ResendInvitationRequest struct {
+	TenantId     string `json:"tenant_id,omitempty"`
+	InvitationId string `json:"invitation_id,omitempty"`
+}
+
+type ResendInvitationResponse struct {
+	Invitation *TenantInvitation `json:"invitation,omitempty"`
+	Token      string            `json:"token,omitempty"`
+}
+
+type RevokeInvitationRequest struct {
+	TenantId     string `json:"tenant_id,omitempty"`
+	InvitationId string `json:"invitation_id,omitempty"`
+}
+
+type RevokeInvitationResponse struct {
+	Success bool `json:"success,omitempty"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token,omitempty"`
+}
+
+type AcceptInvitationResponse struct {
+	Invitation *TenantInvitation `json:"invitation,omitempty"`
+}
+
+type DeclineInvitationRequest struct {
+	Token string `json:"token,omitempty"`
+}
+
+type DeclineInvitationResponse struct {
+	Invitation *TenantInvitation `json:"invitation,omitempty"`
+}
+
+type TenantMember struct {
+	UserId    string `json:"user_id,omitempty"`
+	Role      string `json:"role,omitempty"`
+	JoinedAt  string `json:"joined_at,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+type ListTenantMembersRequest struct {
+	TenantId     string `json:"tenant_id,omitempty"`
+	Page         int32  `json:"page,omitempty"`
+	PageSize     int32  `json:"page_size,omitempty"`
+	Role         string `json:"role,omitempty"`
+	Search       string `json:"search,omitempty"`
+	PageToken    string `json:"page_token,omitempty"`
+	IncludeTotal bool   `json:"include_total,omitempty"`
+}
+
+type ListTenantMembersResponse struct {
+	Members       []*TenantMember `json:"members,omitempty"`
+	Total         int32           `json:"total,omitempty"`
+	NextPageToken string          `json:"next_page_token,omitempty"`
+	PrevPageToken string          `json:"prev_page_token,omitempty"`
+}
+
+type UserTenant struct {
+	TenantId   string `json:"tenant_id,omitempty"`
+	TenantName string `json:"tenant_name,omitempty"`
+	Subdomain  string `json:"subdomain,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Role       string `json:"role,omitempty"`
+	JoinedAt   string `json:"joined_at,omitempty"`
+}
+
+type ListUserTenantsRequest struct {
+	UserId       string `json:"user_id,omitempty"`
+	Page         int32  `json:"page,omitempty"`
+	PageSize     int32  `json:"page_size,omitempty"`
+	Role         string `json:"role,omitempty"`
+	PageToken    string `json:"page_token,omitempty"`
+	IncludeTotal bool   `json:"include_total,omitempty"`
+}
+
+type ListUserTenantsResponse struct {
+	Tenants       []*UserTenant `json:"tenants,omitempty"`
+	Total         int32         `json:"total,omitempty"`
+	NextPageToken string        `json:"next_page_token,omitempty"`
+	PrevPageToken string        `json:"prev_page_token,omitempty"`
+}
+
+type UpdateMemberRoleRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	UserId   string `json:"user_id,omitempty"`
+	Role     string `json:"role,omitempty"`
+}
+
+type UpdateMemberRoleResponse struct {
+	Member *TenantMember `json:"member,omitempty"`
+}
+
+type TransferOwnershipRequest struct {
+	TenantId   string `json:"tenant_id,omitempty"`
+	FromUserId string `json:"from_user_id,omitempty"`
+	ToUserId   string `json:"to_user_id,omitempty"`
+	FromRole   string `json:"from_role,omitempty"`
+}
+
+type TransferOwnershipResponse struct {
+	Success bool `json:"success,omitempty"`
+}
+
+type MembershipEvent struct {
+	Type         string `json:"type,omitempty"`
+	Role         string `json:"role,omitempty"`
+	PreviousRole string `json:"previous_role,omitempty"`
+	At           string `json:"at,omitempty"`
+}
+
+type GetMemberHistoryRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	UserId   string `json:"user_id,omitempty"`
+}
+
+type GetMemberHistoryResponse struct {
+	UserId   string             `json:"user_id,omitempty"`
+	Role     string             `json:"role,omitempty"`
+	IsActive bool               `json:"is_active,omitempty"`
+	History  []*MembershipEvent `json:"history,omitempty"`
+}
+
+type Entitlements struct {
+	MaxMembers            int32           `json:"max_members,omitempty"`
+	MaxServiceConfigs     int32           `json:"max_service_configs,omitempty"`
+	LoadBalanceStrategies []string        `json:"load_balance_strategies,omitempty"`
+	CustomDomains         bool            `json:"custom_domains,omitempty"`
+	RateLimitClass        string          `json:"rate_limit_class,omitempty"`
+	Features              map[string]bool `json:"features,omitempty"`
+}
+
+type EntitlementOverrides struct {
+	MaxMembers            *int32          `json:"max_members,omitempty"`
+	MaxServiceConfigs     *int32          `json:"max_service_configs,omitempty"`
+	LoadBalanceStrategies []string        `json:"load_balance_strategies,omitempty"`
+	CustomDomains         *bool           `json:"custom_domains,omitempty"`
+	RateLimitClass        string          `json:"rate_limit_class,omitempty"`
+	Features              map[string]bool `json:"features,omitempty"`
+}
+
+type GetEntitlementsRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+}
+
+type GetEntitlementsResponse struct {
+	TenantId       string        `json:"tenant_id,omitempty"`
+	Tier           string        `json:"tier,omitempty"`
+	Entitlements   *Entitlements `json:"entitlements,omitempty"`
+	Members        int64         `json:"members,omitempty"`
+	ServiceConfigs int64         `json:"service_configs,omitempty"`
+}
+
+type UpdateEntitlementOverridesRequest struct {
+	TenantId  string                `json:"tenant_id,omitempty"`
+	Overrides *EntitlementOverrides `json:"overrides,omitempty"`
+}
+
+type PlanChange struct {
+	From        string   `json:"from,omitempty"`
+	To          string   `json:"to,omitempty"`
+	Reason      string   `json:"reason,omitempty"`
+	Actor       string   `json:"actor,omitempty"`
+	Outcome     string   `json:"outcome,omitempty"`
+	Violations  []string `json:"violations,omitempty"`
+	RequestedAt string   `json:"requested_at,omitempty"`
+	EffectiveAt string   `json:"effective_at,omitempty"`
+	CompletedAt string   `json:"completed_at,omitempty"`
+}
+
+type GetPlanRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+}
+
+type GetPlanResponse struct {
+	TenantId        string        `json:"tenant_id,omitempty"`
+	Tier            string        `json:"tier,omitempty"`
+	ScheduledChange *PlanChange   `json:"scheduled_change,omitempty"`
+	History         []*PlanChange `json:"history,omitempty"`
+}
+
+type ChangePlanRequest struct {
+	TenantId    string `json:"tenant_id,omitempty"`
+	Tier        string `json:"tier,omitempty"`
+	EffectiveAt string `json:"effective_at,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+type CancelScheduledPlanChangeRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type TenantTrial struct {
+	Tier          string `json:"tier,omitempty"`
+	Status        string `json:"status,omitempty"`
+	StartedAt     string `json:"started_at,omitempty"`
+	EndsAt        string `json:"ends_at,omitempty"`
+	GraceEndsAt   string `json:"grace_ends_at,omitempty"`
+	EndedAt       string `json:"ended_at,omitempty"`
+	DaysRemaining int32  `json:"days_remaining,omitempty"`
+}
+
+type StartTrialRequest struct {
+	TenantId     string `json:"tenant_id,omitempty"`
+	Tier         string `json:"tier,omitempty"`
+	DurationDays int32  `json:"duration_days,omitempty"`
+}
+
+type StartTrialResponse struct {
+	Tenant *Tenant `json:"tenant,omitempty"`
+}
+
+type ListExpiringTrialsRequest struct {
+	WithinDays int32 `json:"within_days,omitempty"`
+	Page       int32 `json:"page,omitempty"`
+	PageSize   int32 `json:"page_size,omitempty"`
+}
+
+type ListExpiringTrialsResponse struct {
+	Tenants []*Tenant `json:"tenants,omitempty"`
+	Total   int32     `json:"total,omitempty"`
+}
+
+type ListChildTenantsRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	Page     int32  `json:"page,omitempty"`
+	PageSize int32  `json:"page_size,omitempty"`
+}
+
+type ListChildTenantsResponse struct {
+	Tenants []*Tenant `json:"tenants,omitempty"`
+	Total   int32     `json:"total,omitempty"`
+}
+
+type GetTenantAncestorsRequest struct {
+	TenantId string `json:"tenant_id,omitempty"`
+}
+
+type GetTenantAncestorsResponse struct {
+	Tenants []*Tenant `json:"tenants,omitempty"`
+}
+
+type SetTenantParentRequest struct {
+	TenantId        string `json:"tenant_id,omitempty"`
+	ParentId        string `json:"parent_id,omitempty"`
+	ExpectedVersion int64  `json:"expected_version,omitempty"`
+}
+
+type SetTenantParentResponse struct {
+	Tenant *Tenant `json:"tenant,omitempty"`
+}
+
+// Settings Registry Messages
+
+type SettingDefinition struct {
+	Key          string           `json:"key,omitempty"`
+	Service      string           `json:"service,omitempty"`
+	Description  string           `json:"description,omitempty"`
+	Schema       *structpb.Struct `json:"schema,omitempty"`
+	DefaultValue *structpb.Value  `json:"default_value,omitempty"`
+	Visibility   string           `json:"visibility,omitempty"`
+	CreatedAt    string           `json:"created_at,omitempty"`
+	UpdatedAt    string           `json:"updated_at,omitempty"`
+}
+
+type RegisterSettingRequest struct {
+	Key          string           `json:"key,omitempty"`
+	Service      string           `json:"service,omitempty"`
+	Description  string           `json:"description,omitempty"`
+	Schema       *structpb.Struct `json:"schema,omitempty"`
+	DefaultValue *structpb.Value  `json:"default_value,omitempty"`
+	Visibility   string           `json:"visibility,omitempty"`
+}
+
+type RegisterSettingResponse struct {
+	Definition *SettingDefinition `json:"definition,omitempty"`
+}
+
+type ListSettingDefinitionsRequest struct {
+	Service string `json:"service,omitempty"`
+}
+
+type ListSettingDefinitionsResponse struct {
+	Definitions []*SettingDefinition `json:"definitions,omitempty"`
+}
+
+type DeleteSettingDefinitionRequest struct {
+	Key string `json:"key,omitempty"`
+}
+
+type DeleteSettingDefinitionResponse struct {
+	Success bool `json:"success,omitempty"`
+}
+
+type GetEffectiveSettingsRequest struct {
+	TenantId   string `json:"tenant_id,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+}
+
+type GetEffectiveSettingsResponse struct {
+	Settings   *structpb.Struct `json:"settings,omitempty"`
+	Defaulted  []string         `json:"defaulted,omitempty"`
+	Visibility string           `json:"visibility,omitempty"`
+}
+
+type AuditActor struct {
+	Id   string `json:"id,omitempty"`
+	Type string `json:"type,omitempty"`
+}
+
+type AuditChange struct {
+	Field  string          `json:"field,omitempty"`
+	Before *structpb.Value `json:"before,omitempty"`
+	After  *structpb.Value `json:"after,omitempty"`
+}
+
+type AuditEvent struct {
+	Id         string         `json:"id,omitempty"`
+	TenantId   string         `json:"tenant_id,omitempty"`
+	Actor      *AuditActor    `json:"actor,omitempty"`
+	Action     string         `json:"action,omitempty"`
+	TargetType string         `json:"target_type,omitempty"`
+	TargetId   string         `json:"target_id,omitempty"`
+	Reason     string         `json:"reason,omitempty"`
+	Changes    []*AuditChange `json:"changes,omitempty"`
+	CreatedAt  string         `json:"created_at,omitempty"`
+}
+
+type ListAuditEventsRequest struct {
+	TenantId      string `json:"tenant_id,omitempty"`
+	Actor         string `json:"actor,omitempty"`
+	Action        string `json:"action,omitempty"`
+	TargetType    string `json:"target_type,omitempty"`
+	TargetId      string `json:"target_id,omitempty"`
+	CreatedAfter  string `json:"created_after,omitempty"`
+	CreatedBefore string `json:"created_before,omitempty"`
+	Page          int32  `json:"page,omitempty"`
+	PageSize      int32  `json:"page_size,omitempty"`
+	PageToken     string `json:"page_token,omitempty"`
+	IncludeTotal  bool   `json:"include_total,omitempty"`
+}
+
+type ListAuditEventsResponse struct {
+	Events        []*AuditEvent `json:"events,omitempty"`
+	Total         int32         `json:"total,omitempty"`
+	NextPageToken string        `json:"next_page_token,omitempty"`
+	PrevPageToken string        `json:"prev_page_token,omitempty"`
+}
+
+// TenantServiceClient is the client API for TenantService.
+type TenantServiceClient interface {
+	GetTenant(ctx context.Context, in *GetTenantRequest, opts ...grpc.CallOption) (*GetTenantResponse, error)
+	ListTenants(ctx context.Context, in *ListTenantsRequest, opts ...grpc.CallOption) (*ListTenantsResponse, error)
+	CreateTenant(ctx context.Context, in *CreateTenantRequest, opts ...grpc.CallOption) (*CreateTenantResponse, error)
+	UpdateTenant(ctx context.Context, in *UpdateTenantRequest, opts ...grpc.CallOption) (*UpdateTenantResponse, error)
+	DeleteTenant(ctx context.Context, in *DeleteTenantRequest, opts ...grpc.CallOption) (*DeleteTenantResponse, error)
+	AddUserToTenant(ctx context.Context, in *AddUserToTenantRequest, opts ...grpc.CallOption) (*AddUserToTenantResponse, error)
+	RemoveUserFromTenant(ctx context.Context, in *RemoveUserFromTenantRequest, opts ...grpc.CallOption) (*RemoveUserFromTenantResponse, error)
+	GetTenantConfig(ctx context.Context, in *GetTenantConfigRequest, opts ...grpc.CallOption) (*GetTenantConfigResponse, error)
+	GetEffectiveTenantConfig(ctx context.Context, in *GetTenantConfigRequest, opts ...grpc.CallOption) (*GetTenantConfigResponse, error)
+	UpdateTenantConfig(ctx context.Context, in *UpdateTenantConfigRequest, opts ...grpc.CallOption) (*UpdateTenantConfigResponse, error)
+	GetDefaultService(ctx context.Context, in *GetDefaultServiceRequest, opts ...grpc.CallOption) (*GetDefaultServiceResponse, error)
+	GetServiceConfig(ctx context.Context, in *GetServiceConfigRequest, opts ...grpc.CallOption) (*GetServiceConfigResponse, error)
+	UpdateServiceConfig(ctx context.Context, in *UpdateServiceConfigRequest, opts ...grpc.CallOption) (*UpdateServiceConfigResponse, error)
+	GetServiceURL(ctx context.Context, in *GetServiceURLRequest, opts ...grpc.CallOption) (*GetServiceURLResponse, error)
+	ListTenantServices(ctx context.Context, in *ListTenantServicesRequest, opts ...grpc.CallOption) (*ListTenantServicesResponse, error)
+	GetServiceHealth(ctx context.Context, in *GetServiceHealthRequest, opts ...grpc.CallOption) (*GetServiceHealthResponse, error)
+	ResolveTenantByHost(ctx context.Context, in *ResolveTenantByHostRequest, opts ...grpc.CallOption) (*ResolveTenantByHostResponse, error)
+	ReportServiceOutcome(ctx context.Context, in *ReportServiceOutcomeRequest, opts ...grpc.CallOption) (*ReportServiceOutcomeResponse, error)
+	ReleaseServiceConnection(ctx context.Context, in *ServiceConnectionRequest, opts ...grpc.CallOption) (*ServiceConnectionResponse, error)
+	ClaimDomain(ctx context.Context, in *ClaimDomainRequest, opts ...grpc.CallOption) (*ClaimDomainResponse, error)
+	ListDomains(ctx context.Context, in *ListDomainsRequest, opts ...grpc.CallOption) (*ListDomainsResponse, error)
+	VerifyDomain(ctx context.Context, in *VerifyDomainRequest, opts ...grpc.CallOption) (*VerifyDomainResponse, error)
+	SetPrimaryDomain(ctx context.Context, in *SetPrimaryDomainRequest, opts ...grpc.CallOption) (*SetPrimaryDomainResponse, error)
+	RemoveDomain(ctx context.Context, in *RemoveDomainRequest, opts ...grpc.CallOption) (*RemoveDomainResponse, error)
+	SuspendTenant(ctx context.Context, in *SuspendTenantRequest, opts ...grpc.CallOption) (*SuspendTenantResponse, error)
+	ReactivateTenant(ctx context.Context, in *ReactivateTenantRequest, opts ...grpc.CallOption) (*ReactivateTenantResponse, error)
+	CancelTenantDeletion(ctx context.Context, in *CancelTenantDeletionRequest, opts ...grpc.CallOption) (*CancelTenantDeletionResponse, error)
+	PurgeTenant(ctx context.Context, in *PurgeTenantRequest, opts ...grpc.CallOption) (*PurgeTenantResponse, error)
+	GetPurgeReport(ctx context.Context, in *GetPurgeReportRequest, opts ...grpc.CallOption) (*GetPurgeReportResponse, error)
+	ListTenantRoles(ctx context.Context, in *ListTenantRolesRequest, opts ...grpc.CallOption) (*ListTenantRolesResponse, error)
+	CreateTenantRole(ctx context.Context, in *CreateTenantRoleRequest, opts ...grpc.CallOption) (*CreateTenantRoleResponse, error)
+	UpdateTenantRole(ctx context.Context, in *UpdateTenantRoleRequest, opts ...grpc.CallOption) (*UpdateTenantRoleResponse, error)
+	DeleteTenantRole(ctx context.Context, in *DeleteTenantRoleRequest, opts ...grpc.CallOption) (*DeleteTenantRoleResponse, error)
+	CheckPermission(ctx context.Context, in *CheckPermissionRequest, opts ...grpc.CallOption) (*CheckPermissionResponse, error)
+	CreateInvitation(ctx context.Context, in *CreateInvitationRequest, opts ...grpc.CallOption) (*CreateInvitationResponse, error)
+	ListInvitations(ctx context.Context, in *ListInvitationsRequest, opts ...grpc.CallOption) (*ListInvitationsResponse, error)
+	ResendInvitation(ctx context.Context, in *ResendInvitationRequest, opts ...grpc.CallOption) (*ResendInvitationResponse, error)
+	RevokeInvitation(ctx context.Context, in *RevokeInvitationRequest, opts ...grpc.CallOption) (*RevokeInvitationResponse, error)
+	AcceptInvitation(ctx context.Context, in *AcceptInvitationRequest, opts ...grpc.CallOption) (*AcceptInvitationResponse, error)
+	DeclineInvitation(ctx context.Context, in *DeclineInvitationRequest, opts ...grpc.CallOption) (*DeclineInvitationResponse, error)
+	ListTenantMembers(ctx context.Context, in *ListTenantMembersRequest, opts ...grpc.CallOption) (*ListTenantMembersResponse, error)
+	ListUserTenants(ctx context.Context, in *ListUserTenantsRequest, opts ...grpc.CallOption) (*ListUserTenantsResponse, error)
+	UpdateMemberRole(ctx context.Context, in *UpdateMemberRoleRequest, opts ...grpc.CallOption) (*UpdateMemberRoleResponse, error)
+	TransferOwnership(ctx context.Context, in *TransferOwnershipRequest, opts ...grpc.CallOption) (*TransferOwnershipResponse, error)
+	GetMemberHistory(ctx context.Context, in *GetMemberHistoryRequest, opts ...grpc.CallOption) (*GetMemberHistoryResponse, error)
+	GetEntitlements(ctx context.Context, in *GetEntitlementsRequest, opts ...grpc.CallOption) (*GetEntitlementsResponse, error)
+	UpdateEntitlementOverrides(ctx context.Context, in *UpdateEntitlementOverridesRequest, opts ...grpc.CallOption) (*GetEntitlementsResponse, error)
+	GetPlan(ctx context.Context, in *GetPlanRequest, opts ...grpc.CallOption) (*GetPlanResponse, error)
+	ChangePlan(ctx context.Context, in *ChangePlanRequest, opts ...grpc.CallOption) (*GetPlanResponse, error)
+	CancelScheduledPlanChange(ctx context.Context, in *CancelScheduledPlanChangeRequest, opts ...grpc.CallOption) (*GetPlanResponse, error)
+	StartTrial(ctx context.Context, in *StartTrialRequest, opts ...grpc.CallOption) (*StartTrialResponse, error)
+	ListExpiringTrials(ctx context.Context, in *ListExpiringTrialsRequest, opts ...grpc.CallOption) (*ListExpiringTrialsResponse, error)
+	ListChildTenants(ctx context.Context, in *ListChildTenantsRequest, opts ...grpc.CallOption) (*ListChildTenantsResponse, error)
+	GetTenantAncestors(ctx context.Context, in *GetTenantAncestorsRequest, opts ...grpc.CallOption) (*GetTenantAncestorsResponse, error)
+	SetTenantParent(ctx context.Context, in *SetTenantParentRequest, opts ...grpc.CallOption) (*SetTenantParentResponse, error)
+	RegisterSetting(ctx context.Context, in *RegisterSettingRequest, opts ...grpc.CallOption) (*RegisterSettingResponse, error)
+	ListSettingDefinitions(ctx context.Context, in *ListSettingDefinitionsRequest, opts ...grpc.CallOption) (*ListSettingDefinitionsResponse, error)
+	DeleteSettingDefinition(ctx context.Context, in *DeleteSettingDefinitionRequest, opts ...grpc.CallOption) (*DeleteSettingDefinitionResponse, error)
+	GetEffectiveSettings(ctx context.Context, in *GetEffectiveSettingsRequest, opts ...grpc.CallOption) (*GetEffectiveSettingsResponse, error)
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
+}
+
+type tenantServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTenantServiceClient(cc grpc.ClientConnInterface) TenantServiceClient {
+	return &tenantServiceClient{cc}
+}
+
+func (c *tenantServiceClient) GetTenant(ctx context.Context, in *GetTenantRequest, opts ...grpc.CallOption) (*GetTenantResponse, error) {
+	out := new(GetTenantResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/GetTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ListTenants(ctx context.Context, in *ListTenantsRequest, opts ...grpc.CallOption) (*ListTenantsResponse, error) {
+	out := new(ListTenantsResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ListTenants", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) CreateTenant(ctx context.Context, in *CreateTenantRequest, opts ...grpc.CallOption) (*CreateTenantResponse, error) {
+	out := new(CreateTenantResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/CreateTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) UpdateTenant(ctx context.Context, in *UpdateTenantRequest, opts ...grpc.CallOption) (*UpdateTenantResponse, error) {
+	out := new(UpdateTenantResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/UpdateTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) DeleteTenant(ctx context.Context, in *DeleteTenantRequest, opts ...grpc.CallOption) (*DeleteTenantResponse, error) {
+	out := new(DeleteTenantResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/DeleteTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) AddUserToTenant(ctx context.Context, in *AddUserToTenantRequest, opts ...grpc.CallOption) (*AddUserToTenantResponse, error) {
+	out := new(AddUserToTenantResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/AddUserToTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) RemoveUserFromTenant(ctx context.Context, in *RemoveUserFromTenantRequest, opts ...grpc.CallOption) (*RemoveUserFromTenantResponse, error) {
+	out := new(RemoveUserFromTenantResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/RemoveUserFromTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) GetTenantConfig(ctx context.Context, in *GetTenantConfigRequest, opts ...grpc.CallOption) (*GetTenantConfigResponse, error) {
+	out := new(GetTenantConfigResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/GetTenantConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) GetEffectiveTenantConfig(ctx context.Context, in *GetTenantConfigRequest, opts ...grpc.CallOption) (*GetTenantConfigResponse, error) {
+	out := new(GetTenantConfigResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/GetEffectiveTenantConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) UpdateTenantConfig(ctx context.Context, in *UpdateTenantConfigRequest, opts ...grpc.CallOption) (*UpdateTenantConfigResponse, error) {
+	out := new(UpdateTenantConfigResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/UpdateTenantConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) GetDefaultService(ctx context.Context, in *GetDefaultServiceRequest, opts ...grpc.CallOption) (*GetDefaultServiceResponse, error) {
+	out := new(GetDefaultServiceResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/GetDefaultService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) GetServiceConfig(ctx context.Context, in *GetServiceConfigRequest, opts ...grpc.CallOption) (*GetServiceConfigResponse, error) {
+	out := new(GetServiceConfigResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/GetServiceConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) UpdateServiceConfig(ctx context.Context, in *UpdateServiceConfigRequest, opts ...grpc.CallOption) (*UpdateServiceConfigResponse, error) {
+	out := new(UpdateServiceConfigResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/UpdateServiceConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) GetServiceURL(ctx context.Context, in *GetServiceURLRequest, opts ...grpc.CallOption) (*GetServiceURLResponse, error) {
+	out := new(GetServiceURLResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/GetServiceURL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ListTenantServices(ctx context.Context, in *ListTenantServicesRequest, opts ...grpc.CallOption) (*ListTenantServicesResponse, error) {
+	out := new(ListTenantServicesResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ListTenantServices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) GetServiceHealth(ctx context.Context, in *GetServiceHealthRequest, opts ...grpc.CallOption) (*GetServiceHealthResponse, error) {
+	out := new(GetServiceHealthResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/GetServiceHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ResolveTenantByHost(ctx context.Context, in *ResolveTenantByHostRequest, opts ...grpc.CallOption) (*ResolveTenantByHostResponse, error) {
+	out := new(ResolveTenantByHostResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ResolveTenantByHost", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ReportServiceOutcome(ctx context.Context, in *ReportServiceOutcomeRequest, opts ...grpc.CallOption) (*ReportServiceOutcomeResponse, error) {
+	out := new(ReportServiceOutcomeResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ReportServiceOutcome", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ReleaseServiceConnection(ctx context.Context, in *ServiceConnectionRequest, opts ...grpc.CallOption) (*ServiceConnectionResponse, error) {
+	out := new(ServiceConnectionResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ReleaseServiceConnection", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ClaimDomain(ctx context.Context, in *ClaimDomainRequest, opts ...grpc.CallOption) (*ClaimDomainResponse, error) {
+	out := new(ClaimDomainResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ClaimDomain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ListDomains(ctx context.Context, in *ListDomainsRequest, opts ...grpc.CallOption) (*ListDomainsResponse, error) {
+	out := new(ListDomainsResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ListDomains", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) VerifyDomain(ctx context.Context, in *VerifyDomainRequest, opts ...grpc.CallOption) (*VerifyDomainResponse, error) {
+	out := new(VerifyDomainResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/VerifyDomain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) SetPrimaryDomain(ctx context.Context, in *SetPrimaryDomainRequest, opts ...grpc.CallOption) (*SetPrimaryDomainResponse, error) {
+	out := new(SetPrimaryDomainResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/SetPrimaryDomain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) RemoveDomain(ctx context.Context, in *RemoveDomainRequest, opts ...grpc.CallOption) (*RemoveDomainResponse, error) {
+	out := new(RemoveDomainResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/RemoveDomain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) SuspendTenant(ctx context.Context, in *SuspendTenantRequest, opts ...grpc.CallOption) (*SuspendTenantResponse, error) {
+	out := new(SuspendTenantResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/SuspendTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ReactivateTenant(ctx context.Context, in *ReactivateTenantRequest, opts ...grpc.CallOption) (*ReactivateTenantResponse, error) {
+	out := new(ReactivateTenantResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ReactivateTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) CancelTenantDeletion(ctx context.Context, in *CancelTenantDeletionRequest, opts ...grpc.CallOption) (*CancelTenantDeletionResponse, error) {
+	out := new(CancelTenantDeletionResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/CancelTenantDeletion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) PurgeTenant(ctx context.Context, in *PurgeTenantRequest, opts ...grpc.CallOption) (*PurgeTenantResponse, error) {
+	out := new(PurgeTenantResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/PurgeTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) GetPurgeReport(ctx context.Context, in *GetPurgeReportRequest, opts ...grpc.CallOption) (*GetPurgeReportResponse, error) {
+	out := new(GetPurgeReportResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/GetPurgeReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ListTenantRoles(ctx context.Context, in *ListTenantRolesRequest, opts ...grpc.CallOption) (*ListTenantRolesResponse, error) {
+	out := new(ListTenantRolesResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ListTenantRoles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) CreateTenantRole(ctx context.Context, in *CreateTenantRoleRequest, opts ...grpc.CallOption) (*CreateTenantRoleResponse, error) {
+	out := new(CreateTenantRoleResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/CreateTenantRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) UpdateTenantRole(ctx context.Context, in *UpdateTenantRoleRequest, opts ...grpc.CallOption) (*UpdateTenantRoleResponse, error) {
+	out := new(UpdateTenantRoleResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/UpdateTenantRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) DeleteTenantRole(ctx context.Context, in *DeleteTenantRoleRequest, opts ...grpc.CallOption) (*DeleteTenantRoleResponse, error) {
+	out := new(DeleteTenantRoleResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/DeleteTenantRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) CheckPermission(ctx context.Context, in *CheckPermissionRequest, opts ...grpc.CallOption) (*CheckPermissionResponse, error) {
+	out := new(CheckPermissionResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/CheckPermission", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) CreateInvitation(ctx context.Context, in *CreateInvitationRequest, opts ...grpc.CallOption) (*CreateInvitationResponse, error) {
+	out := new(CreateInvitationResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/CreateInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ListInvitations(ctx context.Context, in *ListInvitationsRequest, opts ...grpc.CallOption) (*ListInvitationsResponse, error) {
+	out := new(ListInvitationsResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ListInvitations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ResendInvitation(ctx context.Context, in *ResendInvitationRequest, opts ...grpc.CallOption) (*ResendInvitationResponse, error) {
+	out := new(ResendInvitationResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ResendInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) RevokeInvitation(ctx context.Context, in *RevokeInvitationRequest, opts ...grpc.CallOption) (*RevokeInvitationResponse, error) {
+	out := new(RevokeInvitationResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/RevokeInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) AcceptInvitation(ctx context.Context, in *AcceptInvitationRequest, opts ...grpc.CallOption) (*AcceptInvitationResponse, error) {
+	out := new(AcceptInvitationResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/AcceptInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) DeclineInvitation(ctx context.Context, in *DeclineInvitationRequest, opts ...grpc.CallOption) (*DeclineInvitationResponse, error) {
+	out := new(DeclineInvitationResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/DeclineInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ListTenantMembers(ctx context.Context, in *ListTenantMembersRequest, opts ...grpc.CallOption) (*ListTenantMembersResponse, error) {
+	out := new(ListTenantMembersResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ListTenantMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ListUserTenants(ctx context.Context, in *ListUserTenantsRequest, opts ...grpc.CallOption) (*ListUserTenantsResponse, error) {
+	out := new(ListUserTenantsResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ListUserTenants", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) UpdateMemberRole(ctx context.Context, in *UpdateMemberRoleRequest, opts ...grpc.CallOption) (*UpdateMemberRoleResponse, error) {
+	out := new(UpdateMemberRoleResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/UpdateMemberRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) TransferOwnership(ctx context.Context, in *TransferOwnershipRequest, opts ...grpc.CallOption) (*TransferOwnershipResponse, error) {
+	out := new(TransferOwnershipResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/TransferOwnership", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) GetMemberHistory(ctx context.Context, in *GetMemberHistoryRequest, opts ...grpc.CallOption) (*GetMemberHistoryResponse, error) {
+	out := new(GetMemberHistoryResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/GetMemberHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) GetEntitlements(ctx context.Context, in *GetEntitlementsRequest, opts ...grpc.CallOption) (*GetEntitlementsResponse, error) {
+	out := new(GetEntitlementsResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/GetEntitlements", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) UpdateEntitlementOverrides(ctx context.Context, in *UpdateEntitlementOverridesRequest, opts ...grpc.CallOption) (*GetEntitlementsResponse, error) {
+	out := new(GetEntitlementsResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/UpdateEntitlementOverrides", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) GetPlan(ctx context.Context, in *GetPlanRequest, opts ...grpc.CallOption) (*GetPlanResponse, error) {
+	out := new(GetPlanResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/GetPlan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ChangePlan(ctx context.Context, in *ChangePlanRequest, opts ...grpc.CallOption) (*GetPlanResponse, error) {
+	out := new(GetPlanResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ChangePlan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) CancelScheduledPlanChange(ctx context.Context, in *CancelScheduledPlanChangeRequest, opts ...grpc.CallOption) (*GetPlanResponse, error) {
+	out := new(GetPlanResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/CancelScheduledPlanChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) StartTrial(ctx context.Context, in *StartTrialRequest, opts ...grpc.CallOption) (*StartTrialResponse, error) {
+	out := new(StartTrialResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/StartTrial", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ListExpiringTrials(ctx context.Context, in *ListExpiringTrialsRequest, opts ...grpc.CallOption) (*ListExpiringTrialsResponse, error) {
+	out := new(ListExpiringTrialsResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ListExpiringTrials", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ListChildTenants(ctx context.Context, in *ListChildTenantsRequest, opts ...grpc.CallOption) (*ListChildTenantsResponse, error) {
+	out := new(ListChildTenantsResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ListChildTenants", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) GetTenantAncestors(ctx context.Context, in *GetTenantAncestorsRequest, opts ...grpc.CallOption) (*GetTenantAncestorsResponse, error) {
+	out := new(GetTenantAncestorsResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/GetTenantAncestors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) SetTenantParent(ctx context.Context, in *SetTenantParentRequest, opts ...grpc.CallOption) (*SetTenantParentResponse, error) {
+	out := new(SetTenantParentResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/SetTenantParent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) RegisterSetting(ctx context.Context, in *RegisterSettingRequest, opts ...grpc.CallOption) (*RegisterSettingResponse, error) {
+	out := new(RegisterSettingResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/RegisterSetting", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ListSettingDefinitions(ctx context.Context, in *ListSettingDefinitionsRequest, opts ...grpc.CallOption) (*ListSettingDefinitionsResponse, error) {
+	out := new(ListSettingDefinitionsResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ListSettingDefinitions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) DeleteSettingDefinition(ctx context.Context, in *DeleteSettingDefinitionRequest, opts ...grpc.CallOption) (*DeleteSettingDefinitionResponse, error) {
+	out := new(DeleteSettingDefinitionResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/DeleteSettingDefinition", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) GetEffectiveSettings(ctx context.Context, in *GetEffectiveSettingsRequest, opts ...grpc.CallOption) (*GetEffectiveSettingsResponse, error) {
+	out := new(GetEffectiveSettingsResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/GetEffectiveSettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantServiceClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, "/tenant.TenantService/ListAuditEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TenantServiceServer is the server API for TenantService.
+type TenantServiceServer interface {
+	GetTenant(context.Context, *GetTenantRequest) (*GetTenantResponse, error)
+	ListTenants(context.Context, *ListTenantsRequest) (*ListTenantsResponse, error)
+	CreateTenant(context.Context, *CreateTenantRequest) (*CreateTenantResponse, error)
+	UpdateTenant(context.Context, *UpdateTenantRequest) (*UpdateTenantResponse, error)
+	DeleteTenant(context.Context, *DeleteTenantRequest) (*DeleteTenantResponse, error)
+	AddUserToTenant(context.Context, *AddUserToTenantRequest) (*AddUserToTenantResponse, error)
+	RemoveUserFromTenant(context.Context, *RemoveUserFromTenantRequest) (*RemoveUserFromTenantResponse, error)
+	GetTenantConfig(context.Context, *GetTenantConfigRequest) (*GetTenantConfigResponse, error)
+	GetEffectiveTenantConfig(context.Context, *GetTenantConfigRequest) (*GetTenantConfigResponse, error)
+	UpdateTenantConfig(context.Context, *UpdateTenantConfigRequest) (*UpdateTenantConfigResponse, error)
+	GetDefaultService(context.Context, *GetDefaultServiceRequest) (*GetDefaultServiceResponse, error)
+	GetServiceConfig(context.Context, *GetServiceConfigRequest) (*GetServiceConfigResponse, error)
+	UpdateServiceConfig(context.Context, *UpdateServiceConfigRequest) (*UpdateServiceConfigResponse, error)
+	GetServiceURL(context.Context, *GetServiceURLRequest) (*GetServiceURLResponse, error)
+	ListTenantServices(context.Context, *ListTenantServicesRequest) (*ListTenantServicesResponse, error)
+	GetServiceHealth(context.Context, *GetServiceHealthRequest) (*GetServiceHealthResponse, error)
+	ResolveTenantByHost(context.Context, *ResolveTenantByHostRequest) (*ResolveTenantByHostResponse, error)
+	ReportServiceOutcome(context.Context, *ReportServiceOutcomeRequest) (*ReportServiceOutcomeResponse, error)
+	ReleaseServiceConnection(context.Context, *ServiceConnectionRequest) (*ServiceConnectionResponse, error)
+	ClaimDomain(context.Context, *ClaimDomainRequest) (*ClaimDomainResponse, error)
+	ListDomains(context.Context, *ListDomainsRequest) (*ListDomainsResponse, error)
+	VerifyDomain(context.Context, *VerifyDomainRequest) (*VerifyDomainResponse, error)
+	SetPrimaryDomain(context.Context, *SetPrimaryDomainRequest) (*SetPrimaryDomainResponse, error)
+	RemoveDomain(context.Context, *RemoveDomainRequest) (*RemoveDomainResponse, error)
+	SuspendTenant(context.Context, *SuspendTenantRequest) (*SuspendTenantResponse, error)
+	ReactivateTenant(context.Context, *ReactivateTenantRequest) (*ReactivateTenantResponse, error)
+	CancelTenantDeletion(context.Context, *CancelTenantDeletionRequest) (*CancelTenantDeletionResponse, error)
+	PurgeTenant(context.Context, *PurgeTenantRequest) (*PurgeTenantResponse, error)
+	GetPurgeReport(context.Context, *GetPurgeReportRequest) (*GetPurgeReportResponse, error)
+	ListTenantRoles(context.Context, *ListTenantRolesRequest) (*ListTenantRolesResponse, error)
+	CreateTenantRole(context.Context, *CreateTenantRoleRequest) (*CreateTenantRoleResponse, error)
+	UpdateTenantRole(context.Context, *UpdateTenantRoleRequest) (*UpdateTenantRoleResponse, error)
+	DeleteTenantRole(context.Context, *DeleteTenantRoleRequest) (*DeleteTenantRoleResponse, error)
+	CheckPermission(context.Context, *CheckPermissionRequest) (*CheckPermissionResponse, error)
+	CreateInvitation(context.Context, *CreateInvitationRequest) (*CreateInvitationResponse, error)
+	ListInvitations(context.Context, *ListInvitationsRequest) (*ListInvitationsResponse, error)
+	ResendInvitation(context.Context, *ResendInvitationRequest) (*ResendInvitationResponse, error)
+	RevokeInvitation(context.Context, *RevokeInvitationRequest) (*RevokeInvitationResponse, error)
+	AcceptInvitation(context.Context, *AcceptInvitationRequest) (*AcceptInvitationResponse, error)
+	DeclineInvitation(context.Context, *DeclineInvitationRequest) (*DeclineInvitationResponse, error)
+	ListTenantMembers(context.Context, *ListTenantMembersRequest) (*ListTenantMembersResponse, error)
+	ListUserTenants(context.Context, *ListUserTenantsRequest) (*ListUserTenantsResponse, error)
+	UpdateMemberRole(context.Context, *UpdateMemberRoleRequest) (*UpdateMemberRoleResponse, error)
+	TransferOwnership(context.Context, *TransferOwnershipRequest) (*TransferOwnershipResponse, error)
+	GetMemberHistory(context.Context, *GetMemberHistoryRequest) (*GetMemberHistoryResponse, error)
+	GetEntitlements(context.Context, *GetEntitlementsRequest) (*GetEntitlementsResponse, error)
+	UpdateEntitlementOverrides(context.Context, *UpdateEntitlementOverridesRequest) (*GetEntitlementsResponse, error)
+	GetPlan(context.Context, *GetPlanRequest) (*GetPlanResponse, error)
+	ChangePlan(context.Context, *ChangePlanRequest) (*GetPlanResponse, error)
+	CancelScheduledPlanChange(context.Context, *CancelScheduledPlanChangeRequest) (*GetPlanResponse, error)
+	StartTrial(context.Context, *StartTrialRequest) (*StartTrialResponse, error)
+	ListExpiringTrials(context.Context, *ListExpiringTrialsRequest) (*ListExpiringTrialsResponse, error)
+	ListChildTenants(context.Context, *ListChildTenantsRequest) (*ListChildTenantsResponse, error)
+	GetTenantAncestors(context.Context, *GetTenantAncestorsRequest) (*GetTenantAncestorsResponse, error)
+	SetTenantParent(context.Context, *SetTenantParentRequest) (*SetTenantParentResponse, error)
+	RegisterSetting(context.Context, *RegisterSettingRequest) (*RegisterSettingResponse, error)
+	ListSettingDefinitions(context.Context, *ListSettingDefinitionsRequest) (*ListSettingDefinitionsResponse, error)
+	DeleteSettingDefinition(context.Context, *DeleteSettingDefinitionRequest) (*DeleteSettingDefinitionResponse, error)
+	GetEffectiveSettings(context.Context, *GetEffectiveSettingsRequest) (*GetEffectiveSettingsResponse, error)
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
+	mustEmbedUnimplementedTenantServiceServer()
+}
+
+// UnimplementedTenantServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTenantServiceServer struct{}
+
+func (UnimplementedTenantServiceServer) GetTenant(context.Context, *GetTenantRequest) (*GetTenantResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ListTenants(context.Context, *ListTenantsRequest) (*ListTenantsResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) CreateTenant(context.Context, *CreateTenantRequest) (*CreateTenantResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) UpdateTenant(context.Context, *UpdateTenantRequest) (*UpdateTenantResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) DeleteTenant(context.Context, *DeleteTenantRequest) (*DeleteTenantResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) AddUserToTenant(context.Context, *AddUserToTenantRequest) (*AddUserToTenantResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) RemoveUserFromTenant(context.Context, *RemoveUserFromTenantRequest) (*RemoveUserFromTenantResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) GetTenantConfig(context.Context, *GetTenantConfigRequest) (*GetTenantConfigResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) GetEffectiveTenantConfig(context.Context, *GetTenantConfigRequest) (*GetTenantConfigResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) UpdateTenantConfig(context.Context, *UpdateTenantConfigRequest) (*UpdateTenantConfigResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) GetDefaultService(context.Context, *GetDefaultServiceRequest) (*GetDefaultServiceResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) GetServiceConfig(context.Context, *GetServiceConfigRequest) (*GetServiceConfigResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) UpdateServiceConfig(context.Context, *UpdateServiceConfigRequest) (*UpdateServiceConfigResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) GetServiceURL(context.Context, *GetServiceURLRequest) (*GetServiceURLResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ListTenantServices(context.Context, *ListTenantServicesRequest) (*ListTenantServicesResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) GetServiceHealth(context.Context, *GetServiceHealthRequest) (*GetServiceHealthResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ResolveTenantByHost(context.Context, *ResolveTenantByHostRequest) (*ResolveTenantByHostResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ReportServiceOutcome(context.Context, *ReportServiceOutcomeRequest) (*ReportServiceOutcomeResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ReleaseServiceConnection(context.Context, *ServiceConnectionRequest) (*ServiceConnectionResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ClaimDomain(context.Context, *ClaimDomainRequest) (*ClaimDomainResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ListDomains(context.Context, *ListDomainsRequest) (*ListDomainsResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) VerifyDomain(context.Context, *VerifyDomainRequest) (*VerifyDomainResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) SetPrimaryDomain(context.Context, *SetPrimaryDomainRequest) (*SetPrimaryDomainResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) RemoveDomain(context.Context, *RemoveDomainRequest) (*RemoveDomainResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) SuspendTenant(context.Context, *SuspendTenantRequest) (*SuspendTenantResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ReactivateTenant(context.Context, *ReactivateTenantRequest) (*ReactivateTenantResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) CancelTenantDeletion(context.Context, *CancelTenantDeletionRequest) (*CancelTenantDeletionResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) PurgeTenant(context.Context, *PurgeTenantRequest) (*PurgeTenantResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) GetPurgeReport(context.Context, *GetPurgeReportRequest) (*GetPurgeReportResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ListTenantRoles(context.Context, *ListTenantRolesRequest) (*ListTenantRolesResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) CreateTenantRole(context.Context, *CreateTenantRoleRequest) (*CreateTenantRoleResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) UpdateTenantRole(context.Context, *UpdateTenantRoleRequest) (*UpdateTenantRoleResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) DeleteTenantRole(context.Context, *DeleteTenantRoleRequest) (*DeleteTenantRoleResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) CheckPermission(context.Context, *CheckPermissionRequest) (*CheckPermissionResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) CreateInvitation(context.Context, *CreateInvitationRequest) (*CreateInvitationResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ListInvitations(context.Context, *ListInvitationsRequest) (*ListInvitationsResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ResendInvitation(context.Context, *ResendInvitationRequest) (*ResendInvitationResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) RevokeInvitation(context.Context, *RevokeInvitationRequest) (*RevokeInvitationResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) AcceptInvitation(context.Context, *AcceptInvitationRequest) (*AcceptInvitationResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) DeclineInvitation(context.Context, *DeclineInvitationRequest) (*DeclineInvitationResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ListTenantMembers(context.Context, *ListTenantMembersRequest) (*ListTenantMembersResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ListUserTenants(context.Context, *ListUserTenantsRequest) (*ListUserTenantsResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) UpdateMemberRole(context.Context, *UpdateMemberRoleRequest) (*UpdateMemberRoleResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) TransferOwnership(context.Context, *TransferOwnershipRequest) (*TransferOwnershipResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) GetMemberHistory(context.Context, *GetMemberHistoryRequest) (*GetMemberHistoryResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) GetEntitlements(context.Context, *GetEntitlementsRequest) (*GetEntitlementsResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) UpdateEntitlementOverrides(context.Context, *UpdateEntitlementOverridesRequest) (*GetEntitlementsResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) GetPlan(context.Context, *GetPlanRequest) (*GetPlanResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ChangePlan(context.Context, *ChangePlanRequest) (*GetPlanResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) CancelScheduledPlanChange(context.Context, *CancelScheduledPlanChangeRequest) (*GetPlanResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) StartTrial(context.Context, *StartTrialRequest) (*StartTrialResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ListExpiringTrials(context.Context, *ListExpiringTrialsRequest) (*ListExpiringTrialsResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ListChildTenants(context.Context, *ListChildTenantsRequest) (*ListChildTenantsResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) GetTenantAncestors(context.Context, *GetTenantAncestorsRequest) (*GetTenantAncestorsResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) SetTenantParent(context.Context, *SetTenantParentRequest) (*SetTenantParentResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) RegisterSetting(context.Context, *RegisterSettingRequest) (*RegisterSettingResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ListSettingDefinitions(context.Context, *ListSettingDefinitionsRequest) (*ListSettingDefinitionsResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) DeleteSettingDefinition(context.Context, *DeleteSettingDefinitionRequest) (*DeleteSettingDefinitionResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) GetEffectiveSettings(context.Context, *GetEffectiveSettingsRequest) (*GetEffectiveSettingsResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	return nil, nil
+}
+func (UnimplementedTenantServiceServer) mustEmbedUnimplementedTenantServiceServer() {}
+
+func RegisterTenantServiceServer(s grpc.ServiceRegistrar, srv TenantServiceServer) {
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "tenant.TenantService",
+		HandlerType: (*TenantServiceServer)(nil),
+		Methods: []grpc.MethodDesc{
+			{MethodName: "GetTenant", Handler: nil},
+			{MethodName: "ListTenants", Handler: nil},
+			{MethodName: "CreateTenant", Handler: nil},
+			{MethodName: "UpdateTenant", Handler: nil},
+			{MethodName: "DeleteTenant", Handler: nil},
+			{MethodName: "AddUserToTenant", Handler: nil},
+			{MethodName: "RemoveUserFromTenant", Handler: nil},
+			{MethodName: "GetTenantConfig", Handler: nil},
+			{MethodName: "GetEffectiveTenantConfig", Handler: nil},
+			{MethodName: "UpdateTenantConfig", Handler: nil},
+			{MethodName: "GetDefaultService", Handler: nil},
+			{MethodName: "GetServiceConfig", Handler: nil},
+			{MethodName: "UpdateServiceConfig", Handler: nil},
+			{MethodName: "GetServiceURL", Handler: nil},
+			{MethodName: "ListTenantServices", Handler: nil},
+			{MethodName: "GetServiceHealth", Handler: nil},
+			{MethodName: "ResolveTenantByHost", Handler: nil},
+			{MethodName: "ReportServiceOutcome", Handler: nil},
+			{MethodName: "ReleaseServiceConnection", Handler: nil},
+			{MethodName: "ClaimDomain", Handler: nil},
+			{MethodName: "ListDomains", Handler: nil},
+			{MethodName: "VerifyDomain", Handler: nil},
+			{MethodName: "SetPrimaryDomain", Handler: nil},
+			{MethodName: "RemoveDomain", Handler: nil},
+			{MethodName: "SuspendTenant", Handler: nil},
+			{MethodName: "ReactivateTenant", Handler: nil},
+			{MethodName: "CancelTenantDeletion", Handler: nil},
+			{MethodName: "PurgeTenant", Handler: nil},
+			{MethodName: "GetPurgeReport", Handler: nil},
+			{MethodName: "ListTenantRoles", Handler: nil},
+			{MethodName: "CreateTenantRole", Handler: nil},
+			{MethodName: "UpdateTenantRole", Handler: nil},
+			{MethodName: "DeleteTenantRole", Handler: nil},
+			{MethodName: "CheckPermission", Handler: nil},
+			{MethodName: "CreateInvitation", Handler: nil},
+			{MethodName: "ListInvitations", Handler: nil},
+			{MethodName: "ResendInvitation", Handler: nil},
+			{MethodName: "RevokeInvitation", Handler: nil},
+			{MethodName: "AcceptInvitation", Handler: nil},
+			{MethodName: "DeclineInvitation", Handler: nil},
+			{MethodName: "ListTenantMembers", Handler: nil},
+			{MethodName: "ListUserTenants", Handler: nil},
+			{MethodName: "UpdateMemberRole", Handler: nil},
+			{MethodName: "TransferOwnership", Handler: nil},
+			{MethodName: "GetMemberHistory", Handler: nil},
+			{MethodName: "GetEntitlements", Handler: nil},
+			{MethodName: "UpdateEntitlementOverrides", Handler: nil},
+			{MethodName: "GetPlan", Handler: nil},
+			{MethodName: "ChangePlan", Handler: nil},
+			{MethodName: "CancelScheduledPlanChange", Handler: nil},
+			{MethodName: "StartTrial", Handler: nil},
+			{MethodName: "ListExpiringTrials", Handler: nil},
+			{MethodName: "ListChildTenants", Handler: nil},
+			{MethodName: "GetTenantAncestors", Handler: nil},
+			{MethodName: "SetTenantParent", Handler: nil},
+			{MethodName: "RegisterSetting", Handler: nil},
+			{MethodName: "ListSettingDefinitions", Handler: nil},
+			{MethodName: "DeleteSettingDefinition", Handler: nil},
+			{MethodName: "GetEffectiveSettings", Handler: nil},
+			{MethodName: "ListAuditEvents", Handler: nil},
+		},
+		Streams:  []grpc.StreamDesc{},
+		Metadata: "tenant.proto",
+	}, srv)
+}